@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"justdoit/backup"
+	"justdoit/config"
+	"justdoit/search"
+)
+
+// runBackup handles the backup create/restore subcommands
+func runBackup(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: justdoit backup create|restore [flags] <file.tar.gz>")
+	}
+	switch args[0] {
+	case "create":
+		return runBackupCreate(args[1:])
+	case "restore":
+		return runBackupRestore(args[1:])
+	default:
+		return fmt.Errorf("unknown backup command %q", args[0])
+	}
+}
+
+// backupPaths locates the data directory, config and search index on this machine
+func backupPaths() backup.Paths {
+	todoDir, _ := dataDirs()
+	return backup.Paths{Data: todoDir, Config: config.Path(), Index: search.Path()}
+}
+
+// runBackupCreate writes the whole data directory, config and index to one bundle
+func runBackupCreate(args []string) error {
+	fs := flag.NewFlagSet("backup create", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: justdoit backup create <file.tar.gz>")
+	}
+
+	f, err := os.Create(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	m, err := backup.Create(f, backupPaths(), time.Now())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(fs.Arg(0))
+		return err
+	}
+	fmt.Printf("Backed up %d files to %s\n", len(m.Files), fs.Arg(0))
+	return nil
+}
+
+// runBackupRestore verifies a bundle and unpacks it into place
+func runBackupRestore(args []string) error {
+	fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Overwrite lists already in the data directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: justdoit backup restore [--force] <file.tar.gz>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	m, files, err := backup.Read(f)
+	if err != nil {
+		return err
+	}
+	if err := backup.Restore(files, backupPaths(), *force); err != nil {
+		return err
+	}
+	fmt.Printf("Restored %d files from a backup made %s\n", len(m.Files), m.CreatedAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
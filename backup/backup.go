@@ -0,0 +1,238 @@
+// Package backup packs the data directory, config and search index into one
+// tar.gz bundle for moving between machines.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Format is the bundle layout version, bumped whenever the layout changes
+const Format = 1
+
+// manifestName is the first entry of a bundle
+const manifestName = "manifest.json"
+
+// maxFileSize guards restore against oversized or corrupt entries
+const maxFileSize = 256 << 20
+
+// Paths says where each part of a bundle lives on this machine
+type Paths struct {
+	Data   string // data directory with the active, archived and template lists
+	Config string // config file
+	Index  string // search index file
+}
+
+// Manifest lists the files of a bundle with their checksums
+type Manifest struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []Entry   `json:"files"`
+}
+
+// Entry is one file of a bundle
+type Entry struct {
+	Name   string `json:"name"` // path in the bundle: data/..., config.json or search-index.json
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// listDirs are the directories of the data directory holding lists, relative to it
+var listDirs = []string{".", "archive", "templates"}
+
+// Create writes a bundle of the lists under p and its config and index to w. Only
+// the list files are taken from the data directory: sync state, debug logs and crash
+// reports belong to this machine and are left out.
+func Create(w io.Writer, p Paths, now time.Time) (Manifest, error) {
+	files := map[string][]byte{}
+	for _, dir := range listDirs {
+		entries, err := os.ReadDir(filepath.Join(p.Data, dir))
+		if errors.Is(err, fs.ErrNotExist) && dir != "." {
+			continue
+		}
+		if err != nil {
+			return Manifest{}, err
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(p.Data, dir, entry.Name()))
+			if err != nil {
+				return Manifest{}, err
+			}
+			files[path.Join("data", dir, entry.Name())] = data
+		}
+	}
+	for name, src := range map[string]string{"config.json": p.Config, "search-index.json": p.Index} {
+		if data, err := os.ReadFile(src); err == nil {
+			files[name] = data
+		}
+	}
+
+	m := Manifest{Format: Format, CreatedAt: now.UTC()}
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		m.Files = append(m.Files, Entry{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	if err := writeEntry(tw, manifestName, manifest, now); err != nil {
+		return m, err
+	}
+	for _, e := range m.Files {
+		if err := writeEntry(tw, e.Name, files[e.Name], now); err != nil {
+			return m, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return m, err
+	}
+	return m, gz.Close()
+}
+
+// writeEntry adds one file to the tar stream
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Read unpacks a bundle into memory and checks its format and every checksum
+// before anything touches the disk
+func Read(r io.Reader) (Manifest, map[string][]byte, error) {
+	var m Manifest
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return m, nil, fmt.Errorf("not a backup bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, nil, fmt.Errorf("corrupt bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxFileSize {
+			return m, nil, fmt.Errorf("corrupt bundle: %s is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return m, nil, fmt.Errorf("corrupt bundle: %w", err)
+		}
+		files[hdr.Name] = data
+	}
+
+	raw, ok := files[manifestName]
+	if !ok {
+		return m, nil, fmt.Errorf("not a backup bundle: no %s", manifestName)
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return m, nil, fmt.Errorf("invalid %s: %w", manifestName, err)
+	}
+	if m.Format > Format || m.Format < 1 {
+		return m, nil, fmt.Errorf("bundle format %d is not supported by this version (up to %d), update justdoit", m.Format, Format)
+	}
+
+	verified := map[string][]byte{}
+	for _, e := range m.Files {
+		if !validName(e.Name) {
+			return m, nil, fmt.Errorf("corrupt bundle: unsafe path %q", e.Name)
+		}
+		data, ok := files[e.Name]
+		if !ok {
+			return m, nil, fmt.Errorf("corrupt bundle: %s is missing", e.Name)
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+			return m, nil, fmt.Errorf("corrupt bundle: checksum mismatch for %s", e.Name)
+		}
+		verified[e.Name] = data
+	}
+	return m, verified, nil
+}
+
+// validName reports whether a bundle path stays inside the part it belongs to
+func validName(name string) bool {
+	if name == "config.json" || name == "search-index.json" {
+		return true
+	}
+	rel, ok := strings.CutPrefix(name, "data/")
+	return ok && rel != "" && path.Clean(rel) == rel && !strings.HasPrefix(rel, "../") && rel != ".." && !path.IsAbs(rel)
+}
+
+// Restore writes the files of a verified bundle into place. Unless force is set it
+// refuses to overwrite a data directory that already holds lists.
+func Restore(files map[string][]byte, p Paths, force bool) error {
+	if !force {
+		if entries, err := os.ReadDir(p.Data); err == nil {
+			for _, entry := range entries {
+				if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+					return fmt.Errorf("%s already has lists, use --force to overwrite them", p.Data)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var dst string
+		switch name {
+		case "config.json":
+			dst = p.Config
+		case "search-index.json":
+			dst = p.Index
+		default:
+			dst = filepath.Join(p.Data, filepath.FromSlash(strings.TrimPrefix(name, "data/")))
+		}
+		if err := writeFile(dst, files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile writes data to dst atomically, creating its directory
+func writeFile(dst string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".restore.tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPaths lays out a data directory, config and index under a temp dir
+func testPaths(t *testing.T) Paths {
+	dir := t.TempDir()
+	return Paths{
+		Data:   filepath.Join(dir, "todos"),
+		Config: filepath.Join(dir, "config", "config.json"),
+		Index:  filepath.Join(dir, "cache", "search-index.json"),
+	}
+}
+
+// put writes a file, creating its directory
+func put(t *testing.T, path, content string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRoundTrip tests moving everything to another machine, leaving machine-local files behind
+func TestRoundTrip(t *testing.T) {
+	src := testPaths(t)
+	put(t, filepath.Join(src.Data, "work.json"), `{"todos":[]}`)
+	put(t, filepath.Join(src.Data, "archive", "old.json"), `{"todos":[]}`)
+	put(t, filepath.Join(src.Data, "templates", "sprint.json"), `{"todos":[]}`)
+	put(t, filepath.Join(src.Data, ".remote-state"), `{}`)
+	put(t, filepath.Join(src.Data, "debug.log"), "level=DEBUG")
+	put(t, filepath.Join(src.Data, "crash-20260101-120000.log"), "panic")
+	put(t, src.Config, `{"language":"de"}`)
+	put(t, src.Index, `{"version":2}`)
+
+	var buf bytes.Buffer
+	m, err := Create(&buf, src, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 5 {
+		t.Fatalf("Expected 5 files without the sync state, debug log and crash report, got %+v", m.Files)
+	}
+
+	dst := testPaths(t)
+	_, files, err := Read(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(files, dst, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dst.Data, "archive", "old.json"), filepath.Join(dst.Data, "templates", "sprint.json"), dst.Config, dst.Index} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s restored", path)
+		}
+	}
+
+	if err := Restore(files, dst, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected a second restore to need --force, got %v", err)
+	}
+	if err := Restore(files, dst, true); err != nil {
+		t.Errorf("Expected --force to overwrite, got %v", err)
+	}
+}
+
+// pack writes a bundle with the given manifest and files as they are
+func pack(t *testing.T, m Manifest, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	manifest, _ := json.Marshal(m)
+	writeEntry(tw, manifestName, manifest, m.CreatedAt)
+	for name, data := range files {
+		writeEntry(tw, name, data, m.CreatedAt)
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// TestReadRejectsBadBundles tests the version, checksum and path checks
+func TestReadRejectsBadBundles(t *testing.T) {
+	src := testPaths(t)
+	put(t, filepath.Join(src.Data, "work.json"), `{"todos":[]}`)
+	var buf bytes.Buffer
+	if _, err := Create(&buf, src, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	m, files, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newer := m
+	newer.Format = Format + 1
+	if _, _, err := Read(bytes.NewReader(pack(t, newer, files))); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("Expected a newer format to be refused, got %v", err)
+	}
+
+	changed := map[string][]byte{"data/work.json": []byte(`{"todos":[{}]}`)}
+	if _, _, err := Read(bytes.NewReader(pack(t, m, changed))); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected a changed file to be caught, got %v", err)
+	}
+
+	escape := m
+	escape.Files = []Entry{{Name: "data/../../.bashrc"}}
+	if _, _, err := Read(bytes.NewReader(pack(t, escape, nil))); err == nil || !strings.Contains(err.Error(), "unsafe") {
+		t.Errorf("Expected paths leaving the data directory to be refused, got %v", err)
+	}
+
+	if _, _, err := Read(strings.NewReader("plain text")); err == nil {
+		t.Error("Expected a non-bundle to be refused")
+	}
+}
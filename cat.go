@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+	"justdoit/ui"
+)
+
+// runCat prints a list the way the TUI shows it and exits, for ssh, scripts and pagers
+func runCat(args []string) error {
+	fs := flag.NewFlagSet("cat", flag.ContinueOnError)
+	color := fs.String("color", "auto", "Color the output: auto (only on a terminal), always or never")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: justdoit cat [--color auto|always|never] <file>")
+	}
+	switch *color {
+	case "auto":
+		// lipgloss leaves out colors when stdout isn't a terminal or NO_COLOR is set
+	case "always":
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case "never":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	default:
+		return fmt.Errorf("--color is auto, always or never, not %q", *color)
+	}
+
+	todoDir, _ := dataDirs()
+	path, err := listPath(todoDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	tl := todo.NewTodoList(path)
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	i18n.Set(cfg.Language)
+	highlights, err := ui.CompileHighlights(cfg.Highlights)
+	if err != nil {
+		return err
+	}
+	fmt.Print(ui.RenderList(tl, filepath.Base(path), cfg.Behavior, highlights))
+	return nil
+}
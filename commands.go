@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"justdoit/todo"
+	"justdoit/ui"
+)
+
+// runCommand dispatches a CLI subcommand
+func runCommand(name string, args []string) error {
+	switch name {
+	case "report":
+		return runReport(args)
+	case "csv":
+		return runCSV(args)
+	case "search":
+		return runSearch(args)
+	case "print":
+		return runPrint(args)
+	case "cat":
+		return runCat(args)
+	case "html":
+		return runHTML(args)
+	case "share":
+		return runShare(args)
+	case "ingest-mail":
+		return runIngestMail(args)
+	case "summary":
+		return runSummary(args)
+	case "reminders":
+		return runReminders(args)
+	case "sync":
+		return runSync(args)
+	case "backup":
+		return runBackup(args)
+	case "new":
+		return runNew(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// listPath returns the path of the named list in dir, failing when there is none or
+// the file isn't a todo list
+func listPath(dir, name string) (string, error) {
+	path := filepath.Join(dir, listFilename(name))
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("list %q not found", name)
+	}
+	if todo.ReadHeader(path).Foreign {
+		return "", fmt.Errorf("%s isn't a todo list", filepath.Base(path))
+	}
+	return path, nil
+}
+
+// listPaths returns the paths of the lists in dir, leaving out JSON files that aren't
+// todo lists
+func listPaths(dir string) []string {
+	var paths []string
+	for _, f := range ui.LoadTodoFiles(dir) {
+		path := filepath.Join(dir, f)
+		if !todo.ReadHeader(path).Foreign {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,285 @@
+// Package config loads user configuration from disk.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// HighlightRule styles todos whose title (or custom field) matches a regular expression
+type HighlightRule struct {
+	Pattern       string `json:"pattern"`
+	Field         string `json:"field,omitempty"` // match this custom field's value instead of the title
+	Foreground    string `json:"foreground,omitempty"`
+	Background    string `json:"background,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Faint         bool   `json:"faint,omitempty"`
+	Underline     bool   `json:"underline,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+}
+
+// EscalationRule raises open todos neglected for too long, e.g. open_for "14d" with
+// field "priority=high", or overdue_for "3d" with flag
+type EscalationRule struct {
+	OpenFor    string `json:"open_for,omitempty"`    // age since creation, like "14d" or "36h"
+	OverdueFor string `json:"overdue_for,omitempty"` // time past the due date
+	Flag       bool   `json:"flag,omitempty"`        // mark as a priority
+	Field      string `json:"field,omitempty"`       // key=value to set
+}
+
+// Filter is a saved search shown as a virtual list in the file panel
+type Filter struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`           // search expression, e.g. "is:open due:<7d OR is:flagged"
+	Regex bool   `json:"regex,omitempty"` // Query is a regular expression over titles
+	View
+}
+
+// View is how a smart view, the Today view or a saved filter, orders and shows its todos
+type View struct {
+	Sort string   `json:"sort,omitempty"` // one of the View sorts, "" for the view's own order
+	Hide []string `json:"hide,omitempty"` // columns left out, e.g. ["due"]
+}
+
+// View sorts
+const (
+	SortDue     = "due"     // earliest due first, undated last
+	SortTitle   = "title"   // alphabetical
+	SortCreated = "created" // newest first
+	SortList    = "list"    // by list name
+)
+
+// View columns
+const (
+	ColumnDue    = "due"
+	ColumnFields = "fields"
+	ColumnList   = "list"
+)
+
+// Hides reports whether the view leaves out a column
+func (v View) Hides(column string) bool {
+	return slices.Contains(v.Hide, column)
+}
+
+// Webhook is where the summary command posts
+type Webhook struct {
+	URL  string `json:"url"`
+	Kind string `json:"kind,omitempty"` // slack or discord, guessed from the URL when empty
+	Top  int    `json:"top,omitempty"`  // most pressing todos to list, 5 when unset
+}
+
+// Webhook kinds
+const (
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+)
+
+// AppleReminders controls the macOS Reminders bridge, available in builds with -tags reminders
+type AppleReminders struct {
+	Enabled bool   `json:"enabled"`
+	List    string `json:"list,omitempty"` // Reminders list todos are exported to, "justdoit" when empty
+}
+
+// Remote keeps the canonical data directory on a server, synced with the sftp command
+type Remote struct {
+	Host string `json:"host"`           // user@host or an ssh config alias
+	Path string `json:"path"`           // data directory on the server
+	Key  string `json:"key,omitempty"`  // identity file, ssh's default when empty
+	Port int    `json:"port,omitempty"` // 22 or the ssh config's when unset
+}
+
+// Jira is the server todos are imported from by issue key
+type Jira struct {
+	URL   string `json:"url"`             // like https://example.atlassian.net
+	Email string `json:"email,omitempty"` // Jira Cloud account of the token; empty sends it as a bearer token
+	Token string `json:"token"`           // API token, or a personal access token on Jira Server
+}
+
+// TagIcon is how a @tag is shown in titles
+type TagIcon struct {
+	Icon  string `json:"icon,omitempty"`  // Nerd Font glyph, a generic tag when empty
+	Color string `json:"color,omitempty"` // a list color name like "red" or a hex color
+}
+
+// Celebration effects played when a todo is completed
+const (
+	CelebrateOff      = "off"
+	CelebrateConfetti = "confetti"
+	CelebrateBell     = "bell"
+	CelebrateBoth     = "both"
+)
+
+// Behavior holds the flags exposed in the settings screen
+type Behavior struct {
+	ConfirmDeletes  bool   `json:"confirm_deletes"`  // ask before deleting files
+	ArchivePrompt   bool   `json:"archive_prompt"`   // offer to archive when a list is complete
+	SortCompleted   bool   `json:"sort_completed"`   // move completed todos to the bottom
+	AutosaveSeconds int    `json:"autosave_seconds"` // 0 saves on every change
+	Celebrate       string `json:"celebrate"`        // effect on completion: off, confetti, bell or both
+	MaxTitleLength  int    `json:"max_title_length"` // longer titles spill into the notes, 0 for no limit
+	Accessible      bool   `json:"accessible"`       // monochrome, line-oriented output with text markers
+	FilePanelWidth  int    `json:"file_panel_width"` // percent of the window used by the file panel
+	TitleBar        bool   `json:"title_bar"`        // show the data directory and open file above the panels
+	LineNumbers     string `json:"line_numbers"`     // todo panel gutter: off, absolute or relative
+	LowercaseNames  bool   `json:"lowercase_names"`  // lowercase the names of new lists
+	DatedArchives   bool   `json:"dated_archives"`   // add the archive date to archived file names
+	HideDeferred    bool   `json:"hide_deferred"`    // hide todos until their start date
+	SoundTodo       bool   `json:"sound_todo"`       // play a sound when a todo is completed
+	SoundList       bool   `json:"sound_list"`       // play a sound when the last todo of a list is completed
+	SoundCommand    string `json:"sound_command"`    // run through the shell instead of ringing the terminal bell
+	TodoLayout      string `json:"todo_layout"`      // todo panel: list or table
+	StartupFile     string `json:"startup_file"`     // list opened at startup: first, last or picker
+}
+
+// Lists opened at startup for Behavior.StartupFile
+const (
+	StartupFirst  = "first"  // the first list by name
+	StartupLast   = "last"   // the list open when the last session ended
+	StartupPicker = "picker" // ask with a picker filtered by typing
+)
+
+// Todo panel layouts for Behavior.TodoLayout
+const (
+	LayoutList  = "list"
+	LayoutTable = "table"
+)
+
+// TableColumn is a column of the table layout
+type TableColumn struct {
+	Name  string `json:"name"`            // one of the table columns
+	Width int    `json:"width,omitempty"` // in cells, the column's default when unset; the title takes the rest
+}
+
+// Table columns, in their default order; ColumnDue is shared with the views
+const (
+	ColumnStatus   = "status"
+	ColumnTitle    = "title"
+	ColumnTags     = "tags"
+	ColumnPriority = "priority"
+	ColumnAge      = "age"
+)
+
+// TableColumns are the columns of the table layout in their default order
+var TableColumns = []string{ColumnStatus, ColumnTitle, ColumnTags, ColumnDue, ColumnPriority, ColumnAge}
+
+// Line number styles for Behavior.LineNumbers
+const (
+	LineNumbersOff      = "off"
+	LineNumbersAbsolute = "absolute"
+	LineNumbersRelative = "relative"
+)
+
+// Bounds of Behavior.FilePanelWidth
+const (
+	MinFilePanelWidth = 10
+	MaxFilePanelWidth = 60
+)
+
+// Config holds all user-configurable settings
+type Config struct {
+	Highlights  []HighlightRule     `json:"highlights,omitempty"`
+	TagIcons    map[string]TagIcon  `json:"tag_icons,omitempty"`       // by tag, without the @
+	Escalations []EscalationRule    `json:"escalations,omitempty"`     // applied to every list at startup
+	Filters     []Filter            `json:"filters,omitempty"`         // saved searches
+	Today       View                `json:"today,omitzero"`            // how the Today view is sorted and shown
+	Webhook     *Webhook            `json:"webhook,omitempty"`         // daily summary target
+	Reminders   *AppleReminders     `json:"apple_reminders,omitempty"` // macOS Reminders bridge
+	Remote      *Remote             `json:"remote,omitempty"`          // synced at start and exit
+	Jira        *Jira               `json:"jira,omitempty"`            // issue import
+	Keys        map[string][]string `json:"keys,omitempty"`            // action name -> keys
+	Behavior    Behavior            `json:"behavior"`
+	Table       []TableColumn       `json:"table,omitempty"`          // columns of the table layout, all of them when empty
+	Language    string              `json:"language,omitempty"`       // e.g. "de"; empty follows LANG
+	Timezone    string              `json:"timezone,omitempty"`       // e.g. "Europe/Berlin"; empty follows TZ
+	Author      string              `json:"author,omitempty"`         // recorded on the todos you add and complete
+	Checks      []string            `json:"trusted_checks,omitempty"` // check commands run without asking first
+}
+
+// Default returns the config used when no file exists
+func Default() Config {
+	return Config{
+		Behavior: Behavior{
+			ConfirmDeletes: true,
+			ArchivePrompt:  true,
+			SortCompleted:  true,
+			Celebrate:      CelebrateOff,
+			MaxTitleLength: 200,
+			FilePanelWidth: 25,
+			LineNumbers:    LineNumbersOff,
+			TodoLayout:     LayoutList,
+			StartupFile:    StartupFirst,
+		},
+	}
+}
+
+// Path returns the location of the config file
+func Path() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		homeDir, _ := os.UserHomeDir()
+		dir = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(dir, "justdoit", "config.json")
+}
+
+// Load reads the config file, returning defaults if it doesn't exist.
+// Settings missing from the file keep their default values.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil // No config yet, use defaults
+		}
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Behavior.AutosaveSeconds < 0 {
+		cfg.Behavior.AutosaveSeconds = 0
+	}
+	if cfg.Behavior.MaxTitleLength < 0 {
+		cfg.Behavior.MaxTitleLength = 0
+	}
+	cfg.Behavior.FilePanelWidth = min(max(cfg.Behavior.FilePanelWidth, MinFilePanelWidth), MaxFilePanelWidth)
+	switch cfg.Behavior.LineNumbers {
+	case LineNumbersOff, LineNumbersAbsolute, LineNumbersRelative:
+	default:
+		cfg.Behavior.LineNumbers = LineNumbersOff
+	}
+	switch cfg.Behavior.Celebrate {
+	case CelebrateOff, CelebrateConfetti, CelebrateBell, CelebrateBoth:
+	default:
+		cfg.Behavior.Celebrate = CelebrateOff
+	}
+	if cfg.Behavior.TodoLayout != LayoutTable {
+		cfg.Behavior.TodoLayout = LayoutList
+	}
+	switch cfg.Behavior.StartupFile {
+	case StartupFirst, StartupLast, StartupPicker:
+	default:
+		cfg.Behavior.StartupFile = StartupFirst
+	}
+	return cfg, nil
+}
+
+// Save writes the config file, creating its directory if needed
+func Save(path string, cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
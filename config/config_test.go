@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadMissing tests that a missing config yields defaults
+func TestLoadMissing(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Highlights) != 0 {
+		t.Errorf("Expected no highlights, got %v", cfg.Highlights)
+	}
+}
+
+// TestLoadHighlights tests parsing highlight rules
+func TestLoadHighlights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"highlights": [{"pattern": "URGENT", "foreground": "#f38ba8", "bold": true}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Highlights) != 1 || cfg.Highlights[0].Pattern != "URGENT" || !cfg.Highlights[0].Bold {
+		t.Errorf("Unexpected highlights: %+v", cfg.Highlights)
+	}
+}
+
+// TestLoadInvalid tests that malformed config reports an error
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"highlights": [`), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for invalid config")
+	}
+}
+
+// TestLoadBehavior tests that out-of-range behavior values fall back to safe defaults
+func TestLoadBehavior(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"behavior": {"autosave_seconds": -5, "celebrate": "fireworks", "startup_file": "newest"}}`), 0644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Behavior.AutosaveSeconds != 0 || cfg.Behavior.Celebrate != CelebrateOff || cfg.Behavior.StartupFile != StartupFirst {
+		t.Errorf("Unexpected behavior: %+v", cfg.Behavior)
+	}
+	if !cfg.Behavior.SortCompleted {
+		t.Error("Expected unset flags to keep their defaults")
+	}
+}
+
+// TestSaveViews tests that view settings are kept inline with each saved filter
+func TestSaveViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := Default()
+	cfg.Filters = []Filter{{Name: "soon", Query: "due:<7d", View: View{Sort: SortDue, Hide: []string{ColumnFields}}}}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"sort": "due"`) || strings.Contains(string(data), `"today"`) {
+		t.Errorf("Expected the view inline and no empty Today view, got %s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v := loaded.Filters[0].View; v.Sort != SortDue || !v.Hides(ColumnFields) || v.Hides(ColumnDue) {
+		t.Errorf("Unexpected view: %+v", v)
+	}
+}
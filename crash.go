@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// crashEvents is how many of the latest log records a crash report includes
+const crashEvents = 20
+
+// crash is a panic caught while the TUI was running
+type crash struct {
+	value any
+	stack []byte
+	state string // model summary, "" outside the TUI
+}
+
+var (
+	crashMu  sync.Mutex
+	caught   *crash
+	recorded = &eventRing{}
+)
+
+// eventRing keeps the latest log lines for crash reports
+type eventRing struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// Write adds a formatted log record, dropping the oldest beyond crashEvents
+func (r *eventRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, strings.TrimRight(string(p), "\n"))
+	if len(r.lines) > crashEvents {
+		r.lines = r.lines[len(r.lines)-crashEvents:]
+	}
+	return len(p), nil
+}
+
+// Lines returns the recorded log lines, oldest first
+func (r *eventRing) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+// anonymize keeps numbers, durations and key names in crash report events and drops
+// strings that could hold titles or paths. Typed characters are left out too.
+func anonymize(_ []string, a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+		return a
+	case "key":
+		if utf8.RuneCountInString(a.Value.String()) == 1 {
+			return slog.String(a.Key, "char")
+		}
+		return a
+	}
+	switch a.Value.Kind() {
+	case slog.KindString, slog.KindAny, slog.KindGroup:
+		return slog.Attr{}
+	}
+	return a
+}
+
+// teeHandler records every log record for crash reports and passes those its
+// handler is enabled for on to it
+type teeHandler struct {
+	record, next slog.Handler
+}
+
+// withEvents wraps a handler so the latest records end up in crash reports
+func withEvents(next slog.Handler) slog.Handler {
+	record := slog.NewTextHandler(recorded, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: anonymize})
+	return teeHandler{record, next}
+}
+
+func (h teeHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.record.Handle(ctx, r)
+	if h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return teeHandler{h.record.WithAttrs(attrs), h.next.WithAttrs(attrs)}
+}
+
+func (h teeHandler) WithGroup(name string) slog.Handler {
+	return teeHandler{h.record.WithGroup(name), h.next.WithGroup(name)}
+}
+
+// crashGuard wraps the TUI model to note the state it panicked in. The panic
+// carries on so Bubble Tea restores the terminal.
+type crashGuard struct {
+	tea.Model
+}
+
+// summarizer is a model that can describe its state for a crash report
+type summarizer interface {
+	CrashSummary() string
+}
+
+func (g crashGuard) Init() tea.Cmd {
+	defer g.catch()
+	return g.Model.Init()
+}
+
+func (g crashGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer g.catch()
+	model, cmd := g.Model.Update(msg)
+	return crashGuard{model}, cmd
+}
+
+func (g crashGuard) View() string {
+	defer g.catch()
+	return g.Model.View()
+}
+
+// catch records a panic with the model's state and panics again
+func (g crashGuard) catch() {
+	if r := recover(); r != nil {
+		state := ""
+		if s, ok := g.Model.(summarizer); ok {
+			state = s.CrashSummary()
+		}
+		recordCrash(r, debug.Stack(), state)
+		panic(r)
+	}
+}
+
+// recordCrash keeps the first panic caught
+func recordCrash(value any, stack []byte, state string) {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	if caught == nil {
+		caught = &crash{value, stack, state}
+	}
+}
+
+// takeCrash returns the panic caught, if any
+func takeCrash() *crash {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	return caught
+}
+
+// writeCrashReport writes a crash report next to the todo lists and returns its path.
+// Nothing is sent anywhere.
+func writeCrashReport(c *crash) (string, error) {
+	todoDir, _ := dataDirs()
+	if err := os.MkdirAll(todoDir, 0755); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	path := filepath.Join(todoDir, "crash-"+now.Format("20060102-150405")+".log")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "justdoit crash report\n\n")
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n", c.value)
+	if c.state != "" {
+		fmt.Fprintf(&b, "state: %s\n", c.state)
+	}
+	b.WriteString("\nlast events:\n")
+	for _, line := range recorded.Lines() {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+	fmt.Fprintf(&b, "\nstack:\n%s", c.stack)
+
+	return path, os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// resetTerminal turns off mouse reporting, shows the cursor and leaves the alternate
+// screen in case the TUI exited without doing so. Nothing is written to a pipe.
+func resetTerminal() {
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	fmt.Print(ansi.ResetButtonEventMouseMode + ansi.ResetSgrExtMouseMode + ansi.ShowCursor + ansi.ResetAltScreenMode)
+}
+
+// reportCrash writes the report of a caught panic, tells where it is and exits
+func reportCrash(c *crash) {
+	resetTerminal()
+	path, err := writeCrashReport(c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "justdoit crashed: %v\nThe crash report could not be written: %v\n%s", c.value, err, c.stack)
+	} else {
+		fmt.Fprintf(os.Stderr, "justdoit crashed: %v\nA crash report was written to %s (it stays on this machine).\n", c.value, path)
+	}
+	os.Exit(2)
+}
+
+// recoverCrash reports a panic outside the TUI, such as in a command
+func recoverCrash() {
+	if r := recover(); r != nil {
+		recordCrash(r, debug.Stack(), "")
+		reportCrash(takeCrash())
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// runCSV handles the csv export/import subcommands
+func runCSV(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: justdoit csv export|import [flags]")
+	}
+	switch args[0] {
+	case "export":
+		return runCSVExport(args[1:])
+	case "import":
+		return runCSVImport(args[1:])
+	default:
+		return fmt.Errorf("unknown csv command %q", args[0])
+	}
+}
+
+// runCSVExport writes one list or all lists to CSV
+func runCSVExport(args []string) error {
+	fs := flag.NewFlagSet("csv export", flag.ContinueOnError)
+	file := fs.String("file", "", "List to export (default: all lists)")
+	columns := fs.String("columns", "file,id,title,completed,created_at,completed_at", "Comma-separated columns to export")
+	out := fs.String("out", "", "Output path (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	todoDir, _ := dataDirs()
+	var paths []string
+	if *file != "" {
+		path, err := listPath(todoDir, *file)
+		if err != nil {
+			return err
+		}
+		paths = []string{path}
+	} else {
+		paths = listPaths(todoDir)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return todo.WriteCSV(w, paths, strings.Split(*columns, ","))
+}
+
+// runCSVImport creates a new list from a CSV file
+func runCSVImport(args []string) error {
+	fs := flag.NewFlagSet("csv import", flag.ContinueOnError)
+	name := fs.String("name", "", "Name of the list to create (default: CSV filename)")
+	mapping := fs.String("map", "", "Field mapping, e.g. title=Task,completed=Done (prompted if omitted)")
+	dryRun := fs.Bool("dry-run", false, "Print what would be imported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: justdoit csv import [flags] <file.csv>")
+	}
+	src := fs.Arg(0)
+
+	if *name == "" {
+		*name = strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	}
+	cfg, _ := config.Load(config.Path())
+	normalized := todo.NormalizeListName(*name, cfg.Behavior.LowercaseNames)
+	if err := todo.ValidateListName(normalized); err != nil {
+		return fmt.Errorf("can't create %q: %v", *name, err)
+	}
+	*name = normalized
+	todoDir, _ := dataDirs()
+	dst := filepath.Join(todoDir, listFilename(*name))
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("list %q already exists", *name)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var fields map[string]string
+	if *mapping != "" {
+		if fields, err = parseMapping(*mapping); err != nil {
+			return err
+		}
+	} else {
+		header, err := todo.ReadCSVHeader(f)
+		if err != nil {
+			return err
+		}
+		fields = promptMapping(header, os.Stdin, os.Stdout)
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	todos, err := todo.ReadCSV(f, fields)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		printDryRun(todos, fmt.Sprintf("Would import %d todos into %s", len(todos), filepath.Base(dst)))
+		return nil
+	}
+
+	os.MkdirAll(todoDir, 0755)
+	tl := todo.NewTodoList(dst)
+	tl.SetDeferredSave(true) // written once, reporting a failure
+	tl.Import(todos)
+	if err := tl.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d todos into %s\n", len(todos), filepath.Base(dst))
+	return nil
+}
+
+// listFilename appends .json to a list name if missing
+func listFilename(name string) string {
+	if filepath.Ext(name) == ".json" {
+		return name
+	}
+	return name + ".json"
+}
+
+// printDryRun lists the todos a --dry-run would have written, then what it would have done
+func printDryRun(todos []todo.Todo, summary string) {
+	for _, t := range todos {
+		box := "[ ]"
+		if t.Completed {
+			box = "[x]"
+		}
+		fmt.Printf("%s  %s\n", box, t.Title)
+	}
+	fmt.Println(summary)
+}
+
+// parseMapping parses field=column pairs
+func parseMapping(s string) (map[string]string, error) {
+	fields := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping %q, expected field=column", pair)
+		}
+		fields[strings.TrimSpace(field)] = strings.TrimSpace(column)
+	}
+	return fields, nil
+}
+
+// promptMapping asks which CSV column feeds each todo field
+func promptMapping(header []string, in io.Reader, out io.Writer) map[string]string {
+	fmt.Fprintln(out, "CSV columns:")
+	for i, col := range header {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, col)
+	}
+
+	scanner := bufio.NewScanner(in)
+	fields := map[string]string{}
+	for _, field := range todo.CSVFields {
+		guess := ""
+		for _, col := range header {
+			if strings.EqualFold(col, field) || (field == "title" && strings.EqualFold(col, "task")) {
+				guess = col
+				break
+			}
+		}
+
+		fmt.Fprintf(out, "Column for %s [%s] (number, name, or - to skip): ", field, guess)
+		answer := ""
+		if scanner.Scan() {
+			answer = strings.TrimSpace(scanner.Text())
+		}
+
+		switch {
+		case answer == "-":
+			continue
+		case answer == "":
+			answer = guess
+		default:
+			var n int
+			if _, err := fmt.Sscanf(answer, "%d", &n); err == nil && n >= 1 && n <= len(header) {
+				answer = header[n-1]
+			}
+		}
+		if answer != "" {
+			fields[field] = answer
+		}
+	}
+	return fields
+}
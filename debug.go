@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"justdoit/todo"
+)
+
+// maxDebugLogSize is the size above which the debug log is moved to debug.log.1 at startup
+const maxDebugLogSize = 5 << 20
+
+// debugLogPath returns where --debug writes its log
+func debugLogPath() string {
+	todoDir, _ := dataDirs()
+	return filepath.Join(todoDir, "debug.log")
+}
+
+// setupLogging sends slog records to the debug log when enabled, and discards them
+// otherwise so nothing is written over the TUI. The latest records are kept for crash
+// reports either way. It returns the log's path ("" when
+// disabled) and a function closing it.
+func setupLogging(enabled bool) (string, func(), error) {
+	logListEvents()
+	if !enabled {
+		slog.SetDefault(slog.New(withEvents(slog.DiscardHandler)))
+		return "", func() {}, nil
+	}
+
+	path := debugLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxDebugLogSize {
+		os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", nil, err
+	}
+
+	slog.SetDefault(slog.New(withEvents(slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	slog.Info("start", "pid", os.Getpid(), "args", os.Args[1:])
+	return path, func() {
+		slog.Info("exit")
+		f.Close()
+	}, nil
+}
+
+// logListEvents records the list events as they are published, naming the file and
+// the todo's ID but never its title
+func logListEvents() {
+	todo.Subscribe(func(e todo.Event) {
+		slog.Debug("event", "kind", e.Kind.String(), "file", filepath.Base(e.Path), "todo", e.Todo.ID)
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"justdoit/todo"
+)
+
+// runHTML exports one list or all lists as a standalone HTML page
+func runHTML(args []string) error {
+	fs := flag.NewFlagSet("html", flag.ContinueOnError)
+	file := fs.String("file", "", "List to export (default: all lists)")
+	archived := fs.Bool("archived", false, "Include archived files when exporting all lists")
+	out := fs.String("out", "", "Output path (default: stdout)")
+	links := fs.Bool("links", false, "Link each todo to justdoit://list/id, which opens it in the TUI")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	todoDir, archiveDir := dataDirs()
+	var paths []string
+	if *file != "" {
+		path, err := listPath(todoDir, *file)
+		if err != nil {
+			return err
+		}
+		paths = []string{path}
+	} else {
+		paths = listPaths(todoDir)
+		if *archived {
+			paths = append(paths, listPaths(archiveDir)...)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	linkDir := ""
+	if *links {
+		linkDir = todoDir // archived lists have to be unarchived to open
+	}
+	return todo.WriteHTML(w, paths, linkDir)
+}
@@ -0,0 +1,553 @@
+package i18n
+
+// german is the German (de) catalog
+var german = map[string]string{
+	"$EDITOR":       "$EDITOR",
+	" %d archived ": " %d archiviert ",
+	"%d archived":   "%d archiviert",
+	"%d characters": "%d Zeichen",
+	" %d marked ":   " %d markiert ",
+	"%d marked":     "%d markiert",
+	"%d of %d done": "%d von %d erledigt",
+	"%d open, %d done and %d added in the last %d days": "%d offen, %d erledigt und %d hinzugefügt in den letzten %d Tagen",
+	"%d renamed to keep earlier archives":               "%d umbenannt, um frühere Archive zu behalten",
+	"%dx%d, needs %dx%d":                                "%dx%d, benötigt %dx%d",
+	"  %s  No todos yet":                                "  %s  Noch keine Todos",
+	"%s already exists: r renames the restored file, e edits the name, o overwrites, c cancels": "%s existiert bereits: r benennt die wiederhergestellte Datei um, e bearbeitet den Namen, o überschreibt, c bricht ab",
+	"%s bound to %s": "%s liegt auf %s",
+	"%s clears all":  "%s entfernt alle",
+	" %s Files ":     " %s Dateien ",
+	"%s is already archived: r renames the new archive, e edits the name, o overwrites, c cancels": "%s ist bereits archiviert: r benennt das neue Archiv um, e bearbeitet den Namen, o überschreibt, c bricht ab",
+	"%s is already in this list":         "%s ist schon in dieser Liste",
+	"%s is taken too":                    "%s ist ebenfalls vergeben",
+	"%s, archived as %s with %d todos":   "%s, archiviert als %s mit %d Todos",
+	"%s, copied with %d todos":           "%s, kopiert mit %d Todos",
+	"%s, deleted with %d todos":          "%s, gelöscht mit %d Todos",
+	"%s, merged into %s with %d todos":   "%s, in %s zusammengeführt mit %d Todos",
+	"%s: %d files":                       "%s: %d Dateien",
+	" (active)":                          " (aktiv)",
+	" (done)":                            " (erledigt)",
+	" (not done)":                        " (nicht erledigt)",
+	"(not there)":                        "(nicht vorhanden)",
+	" +%d more":                          " +%d weitere",
+	", %d overdue":                       ", %d überfällig",
+	"@ Switch Context":                   "@ Kontext wechseln",
+	"[ ]":                                "[ ]",
+	"[%d%%]":                             "[%d%%]",
+	"[BY %s]":                            "[VON %s]",
+	"[CHECK %s]":                         "[PRÜFUNG %s]",
+	"[DONE]":                             "[ERLEDIGT]",
+	"[DUE %s]":                           "[FÄLLIG %s]",
+	"[FLAGGED]":                          "[WICHTIG]",
+	"[FOLDED]":                           "[EINGEKLAPPT]",
+	"[MARKED]":                           "[MARKIERT]",
+	"[NOTES]":                            "[NOTIZEN]",
+	"[OPEN]":                             "[GEÖFFNET]",
+	"[OVERDUE %s]":                       "[ÜBERFÄLLIG %s]",
+	"[REMINDER]":                         "[ERINNERUNG]",
+	"[STARTS %s]":                        "[BEGINNT %s]",
+	"[STREAK %d]":                        "[SERIE %d]",
+	"A list by this name already exists": "Eine Liste mit diesem Namen existiert bereits",
+	"absolute":                           "absolut",
+	"Accessible mode":                    "Barrierefreier Modus",
+	"Acknowledge reminders":              "Erinnerungen bestätigen",
+	"add":                                "neu",
+	"Add file / todo":                    "Datei / Todo hinzufügen",
+	"Added: %d todos":                    "Hinzugefügt: %d Todos",
+	"Adding new todo (Enter to save, Esc to cancel)": "Neues Todo (Enter speichert, Esc bricht ab)",
+	"Age":                                    "Alter",
+	"All complete! Archive this list? (y/n)": "Alles erledigt! Liste archivieren? (y/n)",
+	"All contexts":                           "Alle Kontexte",
+	"An unfinished change was undone, %d files put back": "Eine unvollendete Änderung wurde rückgängig gemacht, %d Dateien wiederhergestellt",
+	"apply":                    "anwenden",
+	"archive":                  "archivieren",
+	"Archive %d files?":        "%d Dateien archivieren?",
+	"Archive as":               "Archivieren als",
+	"Archive Confirmation":     "Archivieren bestätigen",
+	"Archive file":             "Datei archivieren",
+	"archive marked":           "markierte archivieren",
+	"Archive this file?":       "Diese Datei archivieren?",
+	"Archive this file? (y/n)": "Diese Datei archivieren? (y/n)",
+	"archived":                 "archiviert",
+	"Archived %d files":        "%d Dateien archiviert",
+	"Archived files: %d":       "Archivierte Dateien: %d",
+	"Archiving %d files":       "%d Dateien werden archiviert",
+	"Archiving %s":             "%s wird archiviert",
+	"as added":                 "wie hinzugefügt",
+	"At the current pace this list finishes ~%s":   "Beim aktuellen Tempo ist diese Liste etwa am %s erledigt",
+	"Autosave interval":                            "Intervall für automatisches Speichern",
+	"back":                                         "zurück",
+	"Back to file panel":                           "Zurück zur Dateiliste",
+	"Breakdown":                                    "Aufschlüsselung",
+	"Bulk edit":                                    "Massenbearbeitung",
+	"Bulk edit discarded":                          "Sammelbearbeitung verworfen",
+	"Bulk edit failed: %v":                         "Sammelbearbeitung fehlgeschlagen: %v",
+	"Bulk edit not applied: %v":                    "Sammelbearbeitung nicht übernommen: %v",
+	"Bulk edit shown todos in $EDITOR":             "Angezeigte Todos gesammelt in $EDITOR bearbeiten",
+	"Bulk edit: %d added, %d updated, %d deleted":  "Sammelbearbeitung: %d hinzugefügt, %d geändert, %d gelöscht",
+	"Burndown (open todos at the end of each day)": "Burndown (offene Todos am Ende jedes Tages)",
+	"by due date":                                  "nach Fälligkeit",
+	"by title":                                     "nach Titel",
+	" Cancel":                                      " Abbrechen",
+	"cancel":                                       "abbrechen",
+	"Cancelled":                                    "Abgebrochen",
+	"Cannot be empty":                              "Darf nicht leer sein",
+	"Cannot read the debug log: %v":                "Debug-Log nicht lesbar: %v",
+	"Celebrate completions":                        "Erledigtes feiern",
+	"change":                                       "ändern",
+	"Check":                                        "Prüfung",
+	"Check command removed":                        "Prüfbefehl entfernt",
+	"Check command: done when it exits 0 (empty removes it)": "Prüfbefehl: erledigt bei Exit-Code 0 (leer entfernt ihn)",
+	"Checks: %d passed, %d failed":                           "Prüfungen: %d bestanden, %d fehlgeschlagen",
+	"choose":                                                 "wählen",
+	"Clear all filters":                                      "Alle Filter entfernen",
+	"clear filters":                                          "Filter entfernen",
+	"close":                                                  "schließen",
+	"Color":                                                  "Farbe",
+	"columns":                                                "Spalten",
+	"Columns: %s":                                            "Spalten: %s",
+	"Columns: none":                                          "Spalten: keine",
+	"Compare %s with":                                        "%s vergleichen mit",
+	"Compare with another list":                              "Mit einer anderen Liste vergleichen",
+	"Comparing %s with %s":                                   "Vergleiche %s mit %s",
+	"complete":                                               "vervollständigen",
+	"Completed todos":                                        "Erledigte Aufgaben",
+	"Completions across all lists":                           "Erledigt in allen Listen",
+	"confirm":                                                "bestätigen",
+	"Confirm file deletes":                                   "Löschen von Dateien bestätigen",
+	"context":                                                "Kontext",
+	"Context: @%s":                                           "Kontext: @%s",
+	"Contexts":                                               "Kontexte",
+	"Copied %d lists as Markdown":                            "%d Listen als Markdown kopiert",
+	"Copied %s as Markdown":                                  "%s als Markdown kopiert",
+	"Copied link to todo":                                    "Link zum Todo kopiert",
+	"Copied todo to clipboard":                               "Todo in die Zwischenablage kopiert",
+	"Copy %d lists as Markdown?":                             "%d Listen als Markdown kopieren?",
+	"Copy link to todo":                                      "Link zum Todo kopieren",
+	"Copy list as Markdown":                                  "Liste als Markdown kopieren",
+	"copy marked":                                            "markierte kopieren",
+	"Copy todo title":                                        "Todo-Titel kopieren",
+	"copy todo/list":                                         "Todo/Liste kopieren",
+	"Couldn't import %s: %v":                                 "%s konnte nicht importiert werden: %v",
+	"Couldn't undo an unfinished change: %v":                 "Eine unvollendete Änderung ließ sich nicht rückgängig machen: %v",
+	"create":                                                 "erstellen",
+	"Created %s from %s":                                     "%s aus %s erstellt",
+	"Created from template":                                  "Aus Vorlage erstellt",
+	"Created: %s":                                            "Erstellt: %s",
+	"Date archived file names":                               "Archivierte Dateien mit Datum benennen",
+	"Debug log (with --debug)":                               "Debug-Log (mit --debug)",
+	"delete":                                                 "löschen",
+	"Delete %d todos removed in the editor?":                 "%d im Editor entfernte Todos löschen?",
+	"Delete %d todos removed in the editor? (y/n)": "%d im Editor entfernte Todos löschen? (y/n)",
+	"Delete Confirmation":                          "Löschen bestätigen",
+	"Delete file / todo":                           "Datei / Todo löschen",
+	"delete marked":                                "markierte löschen",
+	"Delete this file? (y/n)":                      "Diese Datei löschen? (y/n)",
+	"Deleted %d files":                             "%d Dateien gelöscht",
+	"Deleted todo":                                 "Todo gelöscht",
+	"Deleted: %d todos":                            "Gelöscht: %d Todos",
+	"Deleting %d files":                            "%d Dateien werden gelöscht",
+	"Deleting %s":                                  "%s wird gelöscht",
+	" Discard edit":                                " Bearbeitung verwerfen",
+	"discard edit":                                 "Änderungen verwerfen",
+	"Done":                                         "Erledigt",
+	"Due":                                          "Fällig",
+	"due":                                          "fällig",
+	"Due %s":                                       "Fällig %s",
+	"due date":                                     "Fälligkeit",
+	"Due date cleared":                             "Fälligkeitsdatum entfernt",
+	"Due date: today, tomorrow, YYYY-MM-DD [HH:MM] (empty clears)": "Fällig: today, tomorrow, YYYY-MM-DD [HH:MM] (leer entfernt)",
+	"due time":   "Uhrzeit",
+	"Due today":  "Heute fällig",
+	"edit":       "bearbeiten",
+	" Edit name": " Namen bearbeiten",
+	"edit name":  "Namen bearbeiten",
+	"Edit todo":  "Todo bearbeiten",
+	"Editing todo #%d (Enter to save, Esc to cancel)": "Bearbeite Todo #%d (Enter speichert, Esc bricht ab)",
+	"Editor failed: %v":                  "Editor fehlgeschlagen: %v",
+	"Enter filename (without .json)":     "Dateiname eingeben (ohne .json)",
+	"Enter: %s":                          "Enter: %s",
+	"Escalated %d neglected todos in %s": "%d vernachlässigte Todos in %s eskaliert",
+	"every %ds":                          "alle %d s",
+	"Fetching %s…":                       "Lade %s…",
+	"Fetching Jira issue":                "Lade Jira-Vorgang",
+	"Field":                              "Feld",
+	"field":                              "Feld",
+	"field %s":                           "Feld %s",
+	"Field filter cleared":               "Feldfilter entfernt",
+	"Field: key=value (key= removes it)": "Feld: key=value (key= entfernt es)",
+	"Fields":                             "Felder",
+	"fields":                             "Felder",
+	"File archived!":                     "Datei archiviert!",
+	"File deleted!":                      "Datei gelöscht!",
+	"File panel width: %d%%":             "Breite der Dateiliste: %d%%",
+	"Files: %d":                          "Dateien: %d",
+	"Filter %s":                          "Filter %s",
+	"Filter %s: %d todos, Enter opens one in its list": "Filter %s: %d Todos, Enter öffnet eins in seiner Liste",
+	"Filter %s: %v":   "Filter %s: %v",
+	"Filter by field": "Nach Feld filtern",
+	"Filter by field: key or key=value (empty clears)": "Nach Feld filtern: key oder key=value (leer entfernt)",
+	"filter field":                                   "Feld filtern",
+	"Filter name":                                    "Filtername",
+	"Filter the list before splitting":               "Vor dem Aufteilen die Liste filtern",
+	"Filter with key or key=value":                   "Mit key oder key=value filtern",
+	"Filters cleared":                                "Filter entfernt",
+	"Find a list to open by typing part of its name": "Liste zum Öffnen finden: Teil des Namens tippen",
+	"first list":                                     "erste Liste",
+	"flag":                                           "markieren",
+	"Flag as priority":                               "Als wichtig markieren",
+	"Flagged todo":                                   "Todo markiert",
+	"focus":                                          "Fokus",
+	"focus mode":                                     "Fokusmodus",
+	"Focus mode off":                                 "Fokusmodus aus",
+	"Focus mode: flagged and due-today todos only": "Fokusmodus: nur markierte und heute fällige Todos",
+	"Fold / unfold section":                        "Abschnitt ein-/ausklappen",
+	"Follow link":                                  "Link folgen",
+	"follow link":                                  "Link folgen",
+	"for more":                                     "für mehr",
+	"Go to file panel":                             "Zur Dateiliste",
+	"Go to line":                                   "Gehe zu Zeile",
+	"Go to line (count) or last":                   "Zu Zeile (Anzahl) oder ans Ende",
+	"Go to line number":                            "Zu Zeilennummer springen",
+	"Go to todo panel":                             "Zur Todo-Liste",
+	"habit list":                                   "Gewohnheitsliste",
+	"Habit list: checkmarks reset every day":       "Gewohnheitsliste: Häkchen werden täglich zurückgesetzt",
+	"habits":                                       "Gewohnheiten",
+	"Habits are done or not, without progress":     "Gewohnheiten sind erledigt oder nicht, ohne Fortschritt",
+	"heading":                                      "Überschrift",
+	"Hidden file, shown read-only":                 "Versteckte Datei, nur lesbar angezeigt",
+	"Hidden files are hidden again":                "Versteckte Dateien sind wieder ausgeblendet",
+	"Hide todos until their start date":            "Todos bis zu ihrem Startdatum ausblenden",
+	"Import Jira issue":                            "Jira-Vorgang importieren",
+	"Imported %s":                                  "%s importiert",
+	"Indexing lists":                               "Listen werden indiziert",
+	"Input":                                        "Eingabe",
+	"Input truncated to %d characters":             "Eingabe auf %d Zeichen gekürzt",
+	"Invalid name: %v":                             "Ungültiger Name: %v",
+	"Invalid pattern: %v":                          "Ungültiges Muster: %v",
+	"Invalid query: %v":                            "Ungültige Suche: %v",
+	"Jira issue":                                   "Jira-Vorgang",
+	"Jira issue to import, like PROJ-123":          "Zu importierender Jira-Vorgang, z. B. PROJ-123",
+	"Jump back to the previous file":               "Zurück zur vorherigen Datei",
+	"jump to todo":                                 "zum Todo springen",
+	"keep":                                         "behalten",
+	"keep both":                                    "beide behalten",
+	"keep local":                                   "lokal behalten",
+	"keep remote":                                  "Server behalten",
+	"keep their place":                             "bleiben an ihrem Platz",
+	"Keybindings":                                  "Tastenbelegung",
+	"last opened list":                             "zuletzt geöffnete Liste",
+	"like all lists":                               "wie alle Listen",
+	"Line numbers":                                 "Zeilennummern",
+	"list":                                         "Liste",
+	"list order":                                   "Listenreihenfolge",
+	"List settings":                                "Listeneinstellungen",
+	"List settings saved":                          "Listeneinstellungen gespeichert",
+	"List title":                                   "Listentitel",
+	"List: %s":                                     "Liste: %s",
+	"loading":                                      "lädt",
+	"Loading %s…":                                  "Lade %s…",
+	"Loading...":                                   "Lade...",
+	"Local · %s":                                   "Lokal · %s",
+	"Lower progress":                               "Fortschritt verringern",
+	"Lowercase new list names":                     "Namen neuer Listen kleinschreiben",
+	"mark":                                         "markieren",
+	"Mark at least two files to merge":             "Zum Zusammenführen mindestens zwei Dateien markieren",
+	"Mark file / toggle todo":                      "Datei markieren / Todo umschalten",
+	"Marked as section heading":                    "Als Abschnittsüberschrift markiert",
+	"Marks cleared":                                "Markierungen aufgehoben",
+	"Max title length":                             "Maximale Titellänge",
+	"Maximize todo panel":                          "Todo-Liste maximieren",
+	"Merge %d files into %s?":                      "%d Dateien in %s zusammenführen?",
+	"merge marked":                                 "markierte zusammenführen",
+	"Merge marked files":                           "Markierte Dateien zusammenführen",
+	"Merge sync conflicts":                         "Sync-Konflikte zusammenführen",
+	"Merged":                                       "Zusammengeführt",
+	"Merged %d files into %s":                      "%d Dateien in %s zusammengeführt",
+	"Merged %s, it goes to the server on the next sync": "%s zusammengeführt, geht beim nächsten Sync an den Server",
+	"Merging %d files":                      "%d Dateien werden zusammengeführt",
+	"Merging %s: %d todos differ":           "Zusammenführen von %s: %d Todos weichen ab",
+	"move across":                           "hinüber verschieben",
+	"Move completed todos to the bottom":    "Erledigte Todos nach unten verschieben",
+	"Move down":                             "Nach unten",
+	"Move scratchpad todo to the open file": "Todo vom Notizzettel in die offene Datei verschieben",
+	"move section":                          "Abschnitt wechseln",
+	"Move the %d shown todos to new file (without .json)": "Die %d angezeigten Todos in neue Datei verschieben (ohne .json)",
+	"Move to next section":                                "In nächsten Abschnitt verschieben",
+	"Move to previous section":                            "In vorherigen Abschnitt verschieben",
+	"move to the bottom":                                  "wandern nach unten",
+	"Move up":                                             "Nach oben",
+	"Moved %d todos to %s":                                "%d Todos nach %s verschoben",
+	"Moved to %s":                                         "Nach %s verschoben",
+	"Moved to section":                                    "In Abschnitt verschoben",
+	"navigate":                                            "navigieren",
+	"new":                                                 "neu",
+	"New file from template":                              "Neue Datei aus Vorlage",
+	"New list from template":                              "Neue Liste aus Vorlage",
+	"newest first":                                        "neueste zuerst",
+	"no":                                                  "nein",
+	"No @contexts found in any list":                      "Keine @Kontexte in den Listen gefunden",
+	"No check todos in this list, set a command with c": "Keine Prüf-Todos in dieser Liste, Befehl mit c setzen",
+	"No contexts, fields or flags":                      "Keine Kontexte, Felder oder Markierungen",
+	"No debug log, start with --debug to record one":    "Kein Debug-Log, mit --debug starten, um eines aufzuzeichnen",
+	"No filters to clear":                               "Keine Filter aktiv",
+	"no limit":                                          "keine Grenze",
+	"No line %d":                                        "Keine Zeile %d",
+	"No link in this todo":                              "Kein Link in diesem Todo",
+	"No list matches %q":                                "Keine Liste passt zu %q",
+	"No list named %s":                                  "Keine Liste namens %s",
+	"   No matching list":                               "   Keine passende Liste",
+	"No other file opened yet":                          "Noch keine andere Datei geöffnet",
+	"No other list to compare with":                     "Keine andere Liste zum Vergleichen",
+	"No sync conflicts":                                 "Keine Sync-Konflikte",
+	"No templates in %s":                                "Keine Vorlagen in %s",
+	"No todo #%d in %s":                                 "Kein Todo #%d in %s",
+	"No todo %d in %s":                                  "Kein Todo %d in %s",
+	"No todos yet":                                      "Noch keine Todos",
+	" No, cancel":                                       " Nein, abbrechen",
+	" No, keep them":                                    " Nein, behalten",
+	"none":                                              "keine",
+	"Normal list":                                       "Normale Liste",
+	"Not a line number: %s":                             "Keine Zeilennummer: %s",
+	"Not a todo list, the file is shown read-only":      "Keine Todo-Liste, die Datei wird nur angezeigt",
+	"Not an issue key: %s":                              "Kein Vorgangsschlüssel: %s",
+	"Not in a section":                                  "Nicht in einem Abschnitt",
+	"Not reloaded, fix the file and press e again: %v":  "Nicht neu geladen, Datei korrigieren und erneut e drücken: %v",
+	"Not shrinking at the current pace":                 "Beim aktuellen Tempo wird die Liste nicht kürzer",
+	"Nothing changes":                                   "Nichts ändert sich",
+	"Nothing due today":                                 "Heute nichts fällig",
+	"Nothing left to do":                                "Nichts mehr zu tun",
+	"Nothing matches the filters":                       "Nichts passt zu den Filtern",
+	"Nothing merged: %v":                                "Nichts zusammengeführt: %v",
+	"off":                                               "aus",
+	"Offer to archive completed lists":                  "Archivieren erledigter Listen anbieten",
+	"oldest / latest":                                   "älteste / neueste",
+	"on":                                                "an",
+	"on every change":                                   "bei jeder Änderung",
+	"Only the order differs":                            "Nur die Reihenfolge weicht ab",
+	"Only todo lists can be marked":                     "Nur Todo-Listen können markiert werden",
+	"Only todos in an open list have links":             "Nur Todos in einer offenen Liste haben Links",
+	"Open":                                              "Offen",
+	"open":                                              "öffnen",
+	"Open / unarchive file":                             "Datei öffnen / wiederherstellen",
+	"Open at startup":                                   "Beim Start öffnen",
+	"Open file / toggle todo":                           "Datei öffnen / Todo abhaken",
+	"Open in $EDITOR":                                   "In $EDITOR öffnen",
+	"Opened: %s":                                        "Geöffnet: %s",
+	"other list":                                        "andere Liste",
+	"Overdue":                                           "Überfällig",
+	" Overwrite":                                        " Überschreiben",
+	"overwrite":                                         "überschreiben",
+	"Pace: %.1f todos closed a day":                     "Tempo: %.1f Todos pro Tag abgeschlossen",
+	"Permanently delete %d files?":                      "%d Dateien endgültig löschen?",
+	"Permanently delete this file?":                     "Diese Datei endgültig löschen?",
+	"pick a list":                                       "Liste auswählen",
+	"  Press '@' to switch context":                     "  '@' drücken, um den Kontext zu wechseln",
+	"  Press 'a' to add one":                            "  'a' drücken, um eins hinzuzufügen",
+	"  Press 'C' to clear them":                         "  Drücke 'C', um sie zu löschen",
+	"  Press 'F' to leave focus mode":                   "  'F' drücken, um den Fokusmodus zu verlassen",
+	"Press new key for %s (Esc to cancel)":              "Neue Taste für %s drücken (Esc bricht ab)",
+	" Preview":                                          " Vorschau",
+	"preview":                                           "Vorschau",
+	"Priority":                                          "Priorität",
+	"Progress: %d%%":                                    "Fortschritt: %d%%",
+	"promote":                                           "übernehmen",
+	"Quit":                                              "Beenden",
+	"quit":                                              "beenden",
+	" Quit anyway":                                      " Trotzdem beenden",
+	"quit anyway":                                       "trotzdem beenden",
+	"Quit without saving these?":                        "Ohne diese zu speichern beenden?",
+	"Raise progress":                                    "Fortschritt erhöhen",
+	"Reading the archive":                               "Archiv wird gelesen",
+	"rebind":                                            "neu belegen",
+	"Recent: %s":                                        "Zuletzt: %s",
+	"Regex search":                                      "Regex-Suche",
+	"Regex search: RE2 syntax, (?i) ignores case, ctrl+r for queries (empty clears)": "Regex-Suche: RE2-Syntax, (?i) ignoriert Groß-/Kleinschreibung, ctrl+r für Abfragen (leer löscht)",
+	"relative":      "relativ",
+	"reload":        "neu laden",
+	"Reloaded: %s":  "Neu geladen: %s",
+	"remind":        "erinnern",
+	"Remind before": "Erinnern vorher",
+	"Remind before due: e.g. 30m, 1h, 1d (empty clears)": "Erinnern vor Fälligkeit: z. B. 30m, 1h, 1d (leer entfernt)",
+	"Reminder cleared":                      "Erinnerung entfernt",
+	"Reminder set %s before due":            "Erinnerung %s vor Fälligkeit gesetzt",
+	"Reminders acknowledged":                "Erinnerungen bestätigt",
+	"Remote":                                "Server",
+	"Removed %s":                            "%s entfernt",
+	"Removed filter %s":                     "Filter %s entfernt",
+	"rename":                                "umbenennen",
+	" Rename to %s":                         " Umbenennen in %s",
+	"Restore as":                            "Wiederherstellen als",
+	"Restoring %s":                          "%s wird wiederhergestellt",
+	" Retry":                                " Erneut versuchen",
+	"retry":                                 "erneut versuchen",
+	"Run %d commands from this list? (y/n)": "%d Befehle aus dieser Liste ausführen? (y/n)",
+	" Run and trust":                        " Ausführen und vertrauen",
+	"Run check commands":                    "Prüfbefehle ausführen",
+	"Run check commands?":                   "Prüfbefehle ausführen?",
+	"Running %d checks…":                    "%d Prüfungen laufen…",
+	"Running checks":                        "Prüfungen laufen",
+	"Safe mode: nothing is written until you change something": "Abgesicherter Modus: nichts wird geschrieben, bis du etwas änderst",
+	"save":                    "speichern",
+	"Save %s as a filter":     "%s als Filter speichern",
+	"Save search as a filter": "Suche als Filter speichern",
+	"Saved":                   "Gespeichert",
+	"Saved filter %s":         "Filter %s gespeichert",
+	"Saved filters are read-only, Enter opens the todo in its list": "Gespeicherte Filter sind schreibgeschützt, Enter öffnet das Todo in seiner Liste",
+	"Saving %s failed: %v":                        "Speichern von %s fehlgeschlagen: %v",
+	"Scanning for todos due today...":             "Suche heute fällige Todos...",
+	"scratchpad":                                  "Notizzettel",
+	"Scratchpad: not saved, p moves a todo to %s": "Notizzettel: wird nicht gespeichert, p verschiebt ein Todo nach %s",
+	"scroll":         "blättern",
+	"Search":         "Suche",
+	"search %s":      "Suche %s",
+	"Search cleared": "Suche gelöscht",
+	"Search first (/), then save it as a filter": "Erst suchen (/), dann als Filter speichern",
+	"Search the open list":                       "Offene Liste durchsuchen",
+	"Search: words, is:open, tag:home, due:<7d, key:value, OR, NOT, ctrl+r for regex (empty clears)": "Suche: Wörter, is:open, tag:home, due:<7d, key:value, OR, NOT, ctrl+r für Regex (leer löscht)",
+	"Section %s, %d of %d done": "Abschnitt %s, %d von %d erledigt",
+	"select":                    "auswählen",
+	"select week":               "Woche wählen",
+	"Selected: %s":              "Ausgewählt: %s",
+	"Set %s=%s":                 "%s=%s gesetzt",
+	"Set a due date first (D)":  "Zuerst ein Fälligkeitsdatum setzen (D)",
+	"Set check command":         "Prüfbefehl setzen",
+	"Set custom field":          "Eigenes Feld setzen",
+	"Set due date":              "Fälligkeitsdatum setzen",
+	"Set jira.url and jira.token in the config to import issues": "Setze jira.url und jira.token in der Konfiguration, um Vorgänge zu importieren",
+	"Set list title":                             "Listentitel festlegen",
+	"Set reminder":                               "Erinnerung setzen",
+	"Set start date (defer until)":               "Startdatum setzen (zurückstellen bis)",
+	"Set when a list is created from a template": "Wird gesetzt, wenn eine Liste aus einer Vorlage erstellt wird",
+	" Settings":                                  " Einstellungen",
+	"Settings":                                   "Einstellungen",
+	" Settings of %s":                            " Einstellungen von %s",
+	"Settings of %s":                             "Einstellungen von %s",
+	"Settings saved":                             "Einstellungen gespeichert",
+	"show active":                                "aktive zeigen",
+	"Show archived files":                        "Archivierte Dateien anzeigen",
+	"Show hidden files":                          "Versteckte Dateien anzeigen",
+	"Show title bar":                             "Titelleiste anzeigen",
+	"show todos":                                 "Todos zeigen",
+	"Showing %s as %s":                           "%s wird als %s angezeigt",
+	"Showing %s by its filename":                 "%s wird mit Dateinamen angezeigt",
+	"Showing active files":                       "Zeige aktive Dateien",
+	"Showing all contexts":                       "Zeige alle Kontexte",
+	"Showing archived files":                     "Zeige archivierte Dateien",
+	"Showing hidden files, read-only":            "Versteckte Dateien werden angezeigt, nur lesbar",
+	"Showing matches for %s":                     "Treffer für %s",
+	"Showing todos with %s":                      "Zeige Todos mit %s",
+	"  Shown read-only, justdoit won't write to this file": "  Nur lesend angezeigt, justdoit schreibt nicht in diese Datei",
+	"Shrink file panel": "Dateiliste verkleinern",
+	"Some changes couldn't be saved: r retries, q quits anyway, Esc stays": "Einige Änderungen konnten nicht gespeichert werden: r versucht es erneut, q beendet trotzdem, Esc bleibt",
+	"Sort and columns are kept for the Today view and saved filters":       "Sortierung und Spalten gibt es für die Heute-Ansicht und gespeicherte Filter",
+	"Sort Today view / saved filter":                                       "Heute-Ansicht / gespeicherten Filter sortieren",
+	"Sort todos":                                                           "Aufgaben sortieren",
+	"sort view":                                                            "Ansicht sortieren",
+	"Sorted by %s":                                                         "Sortiert nach %s",
+	"Sound command failed: %v":                                             "Tonbefehl fehlgeschlagen: %v",
+	"Sound on completed lists":                                             "Ton bei erledigten Listen",
+	"Sound on completed todos":                                             "Ton bei erledigten Aufgaben",
+	"split":                                                                "abspalten",
+	"Split filtered todos":                                                 "Gefilterte Todos abspalten",
+	"Start":                                                                "Start",
+	"Start date cleared":                                                   "Startdatum entfernt",
+	"Start date: today, tomorrow, YYYY-MM-DD [HH:MM] (empty clears)": "Startdatum: today, tomorrow, YYYY-MM-DD [HH:MM] (leer entfernt)",
+	"Starts %s":                             "Beginnt %s",
+	"Stats and forecast":                    "Statistik und Prognose",
+	"Stats: %s":                             "Statistik: %s",
+	" Stay":                                 " Bleiben",
+	"stay":                                  "bleiben",
+	"Still loading, please wait":            "Wird noch geladen, bitte warten",
+	"Still moving files, please wait":       "Dateien werden noch verschoben, bitte warten",
+	"Still not saved: %v":                   "Immer noch nicht gespeichert: %v",
+	"suggestions":                           "Vorschläge",
+	"switch":                                "wechseln",
+	"Switch context":                        "Kontext wechseln",
+	"Switch panel":                          "Bereich wechseln",
+	"Sync failed, using the local copy: %v": "Sync fehlgeschlagen, die lokale Kopie wird verwendet: %v",
+	"table":                                 "Tabelle",
+	"Tags":                                  "Tags",
+	"Tags added to new todos, like @home @errands (empty clears)": "Tags für neue Aufgaben, z. B. @zuhause @besorgungen (leer löscht)",
+	"Tags for new todos": "Tags für neue Aufgaben",
+	"template":           "Vorlage",
+	"The archive already has a file by this name": "Im Archiv gibt es schon eine Datei mit diesem Namen",
+	"The log is empty":                                 "Das Log ist leer",
+	"The scratchpad has no file to edit":               "Der Notizzettel hat keine Datei zum Bearbeiten",
+	"The scratchpad has no settings, it isn't saved":   "Der Notizblock hat keine Einstellungen, er wird nicht gespeichert",
+	"These run in your shell. Trust them from now on?": "Sie laufen in deiner Shell. Ab jetzt vertrauen?",
+	"Title": "Titel",
+	"title": "Titel",
+	"Title for %s (empty shows the filename)": "Titel für %s (leer zeigt den Dateinamen)",
+	"Today":                  "Heute",
+	"today":                  "heute",
+	"Today scan failed: %v":  "Suche nach heute Fälligem fehlgeschlagen: %v",
+	"Today view":             "Heute-Ansicht",
+	"Todo layout":            "Aufgabenansicht",
+	"toggle":                 "abhaken",
+	"Toggle focus mode":      "Fokusmodus umschalten",
+	"Toggle habit list":      "Gewohnheitsliste umschalten",
+	"Toggle scratchpad":      "Notizzettel umschalten",
+	"Toggle section heading": "Abschnittsüberschrift umschalten",
+	"Toggle Today view / saved filter columns": "Spalten der Heute-Ansicht / des Filters umschalten",
+	"Toggle todo":                                      "Todo abhaken",
+	"Toggle zen mode":                                  "Zen-Modus umschalten",
+	"Toggled section":                                  "Abschnitt umgeschaltet",
+	"Toggled todo status":                              "Todo-Status umgeschaltet",
+	"Toggled: %s":                                      "Abgehakt: %s",
+	"Trusting the command failed: %v":                  "Befehl konnte nicht als vertrauenswürdig gespeichert werden: %v",
+	"Trusting the commands failed: %v":                 "Befehle konnten nicht als vertrauenswürdig gespeichert werden: %v",
+	"Type a free name (Enter to save, Esc to go back)": "Freien Namen eingeben (Enter speichert, Esc geht zurück)",
+	"unarchive":                                        "wiederherstellen",
+	"Unarchived: %s":                                   "Wiederhergestellt: %s",
+	"Unflagged todo":                                   "Markierung entfernt",
+	"unmark":                                           "Markierungen aufheben",
+	"Unmarked section heading":                         "Abschnittsüberschrift entfernt",
+	"Unsaved changes":                                  "Ungespeicherte Änderungen",
+	"Updated: %d todos":                                "Geändert: %d Todos",
+	"Value for {{%s}} (%d/%d)":                         "Wert für {{%s}} (%d/%d)",
+	"Week of %s: %d completed":                         "Woche ab %s: %d erledigt",
+	"Widen file panel":                                 "Dateiliste verbreitern",
+	"Window too small":                                 "Fenster zu klein",
+	"write merge":                                      "Ergebnis schreiben",
+	" Yes":                                             " Ja",
+	"yes":                                              "ja",
+	" Yes, archive":                                    " Ja, archivieren",
+	" Yes, delete":                                     " Ja, löschen",
+	" Yes, merge":                                      " Ja, zusammenführen",
+	"Zen mode: Z or Esc to leave":                      "Zen-Modus: Z oder Esc zum Verlassen",
+	"  ·  %s to merge":                                 "  ·  %s zum Zusammenführen",
+	"  ·  R to acknowledge":                            "  ·  R zum Bestätigen",
+	"  ·  T for Today view":                            "  ·  T für die Heute-Ansicht",
+	"↓ %d more":                                        "↓ %d weitere",
+	"↻ habits":                                         "↻ Gewohnheiten",
+	"⇅ %s":                                             "⇅ %s",
+	"⇅ manual order":                                   "⇅ manuelle Reihenfolge",
+	"  ⊘  Not a todo list":                             "  ⊘  Keine Todo-Liste",
+	"  ─── archived ───":                               "  ─── archiviert ───",
+	"  ─── filters ───":                                "  ─── Filter ───",
+	"  ─── recent ───":                                 "  ─── zuletzt ───",
+	"◎ focus":                                          "◎ Fokus",
+	"● unsaved":                                        "● ungespeichert",
+	"● unsaved changes":                                "● ungespeicherte Änderungen",
+	"⚠ not saved":                                      "⚠ nicht gespeichert",
+	"✓ saved":                                          "✓ gespeichert",
+	" Compare %s with":                                " %s vergleichen mit",
+	"󰂚 %s (%s, due %s)":                                "󰂚 %s (%s, fällig %s)",
+	"󰃰 %d due today":                                   "󰃰 %d heute fällig",
+	"󰃰 Today":                                          "󰃰 Heute",
+	"󰄨 Stats: %s":                                      "󰄨 Statistik: %s",
+	"  󰄱  No todos in @%s":                             "  󰄱  Keine Todos in @%s",
+	"  󰄱  Nothing flagged or due today":                "  󰄱  Nichts markiert oder heute fällig",
+	"  󰄱  Nothing matches the filters":                 "  󰄱  Nichts passt zu den Filtern",
+	"  󰄱  Nothing matches this filter":                 "  󰄱  Nichts passt zu diesem Filter",
+	"󰈅 Name already taken":                             "󰈅 Name bereits vergeben",
+	"󰈈 Preview":                                        "󰈈 Vorschau",
+	"󰈔 Open List":                                      "󰈔 Liste öffnen",
+	"󰈙 New From Template":                              "󰈙 Neu aus Vorlage",
+	"󰌌 Keybindings":                                    "󰌌 Tastenbelegung",
+	"󰌱 Debug log":                                      "󰌱 Debug-Log",
+	"󰒆 Marked files":                                   "󰒆 Markierte Dateien",
+	"󰔟 %d deferred hidden":                             "󰔟 %d zurückgestellte ausgeblendet",
+	"󰕚 %d sync conflicts":                              "󰕚 %d Sync-Konflikte",
+}
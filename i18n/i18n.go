@@ -0,0 +1,80 @@
+// Package i18n translates UI strings.
+//
+// English strings are the message keys: UI code wraps them in T or Tf and a
+// catalog maps them to another language. Missing entries fall back to English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// catalogs maps language codes to their translations
+var catalogs = map[string]map[string]string{
+	"de": german,
+}
+
+// current is the selected catalog, nil for English
+var current map[string]string
+
+// Languages returns the supported language codes, English first
+func Languages() []string {
+	langs := []string{"en"}
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	return langs
+}
+
+// Set selects the UI language. An empty lang is taken from the LC_ALL,
+// LC_MESSAGES or LANG environment variables. Unsupported languages fall back
+// to English. Returns the language in use.
+func Set(lang string) string {
+	if lang == "" {
+		lang = envLanguage()
+	}
+	lang = normalize(lang)
+	current = catalogs[lang]
+	if current == nil {
+		return "en"
+	}
+	return lang
+}
+
+// envLanguage returns the language configured in the environment
+func envLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalize reduces a locale like de_DE.UTF-8 to its language code
+func normalize(locale string) string {
+	lang, _, _ := strings.Cut(locale, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang, _, _ = strings.Cut(lang, "-")
+	return strings.ToLower(lang)
+}
+
+// Lookup returns the translation of s in lang, if the catalog has one
+func Lookup(lang, s string) (string, bool) {
+	t, ok := catalogs[lang][s]
+	return t, ok
+}
+
+// T returns the translation of an English UI string
+func T(s string) string {
+	if t, ok := current[s]; ok {
+		return t
+	}
+	return s
+}
+
+// Tf formats the translation of an English format string
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
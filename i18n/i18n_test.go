@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// TestSet tests choosing a language from config or the environment
+func TestSet(t *testing.T) {
+	defer Set("en")
+
+	tests := []struct {
+		name, lang, env, want string
+	}{
+		{"config", "de", "", "de"},
+		{"locale", "de_DE.UTF-8", "", "de"},
+		{"environment", "", "de_AT.UTF-8", "de"},
+		{"unsupported", "fr", "", "en"},
+		{"nothing set", "", "", "en"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.env)
+		if got := Set(tt.lang); got != tt.want {
+			t.Errorf("%s: Set(%q) = %q, want %q", tt.name, tt.lang, got, tt.want)
+		}
+	}
+}
+
+// TestTranslate tests lookups and the English fallback
+func TestTranslate(t *testing.T) {
+	defer Set("en")
+
+	Set("de")
+	if got := T("Saved"); got != "Gespeichert" {
+		t.Errorf("T(Saved) = %q, want Gespeichert", got)
+	}
+	if got := Tf("Moved %d todos to %s", 2, "work.json"); got != "2 Todos nach work.json verschoben" {
+		t.Errorf("Tf = %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("Expected missing strings to stay English, got %q", got)
+	}
+
+	Set("en")
+	if got := T("Saved"); got != "Saved" {
+		t.Errorf("Expected English after switching back, got %q", got)
+	}
+}
+
+// verbPattern matches fmt verbs in format strings
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogVerbs tests that translations keep the format verbs of their English key
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for en, tr := range catalog {
+			want := verbPattern.FindAllString(en, -1)
+			if got := verbPattern.FindAllString(tr, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", lang, tr, got, want)
+			}
+		}
+	}
+}
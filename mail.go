@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// runIngestMail turns email messages into todos, from stdin, a file or a Maildir
+func runIngestMail(args []string) error {
+	fs := flag.NewFlagSet("ingest-mail", flag.ContinueOnError)
+	list := fs.String("list", "inbox", "List to add the todos to (created if missing)")
+	file := fs.String("file", "", "Read the message from this file instead of stdin")
+	maildir := fs.String("maildir", "", "Ingest every new message of this Maildir and mark it read")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file != "" && *maildir != "" {
+		return fmt.Errorf("use either --file or --maildir")
+	}
+
+	cfg, _ := config.Load(config.Path())
+	name := todo.NormalizeListName(*list, cfg.Behavior.LowercaseNames)
+	if err := todo.ValidateListName(name); err != nil {
+		return fmt.Errorf("can't use list %q: %v", *list, err)
+	}
+	*list = name
+
+	todoDir, _ := dataDirs()
+	os.MkdirAll(todoDir, 0755)
+	tl := todo.NewTodoList(filepath.Join(todoDir, listFilename(*list)))
+	tl.SetDeferredSave(true) // written once per message, reporting a failure
+	tl.SetAuthor(cfg.Author)
+
+	if *maildir != "" {
+		n, err := ingestMaildir(tl, *maildir)
+		fmt.Printf("Added %d todos to %s\n", n, listFilename(*list))
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	m, err := todo.ParseMail(in)
+	if err != nil {
+		return err
+	}
+	tl.AddMail(m)
+	if err := tl.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Added %q to %s\n", tl.Todos[0].Title, listFilename(*list))
+	return nil
+}
+
+// ingestMaildir adds a todo for each message in dir/new, then moves the message
+// to dir/cur flagged as seen so the next run skips it. Unreadable messages stay
+// in new and the first problem is returned.
+func ingestMaildir(tl *todo.TodoList, dir string) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		return 0, fmt.Errorf("not a Maildir: %w", err)
+	}
+
+	added := 0
+	var firstErr error
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		src := filepath.Join(dir, "new", entry.Name())
+		f, err := os.Open(src)
+		if err != nil {
+			return added, err
+		}
+		m, err := todo.ParseMail(f)
+		f.Close()
+		if err != nil {
+			// leave it in new for a look, the rest can still be ingested
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", entry.Name(), err)
+			}
+			continue
+		}
+
+		tl.AddMail(m)
+		if err := tl.Flush(); err != nil { // before the message moves out of new
+			return added, err
+		}
+		added++
+		if err := os.Rename(src, filepath.Join(dir, "cur", entry.Name()+":2,S")); err != nil {
+			return added, err
+		}
+	}
+	return added, firstErr
+}
@@ -1,34 +1,138 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // timezones named in the config work without a system database
 
 	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/remote"
+	"justdoit/search"
 	"justdoit/todo"
 	"justdoit/ui"
 )
 
-// initialModel creates and initializes the application model
-func initialModel() ui.Model {
+// maxFPS caps how often the screen is redrawn; the spinner and confetti need no more
+const maxFPS = 30
+
+// dataDirs returns the todo and archive directories
+func dataDirs() (string, string) {
 	homeDir, _ := os.UserHomeDir()
 	todoDir := filepath.Join(homeDir, ".tui_todos")
 	archiveDir := filepath.Join(homeDir, ".tui_todos", "archive")
+	return todoDir, archiveDir
+}
+
+// recoverChanges puts back the files of a change to several lists that the last run
+// died in the middle of, returning how many it put back
+func recoverChanges() (int, error) {
+	todoDir, archiveDir := dataDirs()
+	total := 0
+	var errs []error
+	for _, dir := range []string{todoDir, archiveDir} {
+		n, err := todo.Recover(dir)
+		total += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// recoveredMessage describes what recoverChanges did, "" when there was nothing to do
+func recoveredMessage(n int, err error) string {
+	if err != nil {
+		return i18n.Tf("Couldn't undo an unfinished change: %v", err)
+	}
+	if n > 0 {
+		return i18n.Tf("An unfinished change was undone, %d files put back", n)
+	}
+	return ""
+}
+
+// initialModel creates and initializes the application model with start open. In safe
+// mode nothing is written at startup: no data directories, no sync, no escalations and
+// no search index cache.
+func initialModel(notify, accessible, safe bool, start startList) ui.Model {
+	todoDir, archiveDir := dataDirs()
+	templateDir := filepath.Join(todoDir, "templates")
 
 	// Create directories if they don't exist
-	os.MkdirAll(todoDir, 0755)
-	os.MkdirAll(archiveDir, 0755)
+	if !safe {
+		os.MkdirAll(todoDir, 0755)
+		os.MkdirAll(archiveDir, 0755)
+		os.MkdirAll(templateDir, 0755)
+	}
+
+	// Load user config; problems are shown as toasts, each of them
+	var toasts []ui.Toast
+	note := func(s ui.Severity, text string) {
+		if text != "" {
+			toasts = append(toasts, ui.Toast{Text: text, Severity: s})
+		}
+	}
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		note(ui.SeverityError, err.Error())
+	}
+	i18n.Set(cfg.Language)
+	if accessible {
+		cfg.Behavior.Accessible = true
+	}
+	ui.SetMonochrome(cfg.Behavior.Accessible)
+	todo.SetSafe(safe)
+	highlights, err := ui.CompileHighlights(cfg.Highlights)
+	if err != nil {
+		note(ui.SeverityError, err.Error())
+	}
+	keys, err := ui.NewKeymap(cfg.Keys)
+	if err != nil {
+		note(ui.SeverityError, err.Error())
+	}
+	if safe {
+		note(ui.SeverityInfo, i18n.T("Safe mode: nothing is written until you change something"))
+	} else {
+		if summary, err := syncRemote(cfg); err != nil {
+			note(ui.SeverityWarning, i18n.Tf("Sync failed, using the local copy: %v", err))
+		} else {
+			note(ui.SeverityInfo, summary)
+		}
+		escalations, err := ui.CompileEscalations(cfg.Escalations)
+		if err != nil {
+			note(ui.SeverityError, err.Error())
+		}
+		note(ui.SeverityInfo, ui.EscalateLists(todoDir, escalations))
+	}
 
 	// Load list of todo files
-	files := ui.LoadTodoFiles(todoDir)
-	archivedFiles := ui.LoadTodoFiles(archiveDir)
+	files, info := ui.ScanTodoFiles(todoDir)
 
 	var currentFile string
 	var todoList *todo.TodoList
+	recent := ui.LoadRecent(todoDir)
 
-	if len(files) > 0 {
+	if start.file != "" {
+		currentFile = start.file
+		todoList = todo.NewTodoList(filepath.Join(todoDir, currentFile))
+		if start.create {
+			todoList.Save()
+			files, info = ui.ScanTodoFiles(todoDir)
+		}
+	} else if len(files) > 0 {
+		// Unless set to the first, start on the list the last session ended on, which the picker opens over
 		currentFile = files[0]
+		if cfg.Behavior.StartupFile != config.StartupFirst && len(recent) > 0 && slices.Contains(files, recent[0]) {
+			currentFile = recent[0]
+		}
 		todoList = todo.NewTodoList(filepath.Join(todoDir, currentFile))
 	} else {
 		// Create default file if none exist
@@ -36,28 +140,192 @@ func initialModel() ui.Model {
 		todoList = todo.NewTodoList(filepath.Join(todoDir, currentFile))
 		files = []string{currentFile}
 	}
+	todoList.SetAutoSort(cfg.Behavior.SortCompleted)
+	todoList.SetMaxTitleLength(cfg.Behavior.MaxTitleLength)
+	todoList.SetAuthor(cfg.Author)
+	todoList.SetDeferredSave(true) // saved in the background by the TUI
 
-	return ui.Model{
+	m := ui.Model{
 		TodoList:       todoList,
 		ActivePanel:    ui.FilePanel,
-		FileCursor:     0,
+		FileCursor:     max(slices.Index(files, currentFile), 0),
 		TodoCursor:     0,
 		Mode:           ui.NormalMode,
-		EditingIndex:   -1,
 		Files:          files,
-		ArchivedFiles:  archivedFiles,
+		Foreign:        info.Foreign,
+		Titles:         info.Titles,
+		Colors:         info.Colors,
+		Recent:         recent,
 		TodoDir:        todoDir,
 		ArchiveDir:     archiveDir,
+		TemplateDir:    templateDir,
 		CurrentFile:    currentFile,
 		ShowingArchive: false,
 		Styles:         ui.NewStyles(),
+		DesktopNotify:  notify,
+		Highlights:     highlights,
+		TagIcons:       ui.CompileTagIcons(cfg.TagIcons),
+		Keys:           keys,
+		ConfigPath:     config.Path(),
+		IndexPath:      search.Path(),
+		Behavior:       cfg.Behavior,
+		Table:          cfg.Table,
+		Author:         cfg.Author,
+		Conflicts:      remote.Conflicts(todoDir),
+		Filters:        cfg.Filters,
+		Jira:           cfg.Jira,
+		TodayView:      cfg.Today,
+		Safe:           safe,
+		TrustedChecks:  cfg.Checks,
+	}
+	for _, t := range toasts {
+		m.Notify(t.Severity, t.Text)
+	}
+	start.place(&m)
+	if start.file == "" && start.todoID == 0 && cfg.Behavior.StartupFile == config.StartupPicker && len(files) > 1 {
+		m.OpenFilePicker()
+	}
+	return m
+}
+
+// setTimezone makes the timezone named in the config the local one, which dates are
+// shown in and days begin in. The system's is kept when none is named.
+func setTimezone() error {
+	cfg, _ := config.Load(config.Path())
+	if cfg.Timezone == "" {
+		return nil
 	}
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return fmt.Errorf("unknown timezone %q in the config, using the system's", cfg.Timezone)
+	}
+	time.Local = loc
+	return nil
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v", err)
+	defer recoverCrash()
+
+	notify := flag.Bool("notify", false, "Send desktop notifications when reminders fire")
+	accessible := flag.Bool("accessible", false, "Monochrome, line-oriented output for screen readers")
+	safe := flag.Bool("safe", false, "Write nothing at startup (no sync, escalations or index cache); only your changes are saved")
+	debug := flag.Bool("debug", false, "Write a structured log of keys, file operations, timings and errors to ~/.tui_todos/debug.log")
+	file := flag.String("file", "", "List to open, with or without .json (also given as e.g. work.json)")
+	create := flag.Bool("create", false, "Create the list to open if it doesn't exist yet")
+	todoID := flag.Int("todo", 0, "ID of the todo to put the cursor on")
+	flag.Parse()
+
+	// A list name ending in .json, or a justdoit:// link, opens that list; more flags may follow it
+	arg := flag.Arg(0)
+	link := strings.HasPrefix(arg, todo.URLScheme+"://")
+	if filepath.Ext(arg) == ".json" || link {
+		*file = arg
+		if link {
+			list, id, err := todo.ParseURL(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			*file, *todoID = list, id
+		}
+		flag.CommandLine.Parse(flag.Args()[1:])
+		if flag.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q after %s\n", flag.Arg(0), *file)
+			os.Exit(2)
+		}
+	}
+
+	logPath, closeLog, err := setupLogging(*debug)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: debug log: %v\n", err)
 		os.Exit(1)
 	}
+	defer closeLog()
+
+	tzErr := setTimezone()
+	var recovered int
+	var recoverErr error
+	if !*safe {
+		recovered, recoverErr = recoverChanges()
+	}
+	if flag.NArg() > 0 {
+		if tzErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", tzErr)
+		}
+		if msg := recoveredMessage(recovered, recoverErr); msg != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		}
+		if err := runCommand(flag.Arg(0), flag.Args()[1:]); err != nil {
+			slog.Error("command", "name", flag.Arg(0), "err", err)
+			closeLog()
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	todoDir, _ := dataDirs()
+	start, err := resolveStart(todoDir, *file, *create, *todoID)
+	if err != nil {
+		closeLog()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	changes := remote.Watch(todoDir) // the escalations at startup count too
+	defer changes.Stop()
+	model := initialModel(*notify, *accessible, *safe, start)
+	model.DebugLog = logPath
+	if tzErr != nil {
+		model.Notify(ui.SeverityWarning, tzErr.Error())
+	}
+	if msg := recoveredMessage(recovered, recoverErr); msg != "" {
+		model.Notify(ui.SeverityWarning, msg) // in the configured language, set up by initialModel
+	}
+	p := tea.NewProgram(crashGuard{model}, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithFPS(maxFPS))
+	final, err := p.Run()
+	if errors.Is(err, tea.ErrProgramPanic) {
+		// Panics in commands are caught by Bubble Tea, which prints their trace
+		recordCrash("panic in a background task, see the trace above", nil, "")
+	}
+	if c := takeCrash(); c != nil {
+		slog.Error("panic", "value", c.value)
+		closeLog()
+		reportCrash(c)
+	}
+	if err != nil {
+		slog.Error("run", "err", err)
+		closeLog()
+		resetTerminal()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Send this session's changes to the server
+	if g, ok := final.(crashGuard); ok {
+		final = g.Model
+	}
+	if m, ok := final.(ui.Model); ok && m.TodoList != nil {
+		m.TodoList.Flush()
+	}
+	todo.Settle() // saves still running in the background
+	for path, err := range todo.Unsaved() {
+		fmt.Fprintf(os.Stderr, "%s wasn't saved: %v\n", path, err)
+	}
+	if *safe {
+		return // nothing was pulled at startup either
+	}
+	if len(changes.Files()) == 0 {
+		return // the server's changes are pulled at the next start
+	}
+	cfg, _ := config.Load(config.Path())
+	if summary, err := syncRemote(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Sync failed, changes stay local until the next run: %v\n", err)
+	} else if summary != "" {
+		fmt.Println(summary)
+	}
+	if cfg.Remote != nil {
+		for _, rel := range changes.InConflict() {
+			fmt.Fprintf(os.Stderr, "%s conflicts with the server, your changes stay local until it is merged\n", rel)
+		}
+	}
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// runNew creates a list from text piped in, one todo per line
+func runNew(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	done := fs.Bool("done", false, "Mark every todo complete")
+	tag := fs.String("tag", "", "Add this @context to every todo")
+	dryRun := fs.Bool("dry-run", false, "Print what would be created")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Flags may also follow the name
+	if fs.NArg() > 0 {
+		name := fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return err
+		}
+		args = append([]string{name}, fs.Args()...)
+	} else {
+		args = nil
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: cat items.txt | justdoit new [--done] [--tag name] [--dry-run] <list>")
+	}
+
+	cfg, _ := config.Load(config.Path())
+	name := todo.NormalizeListName(args[0], cfg.Behavior.LowercaseNames)
+	if err := todo.ValidateListName(name); err != nil {
+		return fmt.Errorf("can't create %q: %v", args[0], err)
+	}
+	context := strings.TrimPrefix(*tag, "@")
+	if c := todo.Contexts("@" + context); *tag != "" && (len(c) != 1 || c[0] != strings.ToLower(context)) {
+		return fmt.Errorf("%q isn't a tag, use letters, digits, _ and -", *tag)
+	}
+	todoDir, _ := dataDirs()
+	dst := filepath.Join(todoDir, listFilename(name))
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("list %q already exists", name)
+	}
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("pipe the todos in, e.g. cat items.txt | justdoit new %s", name)
+	}
+	todos, err := todo.ParseLines(os.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(todos) == 0 {
+		return fmt.Errorf("no lines to turn into todos")
+	}
+	for i := range todos {
+		if *done {
+			todos[i].Completed = true
+		}
+		if context != "" {
+			todos[i].Title = todo.AddContext(todos[i].Title, context)
+		}
+	}
+	if *dryRun {
+		printDryRun(todos, fmt.Sprintf("Would create %s with %d todos", filepath.Base(dst), len(todos)))
+		return nil
+	}
+
+	os.MkdirAll(todoDir, 0755)
+	tl := todo.NewTodoList(dst)
+	tl.SetDeferredSave(true) // written once, reporting a failure
+	tl.Import(todos)
+	if err := tl.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Created %s with %d todos\n", filepath.Base(dst), len(todos))
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"justdoit/todo"
+)
+
+// runPrint writes a list as plain text for printing or pasting
+func runPrint(args []string) error {
+	fs := flag.NewFlagSet("print", flag.ContinueOnError)
+	due := fs.Bool("due", false, "Include due dates")
+	fields := fs.Bool("fields", false, "Include custom fields")
+	notes := fs.Bool("notes", false, "Include notes")
+	open := fs.Bool("open", false, "Only print open todos")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: justdoit print [flags] <file>")
+	}
+
+	todoDir, _ := dataDirs()
+	path, err := listPath(todoDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	tl := todo.NewTodoList(path)
+	name := strings.TrimSuffix(filepath.Base(path), ".json")
+	fmt.Print(tl.PlainText(name, todo.PrintOptions{Due: *due, Fields: *fields, Notes: *notes, OpenOnly: *open}))
+	return nil
+}
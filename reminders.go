@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"justdoit/config"
+	"justdoit/todo"
+	"justdoit/ui"
+)
+
+// reminderItem is a due-dated todo as sent to Apple Reminders
+type reminderItem struct {
+	Key   string // [justdoit:list#id], kept in the reminder's notes to find it on the next export
+	Title string
+	Notes string
+	Due   time.Time
+}
+
+// runReminders handles the Apple Reminders export/import subcommands
+func runReminders(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: justdoit reminders export|import [flags]")
+	}
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	if cfg.Reminders == nil || !cfg.Reminders.Enabled {
+		return fmt.Errorf("the Reminders bridge is off, set apple_reminders.enabled in %s", config.Path())
+	}
+	target := cfg.Reminders.List
+	if target == "" {
+		target = "justdoit"
+	}
+
+	switch args[0] {
+	case "export":
+		return runRemindersExport(args[1:], target)
+	case "import":
+		return runRemindersImport(args[1:])
+	default:
+		return fmt.Errorf("unknown reminders command %q", args[0])
+	}
+}
+
+// runRemindersExport copies open todos with a due date into the Reminders list,
+// updating the reminders made by earlier exports
+func runRemindersExport(args []string, target string) error {
+	fs := flag.NewFlagSet("reminders export", flag.ContinueOnError)
+	file := fs.String("file", "", "List to export (default: all lists)")
+	dryRun := fs.Bool("dry-run", false, "Print what would be exported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	todoDir, _ := dataDirs()
+	files := ui.LoadTodoFiles(todoDir)
+	if *file != "" {
+		if _, err := os.Stat(filepath.Join(todoDir, listFilename(*file))); err != nil {
+			return fmt.Errorf("list %q not found", *file)
+		}
+		files = []string{listFilename(*file)}
+	}
+
+	var items []reminderItem
+	for _, f := range files {
+		tl := todo.NewTodoList(filepath.Join(todoDir, f))
+		if tl.IsHabit() {
+			continue
+		}
+		name := strings.TrimSuffix(f, ".json")
+		for _, t := range tl.Todos {
+			if t.Heading || t.Completed || t.Due == nil {
+				continue
+			}
+			key := fmt.Sprintf("[justdoit:%s#%d]", name, t.ID) // bracketed so #4 doesn't match #40
+			notes := key
+			if t.Notes != "" {
+				notes = t.Notes + "\n\n" + key
+			}
+			items = append(items, reminderItem{Key: key, Title: t.Title, Notes: notes, Due: *t.Due})
+		}
+	}
+
+	if *dryRun {
+		for _, item := range items {
+			fmt.Printf("%s  %s  %s\n", item.Due.Format("2006-01-02 15:04"), strings.Trim(strings.TrimPrefix(item.Key, "[justdoit:"), "]"), item.Title)
+		}
+		fmt.Printf("Would export %d todos to Reminders list %q\n", len(items), target)
+		return nil
+	}
+	n, err := exportReminders(target, items)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d todos to Reminders list %q\n", n, target)
+	return nil
+}
+
+// runRemindersImport creates a new list from a Reminders list
+func runRemindersImport(args []string) error {
+	fs := flag.NewFlagSet("reminders import", flag.ContinueOnError)
+	from := fs.String("from", "", "Reminders list to import")
+	name := fs.String("name", "", "Name of the list to create (default: the Reminders list name)")
+	dryRun := fs.Bool("dry-run", false, "Print what would be imported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" {
+		return fmt.Errorf("usage: justdoit reminders import --from <Reminders list> [--name <list>] [--dry-run]")
+	}
+	if *name == "" {
+		*name = strings.ToLower(strings.ReplaceAll(*from, " ", "-"))
+	}
+
+	todoDir, _ := dataDirs()
+	dst := filepath.Join(todoDir, listFilename(*name))
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("list %q already exists", *name)
+	}
+
+	todos, err := importReminders(*from)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		printDryRun(todos, fmt.Sprintf("Would import %d reminders into %s", len(todos), filepath.Base(dst)))
+		return nil
+	}
+	os.MkdirAll(todoDir, 0755)
+	tl := todo.NewTodoList(dst)
+	tl.Import(todos)
+	if err := tl.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d reminders into %s\n", len(todos), filepath.Base(dst))
+	return nil
+}
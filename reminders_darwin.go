@@ -0,0 +1,122 @@
+//go:build darwin && reminders
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"justdoit/todo"
+)
+
+// exportScript creates or updates one reminder per item. Arguments are the list name
+// followed by key, title, notes, year, month, day, hour and minute for each item.
+const exportScript = `on run argv
+	set listName to item 1 of argv
+	tell application "Reminders"
+		if not (exists list listName) then make new list with properties {name:listName}
+		set targetList to list listName
+		set n to 0
+		repeat with i from 2 to (count of argv) by 8
+			set theKey to item i of argv
+			set dueDate to current date
+			set day of dueDate to 1
+			set year of dueDate to (item (i + 3) of argv) as integer
+			set month of dueDate to (item (i + 4) of argv) as integer
+			set day of dueDate to (item (i + 5) of argv) as integer
+			set hours of dueDate to (item (i + 6) of argv) as integer
+			set minutes of dueDate to (item (i + 7) of argv) as integer
+			set seconds of dueDate to 0
+			set found to (reminders of targetList whose body contains theKey)
+			if (count of found) > 0 then
+				set r to item 1 of found
+				set name of r to item (i + 1) of argv
+				set body of r to item (i + 2) of argv
+				set due date of r to dueDate
+			else
+				make new reminder at end of targetList with properties {name:item (i + 1) of argv, body:item (i + 2) of argv, due date:dueDate}
+			end if
+			set n to n + 1
+		end repeat
+	end tell
+	return n
+end run`
+
+// importScript lists the reminders of a list as records separated by ASCII RS,
+// with name, completed, due (year-month-day-hour-minute) and notes separated by US
+const importScript = `on run argv
+	set rs to character id 30
+	set us to character id 31
+	set out to ""
+	tell application "Reminders"
+		repeat with r in reminders of list (item 1 of argv)
+			set dueText to ""
+			set d to due date of r
+			if d is not missing value then set dueText to ((year of d) as text) & "-" & ((month of d) as integer) & "-" & (day of d) & "-" & (hours of d) & "-" & (minutes of d)
+			set theBody to body of r
+			if theBody is missing value then set theBody to ""
+			set out to out & (name of r) & us & ((completed of r) as text) & us & dueText & us & theBody & rs
+		end repeat
+	end tell
+	return out
+end run`
+
+// osascript runs an AppleScript read from stdin with the given arguments
+func osascript(script string, args ...string) (string, error) {
+	var out, stderr bytes.Buffer
+	cmd := exec.Command("osascript", append([]string{"-"}, args...)...)
+	cmd.Stdin = strings.NewReader(script)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("osascript: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
+
+// exportReminders creates or updates a reminder in list for each item
+func exportReminders(list string, items []reminderItem) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	args := []string{list}
+	for _, item := range items {
+		due := item.Due.Local()
+		args = append(args, item.Key, item.Title, item.Notes,
+			strconv.Itoa(due.Year()), strconv.Itoa(int(due.Month())), strconv.Itoa(due.Day()),
+			strconv.Itoa(due.Hour()), strconv.Itoa(due.Minute()))
+	}
+	out, err := osascript(exportScript, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// importReminders reads every reminder of a Reminders list as a todo
+func importReminders(list string) ([]todo.Todo, error) {
+	out, err := osascript(importScript, list)
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []todo.Todo
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(record, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		t := todo.Todo{Title: fields[0], Completed: fields[1] == "true", CreatedAt: time.Now(), Notes: fields[3]}
+		var y, mo, d, h, mi int
+		if _, err := fmt.Sscanf(fields[2], "%d-%d-%d-%d-%d", &y, &mo, &d, &h, &mi); err == nil {
+			due := time.Date(y, time.Month(mo), d, h, mi, 0, 0, time.Local)
+			t.Due = &due
+		}
+		todos = append(todos, t)
+	}
+	return todos, nil
+}
@@ -0,0 +1,22 @@
+//go:build !darwin || !reminders
+
+package main
+
+import (
+	"errors"
+
+	"justdoit/todo"
+)
+
+// errNoReminders is returned by builds without the Reminders bridge
+var errNoReminders = errors.New("this build has no Reminders support, rebuild on macOS with -tags reminders")
+
+// exportReminders is only available on macOS builds with -tags reminders
+func exportReminders(list string, items []reminderItem) (int, error) {
+	return 0, errNoReminders
+}
+
+// importReminders is only available on macOS builds with -tags reminders
+func importReminders(list string) ([]todo.Todo, error) {
+	return nil, errNoReminders
+}
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"justdoit/todo"
+)
+
+// Changes collects the synced files changed on this machine while it watches, from
+// the saves, moves and deletes the lists publish
+type Changes struct {
+	local string
+	stop  func()
+
+	mu    sync.Mutex
+	files map[string]bool // slash-separated, relative to local
+}
+
+// Watch starts collecting the changes to the synced files of the data directory local
+func Watch(local string) *Changes {
+	c := &Changes{local: local, files: map[string]bool{}}
+	c.stop = todo.Subscribe(c.note, todo.FileSaved, todo.FileDeleted)
+	return c
+}
+
+// note records the file of e when it is synced
+func (c *Changes) note(e todo.Event) {
+	rel, err := filepath.Rel(c.local, e.Path)
+	if err != nil || !synced(rel) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[filepath.ToSlash(rel)] = true
+}
+
+// synced reports whether the file at rel, relative to the data directory, is synced
+func synced(rel string) bool {
+	name := filepath.Base(rel)
+	return slices.Contains(Dirs, filepath.Dir(rel)) && !strings.HasPrefix(name, ".") && filepath.Ext(name) == ".json"
+}
+
+// Files returns the changed files, sorted, as relative paths
+func (c *Changes) Files() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files := make([]string, 0, len(c.files))
+	for rel := range c.files {
+		files = append(files, rel)
+	}
+	slices.Sort(files)
+	return files
+}
+
+// InConflict returns the changed files waiting for a merge with the server's copy.
+// Their changes stay on this machine until the conflict is resolved.
+func (c *Changes) InConflict() []string {
+	conflicts := Conflicts(c.local)
+	return slices.DeleteFunc(c.Files(), func(rel string) bool { return !slices.Contains(conflicts, rel) })
+}
+
+// Stop stops collecting changes
+func (c *Changes) Stop() {
+	c.stop()
+}
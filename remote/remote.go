@@ -0,0 +1,276 @@
+// Package remote syncs the data directory with a copy kept on a server.
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dirs are the parts of the data directory that are synced, relative to its root
+var Dirs = []string{".", "archive", "templates"}
+
+// StateFile records what each file looked like after the last sync
+const StateFile = ".remote-state"
+
+// ConflictDir holds the server's copy of files changed on both sides
+const ConflictDir = ".conflicts"
+
+// Transport moves files to and from the server
+type Transport interface {
+	// Fetch copies the server's data directory into dst, which starts empty
+	Fetch(dst string) error
+	// Push uploads the files at local/rel for each rel in put and deletes the remove paths
+	Push(local string, put, remove []string) error
+}
+
+// Result summarizes a sync
+type Result struct {
+	Pulled    []string // updated or deleted locally
+	Pushed    []string // uploaded or deleted on the server
+	Conflicts []string // changed on both sides, the server's copy is in ConflictDir
+}
+
+// String describes the result in one line
+func (r Result) String() string {
+	s := fmt.Sprintf("Synced: %d pulled, %d pushed", len(r.Pulled), len(r.Pushed))
+	if len(r.Conflicts) > 0 {
+		s += fmt.Sprintf(", %d in conflict (%s)", len(r.Conflicts), strings.Join(r.Conflicts, ", "))
+	}
+	return s
+}
+
+// Sync brings the local data directory and the server's copy together. Files changed
+// on one side since the last sync are copied to the other, and edits win over deletes.
+// Files edited on both are left alone locally, with the server's copy saved under ConflictDir.
+// A server with none of the files synced before deletes nothing here, that's an error.
+func Sync(local string, t Transport) (Result, error) {
+	var res Result
+	base, err := loadState(local)
+	if err != nil {
+		return res, err
+	}
+
+	staging, err := os.MkdirTemp("", "justdoit-remote-*")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(staging)
+	if err := t.Fetch(staging); err != nil {
+		return res, fmt.Errorf("fetching from server: %w", err)
+	}
+
+	locals, err := hashFiles(local)
+	if err != nil {
+		return res, err
+	}
+	remotes, err := hashFiles(staging)
+	if err != nil {
+		return res, err
+	}
+	// A server with none of the synced files more likely means a fetch gone wrong
+	// than every list deleted there, so its deletions aren't applied
+	emptied := len(remotes) == 0 && len(base) > 0
+	kept := 0
+
+	var put, remove []string
+	for _, rel := range union(base, locals, remotes) {
+		l, r, b := locals[rel], remotes[rel], base[rel]
+		switch {
+		case l == r:
+			// already in step
+		case l == b && r == "" && emptied:
+			kept++
+			continue
+		case l == b:
+			// only the server changed
+			if err := pull(local, staging, rel, r == ""); err != nil {
+				return res, err
+			}
+			res.Pulled = append(res.Pulled, rel)
+		case r == b:
+			// only this machine changed
+			if l == "" {
+				remove = append(remove, rel)
+			} else {
+				put = append(put, rel)
+			}
+			res.Pushed = append(res.Pushed, rel)
+			continue
+		case l == "":
+			// deleted here but edited on the server: keep the edits
+			if err := pull(local, staging, rel, false); err != nil {
+				return res, err
+			}
+			res.Pulled = append(res.Pulled, rel)
+		case r == "":
+			// deleted on the server but edited here: keep the edits
+			put = append(put, rel)
+			res.Pushed = append(res.Pushed, rel)
+			continue
+		default:
+			if err := copyFile(filepath.Join(staging, rel), filepath.Join(local, ConflictDir, rel)); err != nil {
+				return res, err
+			}
+			res.Conflicts = append(res.Conflicts, rel)
+			continue
+		}
+		os.Remove(filepath.Join(local, ConflictDir, rel)) // settled, e.g. by copying one side over
+		if r == "" {
+			delete(base, rel)
+		} else {
+			base[rel] = r
+		}
+	}
+
+	if len(put) > 0 || len(remove) > 0 {
+		if err := t.Push(local, put, remove); err != nil {
+			return Result{Pulled: res.Pulled, Conflicts: res.Conflicts}, fmt.Errorf("pushing to server: %w", err)
+		}
+		for _, rel := range put {
+			base[rel] = locals[rel]
+		}
+		for _, rel := range remove {
+			delete(base, rel)
+		}
+	}
+	if err := saveState(local, base); err != nil {
+		return res, err
+	}
+	if kept > 0 {
+		return res, fmt.Errorf("the server has none of the synced files, kept %d local lists instead of deleting them: check remote.path", kept)
+	}
+	return res, nil
+}
+
+// pull copies the server's version of rel over the local one, or deletes it
+func pull(local, staging, rel string, deleted bool) error {
+	if deleted {
+		if err := os.Remove(filepath.Join(local, rel)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return copyFile(filepath.Join(staging, rel), filepath.Join(local, rel))
+}
+
+// hashFiles hashes the list files under each synced directory of root, keyed by
+// slash-separated relative path
+func hashFiles(root string) (map[string]string, error) {
+	hashes := map[string]string{}
+	for _, dir := range Dirs {
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(root, dir, name))
+			if err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256(data)
+			hashes[filepath.ToSlash(filepath.Join(dir, name))] = hex.EncodeToString(sum[:])
+		}
+	}
+	return hashes, nil
+}
+
+// union returns the sorted keys of the given maps
+func union(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// copyFile copies src to dst atomically, creating dst's directory
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// loadState reads the file hashes recorded by the last sync
+func loadState(local string) (map[string]string, error) {
+	state := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(local, StateFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid %s, delete it to sync from scratch: %w", StateFile, err)
+	}
+	return state, nil
+}
+
+// saveState records the file hashes both sides agree on
+func saveState(local string, state map[string]string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(local, StateFile), data, 0644)
+}
+
+// Conflicts lists the files waiting for a merge, as relative paths
+func Conflicts(local string) []string {
+	theirs, err := hashFiles(filepath.Join(local, ConflictDir))
+	if err != nil {
+		return nil
+	}
+	return union(theirs)
+}
+
+// Resolve settles a conflict in favor of the local file, which may already hold a
+// manual merge: the next Sync uploads it unless the server changed again meanwhile
+func Resolve(local, rel string) error {
+	state, err := loadState(local)
+	if err != nil {
+		return err
+	}
+	theirs, err := hashFiles(filepath.Join(local, ConflictDir))
+	if err != nil {
+		return err
+	}
+	h, ok := theirs[rel]
+	if !ok {
+		return fmt.Errorf("%s is not in conflict", rel)
+	}
+	state[rel] = h
+	if err := saveState(local, state); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(local, ConflictDir, rel))
+}
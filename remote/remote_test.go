@@ -0,0 +1,202 @@
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// dirTransport stands in for a server with a local directory
+type dirTransport struct {
+	dir string
+}
+
+// Fetch copies the directory's list files into dst
+func (d dirTransport) Fetch(dst string) error {
+	hashes, err := hashFiles(d.dir)
+	if err != nil {
+		return err
+	}
+	for rel := range hashes {
+		if err := copyFile(filepath.Join(d.dir, rel), filepath.Join(dst, rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Push copies and deletes files in the directory
+func (d dirTransport) Push(local string, put, remove []string) error {
+	for _, rel := range put {
+		if err := copyFile(filepath.Join(local, rel), filepath.Join(d.dir, rel)); err != nil {
+			return err
+		}
+	}
+	for _, rel := range remove {
+		if err := os.Remove(filepath.Join(d.dir, rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write creates a file under dir with the given content
+func write(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(filepath.Join(dir, rel)), 0755)
+	if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read returns a file's content, or "" if it's missing
+func read(dir, rel string) string {
+	data, _ := os.ReadFile(filepath.Join(dir, rel))
+	return string(data)
+}
+
+// TestSyncTwoMachines tests changes flowing between two machines through the server
+func TestSyncTwoMachines(t *testing.T) {
+	server := dirTransport{t.TempDir()}
+	laptop, desktop := t.TempDir(), t.TempDir()
+
+	write(t, laptop, "work.json", "v1")
+	write(t, laptop, "archive/old.json", "done")
+	if res, err := Sync(laptop, server); err != nil || len(res.Pushed) != 2 {
+		t.Fatalf("Expected both files pushed, got %+v, %v", res, err)
+	}
+	if res, err := Sync(desktop, server); err != nil || len(res.Pulled) != 2 || read(desktop, "archive/old.json") != "done" {
+		t.Fatalf("Expected both files pulled, got %+v, %v", res, err)
+	}
+
+	write(t, desktop, "work.json", "v2")
+	os.Remove(filepath.Join(desktop, "archive/old.json"))
+	Sync(desktop, server)
+	res, err := Sync(laptop, server)
+	if err != nil || len(res.Pulled) != 2 || read(laptop, "work.json") != "v2" || read(laptop, "archive/old.json") != "" {
+		t.Errorf("Expected the edit and the delete to reach the laptop, got %+v, %v", res, err)
+	}
+
+	if res, _ := Sync(laptop, server); len(res.Pulled)+len(res.Pushed)+len(res.Conflicts) != 0 {
+		t.Errorf("Expected nothing to do when in step, got %+v", res)
+	}
+}
+
+// TestSyncConflict tests that edits on both sides are kept apart until resolved
+func TestSyncConflict(t *testing.T) {
+	server := dirTransport{t.TempDir()}
+	laptop, desktop := t.TempDir(), t.TempDir()
+	write(t, laptop, "work.json", "v1")
+	Sync(laptop, server)
+	Sync(desktop, server)
+
+	write(t, desktop, "work.json", "desktop edit")
+	Sync(desktop, server)
+	write(t, laptop, "work.json", "laptop edit")
+	res, err := Sync(laptop, server)
+	if err != nil || !slices.Equal(res.Conflicts, []string{"work.json"}) {
+		t.Fatalf("Expected work.json in conflict, got %+v, %v", res, err)
+	}
+	if read(laptop, "work.json") != "laptop edit" || read(laptop, ".conflicts/work.json") != "desktop edit" {
+		t.Error("Expected the local file kept and the server's copy set aside")
+	}
+	if read(server.dir, "work.json") != "desktop edit" {
+		t.Error("Expected the server untouched while in conflict")
+	}
+
+	write(t, laptop, "work.json", "merged")
+	if err := Resolve(laptop, "work.json"); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := Sync(laptop, server); err != nil || len(res.Pushed) != 1 || read(server.dir, "work.json") != "merged" {
+		t.Errorf("Expected the merge pushed after resolving, got %+v, %v", res, err)
+	}
+	if err := Resolve(laptop, "work.json"); err == nil {
+		t.Error("Expected resolving twice to fail")
+	}
+}
+
+// TestSyncEditBeatsDelete tests that a list deleted on one side but edited on the other survives
+func TestSyncEditBeatsDelete(t *testing.T) {
+	server := dirTransport{t.TempDir()}
+	laptop, desktop := t.TempDir(), t.TempDir()
+	write(t, laptop, "work.json", "v1")
+	Sync(laptop, server)
+	Sync(desktop, server)
+
+	os.Remove(filepath.Join(desktop, "work.json"))
+	Sync(desktop, server)
+	write(t, laptop, "work.json", "v2")
+	if res, _ := Sync(laptop, server); len(res.Pushed) != 1 || read(server.dir, "work.json") != "v2" {
+		t.Errorf("Expected the edited list pushed back, got %+v", res)
+	}
+	if len(Conflicts(laptop)) != 0 {
+		t.Error("Expected no conflicts")
+	}
+}
+
+// TestSyncEmptyServer tests that a server coming back empty after earlier syncs fails
+// the sync instead of deleting every local list
+func TestSyncEmptyServer(t *testing.T) {
+	server := dirTransport{t.TempDir()}
+	laptop := t.TempDir()
+	write(t, laptop, "work.json", "v1")
+	Sync(laptop, server)
+
+	if _, err := Sync(laptop, dirTransport{t.TempDir()}); err == nil {
+		t.Error("Expected the sync to fail against an empty server")
+	}
+	if read(laptop, "work.json") != "v1" {
+		t.Error("Expected work.json kept")
+	}
+}
+
+// TestFetchBatch tests that only the archive and templates directories may be missing
+// on the server
+func TestFetchBatch(t *testing.T) {
+	batch := NewSFTP(config.Remote{Host: "box", Path: "/srv/todos"}).fetchBatch("/tmp/stage")
+	if batch[0] != `cd "/srv/todos"` {
+		t.Errorf("Expected a failing cd into the data directory first, got %q", batch[0])
+	}
+	if want := `-get "archive"/*.json`; !slices.Contains(batch, want) {
+		t.Errorf("Expected %q in %q", want, batch)
+	}
+}
+
+// TestQuote tests quoting paths for sftp batch files
+func TestQuote(t *testing.T) {
+	if got := quote(`/srv/my "todos"\x`); got != `"/srv/my \"todos\"\\x"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
+
+// TestWatch tests that the synced files saved or deleted while watching are collected,
+// and those waiting for a merge picked out
+func TestWatch(t *testing.T) {
+	laptop := t.TempDir()
+	changes := Watch(laptop)
+	defer changes.Stop()
+
+	work := todo.NewTodoList(filepath.Join(laptop, "work.json"))
+	work.Add("write report")
+	for _, path := range []string{
+		filepath.Join(laptop, "templates", "trip.json"),
+		filepath.Join(laptop, ".recent.json"),
+		filepath.Join(t.TempDir(), "elsewhere.json"),
+	} {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		todo.NewTodoList(path).Add("pack")
+	}
+	if got := changes.Files(); !slices.Equal(got, []string{"templates/trip.json", "work.json"}) {
+		t.Errorf("Expected the synced lists saved, got %v", got)
+	}
+
+	write(t, laptop, ConflictDir+"/work.json", "server edit")
+	if got := changes.InConflict(); !slices.Equal(got, []string{"work.json"}) {
+		t.Errorf("Expected work.json waiting for a merge, got %v", got)
+	}
+}
@@ -0,0 +1,98 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"justdoit/config"
+)
+
+// SFTP reaches the server with OpenSSH's sftp command in batch mode, so host
+// aliases, agents and known_hosts work as they do for ssh
+type SFTP struct {
+	cfg config.Remote
+}
+
+// NewSFTP returns a transport for the configured server
+func NewSFTP(cfg config.Remote) SFTP {
+	return SFTP{cfg: cfg}
+}
+
+// Fetch downloads the synced directories of the server's data directory. It fails
+// when the data directory itself can't be reached, so a wrong remote.path never
+// looks like a server with every list deleted.
+func (s SFTP) Fetch(dst string) error {
+	for _, dir := range Dirs {
+		if err := os.MkdirAll(filepath.Join(dst, dir), 0755); err != nil {
+			return err
+		}
+	}
+	return s.run(s.fetchBatch(dst))
+}
+
+// fetchBatch returns the sftp commands fetching the synced directories into dst
+func (s SFTP) fetchBatch(dst string) []string {
+	// Without "-", a missing data directory stops the batch
+	batch := []string{"cd " + quote(s.cfg.Path)}
+	for _, dir := range Dirs {
+		// "-" ignores errors: an empty directory, or an archive or templates
+		// directory that doesn't exist yet
+		batch = append(batch, "lcd "+quote(filepath.Join(dst, dir)), "-get "+quote(dir)+"/*.json")
+	}
+	return batch
+}
+
+// Push uploads files next to their target and renames them into place, so readers
+// never see half a file
+func (s SFTP) Push(local string, put, remove []string) error {
+	var batch []string
+	for _, dir := range Dirs {
+		batch = append(batch, "-mkdir "+quote(path.Join(s.cfg.Path, dir)))
+	}
+	for _, rel := range put {
+		dst := path.Join(s.cfg.Path, rel)
+		tmp := path.Join(path.Dir(dst), "."+path.Base(dst)+".tmp")
+		batch = append(batch,
+			"put "+quote(filepath.Join(local, filepath.FromSlash(rel)))+" "+quote(tmp),
+			"rename "+quote(tmp)+" "+quote(dst))
+	}
+	for _, rel := range remove {
+		batch = append(batch, "-rm "+quote(path.Join(s.cfg.Path, rel)))
+	}
+	return s.run(batch)
+}
+
+// run executes sftp batch commands, stopping at the first failing one
+func (s SFTP) run(batch []string) error {
+	args := []string{"-q", "-b", "-", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if s.cfg.Key != "" {
+		args = append(args, "-i", s.cfg.Key)
+	}
+	if s.cfg.Port != 0 {
+		args = append(args, "-P", strconv.Itoa(s.cfg.Port))
+	}
+	args = append(args, s.cfg.Host)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("sftp", args...)
+	cmd.Stdin = strings.NewReader(strings.Join(batch, "\n") + "\n")
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("sftp %s: %s", s.cfg.Host, msg)
+		}
+		return fmt.Errorf("sftp %s: %w", s.cfg.Host, err)
+	}
+	return nil
+}
+
+// quote wraps a path in double quotes for an sftp batch file
+func quote(p string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(p) + `"`
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"justdoit/todo"
+)
+
+// runReport prints a Markdown report of todos completed in a date range
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	since := fs.String("since", "monday", "Start of range: today, yesterday, a weekday, Nd (days ago) or YYYY-MM-DD")
+	until := fs.String("until", "", "End of range (exclusive), same formats as --since (default: now)")
+	archived := fs.Bool("archived", true, "Include archived files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	start, err := parseDay(*since, now)
+	if err != nil {
+		return err
+	}
+	end := now
+	if *until != "" {
+		if end, err = parseDay(*until, now); err != nil {
+			return err
+		}
+	}
+
+	todoDir, archiveDir := dataDirs()
+	paths := listPaths(todoDir)
+	if *archived {
+		paths = append(paths, listPaths(archiveDir)...)
+	}
+
+	fmt.Print(todo.DoneReport(paths, start, end))
+	return nil
+}
+
+// parseDay resolves a day expression to local midnight relative to now
+func parseDay(s string, now time.Time) (time.Time, error) {
+	today := todo.Midnight(now)
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	// Most recent occurrence of a weekday (today counts)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if s == strings.ToLower(d.String()) {
+			back := (int(today.Weekday()) - int(d) + 7) % 7
+			return today.AddDate(0, 0, -back), nil
+		}
+	}
+
+	var days int
+	if _, err := fmt.Sscanf(s, "%dd", &days); err == nil && strings.HasSuffix(s, "d") {
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
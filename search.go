@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"justdoit/search"
+	"justdoit/ui"
+)
+
+// runSearch prints todos across all lists matching the query
+func runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	limit := fs.Int("limit", 50, "Maximum number of results (0 for all)")
+	archived := fs.Bool("archived", false, "Include archived files")
+	regex := fs.Bool("regex", false, "Match titles against a regular expression")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	text := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("usage: justdoit search [--limit N] [--archived] [--regex] <query>")
+	}
+	query, err := search.Compile(text, *regex)
+	if err != nil {
+		return err
+	}
+
+	todoDir, archiveDir := dataDirs()
+	dirs := []string{todoDir}
+	if *archived {
+		dirs = append(dirs, archiveDir)
+	}
+
+	var hits []search.Hit
+	if query.Plain() {
+		idx := search.Open(search.Path())
+		if err := idx.Refresh(dirs...); err != nil {
+			return err
+		}
+		if err := idx.Save(); err != nil {
+			return err
+		}
+		hits = idx.Search(text, *limit, dirs...)
+	} else {
+		// Operators and patterns need more than indexed words, so read the lists themselves
+		var paths []string
+		for _, dir := range dirs {
+			for _, f := range ui.LoadTodoFiles(dir) {
+				paths = append(paths, filepath.Join(dir, f))
+			}
+		}
+		hits = search.Find(query, paths, time.Now(), *limit)
+	}
+
+	for _, hit := range hits {
+		file := filepath.Base(hit.File)
+		if filepath.Dir(hit.File) == archiveDir {
+			file = "archive/" + file
+		}
+		fmt.Printf("%s:%d\t%s\n", file, hit.ID, hit.Title)
+	}
+	return nil
+}
@@ -0,0 +1,362 @@
+// Package search maintains an inverted index over todo titles across files.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"justdoit/todo"
+)
+
+// version is bumped whenever the on-disk index layout changes
+const version = 3
+
+// Hit is a todo matching a search
+type Hit struct {
+	File  string // path of the list containing the todo
+	ID    int
+	Title string
+	Due   *time.Time
+}
+
+// fileIndex holds the indexed todos of one file and its token postings
+type fileIndex struct {
+	ModTime  time.Time        `json:"mod_time"`
+	Size     int64            `json:"size"`
+	IDs      []int            `json:"ids"`
+	Titles   []string         `json:"titles"`
+	Dues     []*time.Time     `json:"dues"`     // due dates of open todos, nil otherwise
+	Starts   []*time.Time     `json:"starts"`   // start dates of open todos, nil otherwise
+	Postings map[string][]int `json:"postings"` // token -> positions in IDs/Titles
+
+	tokens []string // sorted keys of Postings, built on first search
+}
+
+// Index is an inverted index over the todos of many files, kept in sync
+// with the files by modification time
+type Index struct {
+	Version int                   `json:"version"`
+	Files   map[string]*fileIndex `json:"files"`
+	path    string
+	dirty   bool
+}
+
+// Path returns the location of the index in the user cache directory
+func Path() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "justdoit", "search-index.json")
+}
+
+// Open reads the index at path. A missing or unreadable index starts empty
+// and is rebuilt by the next Refresh.
+func Open(path string) *Index {
+	idx := &Index{Version: version, Files: map[string]*fileIndex{}, path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return idx
+	}
+	if err := json.Unmarshal(data, idx); err != nil || idx.Files == nil || idx.Version != version {
+		idx.Version = version
+		idx.Files = map[string]*fileIndex{}
+	}
+	// Dates are shown in the local timezone, which may not be the one they were cached in
+	for _, f := range idx.Files {
+		localize(f.Dues)
+		localize(f.Starts)
+	}
+	return idx
+}
+
+// localize moves the dates in times into the local timezone
+func localize(times []*time.Time) {
+	for i, t := range times {
+		if t != nil {
+			local := t.Local()
+			times[i] = &local
+		}
+	}
+}
+
+// Refresh re-indexes the .json files in dirs that changed since they were
+// last indexed and drops files that no longer exist
+func (idx *Index) Refresh(dirs ...string) error {
+	return idx.RefreshProgress(func(done, total int) {}, dirs...)
+}
+
+// RefreshProgress is Refresh, calling report after each file with how many of the
+// files in dirs are done
+func (idx *Index) RefreshProgress(report func(done, total int), dirs ...string) error {
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	present := map[string]bool{}
+	for i, path := range paths {
+		present[path] = true
+		if err := idx.Update(path); err != nil {
+			return err
+		}
+		report(i+1, len(paths))
+	}
+
+	for path := range idx.Files {
+		if !present[path] && idx.inDirs(path, dirs) {
+			delete(idx.Files, path)
+			idx.dirty = true
+		}
+	}
+	return nil
+}
+
+// inDirs reports whether path is directly inside one of dirs
+func (idx *Index) inDirs(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if filepath.Dir(path) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update re-indexes a single file if it changed since it was last indexed
+func (idx *Index) Update(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) && idx.Files[path] != nil {
+			delete(idx.Files, path)
+			idx.dirty = true
+			return nil
+		}
+		return err
+	}
+	if fi := idx.Files[path]; fi != nil && fi.ModTime.Equal(info.ModTime()) && fi.Size == info.Size() {
+		return nil
+	}
+
+	tl := todo.NewTodoList(path)
+	fi := &fileIndex{ModTime: info.ModTime(), Size: info.Size(), Postings: map[string][]int{}}
+	for _, t := range tl.Todos {
+		pos := len(fi.IDs)
+		fi.IDs = append(fi.IDs, t.ID)
+		fi.Titles = append(fi.Titles, t.Title)
+		if t.Completed || t.Heading {
+			fi.Dues = append(fi.Dues, nil)
+			fi.Starts = append(fi.Starts, nil)
+		} else {
+			fi.Dues = append(fi.Dues, t.Due)
+			fi.Starts = append(fi.Starts, t.Start)
+		}
+		for _, token := range uniqueTokens(t.Title) {
+			fi.Postings[token] = append(fi.Postings[token], pos)
+		}
+	}
+	idx.Files[path] = fi
+	idx.dirty = true
+	return nil
+}
+
+// Save writes the index to disk if it changed. An index opened with an
+// empty path lives in memory only.
+func (idx *Index) Save() error {
+	if !idx.dirty || idx.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated index
+	tmp := idx.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp, idx.path); err != nil {
+		return fmt.Errorf("failed to replace index: %w", err)
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Search returns todos whose title contains every query word, matching each
+// word as a prefix of a title word. Only files inside dirs are searched when
+// any are given. Results are ordered by file, then list order.
+func (idx *Index) Search(query string, limit int, dirs ...string) []Hit {
+	words := uniqueTokens(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(idx.Files))
+	for path := range idx.Files {
+		if len(dirs) == 0 || idx.inDirs(path, dirs) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var hits []Hit
+	for _, path := range paths {
+		fi := idx.Files[path]
+		for _, pos := range fi.match(words) {
+			hits = append(hits, fi.hit(path, pos))
+			if limit > 0 && len(hits) == limit {
+				return hits
+			}
+		}
+	}
+	return hits
+}
+
+// DueBefore returns open todos due before t, soonest first. Todos deferred until t
+// or later aren't actionable yet and are left out.
+func (idx *Index) DueBefore(t time.Time, dirs ...string) []Hit {
+	var hits []Hit
+	for path, fi := range idx.Files {
+		if len(dirs) > 0 && !idx.inDirs(path, dirs) {
+			continue
+		}
+		for pos, due := range fi.Dues {
+			if start := fi.Starts[pos]; start != nil && !start.Before(t) {
+				continue
+			}
+			if due != nil && due.Before(t) {
+				hits = append(hits, fi.hit(path, pos))
+			}
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if !hits[i].Due.Equal(*hits[j].Due) {
+			return hits[i].Due.Before(*hits[j].Due)
+		}
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
+
+// Phrases returns the distinct titles of todos matching text like Search, for
+// completing a new todo. Titles starting with text come first, and text itself is
+// left out.
+func (idx *Index) Phrases(text string, limit int, dirs ...string) []string {
+	typed := strings.ToLower(strings.TrimSpace(text))
+	seen := map[string]bool{typed: true}
+	var prefixed, others []string
+	for _, hit := range idx.Search(text, 0, dirs...) {
+		title := strings.ToLower(hit.Title)
+		if seen[title] {
+			continue
+		}
+		seen[title] = true
+		if strings.HasPrefix(title, typed) {
+			prefixed = append(prefixed, hit.Title)
+		} else {
+			others = append(others, hit.Title)
+		}
+	}
+	phrases := append(prefixed, others...)
+	if limit > 0 && len(phrases) > limit {
+		phrases = phrases[:limit]
+	}
+	return phrases
+}
+
+// Contexts returns the sorted, de-duplicated @contexts of the indexed todos
+func (idx *Index) Contexts(dirs ...string) []string {
+	seen := map[string]bool{}
+	var contexts []string
+	for path, fi := range idx.Files {
+		if len(dirs) > 0 && !idx.inDirs(path, dirs) {
+			continue
+		}
+		for _, title := range fi.Titles {
+			for _, c := range todo.Contexts(title) {
+				if !seen[c] {
+					seen[c] = true
+					contexts = append(contexts, c)
+				}
+			}
+		}
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// hit builds the result for the todo at pos
+func (fi *fileIndex) hit(path string, pos int) Hit {
+	return Hit{File: path, ID: fi.IDs[pos], Title: fi.Titles[pos], Due: fi.Dues[pos]}
+}
+
+// match returns the sorted positions of todos matching every word
+func (fi *fileIndex) match(words []string) []int {
+	if fi.tokens == nil {
+		fi.tokens = make([]string, 0, len(fi.Postings))
+		for token := range fi.Postings {
+			fi.tokens = append(fi.tokens, token)
+		}
+		sort.Strings(fi.tokens)
+	}
+
+	var result map[int]bool
+	for _, word := range words {
+		found := map[int]bool{}
+		// Tokens sharing the prefix are contiguous in sorted order
+		for i := sort.SearchStrings(fi.tokens, word); i < len(fi.tokens) && strings.HasPrefix(fi.tokens[i], word); i++ {
+			for _, pos := range fi.Postings[fi.tokens[i]] {
+				if result == nil || result[pos] {
+					found[pos] = true
+				}
+			}
+		}
+		if len(found) == 0 {
+			return nil
+		}
+		result = found
+	}
+
+	positions := make([]int, 0, len(result))
+	for pos := range result {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// uniqueTokens splits text into distinct lowercase words
+func uniqueTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,188 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"justdoit/todo"
+)
+
+// writeList creates a list file with the given titles in order
+func writeList(t *testing.T, path string, titles ...string) {
+	t.Helper()
+	os.Remove(path)
+	tl := todo.NewTodoList(path)
+	for i := len(titles) - 1; i >= 0; i-- {
+		tl.Add(titles[i])
+	}
+	if err := tl.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// titlesOf returns the titles of hits in order
+func titlesOf(hits []Hit) []string {
+	var out []string
+	for _, h := range hits {
+		out = append(out, h.Title)
+	}
+	return out
+}
+
+// TestSearch tests word-prefix matching across files
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, filepath.Join(dir, "home.json"), "Call the plumber", "Buy milk", "Pay water bill")
+	writeList(t, filepath.Join(dir, "work.json"), "Plan sprint", "Call Alice about billing")
+
+	idx := Open(filepath.Join(t.TempDir(), "index.json"))
+	if err := idx.Refresh(dir); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"call", []string{"Call the plumber", "Call Alice about billing"}},
+		{"bill", []string{"Pay water bill", "Call Alice about billing"}},
+		{"CALL bil", []string{"Call Alice about billing"}},
+		{"pl", []string{"Call the plumber", "Plan sprint"}},
+		{"nothing", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := titlesOf(idx.Search(tt.query, 0))
+		if len(got) != len(tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if hits := idx.Search("call", 1); len(hits) != 1 || hits[0].Title != "Call the plumber" {
+		t.Errorf("Expected limit to keep the first hit, got %+v", hits)
+	}
+}
+
+// TestRefreshIncremental tests that only changed files are re-indexed and the index persists
+func TestRefreshIncremental(t *testing.T) {
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home.json")
+	work := filepath.Join(dir, "work.json")
+	writeList(t, home, "Buy milk")
+	writeList(t, work, "Plan sprint")
+
+	indexPath := filepath.Join(t.TempDir(), "cache", "index.json")
+	idx := Open(indexPath)
+	idx.Refresh(dir)
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Reopen from disk, change one file and remove the other
+	idx = Open(indexPath)
+	unchanged := idx.Files[home]
+	writeList(t, work, "Plan retro", "Plan sprint")
+	os.Chtimes(work, time.Now(), time.Now().Add(time.Second))
+	os.Remove(home)
+	writeList(t, filepath.Join(dir, "new.json"), "Buy bread")
+	if err := idx.Refresh(dir); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if unchanged == nil {
+		t.Fatal("Expected the index to be read back from disk")
+	}
+	if got := titlesOf(idx.Search("plan", 0)); len(got) != 2 {
+		t.Errorf("Expected changed file to be re-indexed, got %v", got)
+	}
+	if got := titlesOf(idx.Search("buy", 0)); len(got) != 1 || got[0] != "Buy bread" {
+		t.Errorf("Expected deleted file dropped and new file added, got %v", got)
+	}
+}
+
+// TestSearchDirs tests restricting a search to some directories
+func TestSearchDirs(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "archive")
+	os.MkdirAll(archive, 0755)
+	writeList(t, filepath.Join(dir, "home.json"), "Buy milk")
+	writeList(t, filepath.Join(archive, "old.json"), "Buy stamps")
+
+	idx := Open(filepath.Join(t.TempDir(), "index.json"))
+	idx.Refresh(dir, archive)
+
+	if got := idx.Search("buy", 0); len(got) != 2 {
+		t.Errorf("Expected hits from both directories, got %v", titlesOf(got))
+	}
+	if got := idx.Search("buy", 0, dir); len(got) != 1 || got[0].Title != "Buy milk" {
+		t.Errorf("Expected only the active list, got %v", titlesOf(got))
+	}
+}
+
+// TestDueBefore tests collecting open todos due before a time, soonest first
+func TestDueBefore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "home.json")
+	writeList(t, path, "Later", "Soon", "Done", "No date")
+
+	now := time.Now()
+	tl := todo.NewTodoList(path)
+	later, soon := now.Add(2*time.Hour), now.Add(-time.Hour)
+	tl.SetDue(0, &later)
+	tl.SetDue(1, &soon)
+	tl.SetDue(2, &soon)
+	tl.Toggle(2)
+
+	idx := Open("")
+	idx.Refresh(dir)
+	got := idx.DueBefore(now.Add(3 * time.Hour))
+	if len(got) != 2 || got[0].Title != "Soon" || got[1].Title != "Later" {
+		t.Errorf("Unexpected due todos: %v", titlesOf(got))
+	}
+	if got := idx.DueBefore(now); len(got) != 1 || got[0].Title != "Soon" {
+		t.Errorf("Expected only the overdue todo, got %v", titlesOf(got))
+	}
+
+	start := now.Add(3 * time.Hour)
+	tl.SetStart(0, &start) // Later
+	idx.Refresh(dir)
+	if got := idx.DueBefore(now.Add(3 * time.Hour)); len(got) != 1 || got[0].Title != "Soon" {
+		t.Errorf("Expected the todo deferred past the cutoff left out, got %v", titlesOf(got))
+	}
+	if err := idx.Save(); err != nil {
+		t.Errorf("Expected in-memory index to skip saving, got %v", err)
+	}
+}
+
+// TestPhrases tests completing a new todo from the titles and contexts already used
+func TestPhrases(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, filepath.Join(dir, "home.json"), "Water the plants @home", "Pay water bill @home", "water the plants @HOME")
+	writeList(t, filepath.Join(dir, "work.json"), "Plan sprint @office", "Pay water bill")
+
+	idx := Open("")
+	idx.Refresh(dir)
+	got := idx.Phrases("wat", 0)
+	if want := []string{"Water the plants @home", "Pay water bill @home", "Pay water bill"}; !slices.Equal(got, want) {
+		t.Errorf("Phrases(wat) = %v, want %v", got, want)
+	}
+	if got := idx.Phrases("pay water bill", 0); !slices.Equal(got, []string{"Pay water bill @home"}) {
+		t.Errorf("Expected the typed title left out, got %v", got)
+	}
+	if got := idx.Phrases("wat", 1); len(got) != 1 {
+		t.Errorf("Expected the limit applied, got %v", got)
+	}
+	if got := idx.Contexts(); !slices.Equal(got, []string{"home", "office"}) {
+		t.Errorf("Contexts() = %v", got)
+	}
+}
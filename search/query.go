@@ -0,0 +1,430 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"justdoit/todo"
+)
+
+// Query is a parsed search expression. Words match the start of title words,
+// "quoted phrases" match anywhere in the title, and operators match other parts of a todo:
+//
+//	is:open is:done is:flagged is:overdue is:deferred is:actionable
+//	tag:home (or @home)
+//	due:today due:overdue due:none due:any due:<7d due:>2h
+//	key:value for custom fields, e.g. priority:high (key: alone for any value)
+//
+// Terms are joined by AND, which may be left out, and OR, which binds looser.
+// NOT or a leading - negates a term, and parentheses group.
+type Query struct {
+	root  node
+	plain bool // only words, so the index can answer it
+	regex bool // a regular expression over titles rather than a query
+	text  string
+}
+
+// node is a part of a parsed query
+type node interface {
+	match(t todo.Todo, now time.Time) bool
+}
+
+type (
+	andNode []node
+	orNode  []node
+	notNode struct{ node }
+	// matchNode is a single term
+	matchNode func(t todo.Todo, now time.Time) bool
+)
+
+func (n andNode) match(t todo.Todo, now time.Time) bool {
+	for _, c := range n {
+		if !c.match(t, now) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n orNode) match(t todo.Todo, now time.Time) bool {
+	for _, c := range n {
+		if c.match(t, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notNode) match(t todo.Todo, now time.Time) bool {
+	return !n.node.match(t, now)
+}
+
+func (f matchNode) match(t todo.Todo, now time.Time) bool {
+	return f(t, now)
+}
+
+// ParseQuery parses a search expression
+func ParseQuery(text string) (Query, error) {
+	p := &parser{tokens: lex(text), plain: true}
+	if len(p.tokens) == 0 {
+		return Query{}, fmt.Errorf("empty query")
+	}
+	root, err := p.or()
+	if err != nil {
+		return Query{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Query{}, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return Query{root: root, plain: p.plain, text: text}, nil
+}
+
+// ParseRegexp makes a query matching titles against a regular expression in RE2 syntax,
+// e.g. \bJIRA-\d+\b. Matching is case-sensitive unless the pattern starts with (?i).
+func ParseRegexp(pattern string) (Query, error) {
+	if pattern == "" {
+		return Query{}, fmt.Errorf("empty pattern")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Query{}, fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+	root := matchNode(func(t todo.Todo, _ time.Time) bool {
+		return re.MatchString(t.Title)
+	})
+	return Query{root: root, regex: true, text: pattern}, nil
+}
+
+// Compile parses text as a regular expression when regex is set, or as a query otherwise
+func Compile(text string, regex bool) (Query, error) {
+	if regex {
+		return ParseRegexp(text)
+	}
+	return ParseQuery(text)
+}
+
+// Match reports whether a todo matches the query at now. Headings never match.
+func (q Query) Match(t todo.Todo, now time.Time) bool {
+	return q.root != nil && !t.Heading && q.root.match(t, now)
+}
+
+// Plain reports whether the query is only words, which Index.Search answers from the index
+func (q Query) Plain() bool {
+	return q.plain
+}
+
+// Regexp reports whether the query is a regular expression
+func (q Query) Regexp() bool {
+	return q.regex
+}
+
+// String returns the query as it was written
+func (q Query) String() string {
+	return q.text
+}
+
+// Find returns the todos in the given lists matching q, ordered by file, then list order.
+// Up to limit hits are returned, all of them when limit is 0.
+func Find(q Query, paths []string, now time.Time, limit int) []Hit {
+	paths = append([]string(nil), paths...)
+	sort.Strings(paths)
+
+	var hits []Hit
+	for _, path := range paths {
+		tl := todo.NewTodoList(path)
+		for _, t := range tl.Todos {
+			if !q.Match(t, now) {
+				continue
+			}
+			hit := Hit{File: path, ID: t.ID, Title: t.Title}
+			if !t.Completed {
+				hit.Due = t.Due
+			}
+			hits = append(hits, hit)
+			if limit > 0 && len(hits) == limit {
+				return hits
+			}
+		}
+	}
+	return hits
+}
+
+// lex splits a query into words, quoted phrases (kept with their quotes) and parentheses
+func lex(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			word.WriteRune(r)
+			if quoted {
+				flush()
+			}
+			quoted = !quoted
+		case quoted:
+			word.WriteRune(r)
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// parser reads tokens by recursive descent: OR of ANDs of (possibly negated) terms
+type parser struct {
+	tokens []string
+	pos    int
+	plain  bool
+}
+
+// peek returns the next token, or "" at the end
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// missingTerm reports a term missing before the next token
+func (p *parser) missingTerm() error {
+	if p.peek() == "" {
+		return fmt.Errorf("query ends too early")
+	}
+	return fmt.Errorf("missing term before %s", p.peek())
+}
+
+func (p *parser) or() (node, error) {
+	first, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	nodes := orNode{first}
+	for p.peek() == "OR" {
+		p.pos++
+		p.plain = false
+		next, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+func (p *parser) and() (node, error) {
+	var nodes andNode
+	for {
+		switch p.peek() {
+		case "", ")", "OR":
+			if len(nodes) == 0 {
+				return nil, p.missingTerm()
+			}
+			return nodes, nil
+		case "AND":
+			if len(nodes) == 0 {
+				return nil, p.missingTerm()
+			}
+			p.pos++
+			p.plain = false
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	token := p.peek()
+	switch {
+	case token == "" || token == ")" || token == "OR" || token == "AND":
+		return nil, p.missingTerm()
+	case token == "NOT":
+		p.pos++
+		p.plain = false
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case token == "(":
+		p.pos++
+		p.plain = false
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case len(token) > 1 && token[0] == '-':
+		p.tokens[p.pos] = token[1:]
+		p.plain = false
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+	p.pos++
+	return p.term(token)
+}
+
+// term parses a word, phrase or operator
+func (p *parser) term(token string) (node, error) {
+	if len(token) >= 2 && strings.HasPrefix(token, `"`) && strings.HasSuffix(token, `"`) {
+		p.plain = false
+		phrase := strings.ToLower(token[1 : len(token)-1])
+		return matchNode(func(t todo.Todo, _ time.Time) bool {
+			return strings.Contains(strings.ToLower(t.Title), phrase)
+		}), nil
+	}
+	if context, ok := strings.CutPrefix(token, "@"); ok && context != "" {
+		p.plain = false
+		return contextTerm(context), nil
+	}
+
+	key, value, ok := strings.Cut(token, ":")
+	if !ok || key == "" {
+		words := uniqueTokens(token)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("nothing to search for in %q", token)
+		}
+		return matchNode(func(t todo.Todo, _ time.Time) bool {
+			return hasWords(t.Title, words)
+		}), nil
+	}
+
+	p.plain = false
+	switch strings.ToLower(key) {
+	case "is":
+		return isTerm(strings.ToLower(value))
+	case "tag", "context":
+		if value == "" {
+			return nil, fmt.Errorf("tag: needs a name")
+		}
+		return contextTerm(value), nil
+	case "due":
+		return dueTerm(strings.ToLower(value))
+	case "by":
+		if value == "" {
+			return nil, fmt.Errorf("by: needs an author")
+		}
+		return matchNode(func(t todo.Todo, _ time.Time) bool {
+			return t.ByAuthor(value)
+		}), nil
+	}
+	filter := key
+	if value != "" {
+		filter += "=" + value
+	}
+	if !todo.ValidFieldFilter(filter) {
+		return nil, fmt.Errorf("invalid field %q", key)
+	}
+	return matchNode(func(t todo.Todo, _ time.Time) bool {
+		return t.MatchesField(filter)
+	}), nil
+}
+
+// contextTerm matches todos tagged with an @context
+func contextTerm(context string) node {
+	context = strings.ToLower(context)
+	return matchNode(func(t todo.Todo, _ time.Time) bool {
+		return t.HasContext(context)
+	})
+}
+
+// isTerm matches todos by state
+func isTerm(state string) (node, error) {
+	switch state {
+	case "open":
+		return matchNode(func(t todo.Todo, _ time.Time) bool { return !t.Completed }), nil
+	case "done", "completed":
+		return matchNode(func(t todo.Todo, _ time.Time) bool { return t.Completed }), nil
+	case "flagged":
+		return matchNode(func(t todo.Todo, _ time.Time) bool { return t.Flagged }), nil
+	case "overdue":
+		return matchNode(func(t todo.Todo, now time.Time) bool {
+			return !t.Completed && t.Due != nil && t.Due.Before(now)
+		}), nil
+	case "deferred":
+		return matchNode(func(t todo.Todo, now time.Time) bool { return t.Deferred(now) }), nil
+	case "actionable":
+		return matchNode(func(t todo.Todo, now time.Time) bool { return !t.Completed && !t.Deferred(now) }), nil
+	}
+	return nil, fmt.Errorf("unknown is:%s (use open, done, flagged, overdue, deferred or actionable)", state)
+}
+
+// dueTerm matches todos by due date
+func dueTerm(value string) (node, error) {
+	switch value {
+	case "none":
+		return matchNode(func(t todo.Todo, _ time.Time) bool { return t.Due == nil }), nil
+	case "any":
+		return matchNode(func(t todo.Todo, _ time.Time) bool { return t.Due != nil }), nil
+	case "overdue":
+		return isTerm("overdue")
+	case "today":
+		return matchNode(func(t todo.Todo, now time.Time) bool {
+			return t.Due != nil && t.Due.Before(todo.Tomorrow(now)) && !t.Due.Before(todo.Midnight(now))
+		}), nil
+	}
+
+	before := strings.HasPrefix(value, "<")
+	if !before && !strings.HasPrefix(value, ">") {
+		return nil, fmt.Errorf("unknown due:%s (use today, overdue, none, any, <7d or >7d)", value)
+	}
+	within, err := todo.ParseOffset(value[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid due:%s: %w", value, err)
+	}
+	return matchNode(func(t todo.Todo, now time.Time) bool {
+		if t.Due == nil {
+			return false
+		}
+		if before {
+			return t.Due.Before(now.Add(within))
+		}
+		return t.Due.After(now.Add(within))
+	}), nil
+}
+
+// hasWords reports whether every word starts a word of the title
+func hasWords(title string, words []string) bool {
+	tokens := uniqueTokens(title)
+	for _, word := range words {
+		found := false
+		for _, token := range tokens {
+			if strings.HasPrefix(token, word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package search
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"justdoit/todo"
+)
+
+// TestQuery tests operators, boolean logic and grouping
+func TestQuery(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	todos := []todo.Todo{
+		{Title: "Call the plumber @home", Due: at(-1)},
+		{Title: "Buy milk @home @errands", Completed: true, AddedBy: "Sam", CompletedBy: "Alex"},
+		{Title: "Plan sprint", Due: at(3), Fields: map[string]string{"priority": "high"}, AddedBy: "alex"},
+		{Title: "Fix JIRA-42 login bug", Flagged: true, Due: at(10)},
+		{Title: "Review billing", Fields: map[string]string{"priority": "low"}, Start: at(2)},
+		{Title: "Home office", Heading: true},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"plumb", []string{"Call the plumber @home"}},
+		{"is:open tag:home", []string{"Call the plumber @home"}},
+		{"@home", []string{"Call the plumber @home", "Buy milk @home @errands"}},
+		{"is:done", []string{"Buy milk @home @errands"}},
+		{"due:<7d", []string{"Call the plumber @home", "Plan sprint"}},
+		{"due:>7d", []string{"Fix JIRA-42 login bug"}},
+		{"is:overdue", []string{"Call the plumber @home"}},
+		{"is:deferred", []string{"Review billing"}},
+		{"is:actionable", []string{"Call the plumber @home", "Plan sprint", "Fix JIRA-42 login bug"}},
+		{"due:none is:open", []string{"Review billing"}},
+		{"priority:high", []string{"Plan sprint"}},
+		{"priority:", []string{"Plan sprint", "Review billing"}},
+		{"is:flagged OR priority:high", []string{"Plan sprint", "Fix JIRA-42 login bug"}},
+		{"is:open AND NOT (tag:home OR priority:high)", []string{"Fix JIRA-42 login bug", "Review billing"}},
+		{"-@home is:open due:any", []string{"Plan sprint", "Fix JIRA-42 login bug"}},
+		{`"jira-42 log"`, []string{"Fix JIRA-42 login bug"}},
+		{"jira-42", []string{"Fix JIRA-42 login bug"}},
+		{"office", nil},
+		{"by:alex", []string{"Buy milk @home @errands", "Plan sprint"}},
+		{"by:sam is:done", []string{"Buy milk @home @errands"}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, todo := range todos {
+			if q.Match(todo, now) {
+				got = append(got, todo.Title)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+}
+
+// TestQueryErrors tests that malformed queries are rejected
+func TestQueryErrors(t *testing.T) {
+	for _, query := range []string{"", "a OR", "(a b", "a)", "is:maybe", "due:soon", "due:<x", "AND a", "tag:", "by:", "a b!:c"} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("Expected %q to be rejected", query)
+		}
+	}
+}
+
+// TestQueryPlain tests which queries the index can answer
+func TestQueryPlain(t *testing.T) {
+	for query, want := range map[string]bool{"call plumber": true, "call AND plumber": false, "call OR plumber": false, "is:open": false, "-call": false} {
+		if q, _ := ParseQuery(query); q.Plain() != want {
+			t.Errorf("%q: expected plain %v", query, want)
+		}
+	}
+}
+
+// TestFind tests evaluating a query across lists
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, filepath.Join(dir, "work.json"), "Ship release @office", "Write notes")
+	writeList(t, filepath.Join(dir, "home.json"), "Clean @home", "Call office @phone")
+
+	q, _ := ParseQuery("@office OR office")
+	paths := []string{filepath.Join(dir, "work.json"), filepath.Join(dir, "home.json")}
+	hits := Find(q, paths, time.Now(), 0)
+	if want := []string{"Call office @phone", "Ship release @office"}; !slices.Equal(titlesOf(hits), want) {
+		t.Errorf("Expected %v, got %v", want, titlesOf(hits))
+	}
+	if hits := Find(q, paths, time.Now(), 1); len(hits) != 1 {
+		t.Errorf("Expected the limit to apply, got %d hits", len(hits))
+	}
+}
+
+// TestParseRegexp tests regular expression queries and their compile errors
+func TestParseRegexp(t *testing.T) {
+	q, err := Compile(`\bJIRA-\d+\b`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for title, want := range map[string]bool{"Fix JIRA-42 login": true, "JIRA-x": false, "NOJIRA-1": false, "jira-7": false} {
+		if got := q.Match(todo.Todo{Title: title}, time.Now()); got != want {
+			t.Errorf("%q: expected %v", title, want)
+		}
+	}
+	if !q.Regexp() || q.Plain() {
+		t.Error("Expected a regex query the index can't answer")
+	}
+
+	if _, err := Compile("(JIRA", true); err == nil || strings.HasPrefix(err.Error(), "error parsing") {
+		t.Errorf("Expected a short compile error, got %v", err)
+	}
+	if _, err := Compile("", true); err == nil {
+		t.Error("Expected an empty pattern to be rejected")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"justdoit/todo"
+)
+
+// runShare serves a read-only page of a list on the local network that reloads
+// itself, so others can follow along in a browser
+func runShare(args []string) error {
+	fs := flag.NewFlagSet("share", flag.ContinueOnError)
+	port := fs.Int("port", 9090, "Port to listen on")
+	host := fs.String("host", "", "Address to listen on (default: every interface, so the LAN can reach it)")
+	refresh := fs.Duration("refresh", 5*time.Second, "How often the page reloads")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// The list may come before the flags, as in "share moving --port 9090"
+	name := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[min(1, fs.NArg()):]); err != nil {
+		return err
+	}
+	if name == "" || fs.NArg() != 0 {
+		return fmt.Errorf("usage: justdoit share <file> [--port 9090] [--host addr] [--refresh 5s]")
+	}
+
+	todoDir, _ := dataDirs()
+	path, err := listPath(todoDir, name)
+	if err != nil {
+		return err
+	}
+	todo.SetSafe(true) // loading the list for each request never writes it
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		var page bytes.Buffer
+		if err := todo.WriteLiveHTML(&page, path, *refresh); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(page.Bytes())
+	})
+	server := &http.Server{
+		Addr:              net.JoinHostPort(*host, strconv.Itoa(*port)),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+
+	url := *host
+	if url == "" {
+		url = lanAddress()
+	}
+	fmt.Printf("Sharing %s read-only at http://%s (Ctrl+C to stop)\n", filepath.Base(path),
+		net.JoinHostPort(url, strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// lanAddress returns an address others on the local network can reach this machine
+// at, or localhost when it has none
+func lanAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "localhost"
+	}
+	for _, addr := range addrs {
+		if ip, ok := addr.(*net.IPNet); ok && ip.IP.To4() != nil && !ip.IP.IsLoopback() && !ip.IP.IsLinkLocalUnicast() {
+			return ip.IP.String()
+		}
+	}
+	return "localhost"
+}
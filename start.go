@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"justdoit/todo"
+	"justdoit/ui"
+)
+
+// startList is the list the TUI opens with, and the todo the cursor starts on
+type startList struct {
+	file   string // "" opens the first list
+	todoID int    // 0 starts at the top
+	create bool   // file doesn't exist yet and is created
+}
+
+// resolveStart checks the list named on the command line, with or without .json.
+// A list that doesn't exist is only accepted with create set.
+func resolveStart(todoDir, name string, create bool, todoID int) (startList, error) {
+	if name == "" {
+		return startList{todoID: todoID}, nil
+	}
+	file := listFilename(name)
+	if strings.ContainsAny(file, `/\`) || ui.IsHiddenFile(file) {
+		return startList{}, fmt.Errorf("%q isn't a list in %s", name, todoDir)
+	}
+	if _, err := os.Stat(filepath.Join(todoDir, file)); err == nil {
+		return startList{file: file, todoID: todoID}, nil
+	}
+	if !create {
+		return startList{}, fmt.Errorf("list %q not found, add --create to start it", name)
+	}
+	if err := todo.ValidateListName(strings.TrimSuffix(file, ".json")); err != nil {
+		return startList{}, fmt.Errorf("can't create %q: %v", name, err)
+	}
+	return startList{file: file, todoID: todoID, create: true}, nil
+}
+
+// place opens the start list in the model and puts the cursor on its todo
+func (s startList) place(m *ui.Model) {
+	if s.file != "" {
+		m.ActivePanel = ui.TodoPanel
+		for i, f := range m.Files {
+			if f == s.file {
+				m.FileCursor = i
+			}
+		}
+	}
+	if s.todoID == 0 {
+		return
+	}
+	if i := m.TodoList.IndexOf(s.todoID); i >= 0 {
+		m.ActivePanel = ui.TodoPanel
+		m.TodoCursor = i
+	} else {
+		m.Notify(ui.SeverityWarning, fmt.Sprintf("No todo #%d in %s", s.todoID, m.CurrentFile))
+	}
+}
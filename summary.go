@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// runSummary posts a summary of open, overdue and completed todos to the configured webhook
+func runSummary(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Print the request instead of sending it")
+	url := fs.String("url", "", "Webhook URL (default: webhook.url from the config)")
+	top := fs.Int("top", 0, "Most pressing todos to list (default: webhook.top from the config, or 5)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	hook := config.Webhook{Top: 5}
+	if cfg.Webhook != nil {
+		hook = *cfg.Webhook
+		if hook.Top <= 0 {
+			hook.Top = 5
+		}
+	}
+	if *url != "" {
+		hook.URL = *url
+	}
+	if *top > 0 {
+		hook.Top = *top
+	}
+	if hook.URL == "" && !*dryRun {
+		return fmt.Errorf("no webhook configured, set webhook.url in %s or pass --url", config.Path())
+	}
+
+	todoDir, _ := dataDirs()
+	paths := listPaths(todoDir)
+	now := time.Now()
+	text := todo.Summarize(paths, now, hook.Top).Text(now)
+
+	body, err := webhookPayload(hook, text)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		target := hook.URL
+		if target == "" {
+			target = "(no webhook.url set)"
+		}
+		fmt.Printf("POST %s\n%s\n", target, body)
+		return nil
+	}
+	return postWebhook(hook.URL, body)
+}
+
+// webhookPayload wraps the message in the JSON body Slack or Discord expects
+func webhookPayload(hook config.Webhook, text string) ([]byte, error) {
+	kind := hook.Kind
+	if kind == "" {
+		kind = config.WebhookSlack
+		if strings.Contains(hook.URL, "discord.com/") || strings.Contains(hook.URL, "discordapp.com/") {
+			kind = config.WebhookDiscord
+		}
+	}
+	switch kind {
+	case config.WebhookSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case config.WebhookDiscord:
+		return json.Marshal(map[string]string{"content": text})
+	}
+	return nil, fmt.Errorf("unknown webhook kind %q (use slack or discord)", kind)
+}
+
+// postWebhook sends the payload, failing on any non-2xx response
+func postWebhook(url string, body []byte) error {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
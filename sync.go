@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"justdoit/config"
+	"justdoit/remote"
+)
+
+// runSync syncs the data directory with the configured server, or settles a conflict
+func runSync(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	resolve := fs.String("resolve", "", "Keep the local version of a conflicting file, e.g. work.json, and push it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return err
+	}
+	if cfg.Remote == nil {
+		return fmt.Errorf("no remote configured, set remote.host and remote.path in %s", config.Path())
+	}
+	if *resolve != "" {
+		todoDir, _ := dataDirs()
+		if err := remote.Resolve(todoDir, *resolve); err != nil {
+			return err
+		}
+	}
+
+	summary, err := syncRemote(cfg)
+	if err != nil {
+		return err
+	}
+	if summary == "" {
+		summary = "Already in sync"
+	}
+	fmt.Println(summary)
+	return nil
+}
+
+// syncRemote syncs the data directory with the server from the config, returning
+// a one-line summary, or "" when there is no remote or nothing changed
+func syncRemote(cfg config.Config) (string, error) {
+	if cfg.Remote == nil {
+		return "", nil
+	}
+	todoDir, _ := dataDirs()
+	os.MkdirAll(todoDir, 0755)
+	res, err := remote.Sync(todoDir, remote.NewSFTP(*cfg.Remote))
+	if err != nil {
+		return "", err
+	}
+	if len(res.Pulled)+len(res.Pushed)+len(res.Conflicts) == 0 {
+		return "", nil
+	}
+	return res.String(), nil
+}
@@ -0,0 +1,35 @@
+package todo
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetAuthor sets the name recorded on the todos added and completed from now on, so
+// people sharing a synced list can see who did what. "" records none.
+func (tl *TodoList) SetAuthor(name string) {
+	tl.author = strings.TrimSpace(name)
+}
+
+// Author returns who completed the todo, or else who added it, "" when not recorded
+func (t Todo) Author() string {
+	if t.Completed && t.CompletedBy != "" {
+		return t.CompletedBy
+	}
+	return t.AddedBy
+}
+
+// ByAuthor reports whether name added or completed the todo, ignoring case
+func (t Todo) ByAuthor(name string) bool {
+	return name != "" && (strings.EqualFold(t.AddedBy, name) || strings.EqualFold(t.CompletedBy, name))
+}
+
+// Initial returns the uppercase first letter of an author's name, "" for no name
+func Initial(name string) string {
+	r, _ := utf8.DecodeRuneInString(strings.TrimSpace(name))
+	if r == utf8.RuneError {
+		return ""
+	}
+	return string(unicode.ToUpper(r))
+}
@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestAuthor tests that the author is recorded on the todos added and completed
+func TestAuthor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moving.json")
+	tl := NewTodoList(path)
+	tl.Add("book van")
+	tl.SetAuthor(" Sam ")
+	tl.Add("pack kitchen")
+	if tl.Todos[1].AddedBy != "" || tl.Todos[0].AddedBy != "Sam" {
+		t.Fatalf("Expected only the todo added since SetAuthor attributed, got %+v", tl.Todos)
+	}
+
+	tl.SetAuthor("Alex")
+	tl.Toggle(tl.IndexOf(1))
+	van := tl.Todos[tl.IndexOf(1)]
+	if van.CompletedBy != "Alex" || van.Author() != "Alex" || !van.ByAuthor("alex") || van.ByAuthor("sam") {
+		t.Errorf("Expected the completion attributed, got %+v", van)
+	}
+	if got := NewTodoList(path).Todos[tl.IndexOf(1)].CompletedBy; got != "Alex" {
+		t.Errorf("Expected the author saved, got %q", got)
+	}
+
+	tl.Toggle(tl.IndexOf(1))
+	if van := tl.Todos[tl.IndexOf(1)]; van.CompletedBy != "" || van.Author() != "" {
+		t.Errorf("Expected reopening to clear who completed it, got %+v", van)
+	}
+	if got := tl.Todos[tl.IndexOf(2)].Author(); got != "Sam" {
+		t.Errorf("Expected an open todo's author to be who added it, got %q", got)
+	}
+
+	for name, want := range map[string]string{"sam": "S", " émile": "É", "": ""} {
+		if got := Initial(name); got != want {
+			t.Errorf("Initial(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -182,6 +183,71 @@ func benchmarkAddTodo(b *testing.B, numTodos int) {
 	}
 }
 
+// BenchmarkInsertTodo tests inserting todos in the middle of a list
+func BenchmarkInsertTodo_Large(b *testing.B) {
+	benchmarkInsertTodo(b, 10000)
+}
+
+func BenchmarkInsertTodo_VeryLarge(b *testing.B) {
+	benchmarkInsertTodo(b, 100000)
+}
+
+// BenchmarkAddTodoDeferred tests adding todos without the save, isolating the slice cost
+func BenchmarkAddTodoDeferred_VeryLarge(b *testing.B) {
+	tl := generateLargeTodoList(100000)
+	tl.SetDeferredSave(true)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		tl.Add("New benchmark todo item")
+	}
+}
+
+// benchmarkInsertTodo defers saving so only the slice and sort cost is measured
+func benchmarkInsertTodo(b *testing.B, numTodos int) {
+	tl := generateLargeTodoList(numTodos)
+	for i := 0; i < numTodos; i += 100 {
+		tl.Todos[i].Heading = true
+		tl.Todos[i].Completed = false
+	}
+	tl.SetDeferredSave(true)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		tl.Insert(numTodos/2, "New benchmark todo item")
+	}
+}
+
+// BenchmarkLoadRepeatedTitles tests the retained heap of a list with many repeated titles
+func BenchmarkLoadRepeatedTitles(b *testing.B) {
+	tl := generateLargeTodoList(100000)
+	for i := range tl.Todos {
+		tl.Todos[i].Title = fmt.Sprintf("Recurring chore number %d with some descriptive text", i%50)
+	}
+	tl.filepath = filepath.Join(b.TempDir(), "repeated.json")
+	if err := tl.Save(); err != nil {
+		b.Fatalf("Save failed: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	var before, after runtime.MemStats
+	var kept *TodoList
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		kept = NewTodoList(tl.filepath)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+	}
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(len(kept.Todos)), "heap-B/todo")
+}
+
 // BenchmarkToggleTodo tests toggling completion status
 func BenchmarkToggleTodo_Small(b *testing.B) {
 	benchmarkToggleTodo(b, 100)
@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"sort"
+	"time"
+)
+
+// Breakdown groups
+const (
+	GroupContext  = "context"  // Key is an @context without the @
+	GroupField    = "field"    // Key is a key=value custom field
+	GroupPriority = "priority" // Key is "flagged"
+)
+
+// Breakdown counts the todos sharing a context, custom field or flag
+type Breakdown struct {
+	Group     string
+	Key       string
+	Open      int
+	Completed int
+	OpenAge   time.Duration // average age of the open todos, 0 if there are none
+}
+
+// Breakdowns groups the todos by context, custom field and flag, each group sorted by key.
+// A todo counts once in every group it belongs to; headings aren't counted.
+func (tl *TodoList) Breakdowns(now time.Time) []Breakdown {
+	rows := map[[2]string]*Breakdown{}
+	ages := map[[2]string]time.Duration{}
+	add := func(group, key string, t Todo) {
+		id := [2]string{group, key}
+		b := rows[id]
+		if b == nil {
+			b = &Breakdown{Group: group, Key: key}
+			rows[id] = b
+		}
+		if t.Completed {
+			b.Completed++
+			return
+		}
+		b.Open++
+		ages[id] += now.Sub(t.CreatedAt)
+	}
+
+	for _, t := range tl.Todos {
+		if t.Heading {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, c := range Contexts(t.Title) {
+			if !seen[c] {
+				seen[c] = true
+				add(GroupContext, c, t)
+			}
+		}
+		for _, k := range t.FieldKeys() {
+			add(GroupField, k+"="+t.Fields[k], t)
+		}
+		if t.Flagged {
+			add(GroupPriority, "flagged", t)
+		}
+	}
+
+	order := map[string]int{GroupContext: 0, GroupField: 1, GroupPriority: 2}
+	breakdowns := make([]Breakdown, 0, len(rows))
+	for id, b := range rows {
+		if b.Open > 0 {
+			b.OpenAge = ages[id] / time.Duration(b.Open)
+		}
+		breakdowns = append(breakdowns, *b)
+	}
+	sort.Slice(breakdowns, func(i, j int) bool {
+		a, b := breakdowns[i], breakdowns[j]
+		if a.Group != b.Group {
+			return order[a.Group] < order[b.Group]
+		}
+		return a.Key < b.Key
+	})
+	return breakdowns
+}
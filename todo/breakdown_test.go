@@ -0,0 +1,37 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBreakdowns tests counts and open ages grouped by context, field and flag
+func TestBreakdowns(t *testing.T) {
+	now := time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tl := &TodoList{Todos: []Todo{
+		{Title: "Shopping @home", Heading: true},
+		{Title: "Call plumber @home @phone", CreatedAt: now.Add(-2 * day), Flagged: true},
+		{Title: "Fix sink @home @Home", CreatedAt: now.Add(-4 * day), Fields: map[string]string{"client": "acme"}},
+		{Title: "Invoice @office", CreatedAt: now.Add(-9 * day), Completed: true, Fields: map[string]string{"client": "acme", "ticket": "7"}},
+		{Title: "No tags", CreatedAt: now},
+	}}
+
+	want := []Breakdown{
+		{Group: GroupContext, Key: "home", Open: 2, OpenAge: 3 * day},
+		{Group: GroupContext, Key: "office", Completed: 1},
+		{Group: GroupContext, Key: "phone", Open: 1, OpenAge: 2 * day},
+		{Group: GroupField, Key: "client=acme", Open: 1, Completed: 1, OpenAge: 4 * day},
+		{Group: GroupField, Key: "ticket=7", Completed: 1},
+		{Group: GroupPriority, Key: "flagged", Open: 1, OpenAge: 2 * day},
+	}
+	got := tl.Breakdowns(now)
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d rows, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Row %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
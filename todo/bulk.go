@@ -0,0 +1,235 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bulkID matches the {#id} a bulk text line ends with
+var bulkID = regexp.MustCompile(`\s*\{#(\d+)\}$`)
+
+// BulkLine is a todo line of an edited bulk text
+type BulkLine struct {
+	ID        int // 0 for a line added in the editor
+	Title     string
+	Completed bool
+}
+
+// BulkResult counts what ApplyBulk changed
+type BulkResult struct {
+	Added, Updated, Deleted int
+}
+
+// BulkPreview lists what ApplyBulk would change, worked out without changing the list
+type BulkPreview struct {
+	Added   []BulkLine   // lines added in the editor
+	Updated []BulkUpdate // listed todos whose lines were edited
+	Deleted []Todo       // listed todos whose lines were deleted, if they're deleted
+}
+
+// BulkUpdate is a todo and the edited line it is updated from
+type BulkUpdate struct {
+	Before Todo
+	After  BulkLine
+}
+
+// BulkText writes todos as a Markdown checklist for editing in a text editor,
+// each line ending with the todo's {#id}. Headings are left out.
+func BulkText(name string, todos []Todo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: edit titles, check [x] or uncheck [ ], add lines for new todos\n", name)
+	b.WriteString("# and delete lines to delete todos. Keep the {#id} at the end of each line.\n\n")
+	for _, t := range todos {
+		if t.Heading {
+			continue
+		}
+		box := "[ ]"
+		if t.Completed {
+			box = "[x]"
+		}
+		fmt.Fprintf(&b, "- %s %s {#%d}\n", box, t.Title, t.ID)
+	}
+	return b.String()
+}
+
+// ParseBulk reads back an edited bulk text. Blank lines and lines starting with #
+// are skipped; the "- " and checkbox of new lines are optional.
+func ParseBulk(text string) ([]BulkLine, error) {
+	var lines []BulkLine
+	seen := map[int]bool{}
+	for n, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var bl BulkLine
+		if m := bulkID.FindStringSubmatch(line); m != nil {
+			bl.ID, _ = strconv.Atoi(m[1])
+			if seen[bl.ID] {
+				return nil, fmt.Errorf("line %d: #%d appears twice", n+1, bl.ID)
+			}
+			seen[bl.ID] = true
+			line = line[:len(line)-len(m[0])]
+		}
+		line = strings.TrimPrefix(line, "- ")
+		switch {
+		case strings.HasPrefix(line, "[x]"), strings.HasPrefix(line, "[X]"):
+			bl.Completed = true
+			line = line[3:]
+		case strings.HasPrefix(line, "[ ]"):
+			line = line[3:]
+		}
+		bl.Title = strings.TrimSpace(line)
+		if bl.Title == "" {
+			return nil, fmt.Errorf("line %d: empty title", n+1)
+		}
+		lines = append(lines, bl)
+	}
+	return lines, nil
+}
+
+// BulkRemoved returns the listed IDs whose lines were deleted from the bulk text
+func BulkRemoved(listed []int, lines []BulkLine) []int {
+	kept := map[int]bool{}
+	for _, l := range lines {
+		kept[l.ID] = true
+	}
+	var removed []int
+	for _, id := range listed {
+		if !kept[id] {
+			removed = append(removed, id)
+		}
+	}
+	return removed
+}
+
+// checkBulk makes sure an edited bulk text only refers to the listed todos
+func checkBulk(listed []int, lines []BulkLine) error {
+	for _, l := range lines {
+		if l.ID != 0 && !slices.Contains(listed, l.ID) {
+			return fmt.Errorf("#%d is not one of the edited todos", l.ID)
+		}
+	}
+	return nil
+}
+
+// PreviewBulk returns what ApplyBulk would change with the same arguments
+func (tl *TodoList) PreviewBulk(listed []int, lines []BulkLine, deleteRemoved bool) (BulkPreview, error) {
+	if err := checkBulk(listed, lines); err != nil {
+		return BulkPreview{}, err
+	}
+	var p BulkPreview
+	for _, l := range lines {
+		i := tl.IndexOf(l.ID)
+		switch {
+		case l.ID == 0:
+			p.Added = append(p.Added, l)
+		case i >= 0 && (tl.Todos[i].Title != l.Title || tl.Todos[i].Completed != l.Completed):
+			p.Updated = append(p.Updated, BulkUpdate{tl.Todos[i], l})
+		}
+	}
+	if deleteRemoved {
+		for _, id := range BulkRemoved(listed, lines) {
+			if i := tl.IndexOf(id); i >= 0 {
+				p.Deleted = append(p.Deleted, tl.Todos[i])
+			}
+		}
+	}
+	return p, nil
+}
+
+// ApplyBulk applies an edited bulk text made from the todos with the listed IDs.
+// New lines are inserted below the line above them, or above the first listed todo.
+// Removed lines delete their todos only when deleteRemoved is set.
+func (tl *TodoList) ApplyBulk(listed []int, lines []BulkLine, deleteRemoved bool) (BulkResult, error) {
+	if err := checkBulk(listed, lines); err != nil {
+		return BulkResult{}, err
+	}
+
+	var result BulkResult
+	var events []Event // published once the edit is applied
+	now := time.Now()
+	for _, l := range lines {
+		i := tl.IndexOf(l.ID)
+		if l.ID == 0 || i < 0 {
+			continue
+		}
+		t := &tl.Todos[i]
+		changed := false
+		if t.Title != l.Title {
+			t.Title = l.Title
+			tl.fitTitle(t)
+			changed = true
+		}
+		if t.Completed != l.Completed {
+			t.Completed = l.Completed
+			t.CompletedAt, t.CompletedBy = nil, ""
+			if t.Completed {
+				t.CompletedAt, t.CompletedBy = &now, tl.author
+			}
+			if tl.IsHabit() {
+				t.recordHabit(now, t.Completed)
+			}
+			kind := TodoReopened
+			if t.Completed {
+				kind = TodoCompleted
+			}
+			events = append(events, Event{Kind: kind, Todo: *t})
+			changed = true
+		}
+		if changed {
+			result.Updated++
+		}
+	}
+
+	after := 0 // ID of the line above, 0 at the top
+	for _, l := range lines {
+		if l.ID != 0 {
+			after = l.ID
+			continue
+		}
+		at := 0
+		if after != 0 {
+			at = tl.IndexOf(after) + 1
+		} else if len(listed) > 0 {
+			at = max(tl.IndexOf(listed[0]), 0)
+		}
+		t := Todo{ID: tl.NextID, Title: tl.withTags(l.Title), Completed: l.Completed, CreatedAt: now, AddedBy: tl.author}
+		if t.Completed {
+			t.CompletedAt, t.CompletedBy = &now, tl.author
+		}
+		tl.fitTitle(&t)
+		tl.Todos = slices.Insert(tl.Todos, at, t)
+		tl.NextID++
+		after = t.ID
+		result.Added++
+		events = append(events, Event{Kind: TodoAdded, Todo: t})
+	}
+
+	if deleteRemoved {
+		for _, id := range BulkRemoved(listed, lines) {
+			if i := tl.IndexOf(id); i >= 0 {
+				events = append(events, Event{Kind: TodoDeleted, Todo: tl.Todos[i]})
+				tl.Todos = slices.Delete(tl.Todos, i, i+1)
+				result.Deleted++
+			}
+		}
+	}
+
+	if result != (BulkResult{}) {
+		tl.Sort()
+		tl.persist()
+	}
+	for _, e := range events {
+		tl.publish(e.Kind, e.Todo)
+	}
+	if slices.ContainsFunc(events, func(e Event) bool { return e.Kind == TodoCompleted }) {
+		tl.publishIfDone()
+	}
+	return result, nil
+}
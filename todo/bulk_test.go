@@ -0,0 +1,76 @@
+package todo
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestBulkRoundTrip tests editing titles, completion, new lines and deletions through bulk text
+func TestBulkRoundTrip(t *testing.T) {
+	tl := NewTodoList(filepath.Join(t.TempDir(), "work.json"))
+	for _, title := range []string{"ship it", "write docs", "Release", "fix bug"} {
+		tl.Add(title)
+	}
+	tl.Todos[1].Heading = true // "Release"
+	listed := []int{4, 2, 1}   // fix bug, write docs, ship it
+
+	text := BulkText("work", []Todo{tl.Todos[0], tl.Todos[1], tl.Todos[2], tl.Todos[3]})
+	if strings.Contains(text, "Release") || !strings.Contains(text, "- [ ] fix bug {#4}\n") {
+		t.Fatalf("Unexpected bulk text:\n%s", text)
+	}
+
+	edited := strings.NewReplacer(
+		"- [ ] fix bug {#4}", "- [x] fix the bug {#4}\nadd tests",
+		"- [ ] ship it {#1}\n", "",
+	).Replace(text)
+	lines, err := ParseBulk(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed := BulkRemoved(listed, lines); !slices.Equal(removed, []int{1}) {
+		t.Fatalf("Expected ship it removed, got %v", removed)
+	}
+
+	preview, err := tl.PreviewBulk(listed, lines, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Added) != 1 || len(preview.Updated) != 1 || len(preview.Deleted) != 1 ||
+		preview.Updated[0].Before.Title != "fix bug" || preview.Deleted[0].Title != "ship it" || len(tl.Todos) != 4 {
+		t.Errorf("Unexpected preview %+v", preview)
+	}
+
+	result, err := tl.ApplyBulk(listed, lines, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (BulkResult{Added: 1, Updated: 1}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	var titles []string
+	for _, todo := range tl.Todos {
+		titles = append(titles, todo.Title)
+	}
+	if want := []string{"add tests", "fix the bug", "Release", "write docs", "ship it"}; !slices.Equal(titles, want) {
+		t.Errorf("Expected %v, got %v", want, titles)
+	}
+
+	if result, _ := tl.ApplyBulk(listed, lines, true); result.Deleted != 1 || tl.IndexOf(1) >= 0 {
+		t.Errorf("Expected ship it deleted once confirmed, got %+v", result)
+	}
+}
+
+// TestParseBulkErrors tests that ambiguous bulk text is rejected
+func TestParseBulkErrors(t *testing.T) {
+	for _, text := range []string{"- [ ] a {#1}\n- [ ] b {#1}", "- [ ]  {#2}"} {
+		if _, err := ParseBulk(text); err == nil {
+			t.Errorf("Expected %q to be rejected", text)
+		}
+	}
+	tl := &TodoList{Todos: []Todo{{ID: 1, Title: "a"}}}
+	if _, err := tl.ApplyBulk([]int{1}, []BulkLine{{ID: 7, Title: "b"}}, false); err == nil {
+		t.Error("Expected an unknown ID to be rejected")
+	}
+}
@@ -0,0 +1,34 @@
+package todo
+
+import "strings"
+
+// SetCheck sets the shell command that decides whether a todo is done, "" removes it
+func (tl *TodoList) SetCheck(index int, command string) {
+	if index < 0 || index >= len(tl.Todos) || tl.Todos[index].Heading {
+		return
+	}
+	tl.Todos[index].Check = strings.TrimSpace(command)
+	tl.persist()
+}
+
+// Checks returns the commands of the list's check todos by todo ID
+func (tl *TodoList) Checks() map[int]string {
+	checks := map[int]string{}
+	for _, t := range tl.Todos {
+		if t.Check != "" && !t.Heading {
+			checks[t.ID] = t.Check
+		}
+	}
+	return checks
+}
+
+// SetChecked marks the todo with the given ID done or open to match its check
+// and reports whether that changed anything
+func (tl *TodoList) SetChecked(id int, passed bool) bool {
+	i := tl.IndexOf(id)
+	if i < 0 || tl.Todos[i].Completed == passed {
+		return false
+	}
+	tl.Toggle(i)
+	return true
+}
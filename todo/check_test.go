@@ -0,0 +1,34 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckTodos tests that check results mark todos done or open again
+func TestCheckTodos(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.json")
+	tl := NewTodoList(path)
+	tl.Add("Backup ran today")
+	tl.Add("CI green on main")
+	tl.SetCheck(0, "  make ci ")
+	tl.SetCheck(1, "test -f backup.log")
+
+	checks := NewTodoList(path).Checks()
+	if len(checks) != 2 || checks[2] != "make ci" {
+		t.Fatalf("Expected two trimmed check commands, got %v", checks)
+	}
+
+	if !tl.SetChecked(2, true) || tl.SetChecked(2, true) {
+		t.Error("Expected only the first passing result to change the todo")
+	}
+	if todo := NewTodoList(path).Todos[tl.IndexOf(2)]; !todo.Completed {
+		t.Errorf("Expected the passing check saved as done, got %+v", todo)
+	}
+	if !tl.SetChecked(2, false) || tl.Todos[tl.IndexOf(2)].Completed {
+		t.Error("Expected a failing check to reopen the todo")
+	}
+	if tl.SetChecked(99, true) {
+		t.Error("Expected unknown IDs to be ignored")
+	}
+}
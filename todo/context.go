@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// contextPattern matches GTD-style @context tokens in titles
+var contextPattern = regexp.MustCompile(`(?:^|\s)@([\w-]+)`)
+
+// Contexts returns the lowercased @context tokens in a title
+func Contexts(title string) []string {
+	var contexts []string
+	for _, match := range contextPattern.FindAllStringSubmatch(title, -1) {
+		contexts = append(contexts, strings.ToLower(match[1]))
+	}
+	return contexts
+}
+
+// ContextIndexes returns the byte offsets of the @context tokens in a title, each
+// pair spanning the @ and the name
+func ContextIndexes(title string) [][2]int {
+	var spans [][2]int
+	for _, loc := range contextPattern.FindAllStringSubmatchIndex(title, -1) {
+		spans = append(spans, [2]int{loc[2] - 1, loc[3]})
+	}
+	return spans
+}
+
+// HasContext reports whether the todo's title contains the given @context
+func (t Todo) HasContext(context string) bool {
+	for _, c := range Contexts(t.Title) {
+		if c == context {
+			return true
+		}
+	}
+	return false
+}
+
+// AllContexts returns the sorted, de-duplicated contexts used across lists
+func AllContexts(lists ...*TodoList) []string {
+	seen := map[string]bool{}
+	var contexts []string
+	for _, tl := range lists {
+		for _, todo := range tl.Todos {
+			for _, c := range Contexts(todo.Title) {
+				if !seen[c] {
+					seen[c] = true
+					contexts = append(contexts, c)
+				}
+			}
+		}
+	}
+	sort.Strings(contexts)
+	return contexts
+}
@@ -0,0 +1,33 @@
+package todo
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestContexts tests @context parsing from titles
+func TestContexts(t *testing.T) {
+	cases := map[string][]string{
+		"buy milk @errands":          {"errands"},
+		"@Home fix sink @office":     {"home", "office"},
+		"email bob@example.com":      nil,
+		"plan trip @deep-work later": {"deep-work"},
+	}
+	for title, want := range cases {
+		if got := Contexts(title); !reflect.DeepEqual(got, want) {
+			t.Errorf("Contexts(%q) = %v, want %v", title, got, want)
+		}
+	}
+	if got := ContextIndexes("@Home fix sink @office"); !reflect.DeepEqual(got, [][2]int{{0, 5}, {15, 22}}) {
+		t.Errorf("ContextIndexes = %v", got)
+	}
+}
+
+// TestAllContexts tests collecting contexts across lists
+func TestAllContexts(t *testing.T) {
+	a := &TodoList{Todos: []Todo{{Title: "x @office"}, {Title: "y @home"}}}
+	b := &TodoList{Todos: []Todo{{Title: "z @home"}}}
+	if got := AllContexts(a, b); !reflect.DeepEqual(got, []string{"home", "office"}) {
+		t.Errorf("AllContexts = %v", got)
+	}
+}
@@ -0,0 +1,201 @@
+package todo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVColumns lists the columns available for CSV export
+var CSVColumns = []string{"file", "id", "title", "completed", "created_at", "completed_at"}
+
+// CSVFields lists the todo fields that can be mapped from CSV columns on import
+var CSVFields = []string{"title", "completed", "created_at", "completed_at"}
+
+// WriteCSV writes the todos of the given files to w using the selected columns
+func WriteCSV(w io.Writer, paths []string, columns []string) error {
+	for _, col := range columns {
+		if !contains(CSVColumns, col) {
+			return fmt.Errorf("unknown column %q (available: %s)", col, strings.Join(CSVColumns, ","))
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		tl := NewTodoList(path)
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, todo := range tl.Todos {
+			record := make([]string, len(columns))
+			for i, col := range columns {
+				record[i] = csvValue(name, todo, col)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue formats a single todo column for export
+func csvValue(file string, todo Todo, column string) string {
+	switch column {
+	case "file":
+		return file
+	case "id":
+		return strconv.Itoa(todo.ID)
+	case "title":
+		return todo.Title
+	case "completed":
+		return strconv.FormatBool(todo.Completed)
+	case "created_at":
+		return todo.CreatedAt.Format(time.RFC3339)
+	case "completed_at":
+		if todo.CompletedAt != nil {
+			return todo.CompletedAt.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
+
+// ReadCSVHeader reads the header row of a CSV document
+func ReadCSVHeader(r io.Reader) ([]string, error) {
+	header, err := csv.NewReader(r).Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	return header, nil
+}
+
+// ReadCSV parses CSV rows into todos. mapping maps todo fields (see CSVFields)
+// to CSV header names; a title mapping is required.
+func ReadCSV(r io.Reader, mapping map[string]string) ([]Todo, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := map[string]int{}
+	for field, column := range mapping {
+		if !contains(CSVFields, field) {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(CSVFields, ","))
+		}
+		i := indexOf(header, column)
+		if i < 0 {
+			return nil, fmt.Errorf("column %q not found in CSV header", column)
+		}
+		index[field] = i
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, fmt.Errorf("a column must be mapped to title")
+	}
+
+	var todos []Todo
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		title := get("title")
+		if title == "" {
+			continue
+		}
+
+		todo := Todo{Title: title, Completed: parseCSVBool(get("completed")), CreatedAt: time.Now()}
+		if v := get("created_at"); v != "" {
+			t, err := parseCSVTime(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			todo.CreatedAt = t
+		}
+		if v := get("completed_at"); v != "" {
+			t, err := parseCSVTime(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			todo.CompletedAt = &t
+			todo.Completed = true
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, nil
+}
+
+// Import appends todos to the list, assigning fresh IDs and adding the list's tags
+func (tl *TodoList) Import(todos []Todo) {
+	for _, todo := range todos {
+		todo.ID = tl.NextID
+		todo.Title = tl.withTags(todo.Title)
+		tl.NextID++
+		if todo.Completed && todo.CompletedAt == nil {
+			now := time.Now()
+			todo.CompletedAt = &now
+		}
+		tl.Todos = append(tl.Todos, todo)
+	}
+	tl.Sort()
+	tl.persist()
+}
+
+// parseCSVBool interprets common spreadsheet truthy values
+func parseCSVBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "1", "true", "yes", "y", "x", "done", "✓":
+		return true
+	}
+	return false
+}
+
+// parseCSVTime accepts RFC3339 timestamps or plain dates
+func parseCSVTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return t, nil
+}
+
+// contains reports whether s is in list
+func contains(list []string, s string) bool {
+	return indexOf(list, s) >= 0
+}
+
+// indexOf returns the index of s in list (case-insensitive), or -1
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,44 @@
+package todo
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCSVRoundTrip tests exporting a list and importing it back
+func TestCSVRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groceries.json")
+	tl := NewTodoList(path)
+	tl.Add("milk, whole")
+	tl.Add("eggs")
+	tl.Toggle(0)
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []string{path}, []string{"file", "title", "completed"}); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `groceries,"milk, whole",false`) {
+		t.Errorf("Unexpected CSV output:\n%s", buf.String())
+	}
+
+	todos, err := ReadCSV(&buf, map[string]string{"title": "title", "completed": "completed"})
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("Expected 2 todos, got %d", len(todos))
+	}
+	if todos[1].Title != "eggs" || !todos[1].Completed {
+		t.Errorf("Unexpected imported todo: %+v", todos[1])
+	}
+}
+
+// TestReadCSVRequiresTitle tests that imports without a title mapping fail
+func TestReadCSVRequiresTitle(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader("Task,Done\nx,1\n"), map[string]string{"completed": "Done"})
+	if err == nil {
+		t.Error("Expected error when title is not mapped")
+	}
+}
@@ -0,0 +1,60 @@
+package todo
+
+import "time"
+
+// Escalation raises neglected todos: open ones older than OpenFor, or overdue by
+// more than OverdueFor, get flagged and/or a field set. Zero thresholds are ignored.
+type Escalation struct {
+	OpenFor    time.Duration
+	OverdueFor time.Duration
+	Flag       bool
+	FieldKey   string // set to FieldValue, "" for none
+	FieldValue string
+}
+
+// matches reports whether an open todo has crossed the rule's thresholds at now
+func (e Escalation) matches(t Todo, now time.Time) bool {
+	if t.Completed || t.Heading || (e.OpenFor == 0 && e.OverdueFor == 0) {
+		return false
+	}
+	if e.OpenFor > 0 && now.Sub(t.CreatedAt) < e.OpenFor {
+		return false
+	}
+	if e.OverdueFor > 0 && (t.Due == nil || now.Sub(*t.Due) < e.OverdueFor) {
+		return false
+	}
+	return true
+}
+
+// Escalate applies the rules at now and returns how many todos changed.
+// Rules only add flags and fields, so applying them again changes nothing.
+func (tl *TodoList) Escalate(rules []Escalation, now time.Time) int {
+	changed := 0
+	for i := range tl.Todos {
+		t := &tl.Todos[i]
+		touched := false
+		for _, e := range rules {
+			if !e.matches(*t, now) {
+				continue
+			}
+			if e.Flag && !t.Flagged {
+				t.Flagged = true
+				touched = true
+			}
+			if value, ok := t.Fields[e.FieldKey]; e.FieldKey != "" && (!ok || value != e.FieldValue) {
+				if t.Fields == nil {
+					t.Fields = map[string]string{}
+				}
+				t.Fields[e.FieldKey] = e.FieldValue
+				touched = true
+			}
+		}
+		if touched {
+			changed++
+		}
+	}
+	if changed > 0 {
+		tl.persist()
+	}
+	return changed
+}
@@ -0,0 +1,48 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEscalate tests that neglected todos are flagged and prioritized once
+func TestEscalate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "escalate.json")
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC)
+	tl := NewTodoList(path)
+	tl.Add("Fresh")
+	tl.Add("Stale")
+	tl.Add("Overdue")
+	tl.Add("Done")
+	tl.Todos[0].CreatedAt = now.AddDate(0, 0, -20)
+	tl.Todos[0].Completed = true
+	tl.Todos[1].CreatedAt = now.AddDate(0, 0, -1)
+	due := now.AddDate(0, 0, -4)
+	tl.Todos[1].Due = &due
+	tl.Todos[2].CreatedAt = now.AddDate(0, 0, -15)
+	tl.Todos[3].CreatedAt = now.AddDate(0, 0, -2)
+
+	rules := []Escalation{
+		{OpenFor: 14 * 24 * time.Hour, FieldKey: "priority", FieldValue: "high"},
+		{OverdueFor: 3 * 24 * time.Hour, Flag: true},
+	}
+	if n := tl.Escalate(rules, now); n != 2 {
+		t.Fatalf("Expected 2 escalated todos, got %d", n)
+	}
+
+	loaded := NewTodoList(path)
+	if loaded.Todos[2].Fields["priority"] != "high" || loaded.Todos[2].Flagged {
+		t.Errorf("Expected the stale todo to get priority=high only, got %+v", loaded.Todos[2])
+	}
+	if !loaded.Todos[1].Flagged || loaded.Todos[1].Fields != nil {
+		t.Errorf("Expected the overdue todo to be flagged only, got %+v", loaded.Todos[1])
+	}
+	if loaded.Todos[0].Fields != nil || loaded.Todos[3].Fields != nil {
+		t.Error("Expected completed and fresh todos to be left alone")
+	}
+
+	if n := loaded.Escalate(rules, now); n != 0 {
+		t.Errorf("Expected a second pass to change nothing, got %d", n)
+	}
+}
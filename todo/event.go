@@ -0,0 +1,107 @@
+package todo
+
+import (
+	"slices"
+	"sync"
+)
+
+// EventKind names what happened to a list or one of its todos
+type EventKind int
+
+const (
+	TodoAdded      EventKind = iota // a todo was added
+	TodoCompleted                   // a todo was checked off
+	TodoReopened                    // a completed todo was unchecked
+	TodoDeleted                     // a todo was removed
+	ListCompleted                   // the last open todo of a list was checked off
+	FileArchived                    // a list is marked archived, just before it moves to the archive
+	FileUnarchived                  // an archived list is unmarked, just before it moves back
+	FileSaved                       // a list file was written, or moved in
+	FileDeleted                     // a list file was deleted, or moved away
+)
+
+// eventNames are the names of the event kinds, as they appear in logs
+var eventNames = []string{"todo_added", "todo_completed", "todo_reopened", "todo_deleted", "list_completed", "file_archived", "file_unarchived", "file_saved", "file_deleted"}
+
+// String returns the kind's name, like "todo_completed"
+func (k EventKind) String() string {
+	if int(k) < len(eventNames) {
+		return eventNames[k]
+	}
+	return "unknown"
+}
+
+// Event is something that happened to the list at Path, published to the subscribers
+// as the list changes
+type Event struct {
+	Kind EventKind
+	Path string
+	Todo Todo // the todo it happened to, zero for list and file events
+}
+
+// subscriber is a function subscribed to some kinds of events, all of them when empty
+type subscriber struct {
+	id    int
+	fn    func(Event)
+	kinds []EventKind
+}
+
+// bus holds the subscribers in the order they subscribed
+var bus struct {
+	sync.Mutex
+	subscribers []subscriber
+	nextID      int
+}
+
+// Subscribe calls fn with every event of the given kinds, or of any kind when none are
+// given, and returns a function that stops it. fn runs on the goroutine changing the
+// list, before the change returns, so it should be quick and not change lists itself.
+func Subscribe(fn func(Event), kinds ...EventKind) (unsubscribe func()) {
+	bus.Lock()
+	defer bus.Unlock()
+	bus.nextID++
+	id := bus.nextID
+	bus.subscribers = append(bus.subscribers, subscriber{id: id, fn: fn, kinds: kinds})
+	return func() {
+		bus.Lock()
+		defer bus.Unlock()
+		bus.subscribers = slices.DeleteFunc(bus.subscribers, func(s subscriber) bool { return s.id == id })
+	}
+}
+
+// publish delivers an event about the list to its subscribers
+func (tl *TodoList) publish(kind EventKind, t Todo) {
+	publishEvent(Event{Kind: kind, Path: tl.filepath, Todo: t})
+}
+
+// publishEvent delivers an event to its subscribers
+func publishEvent(e Event) {
+	bus.Lock()
+	subscribers := slices.Clone(bus.subscribers)
+	bus.Unlock()
+
+	for _, s := range subscribers {
+		if len(s.kinds) == 0 || slices.Contains(s.kinds, e.Kind) {
+			s.fn(e)
+		}
+	}
+}
+
+// publishToggled publishes that t was checked off or unchecked, and that the list is
+// done when t was its last open todo
+func (tl *TodoList) publishToggled(t Todo) {
+	if !t.Completed {
+		tl.publish(TodoReopened, t)
+		return
+	}
+	tl.publish(TodoCompleted, t)
+	tl.publishIfDone()
+}
+
+// publishIfDone publishes ListCompleted when the list has no open todo left. Habit
+// lists are never done.
+func (tl *TodoList) publishIfDone() {
+	if completed, total := tl.Counts(); total > 0 && completed == total && !tl.IsHabit() {
+		tl.publish(ListCompleted, Todo{})
+	}
+}
@@ -0,0 +1,112 @@
+package todo
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestSubscribe tests that subscribers get the events of the kinds they asked for,
+// in order, until they unsubscribe
+func TestSubscribe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	tl := NewTodoList(path)
+
+	var all, done []EventKind
+	stopAll := Subscribe(func(e Event) {
+		if e.Path == path {
+			all = append(all, e.Kind)
+		}
+	})
+	stopDone := Subscribe(func(e Event) {
+		if e.Path == path {
+			done = append(done, e.Kind)
+		}
+	}, TodoCompleted, ListCompleted)
+	defer stopAll()
+
+	tl.Add("first")
+	tl.Add("second")
+	tl.Toggle(tl.IndexOf(1))
+	tl.Toggle(tl.IndexOf(2))
+	tl.Toggle(tl.IndexOf(2))
+	tl.Delete(tl.IndexOf(1))
+	tl.SetArchived(time.Now(), "")
+
+	// Each change is saved before it's published
+	want := []EventKind{FileSaved, TodoAdded, FileSaved, TodoAdded, FileSaved, TodoCompleted, FileSaved, TodoCompleted,
+		ListCompleted, FileSaved, TodoReopened, FileSaved, TodoDeleted, FileSaved, FileArchived}
+	if !slices.Equal(all, want) {
+		t.Errorf("Expected %v, got %v", want, all)
+	}
+	if want := []EventKind{TodoCompleted, TodoCompleted, ListCompleted}; !slices.Equal(done, want) {
+		t.Errorf("Expected %v for the filtered subscriber, got %v", want, done)
+	}
+
+	stopDone()
+	tl.Toggle(tl.IndexOf(2))
+	if len(done) != 3 {
+		t.Errorf("Expected no events after unsubscribing, got %v", done)
+	}
+}
+
+// TestBulkEvents tests that a bulk edit publishes an event per change once applied
+func TestBulkEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	tl := NewTodoList(path)
+	tl.Add("a")
+	tl.Add("b")
+
+	var kinds []EventKind
+	defer Subscribe(func(e Event) {
+		if e.Path == path {
+			kinds = append(kinds, e.Kind)
+		}
+	}, TodoAdded, TodoCompleted, TodoDeleted)()
+
+	lines := []BulkLine{{ID: 1, Title: "a", Completed: true}, {Title: "c"}}
+	if _, err := tl.ApplyBulk([]int{1, 2}, lines, true); err != nil {
+		t.Fatal(err)
+	}
+	want := []EventKind{TodoCompleted, TodoAdded, TodoDeleted}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("Expected %v, got %v", want, kinds)
+	}
+}
+
+// TestFileEvents tests that moving and deleting files, alone or in a transaction,
+// is published
+func TestFileEvents(t *testing.T) {
+	dir := t.TempDir()
+	var got []string
+	defer Subscribe(func(e Event) {
+		if filepath.Dir(e.Path) == dir {
+			got = append(got, e.Kind.String()+" "+filepath.Base(e.Path))
+		}
+	}, FileSaved, FileDeleted)()
+
+	a := NewTodoList(filepath.Join(dir, "a.json"))
+	a.Add("one")
+	Rename(a.Path(), filepath.Join(dir, "b.json"))
+	tx := Begin(NewTodoList(filepath.Join(dir, "c.json")))
+	tx.Remove(filepath.Join(dir, "b.json"))
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"file_saved a.json", "file_deleted a.json", "file_saved b.json", "file_saved c.json", "file_deleted b.json"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestEventKindString tests the names the kinds are logged with
+func TestEventKindString(t *testing.T) {
+	if got := FileUnarchived.String(); got != "file_unarchived" {
+		t.Errorf("Expected file_unarchived, got %q", got)
+	}
+	if got := EventKind(99).String(); got != "unknown" {
+		t.Errorf("Expected unknown, got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fieldKeyPattern matches valid custom field names
+var fieldKeyPattern = regexp.MustCompile(`^[\w-]+$`)
+
+// ParseField splits "key=value" into a field name and value.
+// An empty value means the field should be removed.
+func ParseField(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !fieldKeyPattern.MatchString(key) {
+		return "", "", fmt.Errorf("use key=value (letters, digits, _ or - in the key)")
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// SetField sets a custom field on a todo, removing it when value is empty
+func (tl *TodoList) SetField(index int, key, value string) {
+	if index < 0 || index >= len(tl.Todos) || tl.Todos[index].Heading {
+		return
+	}
+	t := &tl.Todos[index]
+	if value == "" {
+		delete(t.Fields, key)
+		if len(t.Fields) == 0 {
+			t.Fields = nil
+		}
+	} else {
+		if t.Fields == nil {
+			t.Fields = make(map[string]string)
+		}
+		t.Fields[key] = value
+	}
+	tl.persist()
+}
+
+// FieldKeys returns the todo's field names in sorted order
+func (t Todo) FieldKeys() []string {
+	keys := make([]string, 0, len(t.Fields))
+	for k := range t.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidFieldFilter reports whether expr is a "key" or "key=value" field filter
+func ValidFieldFilter(expr string) bool {
+	key, _, _ := strings.Cut(expr, "=")
+	return fieldKeyPattern.MatchString(strings.TrimSpace(key))
+}
+
+// MatchesField reports whether the todo has a field matching expr:
+// "key" matches any value, "key=value" matches the value case-insensitively
+func (t Todo) MatchesField(expr string) bool {
+	key, want, hasValue := strings.Cut(expr, "=")
+	value, ok := t.Fields[strings.TrimSpace(key)]
+	if !ok {
+		return false
+	}
+	return !hasValue || strings.EqualFold(value, strings.TrimSpace(want))
+}
@@ -0,0 +1,60 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestParseField tests splitting key=value input
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		in         string
+		key, value string
+		ok         bool
+	}{
+		{"ticket=JIRA-123", "ticket", "JIRA-123", true},
+		{" client = acme corp ", "client", "acme corp", true},
+		{"client=", "client", "", true},
+		{"url=https://x.test/?a=b", "url", "https://x.test/?a=b", true},
+		{"client", "", "", false},
+		{"=acme", "", "", false},
+		{"two words=x", "", "", false},
+	}
+	for _, tt := range tests {
+		key, value, err := ParseField(tt.in)
+		if (err == nil) != tt.ok || key != tt.key || value != tt.value {
+			t.Errorf("ParseField(%q) = %q, %q, %v", tt.in, key, value, err)
+		}
+	}
+}
+
+// TestSetField tests that fields are saved, matched and removed
+func TestSetField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fields.json")
+	tl := NewTodoList(path)
+	tl.Add("Fix login")
+	tl.SetField(0, "ticket", "JIRA-123")
+	tl.SetField(0, "client", "Acme")
+
+	loaded := NewTodoList(path)
+	todo := loaded.Todos[0]
+	if got := todo.FieldKeys(); len(got) != 2 || got[0] != "client" || got[1] != "ticket" {
+		t.Fatalf("Expected sorted field keys, got %v", got)
+	}
+	for expr, want := range map[string]bool{
+		"client":        true,
+		"client=acme":   true,
+		"client=globex": false,
+		"owner":         false,
+	} {
+		if got := todo.MatchesField(expr); got != want {
+			t.Errorf("MatchesField(%q) = %v, want %v", expr, got, want)
+		}
+	}
+
+	loaded.SetField(0, "client", "")
+	loaded.SetField(0, "ticket", "")
+	if loaded.Todos[0].Fields != nil {
+		t.Errorf("Expected no fields left, got %v", loaded.Todos[0].Fields)
+	}
+}
@@ -0,0 +1,61 @@
+package todo
+
+import (
+	"math"
+	"time"
+)
+
+// Forecast is a list's recent pace and when its open todos run out at that pace
+type Forecast struct {
+	Days     int        // length of the window the pace is measured over
+	Open     int        // open todos now
+	Done     int        // todos completed within the window
+	Added    int        // todos created within the window
+	Finish   *time.Time // day the list empties at the current pace, nil if it isn't shrinking
+	Burndown []int      // open todos at the end of each day of the window, oldest first
+}
+
+// Pace returns the net number of todos closed per day over the window
+func (f Forecast) Pace() float64 {
+	if f.Days == 0 {
+		return 0
+	}
+	return float64(f.Done-f.Added) / float64(f.Days)
+}
+
+// Forecast measures the last days of the list (today included) from the creation
+// and completion times of its todos. Headings aren't counted.
+func (tl *TodoList) Forecast(now time.Time, days int) Forecast {
+	today := Midnight(now)
+	start := today.AddDate(0, 0, -days+1)
+	f := Forecast{Days: days, Burndown: make([]int, days)}
+
+	for _, t := range tl.Todos {
+		if t.Heading {
+			continue
+		}
+		if !t.Completed {
+			f.Open++
+		}
+		if !t.CreatedAt.Before(start) {
+			f.Added++
+		}
+		done := t.Completed && t.CompletedAt != nil
+		if done && !t.CompletedAt.Before(start) {
+			f.Done++
+		}
+		for d := range f.Burndown {
+			end := start.AddDate(0, 0, d+1)
+			open := t.CreatedAt.Before(end) && (!t.Completed || (done && !t.CompletedAt.Before(end)))
+			if open {
+				f.Burndown[d]++
+			}
+		}
+	}
+
+	if pace := f.Pace(); pace > 0 && f.Open > 0 {
+		finish := today.AddDate(0, 0, int(math.Ceil(float64(f.Open)/pace)))
+		f.Finish = &finish
+	}
+	return f
+}
@@ -0,0 +1,60 @@
+package todo
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestForecast tests the burndown and the finish date at the recent pace
+func TestForecast(t *testing.T) {
+	now := time.Date(2025, 5, 1, 15, 0, 0, 0, time.UTC)
+	tl := &TodoList{}
+	add := func(created, completed int) {
+		todo := Todo{CreatedAt: now.AddDate(0, 0, -created)}
+		if completed >= 0 {
+			at := now.AddDate(0, 0, -completed)
+			todo.Completed, todo.CompletedAt = true, &at
+		}
+		tl.Todos = append(tl.Todos, todo)
+	}
+	for range 4 {
+		add(10, -1)
+	}
+	add(10, 3)
+	add(10, 2)
+	add(10, 1)
+	add(1, 0)
+	add(10, 0)
+	tl.Todos = append(tl.Todos, Todo{Heading: true, CreatedAt: now})
+
+	f := tl.Forecast(now, 4)
+	if f.Open != 4 || f.Done != 5 || f.Added != 1 {
+		t.Fatalf("Expected 4 open, 5 done, 1 added, got %+v", f)
+	}
+	if want := []int{7, 6, 6, 4}; !slices.Equal(f.Burndown, want) {
+		t.Errorf("Expected burndown %v, got %v", want, f.Burndown)
+	}
+	if f.Pace() != 1 {
+		t.Errorf("Expected a pace of 1 a day, got %v", f.Pace())
+	}
+	if want := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC); f.Finish == nil || !f.Finish.Equal(want) {
+		t.Errorf("Expected to finish on %v, got %v", want, f.Finish)
+	}
+}
+
+// TestForecastNotShrinking tests that a growing list has no finish date
+func TestForecastNotShrinking(t *testing.T) {
+	tl := NewTodoList(filepath.Join(t.TempDir(), "growing.json"))
+	tl.Add("One")
+	tl.Add("Two")
+
+	f := tl.Forecast(time.Now(), 14)
+	if f.Finish != nil || f.Pace() >= 0 {
+		t.Errorf("Expected no finish date for a growing list, got %+v", f)
+	}
+	if f.Burndown[13] != 2 || f.Burndown[0] != 0 {
+		t.Errorf("Expected the burndown to climb to 2 today, got %v", f.Burndown)
+	}
+}
@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// FormatVersion is the version of the list file format written by Save
+const FormatVersion = 1
+
+// ErrForeign is returned for a JSON file that isn't a todo list this version can read.
+// Such files are opened empty and never written.
+var ErrForeign = errors.New("not a todo list")
+
+// checkShape returns ErrForeign unless r holds a JSON object with a todos array and
+// no newer format version, see scanHeader
+func checkShape(r io.Reader) error {
+	_, err := scanHeader(r)
+	return err
+}
+
+// IsTodoFile reports whether the JSON file at path looks like a todo list. Missing,
+// unreadable and broken files count as todo lists, loading them reports the problem.
+func IsTodoFile(path string) bool {
+	return !ReadHeader(path).Foreign
+}
+
+// Foreign reports whether the list was loaded from a file that isn't a todo list.
+// A foreign list stays empty and Save refuses to overwrite the file.
+func (tl *TodoList) Foreign() bool {
+	return tl.foreign
+}
+
+// shapeError checks data read from the list's file before it is parsed
+func (tl *TodoList) shapeError(data []byte) error {
+	if err := checkShape(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("%s: %w", tl.filepath, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package todo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestForeignFile tests that JSON files that aren't todo lists load empty and are never written
+func TestForeignFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"config.json": `{"theme": "dark", "items": [1, 2]}`,
+		"array.json":  `[{"id": 1}]`,
+		"todos.json":  `{"todos": {"id": 1}}`,
+		"newer.json":  `{"version": 99, "todos": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(data), 0644)
+		if IsTodoFile(path) {
+			t.Errorf("Expected %s to be foreign", name)
+		}
+
+		tl := &TodoList{filepath: path}
+		if err := tl.Load(); !errors.Is(err, ErrForeign) || !tl.Foreign() || len(tl.Todos) != 0 {
+			t.Errorf("Expected %s to load as foreign, got %v", name, err)
+		}
+		tl.Add("oops")
+		if err := tl.Save(); !errors.Is(err, ErrForeign) {
+			t.Errorf("Expected saving %s to be refused, got %v", name, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != data {
+			t.Errorf("Expected %s untouched, got %s", name, got)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"habits.json": `{"kind": "habit", "next_id": 1, "todos": []}`,
+		"broken.json": `{"todos": [`,
+		"empty.json":  `{"todos": null}`,
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(data), 0644)
+		if !IsTodoFile(path) {
+			t.Errorf("Expected %s to count as a todo list", name)
+		}
+	}
+
+	tl := NewTodoList(filepath.Join(dir, "work.json"))
+	tl.Add("ship it")
+	if !IsTodoFile(tl.Path()) || NewTodoList(tl.Path()).Version != FormatVersion {
+		t.Error("Expected saved lists to carry the format version")
+	}
+}
@@ -0,0 +1,63 @@
+package todo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzLoad feeds arbitrary bytes to Load and checks the list stays usable
+func FuzzLoad(f *testing.F) {
+	f.Add([]byte(`{"todos": [{"id": 1, "title": "a"}], "next_id": 2}`))
+	f.Add([]byte(`{"todos": [`))
+	f.Add([]byte(`{"todos": null, "next_id": 0}`))
+	f.Add([]byte(`{"todos": [{"id": 3}, {"id": 3}, {"id": -1}], "next_id": 1}`))
+	f.Add([]byte(`{"todos": [{"id": 9223372036854775807}], "next_id": 9223372036854775807}`))
+	f.Add([]byte(`{"todos": [{"id": 1, "due": "not a date", "remind_before": 5}]}`))
+	f.Add([]byte(``))
+	f.Add([]byte(`0`))
+	f.Add([]byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), "fuzz.json")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		tl := NewTodoList(path)
+		if tl.Todos == nil {
+			t.Fatal("Todos should never be nil")
+		}
+		seen := map[int]bool{}
+		for _, todo := range tl.Todos {
+			if todo.ID <= 0 || seen[todo.ID] {
+				t.Fatalf("invalid or duplicate ID %d", todo.ID)
+			}
+			if todo.ID >= tl.NextID {
+				t.Fatalf("NextID %d would reuse ID %d", tl.NextID, todo.ID)
+			}
+			seen[todo.ID] = true
+		}
+
+		// Files that aren't todo lists are never written
+		if tl.Foreign() {
+			tl.Add("fuzz")
+			if got, err := os.ReadFile(path); err != nil || !bytes.Equal(got, data) {
+				t.Fatalf("foreign file changed: %q, %v", got, err)
+			}
+			return
+		}
+
+		// The repaired list must survive a mutation and a round trip
+		tl.Add("fuzz")
+		if err := tl.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		again := NewTodoList(path)
+		if len(again.Todos) != len(tl.Todos) || again.NextID != tl.NextID {
+			t.Fatalf("round trip changed list: %d todos/next %d, want %d/%d",
+				len(again.Todos), again.NextID, len(tl.Todos), tl.NextID)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package todo
+
+import (
+	"slices"
+	"time"
+)
+
+// KindHabit marks a list whose todos are daily habits
+const KindHabit = "habit"
+
+// dayLayout is the format of dates in a habit's history
+const dayLayout = "2006-01-02"
+
+// IsHabit reports whether the list tracks daily habits
+func (tl *TodoList) IsHabit() bool {
+	return tl.Kind == KindHabit
+}
+
+// SetKind changes the list type ("" for a normal list or KindHabit)
+func (tl *TodoList) SetKind(kind string) {
+	tl.Kind = kind
+	tl.resetHabits(time.Now())
+	tl.Sort()
+	tl.persist()
+}
+
+// RefreshHabits clears yesterday's checkmarks once a new day starts. Nothing is saved:
+// the checkmarks follow from the history, so loading the list resets them the same way.
+func (tl *TodoList) RefreshHabits(now time.Time) {
+	if tl.resetHabits(now) {
+		tl.counts = nil
+		tl.Sort()
+	}
+}
+
+// resetHabits marks each habit completed only if it was done on now's day,
+// reporting whether anything changed
+func (tl *TodoList) resetHabits(now time.Time) bool {
+	if !tl.IsHabit() {
+		return false
+	}
+	changed := false
+	for i := range tl.Todos {
+		t := &tl.Todos[i]
+		if t.Heading {
+			continue
+		}
+		if done := t.DoneOn(now); done != t.Completed {
+			t.Completed = done
+			if !done {
+				t.CompletedAt, t.CompletedBy = nil, ""
+			}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// recordHabit adds or removes now's day in a habit's history
+func (t *Todo) recordHabit(now time.Time, done bool) {
+	day := now.Format(dayLayout)
+	i, found := slices.BinarySearch(t.History, day)
+	switch {
+	case done && !found:
+		t.History = slices.Insert(t.History, i, day)
+	case !done && found:
+		t.History = slices.Delete(t.History, i, i+1)
+	}
+}
+
+// DoneOn reports whether a habit was done on the given day
+func (t Todo) DoneOn(day time.Time) bool {
+	_, found := slices.BinarySearch(t.History, day.Format(dayLayout))
+	return found
+}
+
+// Streak returns how many consecutive days a habit has been done, up to today.
+// A streak that ended yesterday still counts until today is over.
+func (t Todo) Streak(now time.Time) int {
+	day := now
+	if !t.DoneOn(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for t.DoneOn(day) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// HabitDays returns whether a habit was done on each of the last n days, oldest first
+func (t Todo) HabitDays(now time.Time, n int) []bool {
+	days := make([]bool, n)
+	for i := range days {
+		days[i] = t.DoneOn(now.AddDate(0, 0, i-n+1))
+	}
+	return days
+}
@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHabitToggleRecordsHistory tests that completing a habit records today once
+func TestHabitToggleRecordsHistory(t *testing.T) {
+	tl := NewTodoList(filepath.Join(t.TempDir(), "habits.json"))
+	tl.SetKind(KindHabit)
+	tl.Add("Stretch")
+
+	tl.Toggle(0)
+	today := time.Now().Format(dayLayout)
+	if len(tl.Todos[0].History) != 1 || tl.Todos[0].History[0] != today {
+		t.Fatalf("Expected history [%s], got %v", today, tl.Todos[0].History)
+	}
+
+	// Unchecking on the same day takes the entry back
+	tl.Toggle(0)
+	if len(tl.Todos[0].History) != 0 {
+		t.Errorf("Expected empty history after unchecking, got %v", tl.Todos[0].History)
+	}
+
+	// Normal lists don't keep history
+	plain := NewTodoList(filepath.Join(t.TempDir(), "plain.json"))
+	plain.Add("Buy milk")
+	plain.Toggle(0)
+	if plain.Todos[0].History != nil {
+		t.Errorf("Expected no history in a normal list, got %v", plain.Todos[0].History)
+	}
+}
+
+// TestHabitDailyReset tests that checkmarks from earlier days are cleared on load and refresh
+func TestHabitDailyReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "habits.json")
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1).Format(dayLayout)
+
+	tl := NewTodoList(path)
+	tl.Kind = KindHabit
+	tl.Todos = []Todo{
+		{ID: 1, Title: "Read", Completed: true, History: []string{yesterday}},
+		{ID: 2, Title: "Walk", Completed: true, History: []string{now.Format(dayLayout)}},
+	}
+	tl.NextID = 3
+	tl.Save()
+
+	loaded := NewTodoList(path)
+	if !loaded.IsHabit() {
+		t.Fatal("Expected list kind to survive a reload")
+	}
+	if loaded.Todos[0].Title != "Read" || loaded.Todos[0].Completed {
+		t.Errorf("Expected Read reset and moved up, got %+v", loaded.Todos[0])
+	}
+	if !loaded.Todos[1].Completed {
+		t.Error("Expected Walk to stay done today")
+	}
+
+	// The next day clears today's checkmark too, but keeps the history
+	loaded.RefreshHabits(now.AddDate(0, 0, 1))
+	for _, todo := range loaded.Todos {
+		if todo.Completed {
+			t.Errorf("Expected %s reset the next day", todo.Title)
+		}
+	}
+	if len(loaded.Todos[1].History) != 1 {
+		t.Errorf("Expected history to be kept, got %v", loaded.Todos[1].History)
+	}
+}
+
+// TestStreak tests counting consecutive days, with grace until today ends
+func TestStreak(t *testing.T) {
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.Local)
+	day := func(offset int) string {
+		return now.AddDate(0, 0, offset).Format(dayLayout)
+	}
+
+	tests := []struct {
+		name    string
+		history []string
+		want    int
+	}{
+		{"never", nil, 0},
+		{"today only", []string{day(0)}, 1},
+		{"through today", []string{day(-2), day(-1), day(0)}, 3},
+		{"through yesterday", []string{day(-3), day(-2), day(-1)}, 3},
+		{"gap", []string{day(-4), day(-2), day(-1), day(0)}, 3},
+		{"lapsed", []string{day(-3), day(-2)}, 0},
+	}
+	for _, tt := range tests {
+		habit := Todo{History: tt.history}
+		if got := habit.Streak(now); got != tt.want {
+			t.Errorf("%s: Streak = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestHabitDays tests the per-day heatmap, oldest day first
+func TestHabitDays(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local) // spans the end of February
+	habit := Todo{History: []string{"2024-02-27", "2024-02-29", "2024-03-01"}}
+
+	got := habit.HabitDays(now, 5)
+	want := []bool{false, true, false, true, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("HabitDays = %v, want %v", got, want)
+		}
+	}
+}
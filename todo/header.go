@@ -0,0 +1,139 @@
+package todo
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxListTitle is the longest display title a list keeps
+const maxListTitle = 64
+
+// Header is the metadata saved ahead of a list's todos
+type Header struct {
+	Title    string        `json:"title"`
+	Archived time.Time     `json:"archived"`
+	Original string        `json:"original"`
+	Settings *ListSettings `json:"settings"`
+
+	Foreign bool `json:"-"` // the file isn't a todo list, see IsTodoFile
+}
+
+// SetTitle sets the name shown for the list instead of its filename. Whitespace is
+// collapsed and an empty title removes it, so the filename is shown again.
+func (tl *TodoList) SetTitle(title string) {
+	title = strings.Join(strings.Fields(title), " ")
+	if r := []rune(title); len(r) > maxListTitle {
+		title = strings.TrimSpace(string(r[:maxListTitle]))
+	}
+	tl.Title = title
+	tl.persist()
+}
+
+// SetArchived records when the list was archived and, if archiving renames its file,
+// the name it had before. The zero time and "" clear them when it's restored. The
+// file is moved after, once the subscribers know.
+func (tl *TodoList) SetArchived(at time.Time, original string) {
+	tl.Archived, tl.Original = at, original
+	tl.persist()
+	if at.IsZero() {
+		tl.publish(FileUnarchived, Todo{})
+	} else {
+		tl.publish(FileArchived, Todo{})
+	}
+}
+
+// ReadTitle returns the display title stored in the list file at path, "" when it has
+// none or can't be read
+func ReadTitle(path string) string {
+	return ReadHeader(path).Title
+}
+
+// ReadHeader returns the metadata stored in the list file at path, empty when it has
+// none or can't be read, and whether the file is a todo list at all. Only the keys
+// before the todos are read, Save writes the metadata ahead of them.
+func ReadHeader(path string) Header {
+	f, err := os.Open(path)
+	if err != nil {
+		return Header{}
+	}
+	defer f.Close()
+	h, err := scanHeader(bufio.NewReader(f))
+	h.Foreign = errors.Is(err, ErrForeign)
+	h.Archived = inZone(h.Archived, time.Local)
+	return h
+}
+
+// scanHeader reads the keys before the todos from r for the metadata they hold. It
+// returns ErrForeign unless r holds a JSON object with a todos array and no newer
+// format version. Broken JSON passes with no metadata, so it is reported and backed
+// up as a corrupted list instead.
+func scanHeader(r io.Reader) (Header, error) {
+	var h Header
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return Header{}, nil
+	}
+	if tok != json.Delim('{') {
+		return Header{}, ErrForeign
+	}
+	valid := true // every metadata value decoded
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return Header{}, nil
+		}
+		switch key {
+		case "todos":
+			tok, err := dec.Token()
+			if !valid {
+				h = Header{}
+			}
+			if err == nil && tok != json.Delim('[') && tok != nil {
+				return h, ErrForeign
+			}
+			return h, nil
+		case "version":
+			var version int
+			if err := dec.Decode(&version); err != nil {
+				return Header{}, ErrForeign
+			}
+			if version > FormatVersion {
+				return Header{}, fmt.Errorf("%w, it was written by a newer version (format %d)", ErrForeign, version)
+			}
+		default:
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return Header{}, nil
+			}
+			if value := h.field(key); value != nil && json.Unmarshal(raw, value) != nil {
+				valid = false
+			}
+		}
+	}
+	if !valid {
+		h = Header{}
+	}
+	return h, ErrForeign
+}
+
+// field returns where the value of a header key goes, nil for other keys
+func (h *Header) field(key any) any {
+	switch key {
+	case "title":
+		return &h.Title
+	case "archived":
+		return &h.Archived
+	case "original":
+		return &h.Original
+	case "settings":
+		return &h.Settings
+	}
+	return nil
+}
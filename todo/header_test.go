@@ -0,0 +1,96 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSetTitle tests that a list's display title is saved ahead of its todos and read back
+func TestSetTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "week2.json")
+	tl := NewTodoList(path)
+	tl.Add("plan")
+	tl.SetTitle("  Week   two ")
+	if tl.Title != "Week two" {
+		t.Errorf("Expected whitespace collapsed, got %q", tl.Title)
+	}
+	if got := ReadTitle(path); got != "Week two" {
+		t.Errorf("Expected the title read from the file, got %q", got)
+	}
+	if got := NewTodoList(path).Title; got != "Week two" {
+		t.Errorf("Expected the title loaded, got %q", got)
+	}
+
+	tl.SetTitle(strings.Repeat("x", 100))
+	if len(tl.Title) != maxListTitle {
+		t.Errorf("Expected the title cut to %d, got %d", maxListTitle, len(tl.Title))
+	}
+	tl.SetTitle("")
+	if got := ReadTitle(path); got != "" {
+		t.Errorf("Expected the title removed, got %q", got)
+	}
+}
+
+// TestReadTitle tests reading titles from files that have none or aren't lists
+func TestReadTitle(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"missing.json": "",
+		"plain.json":   `{"todos": [], "next_id": 1}`,
+		"late.json":    `{"todos": [], "title": "after the todos"}`,
+		"array.json":   `[1, 2]`,
+		"broken.json":  `{"title": `,
+	} {
+		if data != "" {
+			os.WriteFile(filepath.Join(dir, name), []byte(data), 0644)
+		}
+		if got := ReadTitle(filepath.Join(dir, name)); got != "" {
+			t.Errorf("%s: expected no title, got %q", name, got)
+		}
+	}
+}
+
+// TestReadHeaderForeign tests that reading a header also tells todo lists from other
+// JSON files, the way IsTodoFile does
+func TestReadHeaderForeign(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name, data string
+		want       Header
+	}{
+		{"list.json", `{"title": "Groceries", "todos": []}`, Header{Title: "Groceries"}},
+		{"config.json", `{"title": "Settings", "theme": "dark"}`, Header{Title: "Settings", Foreign: true}},
+		{"newer.json", `{"version": 99, "title": "Later", "todos": []}`, Header{Foreign: true}},
+		{"broken.json", `{"title": 7, "todos": [`, Header{}},
+	} {
+		path := filepath.Join(dir, tc.name)
+		os.WriteFile(path, []byte(tc.data), 0644)
+		if h := ReadHeader(path); h.Title != tc.want.Title || h.Foreign != tc.want.Foreign || h.Foreign == IsTodoFile(path) {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.want, h)
+		}
+	}
+}
+
+// TestSetArchived tests that the archive date and original name are saved ahead of the
+// todos and cleared again
+func TestSetArchived(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sprint_2024-06-01.json")
+	tl := NewTodoList(path)
+	tl.Add("ship it")
+	at := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+	tl.SetArchived(at, "sprint.json")
+	if h := ReadHeader(path); !h.Archived.Equal(at) || h.Original != "sprint.json" {
+		t.Errorf("Expected archived %v from sprint.json, got %+v", at, h)
+	}
+	if got := NewTodoList(path); !got.Archived.Equal(at) || got.Original != "sprint.json" {
+		t.Errorf("Expected the archive metadata loaded, got %v %q", got.Archived, got.Original)
+	}
+
+	tl.SetArchived(time.Time{}, "")
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "archived") || strings.Contains(string(data), "original") {
+		t.Errorf("Expected no archive metadata once restored, got %s", data)
+	}
+}
@@ -0,0 +1,82 @@
+package todo
+
+import (
+	"math"
+	"time"
+)
+
+// Heatmap counts completions per day over whole weeks starting on a Sunday
+type Heatmap struct {
+	Start  time.Time // the Sunday the first week begins
+	Counts []int     // completions per day from Start through the end of the last week
+}
+
+// CompletionHeatmap counts the completions in the given lists over the last weeks up to now.
+// Habit lists count each day in their history, other lists the completion times of their todos.
+func CompletionHeatmap(paths []string, now time.Time, weeks int) Heatmap {
+	today := Midnight(now)
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+	h := Heatmap{Start: start, Counts: make([]int, 7*weeks)}
+
+	count := func(day time.Time) {
+		day = Midnight(day)
+		if i := h.index(day); i >= 0 {
+			h.Counts[i]++
+		}
+	}
+	for _, path := range paths {
+		tl := NewTodoList(path)
+		for _, t := range tl.Todos {
+			switch {
+			case t.Heading:
+			case tl.IsHabit():
+				for _, d := range t.History {
+					if day, err := time.ParseInLocation(dayLayout, d, now.Location()); err == nil {
+						count(day)
+					}
+				}
+			case t.Completed && t.CompletedAt != nil:
+				count(t.CompletedAt.In(now.Location()))
+			}
+		}
+	}
+	return h
+}
+
+// index returns the position of a local midnight in Counts, or -1 outside the heatmap
+func (h Heatmap) index(day time.Time) int {
+	// round so the hour gained or lost to DST doesn't shift days
+	i := int(math.Round(day.Sub(h.Start).Hours() / 24))
+	if i < 0 || i >= len(h.Counts) {
+		return -1
+	}
+	return i
+}
+
+// Weeks returns the number of weeks in the heatmap
+func (h Heatmap) Weeks() int {
+	return len(h.Counts) / 7
+}
+
+// Day returns the completions on a weekday (Sunday is 0) of a week
+func (h Heatmap) Day(week int, weekday time.Weekday) int {
+	return h.Counts[week*7+int(weekday)]
+}
+
+// Week returns the total completions in a week
+func (h Heatmap) Week(week int) int {
+	total := 0
+	for _, n := range h.Counts[week*7 : week*7+7] {
+		total += n
+	}
+	return total
+}
+
+// Busiest returns the most completions on a single day
+func (h Heatmap) Busiest() int {
+	top := 0
+	for _, n := range h.Counts {
+		top = max(top, n)
+	}
+	return top
+}
@@ -0,0 +1,44 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCompletionHeatmap tests that completions and habit days land on their week and weekday
+func TestCompletionHeatmap(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 19, 18, 0, 0, 0, time.UTC) // a Wednesday
+
+	work := NewTodoList(filepath.Join(dir, "work.json"))
+	for _, title := range []string{"Old", "Monday", "Also Monday", "Today", "Open"} {
+		work.Add(title)
+	}
+	done := func(i int, at time.Time) {
+		work.Todos[i].Completed = true
+		work.Todos[i].CompletedAt = &at
+	}
+	done(4, now.AddDate(0, 0, -60))
+	done(3, time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC))
+	done(2, time.Date(2025, 3, 17, 23, 0, 0, 0, time.UTC))
+	done(1, now)
+	work.Save()
+
+	habits := NewTodoList(filepath.Join(dir, "habits.json"))
+	habits.SetKind(KindHabit)
+	habits.Add("Stretch")
+	habits.Todos[0].History = []string{"2025-03-09", "2025-03-19"}
+	habits.Save()
+
+	h := CompletionHeatmap([]string{work.Path(), habits.Path()}, now, 2)
+	if h.Weeks() != 2 || !h.Start.Equal(time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected two weeks from Sunday Mar 9, got %d from %v", h.Weeks(), h.Start)
+	}
+	if h.Day(1, time.Monday) != 2 || h.Day(1, time.Wednesday) != 2 || h.Day(0, time.Sunday) != 1 {
+		t.Errorf("Expected 2 on Monday, 2 today and 1 on the first Sunday, got %v", h.Counts)
+	}
+	if h.Week(0) != 1 || h.Week(1) != 4 || h.Busiest() != 2 {
+		t.Errorf("Expected weeks of 1 and 4 with a busiest day of 2, got %v", h.Counts)
+	}
+}
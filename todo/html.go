@@ -0,0 +1,90 @@
+package todo
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed html.tmpl
+var htmlSource string
+
+// htmlTemplate renders the standalone export page
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"percent": func(done, total int) int {
+		if total == 0 {
+			return 0
+		}
+		return done * 100 / total
+	},
+	"due": func(t *time.Time) string { return t.Format("Mon Jan 2 15:04") },
+}).Parse(htmlSource))
+
+// htmlList is one list on the export page
+type htmlList struct {
+	Name      string
+	Completed int
+	Total     int
+	Todos     []htmlTodo
+}
+
+// htmlTodo is one row on the export page
+type htmlTodo struct {
+	Todo
+	Contexts []string
+	Overdue  bool
+	Done     int // section progress, headings only
+	Count    int
+	URL      template.URL // justdoit:// link to the todo, "" without links
+}
+
+// WriteHTML writes a standalone, styled HTML page with the todos of the given files.
+// Todos of lists in linkDir link to themselves with justdoit:// URLs, "" links none.
+func WriteHTML(w io.Writer, paths []string, linkDir string) error {
+	return writeHTML(w, paths, linkDir, 0)
+}
+
+// WriteLiveHTML writes the page for a list shared while it changes, which reloads
+// itself every refresh to show the latest todos
+func WriteLiveHTML(w io.Writer, path string, refresh time.Duration) error {
+	return writeHTML(w, []string{path}, "", max(refresh, time.Second))
+}
+
+// writeHTML writes the page for WriteHTML, reloading itself every refresh unless it is 0
+func writeHTML(w io.Writer, paths []string, linkDir string, refresh time.Duration) error {
+	now := time.Now()
+	page := struct {
+		Generated string
+		Refresh   int // seconds
+		Completed int
+		Total     int
+		Lists     []htmlList
+	}{Generated: now.Format("Mon Jan 2 2006 15:04"), Refresh: int(refresh.Seconds())}
+	if refresh > 0 {
+		page.Generated = now.Format("15:04:05") // so watchers can tell it is live
+	}
+
+	for _, path := range paths {
+		tl := NewTodoList(path)
+		list := htmlList{Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+		list.Completed, list.Total = tl.Counts()
+		linked := linkDir != "" && filepath.Dir(path) == filepath.Clean(linkDir)
+		for i, todo := range tl.Todos {
+			row := htmlTodo{Todo: todo, Contexts: Contexts(todo.Title)}
+			if todo.Heading {
+				row.Done, row.Count = tl.SectionStats(i)
+			} else if linked {
+				row.URL = template.URL(URL(list.Name, todo.ID)) // a scheme html/template doesn't know
+			}
+			row.Overdue = !todo.Completed && todo.Due != nil && todo.Due.Before(now)
+			list.Todos = append(list.Todos, row)
+		}
+		page.Completed += list.Completed
+		page.Total += list.Total
+		page.Lists = append(page.Lists, list)
+	}
+	return htmlTemplate.Execute(w, page)
+}
@@ -0,0 +1,80 @@
+package todo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteHTML tests that the export page shows progress, styling hooks and escapes titles
+func TestWriteHTML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "work.json")
+	tl := NewTodoList(path)
+	tl.Add("ship <b>it</b> @office")
+	tl.Add("write notes")
+	tl.Toggle(1)
+	past := time.Now().Add(-time.Hour)
+	tl.SetDue(0, &past)
+
+	var b strings.Builder
+	if err := WriteHTML(&b, []string{path}, ""); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+
+	for _, want := range []string{
+		"<h2>work <span class=\"count\">1/2</span></h2>",
+		"width: 50%",
+		`<li class="done">`,
+		"ship &lt;b&gt;it&lt;/b&gt; @office",
+		`<span class="tag">@office</span>`,
+		`class="due overdue"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected page to contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<b>it</b>") {
+		t.Error("Expected titles to be escaped")
+	}
+	if strings.Contains(page, "justdoit://") {
+		t.Error("Expected no links unless asked for")
+	}
+
+	b.Reset()
+	if err := WriteHTML(&b, []string{path}, dir); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<a href="justdoit://work/1">ship &lt;b&gt;it&lt;/b&gt; @office</a>`; !strings.Contains(b.String(), want) {
+		t.Errorf("Expected todos linked to themselves, want %q in:\n%s", want, b.String())
+	}
+}
+
+// TestWriteLiveHTML tests that a shared page reloads itself and has no links
+func TestWriteLiveHTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moving.json")
+	tl := NewTodoList(path)
+	tl.Add("pack kitchen")
+
+	var b strings.Builder
+	if err := WriteLiveHTML(&b, path, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{`<meta http-equiv="refresh" content="5">`, "pack kitchen", "read-only"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected page to contain %q", want)
+		}
+	}
+	if strings.Contains(page, "justdoit://") {
+		t.Error("Expected no links on a shared page")
+	}
+
+	b.Reset()
+	WriteHTML(&b, []string{path}, "")
+	if strings.Contains(b.String(), "http-equiv") {
+		t.Error("Expected an exported page not to reload")
+	}
+}
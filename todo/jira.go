@@ -0,0 +1,51 @@
+package todo
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JiraField is the custom field holding the key of an issue imported from Jira
+const JiraField = "jira"
+
+// issueKeyPattern matches Jira issue keys like PROJ-123
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9]+$`)
+
+// Issue is a Jira issue imported as a todo
+type Issue struct {
+	Key     string // like PROJ-123
+	Summary string
+	URL     string // the issue's page in the browser
+}
+
+// IssueKey returns s as an issue key, uppercased, and whether it is one
+func IssueKey(s string) (string, bool) {
+	key := strings.ToUpper(strings.TrimSpace(s))
+	return key, issueKeyPattern.MatchString(key)
+}
+
+// IssueIndex returns the index of the todo imported from the issue with key, or -1
+func (tl *TodoList) IssueIndex(key string) int {
+	return slices.IndexFunc(tl.Todos, func(t Todo) bool { return t.Fields[JiraField] == key })
+}
+
+// AddIssue adds a todo at the top of the list titled with the issue's summary,
+// keeping its key in the jira field and its link in the notes
+func (tl *TodoList) AddIssue(issue Issue) {
+	todo := Todo{
+		ID:        tl.NextID,
+		Title:     tl.withTags(issue.Summary),
+		CreatedAt: time.Now(),
+		AddedBy:   tl.author,
+		Fields:    map[string]string{JiraField: issue.Key},
+		Notes:     issue.URL,
+	}
+	tl.fitTitle(&todo)
+	tl.Todos = slices.Insert(tl.Todos, 0, todo)
+	tl.NextID++
+	tl.Sort()
+	tl.persist()
+	tl.publish(TodoAdded, todo)
+}
@@ -0,0 +1,48 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestAddIssue tests that an imported Jira issue becomes a todo keeping its key and link
+func TestAddIssue(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		key  string
+		want bool
+	}{
+		{"PROJ-123", "PROJ-123", true},
+		{" proj-7 ", "PROJ-7", true},
+		{"A2_B-1", "A2_B-1", true},
+		{"PROJ", "PROJ", false},
+		{"123-4", "123-4", false},
+		{"PROJ-12a", "PROJ-12A", false},
+	} {
+		if key, ok := IssueKey(tc.in); key != tc.key || ok != tc.want {
+			t.Errorf("IssueKey(%q) = %q, %v, want %q, %v", tc.in, key, ok, tc.key, tc.want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "sprint.json")
+	tl := NewTodoList(path)
+	tl.SetAuthor("ana")
+	tl.Add("stand-up notes")
+	tl.AddIssue(Issue{Key: "PROJ-123", Summary: "Fix login redirect", URL: "https://example.atlassian.net/browse/PROJ-123"})
+
+	loaded := NewTodoList(path)
+	i := loaded.IssueIndex("PROJ-123")
+	if i != 0 {
+		t.Fatalf("Expected the issue at the top, got index %d", i)
+	}
+	got := loaded.Todos[i]
+	if got.Title != "Fix login redirect" || got.Fields[JiraField] != "PROJ-123" || got.AddedBy != "ana" {
+		t.Errorf("Expected the summary, key and author, got %+v", got)
+	}
+	if got.Notes != "https://example.atlassian.net/browse/PROJ-123" {
+		t.Errorf("Expected the link in the notes, got %q", got.Notes)
+	}
+	if loaded.IssueIndex("PROJ-124") != -1 {
+		t.Error("Expected no todo for an issue never imported")
+	}
+}
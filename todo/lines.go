@@ -0,0 +1,43 @@
+package todo
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// linePrefix matches the list markers and Markdown checkboxes pasted text tends to
+// start its lines with: "- ", "* ", "+ ", "1. ", "2) ", "- [ ] " and "- [x] "
+var linePrefix = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+(?:\[([ xX])\]\s+)?`)
+
+// ParseLines reads one todo per non-empty line of r. List markers are dropped, and
+// lines checked off as "- [x]" come in completed.
+func ParseLines(r io.Reader) ([]Todo, error) {
+	var todos []Todo
+	now := time.Now()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024) // long pasted lines
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		done := false
+		if m := linePrefix.FindStringSubmatch(line); m != nil {
+			done = m[1] == "x" || m[1] == "X"
+			line = strings.TrimSpace(line[len(m[0]):])
+		}
+		if line == "" {
+			continue
+		}
+		todos = append(todos, Todo{Title: line, Completed: done, CreatedAt: now})
+	}
+	return todos, sc.Err()
+}
+
+// AddContext returns title with @context added at the end, unless it has it already
+func AddContext(title, context string) string {
+	if (Todo{Title: title}).HasContext(strings.ToLower(context)) {
+		return title
+	}
+	return title + " @" + context
+}
@@ -0,0 +1,34 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseLines tests turning pasted text into todos, one per line
+func TestParseLines(t *testing.T) {
+	in := "milk\n\n  - eggs  \n* [ ] bread\n- [x] coffee\n2) flour\n-not a marker\n"
+	todos, err := ParseLines(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		title string
+		done  bool
+	}{{"milk", false}, {"eggs", false}, {"bread", false}, {"coffee", true}, {"flour", false}, {"-not a marker", false}}
+	if len(todos) != len(want) {
+		t.Fatalf("Expected %d todos, got %+v", len(want), todos)
+	}
+	for i, w := range want {
+		if todos[i].Title != w.title || todos[i].Completed != w.done {
+			t.Errorf("Line %d: got %q done=%v, want %q done=%v", i, todos[i].Title, todos[i].Completed, w.title, w.done)
+		}
+	}
+
+	if got := AddContext("eggs", "shop"); got != "eggs @shop" {
+		t.Errorf("Expected the context added, got %q", got)
+	}
+	if got := AddContext("eggs @Shop", "shop"); got != "eggs @Shop" {
+		t.Errorf("Expected an existing context kept, got %q", got)
+	}
+}
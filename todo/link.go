@@ -0,0 +1,74 @@
+package todo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// linkPattern matches [[file]], [[file#ID]] and ref:ID references in titles
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]#]+)(?:#(\d+))?\]\]|\bref:(\d+)\b`)
+
+// Link is a reference from a title to another list or todo
+type Link struct {
+	File  string // target list name without .json, "" for the same list
+	ID    int    // target todo, 0 for the list itself
+	Start int    // byte offset of the reference in the title
+	End   int
+}
+
+// Links returns the references in a title in order of appearance
+func Links(title string) []Link {
+	var links []Link
+	for _, loc := range linkPattern.FindAllStringSubmatchIndex(title, -1) {
+		link := Link{Start: loc[0], End: loc[1]}
+		if loc[2] >= 0 {
+			link.File = strings.TrimSuffix(strings.TrimSpace(title[loc[2]:loc[3]]), ".json")
+			if loc[4] >= 0 {
+				link.ID, _ = strconv.Atoi(title[loc[4]:loc[5]])
+			}
+		} else {
+			link.ID, _ = strconv.Atoi(title[loc[6]:loc[7]])
+		}
+		if link.File == "" && link.ID <= 0 {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// URLScheme is the scheme of links that open justdoit at a todo, like justdoit://work/12
+const URLScheme = "justdoit"
+
+// URL returns the link that opens justdoit at the todo with id in the list named list
+// (its file name without .json), or at the list itself when id is 0
+func URL(list string, id int) string {
+	u := URLScheme + "://" + url.PathEscape(strings.TrimSuffix(list, ".json"))
+	if id > 0 {
+		u += "/" + strconv.Itoa(id)
+	}
+	return u
+}
+
+// ParseURL returns the list and todo a justdoit:// link points at, with id 0 when it
+// names only the list
+func ParseURL(s string) (list string, id int, err error) {
+	rest, ok := strings.CutPrefix(s, URLScheme+"://")
+	if !ok {
+		return "", 0, fmt.Errorf("%q isn't a %s:// link", s, URLScheme)
+	}
+	rest = strings.TrimSuffix(rest, "/")
+	name, ref, hasID := strings.Cut(rest, "/")
+	if list, err = url.PathUnescape(name); err != nil || list == "" {
+		return "", 0, fmt.Errorf("%q names no list", s)
+	}
+	if hasID {
+		if id, err = strconv.Atoi(ref); err != nil || id <= 0 {
+			return "", 0, fmt.Errorf("%q has no todo ID after the list", s)
+		}
+	}
+	return strings.TrimSuffix(list, ".json"), id, nil
+}
@@ -0,0 +1,60 @@
+package todo
+
+import "testing"
+
+// TestLinks tests parsing list and todo references out of titles
+func TestLinks(t *testing.T) {
+	tests := []struct {
+		title string
+		want  []Link
+	}{
+		{"no links here", nil},
+		{"see [[groceries]]", []Link{{File: "groceries", Start: 4, End: 17}}},
+		{"[[work.json#12]] first", []Link{{File: "work", ID: 12, Start: 0, End: 16}}},
+		{"after ref:3 and ref:4", []Link{{ID: 3, Start: 6, End: 11}, {ID: 4, Start: 16, End: 21}}},
+		{"xref:3 [[ ]] ref:0 [[#5]]", nil},
+		{"[[my list]]", []Link{{File: "my list", Start: 0, End: 11}}},
+	}
+	for _, tt := range tests {
+		got := Links(tt.title)
+		if len(got) != len(tt.want) {
+			t.Errorf("Links(%q) = %+v, want %+v", tt.title, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Links(%q)[%d] = %+v, want %+v", tt.title, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+// TestURL tests that justdoit:// links round-trip and bad ones are refused
+func TestURL(t *testing.T) {
+	if got := URL("work.json", 12); got != "justdoit://work/12" {
+		t.Errorf("URL = %q", got)
+	}
+	if got := URL("my list", 0); got != "justdoit://my%20list" {
+		t.Errorf("URL without a todo = %q", got)
+	}
+
+	for _, tt := range []struct {
+		url  string
+		list string
+		id   int
+	}{
+		{"justdoit://work/12", "work", 12},
+		{"justdoit://work.json/3/", "work", 3},
+		{"justdoit://my%20list", "my list", 0},
+	} {
+		list, id, err := ParseURL(tt.url)
+		if err != nil || list != tt.list || id != tt.id {
+			t.Errorf("ParseURL(%q) = %q, %d, %v", tt.url, list, id, err)
+		}
+	}
+	for _, bad := range []string{"work/12", "https://work/12", "justdoit://", "justdoit://work/x", "justdoit://work/0", "justdoit://%zz/1"} {
+		if _, _, err := ParseURL(bad); err == nil {
+			t.Errorf("Expected ParseURL(%q) to fail", bad)
+		}
+	}
+}
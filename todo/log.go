@@ -0,0 +1,15 @@
+package todo
+
+import (
+	"log/slog"
+	"time"
+)
+
+// logFileOp records a file operation and how long it took in the debug log
+func logFileOp(op, path string, todos int, start time.Time, err error) {
+	if err != nil {
+		slog.Error(op, "path", path, "took", time.Since(start), "err", err)
+		return
+	}
+	slog.Debug(op, "path", path, "todos", todos, "took", time.Since(start))
+}
@@ -0,0 +1,137 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Mail is the part of an email message that becomes a todo
+type Mail struct {
+	Subject string
+	From    string
+	Date    time.Time // zero when missing or unparsable
+	Body    string    // plain text
+}
+
+// forwardPrefix matches the Fwd:/Re: chains mail clients put before a subject
+var forwardPrefix = regexp.MustCompile(`^(?i)((fwd?|re|aw|wg)\s*:\s*)+`)
+
+// ParseMail reads an RFC 822 message, decoding its subject and plain text body
+func ParseMail(r io.Reader) (Mail, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return Mail{}, fmt.Errorf("not an email message: %w", err)
+	}
+
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	from, err := dec.DecodeHeader(msg.Header.Get("From"))
+	if err != nil {
+		from = msg.Header.Get("From")
+	}
+	m := Mail{
+		Subject: strings.TrimSpace(forwardPrefix.ReplaceAllString(strings.TrimSpace(subject), "")),
+		From:    from,
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		m.Date = date
+	}
+
+	body, err := textBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return m, err
+	}
+	m.Body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	return m, nil
+}
+
+// textBody returns the text/plain content of a message or part, searching multipart
+// bodies depth-first and skipping attachments and HTML
+func textBody(contentType, encoding string, r io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain" // RFC 2045 default
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return "", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			text, err := textBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil || text != "" {
+				return text, err
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message body: %w", err)
+	}
+	return string(bytes.ToValidUTF8(data, []byte("?"))), nil
+}
+
+// AddMail adds a todo at the top of the list titled with the message subject,
+// keeping the sender, date and body in its notes
+func (tl *TodoList) AddMail(m Mail) {
+	title := m.Subject
+	if title == "" {
+		title = "(no subject)"
+	}
+
+	var notes []string
+	if m.From != "" {
+		notes = append(notes, "From: "+m.From)
+	}
+	if !m.Date.IsZero() {
+		notes = append(notes, "Date: "+m.Date.Local().Format("2006-01-02 15:04"))
+	}
+	if m.Body != "" {
+		notes = append(notes, "", m.Body)
+	}
+
+	todo := Todo{
+		ID:        tl.NextID,
+		Title:     tl.withTags(title),
+		CreatedAt: time.Now(),
+		Notes:     strings.TrimSpace(strings.Join(notes, "\n")),
+		AddedBy:   tl.author,
+	}
+	tl.fitTitle(&todo)
+	tl.Todos = slices.Insert(tl.Todos, 0, todo)
+	tl.NextID++
+	tl.Sort()
+	tl.persist()
+	tl.publish(TodoAdded, todo)
+}
@@ -0,0 +1,63 @@
+package todo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseMail tests decoding the subject and picking the plain text part
+func TestParseMail(t *testing.T) {
+	raw := "From: =?UTF-8?Q?J=C3=BCrgen?= <j@example.com>\r\n" +
+		"Subject: Fwd: RE: Renew =?UTF-8?Q?domain_=E2=80=93_acme.test?=\r\n" +
+		"Date: Mon, 3 Mar 2025 09:15:00 +0100\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/alternative; boundary=b1\r\n" +
+		"\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"Expires on Friday, please =\r\nrenew.\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/html\r\n" +
+		"\r\n" +
+		"<p>Expires on Friday</p>\r\n" +
+		"--b1--\r\n"
+
+	m, err := ParseMail(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Subject != "Renew domain – acme.test" {
+		t.Errorf("Expected decoded subject without Fwd/RE, got %q", m.Subject)
+	}
+	if m.From != "Jürgen <j@example.com>" || m.Date.Day() != 3 {
+		t.Errorf("Expected sender and date, got %q %v", m.From, m.Date)
+	}
+	if m.Body != "Expires on Friday, please renew." {
+		t.Errorf("Expected the decoded plain text part, got %q", m.Body)
+	}
+
+	if _, err := ParseMail(strings.NewReader("not a message")); err == nil {
+		t.Error("Expected an error for input without headers")
+	}
+}
+
+// TestAddMail tests that a message becomes a todo on top with its details in the notes
+func TestAddMail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox.json")
+	tl := NewTodoList(path)
+	tl.Add("existing")
+	tl.SetAuthor("sam")
+	tl.AddMail(Mail{From: "bob@example.com", Body: "see attached"})
+	tl.Save()
+
+	todo := NewTodoList(path).Todos[0]
+	if todo.Title != "(no subject)" || todo.Notes != "From: bob@example.com\n\nsee attached" {
+		t.Errorf("Expected the mail on top with notes, got %+v", todo)
+	}
+	if todo.AddedBy != "sam" {
+		t.Errorf("Expected the author recorded, got %q", todo.AddedBy)
+	}
+}
@@ -0,0 +1,124 @@
+package todo
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MergeChoice decides which version of a todo a merge keeps
+type MergeChoice int
+
+const (
+	PickLocal  MergeChoice = iota // keep this machine's version (or its deletion)
+	PickRemote                    // keep the server's version (or its deletion)
+	KeepBoth                      // keep both, the server's under a new ID
+)
+
+// MergeItem pairs the two versions of a todo that differ between copies of a list.
+// A nil side means the todo doesn't exist there.
+type MergeItem struct {
+	Local  *Todo
+	Remote *Todo
+	Choice MergeChoice
+}
+
+// Title names the item by whichever version exists, preferring the local one
+func (it MergeItem) Title() string {
+	if it.Local != nil {
+		return it.Local.Title
+	}
+	return it.Remote.Title
+}
+
+// Diverging pairs up the todos of two copies of a list by ID and returns those that
+// differ, in local order followed by remote-only todos. Each defaults to the side it exists on,
+// preferring local.
+func Diverging(local, remote *TodoList) []MergeItem {
+	var items []MergeItem
+	for i := range local.Todos {
+		l := &local.Todos[i]
+		var r *Todo
+		if j := remote.IndexOf(l.ID); j >= 0 {
+			r = &remote.Todos[j]
+		}
+		if r == nil || !sameTodo(*l, *r) {
+			items = append(items, MergeItem{Local: l, Remote: r, Choice: PickLocal})
+		}
+	}
+	for j := range remote.Todos {
+		r := &remote.Todos[j]
+		if local.IndexOf(r.ID) < 0 {
+			items = append(items, MergeItem{Remote: r, Choice: PickRemote})
+		}
+	}
+	return items
+}
+
+// Merge builds the merged list from local, applying the choices made for the diverging items.
+// Todos only on the server that are kept go to the end.
+func Merge(local, remote *TodoList, items []MergeItem) []Todo {
+	chosen := map[int]MergeItem{}
+	for _, it := range items {
+		if it.Local != nil {
+			chosen[it.Local.ID] = it
+		} else {
+			chosen[it.Remote.ID] = it
+		}
+	}
+	nextID := max(local.NextID, remote.NextID)
+
+	var merged []Todo
+	add := func(it MergeItem) {
+		switch {
+		case it.Choice == PickLocal && it.Local != nil:
+			merged = append(merged, *it.Local)
+		case it.Choice == PickRemote && it.Remote != nil:
+			merged = append(merged, *it.Remote)
+		case it.Choice == KeepBoth:
+			if it.Local != nil {
+				merged = append(merged, *it.Local)
+			}
+			if it.Remote != nil {
+				t := *it.Remote
+				if it.Local != nil {
+					t.ID = nextID
+					nextID++
+				}
+				merged = append(merged, t)
+			}
+		}
+	}
+	for _, t := range local.Todos {
+		if it, ok := chosen[t.ID]; ok {
+			add(it)
+		} else {
+			merged = append(merged, t)
+		}
+	}
+	for _, it := range items {
+		if it.Local == nil {
+			add(it)
+		}
+	}
+	return slices.Clip(merged)
+}
+
+// ApplyMerge replaces the list's todos with a merge of itself and remote, then saves
+func (tl *TodoList) ApplyMerge(remote *TodoList, items []MergeItem) error {
+	merged := Merge(tl, remote, items)
+	tl.NextID = max(tl.NextID, remote.NextID)
+	for _, t := range merged {
+		tl.NextID = max(tl.NextID, t.ID+1)
+	}
+	tl.Todos = merged
+	tl.index = nil
+	tl.counts = nil
+	return tl.Save()
+}
+
+// sameTodo reports whether two todos have the same content
+func sameTodo(a, b Todo) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
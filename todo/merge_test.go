@@ -0,0 +1,55 @@
+package todo
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestMerge tests pairing diverging todos by ID and applying per-todo choices
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	local := NewTodoList(filepath.Join(dir, "work.json"))
+	local.Add("shared")
+	local.Add("renamed here")
+	local.Add("only here")
+	local.Save()
+
+	remote := NewTodoList(filepath.Join(dir, "remote.json"))
+	remote.Todos = slices.Clone(local.Todos[1:]) // "only here" is gone on the server
+	remote.NextID = local.NextID
+	remote.Update(0, "renamed there")
+	remote.Add("only there")
+
+	items := Diverging(local, remote)
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 diverging todos, got %d", len(items))
+	}
+	if items[0].Title() != "only here" || items[0].Remote != nil || items[2].Title() != "only there" || items[2].Choice != PickRemote {
+		t.Fatalf("Unexpected pairing: %+v", items)
+	}
+
+	items[0].Choice = PickRemote // accept the deletion
+	items[1].Choice = KeepBoth
+	if err := local.ApplyMerge(remote, items); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := NewTodoList(filepath.Join(dir, "work.json"))
+	var titles []string
+	for _, todo := range saved.Todos {
+		titles = append(titles, todo.Title)
+	}
+	want := []string{"renamed here", "renamed there", "shared", "only there"}
+	if len(titles) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, titles)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, titles)
+		}
+	}
+	if saved.Todos[1].ID == saved.Todos[0].ID || saved.NextID <= saved.Todos[1].ID {
+		t.Errorf("Expected the kept server copy under a fresh ID, got %+v", saved.Todos[:2])
+	}
+}
@@ -0,0 +1,45 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNameLength is the longest list name accepted, without its .json extension
+const maxNameLength = 64
+
+// NormalizeListName tidies a typed list name: surrounding spaces and a .json extension
+// are dropped, runs of spaces inside become a dash and, with lower set, letters are
+// lowercased
+func NormalizeListName(name string, lower bool) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".json")
+	name = strings.Join(strings.Fields(name), "-")
+	if lower {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// ValidateListName reports why a normalized list name can't be used as a file name.
+// Names are ASCII letters, digits, dashes, underscores and dots, and don't start with a dot.
+func ValidateListName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name is empty")
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("names are at most %d characters", maxNameLength)
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		case r == '/' || r == '\\':
+			return fmt.Errorf("names can't contain %c, lists have no folders", r)
+		default:
+			return fmt.Errorf("%q isn't allowed, use letters, digits, - _ and .", r)
+		}
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("names can't start with a dot")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNormalizeListName tests trimming, dashes and lowercasing of typed list names
+func TestNormalizeListName(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		lower bool
+		want  string
+	}{
+		{"  work  ", false, "work"},
+		{"Side  Project.json", false, "Side-Project"},
+		{"Side Project", true, "side-project"},
+	} {
+		if got := NormalizeListName(tc.in, tc.lower); got != tc.want {
+			t.Errorf("NormalizeListName(%q, %v) = %q, want %q", tc.in, tc.lower, got, tc.want)
+		}
+	}
+}
+
+// TestValidateListName tests that names which break as file names are rejected
+func TestValidateListName(t *testing.T) {
+	for _, name := range []string{"work", "side-project_2", "v1.2"} {
+		if err := ValidateListName(name); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../evil", "a/b", `a\b`, ".hidden", "café", "a:b", strings.Repeat("a", 65)} {
+		if err := ValidateListName(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package todo
+
+import "iter"
+
+// counts caches completed and total task counts; headings are not tasks
+type counts struct {
+	completed, total int
+}
+
+// Page returns up to limit todos starting at offset. The result shares the
+// list's backing array, so it must not be modified or kept across mutations.
+func (tl *TodoList) Page(offset, limit int) []Todo {
+	offset = min(max(offset, 0), len(tl.Todos))
+	end := len(tl.Todos)
+	if limit >= 0 {
+		end = min(offset+limit, end)
+	}
+	return tl.Todos[offset:end:end]
+}
+
+// All iterates over the todos with their indices without copying the slice
+func (tl *TodoList) All() iter.Seq2[int, Todo] {
+	return func(yield func(int, Todo) bool) {
+		for i, todo := range tl.Todos {
+			if !yield(i, todo) {
+				return
+			}
+		}
+	}
+}
+
+// Filter iterates over the todos matching match, with their indices
+func (tl *TodoList) Filter(match func(Todo) bool) iter.Seq2[int, Todo] {
+	return func(yield func(int, Todo) bool) {
+		for i, todo := range tl.Todos {
+			if match(todo) && !yield(i, todo) {
+				return
+			}
+		}
+	}
+}
+
+// Counts returns the number of completed and total tasks, skipping headings.
+// The result is cached until the list changes through its methods.
+func (tl *TodoList) Counts() (int, int) {
+	if tl.counts == nil {
+		completed, total := tl.CountFunc(func(Todo) bool { return true })
+		tl.counts = &counts{completed: completed, total: total}
+	}
+	return tl.counts.completed, tl.counts.total
+}
+
+// CountFunc returns the number of completed and total tasks matching match, skipping headings
+func (tl *TodoList) CountFunc(match func(Todo) bool) (int, int) {
+	completed, total := 0, 0
+	for _, todo := range tl.Todos {
+		if todo.Heading || !match(todo) {
+			continue
+		}
+		total++
+		if todo.Completed {
+			completed++
+		}
+	}
+	return completed, total
+}
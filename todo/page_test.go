@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestPage tests slicing a list with clamped bounds
+func TestPage(t *testing.T) {
+	tl := &TodoList{Todos: []Todo{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}}
+
+	tests := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{0, 2, []int{1, 2}},
+		{3, 10, []int{4, 5}},
+		{-1, 1, []int{1}},
+		{5, 2, nil},
+		{2, -1, []int{3, 4, 5}},
+	}
+	for _, tt := range tests {
+		page := tl.Page(tt.offset, tt.limit)
+		if len(page) != len(tt.want) {
+			t.Errorf("Page(%d, %d) has %d todos, want %d", tt.offset, tt.limit, len(page), len(tt.want))
+			continue
+		}
+		for i, id := range tt.want {
+			if page[i].ID != id {
+				t.Errorf("Page(%d, %d)[%d] = %d, want %d", tt.offset, tt.limit, i, page[i].ID, id)
+			}
+		}
+	}
+
+	if page := tl.Page(1, 2); &page[0] != &tl.Todos[1] {
+		t.Error("Expected Page to share the list's backing array")
+	}
+}
+
+// TestFilter tests iterating over matching todos and stopping early
+func TestFilter(t *testing.T) {
+	tl := &TodoList{Todos: []Todo{{ID: 1, Completed: true}, {ID: 2}, {ID: 3, Completed: true}, {ID: 4, Completed: true}}}
+
+	var seen []int
+	for i, todo := range tl.Filter(func(t Todo) bool { return t.Completed }) {
+		if tl.Todos[i].ID != todo.ID {
+			t.Errorf("Index %d does not match todo %d", i, todo.ID)
+		}
+		seen = append(seen, todo.ID)
+		if len(seen) == 2 {
+			break
+		}
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 3 {
+		t.Errorf("Unexpected filtered todos: %v", seen)
+	}
+}
+
+// TestCounts tests that cached counts skip headings and follow changes
+func TestCounts(t *testing.T) {
+	tl := NewTodoList(filepath.Join(t.TempDir(), "counts.json"))
+	tl.Add("b")
+	tl.Add("a")
+	tl.Add("Section")
+	tl.ToggleHeading(0)
+
+	if completed, total := tl.Counts(); completed != 0 || total != 2 {
+		t.Errorf("Counts() = %d/%d, want 0/2", completed, total)
+	}
+	tl.Toggle(1)
+	if completed, total := tl.Counts(); completed != 1 || total != 2 {
+		t.Errorf("Counts() after toggle = %d/%d, want 1/2", completed, total)
+	}
+	tl.Delete(1) // "a" sank below "b" when completed
+	if completed, total := tl.Counts(); completed != 1 || total != 1 {
+		t.Errorf("Counts() after delete = %d/%d, want 1/1", completed, total)
+	}
+}
@@ -0,0 +1,23 @@
+package todo
+
+// ProgressStep is how far one step moves a todo's percent done
+const ProgressStep = 10
+
+// Percent returns how far along a todo is: 100 once completed, otherwise its progress
+func (t Todo) Percent() int {
+	if t.Completed {
+		return 100
+	}
+	return t.Progress
+}
+
+// SetProgress sets the percent done of the todo at index, clamped to 0 to 100.
+// Completing it at 100, or reopening it below, is left to Toggle so the caller
+// can treat it like any other completion.
+func (tl *TodoList) SetProgress(index, percent int) {
+	if index < 0 || index >= len(tl.Todos) || tl.Todos[index].Heading {
+		return
+	}
+	tl.Todos[index].Progress = min(max(percent, 0), 100)
+	tl.persist()
+}
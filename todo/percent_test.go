@@ -0,0 +1,35 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestProgress tests that a todo's percent done is clamped and saved, and that
+// reopening a todo completed by progress starts it over
+func TestProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	tl := NewTodoList(path)
+	tl.Add("Write the thesis")
+
+	tl.SetProgress(0, 40)
+	if got := NewTodoList(path).Todos[0].Percent(); got != 40 {
+		t.Fatalf("Expected 40%% saved, got %d", got)
+	}
+	tl.SetProgress(0, 130)
+	if got := tl.Todos[0].Progress; got != 100 {
+		t.Errorf("Expected progress capped at 100, got %d", got)
+	}
+
+	tl.Toggle(0)
+	tl.Toggle(0)
+	if todo := tl.Todos[0]; todo.Completed || todo.Percent() != 0 {
+		t.Errorf("Expected the reopened todo back at 0%%, got %+v", todo)
+	}
+
+	tl.SetProgress(0, 60)
+	tl.Toggle(0)
+	if todo := tl.Todos[0]; todo.Percent() != 100 || todo.Progress != 60 {
+		t.Errorf("Expected a completed todo at 100%% keeping its progress, got %+v", todo)
+	}
+}
@@ -0,0 +1,49 @@
+package todo
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain drops the log records of the load and save failures tests provoke
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// TestDeferredSave tests that deferred lists only write on Flush
+func TestDeferredSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deferred.json")
+	tl := NewTodoList(path)
+	tl.SetDeferredSave(true)
+	tl.Add("pending")
+
+	if !tl.Dirty() {
+		t.Error("Expected list to be dirty after a deferred mutation")
+	}
+	if len(NewTodoList(path).Todos) != 0 {
+		t.Error("Deferred mutation should not be written before Flush")
+	}
+
+	if err := tl.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if tl.Dirty() || len(NewTodoList(path).Todos) != 1 {
+		t.Error("Flush should write pending changes and clear dirty")
+	}
+}
+
+// TestAutoSortDisabled tests that completed todos keep their position
+func TestAutoSortDisabled(t *testing.T) {
+	tl := NewTodoList(filepath.Join(t.TempDir(), "manual.json"))
+	tl.SetAutoSort(false)
+	tl.Add("b")
+	tl.Add("a")
+	tl.Toggle(0)
+
+	if tl.Todos[0].Title != "a" || !tl.Todos[0].Completed {
+		t.Errorf("Completed todo moved with auto-sort off: %+v", tl.Todos)
+	}
+}
@@ -0,0 +1,74 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrintOptions selects what PlainText includes besides titles
+type PrintOptions struct {
+	Due      bool // append due dates
+	Fields   bool // list custom fields under each todo
+	Notes    bool // print notes under each todo
+	OpenOnly bool // leave out completed todos
+}
+
+// PlainText renders the list as color-free, checkbox-annotated text
+// for printing or pasting into email
+func (tl *TodoList) PlainText(name string, opts PrintOptions) string {
+	var b strings.Builder
+	completed, total := tl.Counts()
+	header := fmt.Sprintf("%s (%d/%d done)", name, completed, total)
+	fmt.Fprintf(&b, "%s\n%s\n", header, strings.Repeat("=", len([]rune(header))))
+
+	for i, todo := range tl.Todos {
+		if todo.Heading {
+			done, n := tl.SectionStats(i)
+			heading := fmt.Sprintf("%s (%d/%d)", todo.Title, done, n)
+			fmt.Fprintf(&b, "\n%s\n%s\n", heading, strings.Repeat("-", len([]rune(heading))))
+			continue
+		}
+		if opts.OpenOnly && todo.Completed {
+			continue
+		}
+
+		box := "[ ]"
+		if todo.Completed {
+			box = "[x]"
+		}
+		fmt.Fprintf(&b, "%s %s", box, todo.Title)
+		if opts.Due && todo.Due != nil {
+			fmt.Fprintf(&b, "  (due %s)", todo.Due.Format("Mon Jan 2 15:04"))
+		}
+		b.WriteString("\n")
+		if opts.Fields {
+			for _, key := range todo.FieldKeys() {
+				fmt.Fprintf(&b, "    %s: %s\n", key, todo.Fields[key])
+			}
+		}
+		if opts.Notes && todo.Notes != "" {
+			for _, line := range strings.Split(todo.Notes, "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the list as a Markdown task list with sections as subheadings
+func (tl *TodoList) Markdown(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", name)
+	for _, todo := range tl.Todos {
+		if todo.Heading {
+			fmt.Fprintf(&b, "\n## %s\n\n", todo.Title)
+			continue
+		}
+		box := " "
+		if todo.Completed {
+			box = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", box, todo.Title)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPlainText tests the printable rendering with and without extras
+func TestPlainText(t *testing.T) {
+	due := time.Date(2024, 5, 3, 17, 0, 0, 0, time.Local)
+	tl := &TodoList{Todos: []Todo{
+		{ID: 1, Title: "call bank", Due: &due, Fields: map[string]string{"ticket": "T-1"}},
+		{ID: 2, Title: "Trip", Heading: true},
+		{ID: 3, Title: "book hotel"},
+		{ID: 4, Title: "buy tickets", Completed: true},
+	}}
+
+	want := `work (1/3 done)
+===============
+[ ] call bank
+
+Trip (1/2)
+----------
+[ ] book hotel
+[x] buy tickets
+`
+	if got := tl.PlainText("work", PrintOptions{}); got != want {
+		t.Errorf("PlainText =\n%s\nwant\n%s", got, want)
+	}
+
+	want = `work (1/3 done)
+===============
+[ ] call bank  (due Fri May 3 17:00)
+    ticket: T-1
+
+Trip (1/2)
+----------
+[ ] book hotel
+`
+	if got := tl.PlainText("work", PrintOptions{Due: true, Fields: true, OpenOnly: true}); got != want {
+		t.Errorf("PlainText with options =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestMarkdown tests rendering a list as a Markdown task list
+func TestMarkdown(t *testing.T) {
+	tl := &TodoList{Todos: []Todo{
+		{ID: 1, Title: "call bank"},
+		{ID: 2, Title: "Trip", Heading: true},
+		{ID: 3, Title: "buy tickets", Completed: true},
+	}}
+
+	want := "# work\n\n- [ ] call bank\n\n## Trip\n\n- [x] buy tickets\n"
+	if got := tl.Markdown("work"); got != want {
+		t.Errorf("Markdown = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package todo
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"testing/quick"
+)
+
+// checkInvariants reports the first broken list invariant, if any
+func checkInvariants(tl *TodoList) error {
+	seen := map[int]bool{}
+	for _, t := range tl.Todos {
+		if seen[t.ID] {
+			return fmt.Errorf("duplicate ID %d", t.ID)
+		}
+		if t.ID >= tl.NextID {
+			return fmt.Errorf("NextID %d not above ID %d", tl.NextID, t.ID)
+		}
+		seen[t.ID] = true
+	}
+	for i, t := range tl.Todos {
+		if got := tl.IndexOf(t.ID); got != i {
+			return fmt.Errorf("IndexOf(%d) = %d, want %d", t.ID, got, i)
+		}
+	}
+
+	// Within each section no incomplete todo follows a completed one
+	done := false
+	for _, t := range tl.Todos {
+		switch {
+		case t.Heading:
+			done = false
+		case t.Completed:
+			done = true
+		case done:
+			return fmt.Errorf("incomplete %q below a completed todo", t.Title)
+		}
+	}
+	return nil
+}
+
+// TestPropertyOperations applies random operation sequences and checks invariants after each step
+func TestPropertyOperations(t *testing.T) {
+	dir := t.TempDir()
+	run := 0
+
+	property := func(ops []uint16) bool {
+		run++
+		tl := NewTodoList(filepath.Join(dir, fmt.Sprintf("p%d.json", run)))
+		tl.SetDeferredSave(true)
+
+		for step, op := range ops {
+			n := len(tl.Todos)
+			index := 0
+			if n > 0 {
+				index = int(op>>3) % n
+			}
+			title := fmt.Sprintf("t%d", step)
+
+			switch op % 7 {
+			case 0:
+				tl.Add(title)
+				if len(tl.Todos) != n+1 {
+					t.Logf("Add changed length %d -> %d", n, len(tl.Todos))
+					return false
+				}
+			case 1:
+				tl.Insert(index, title)
+				if len(tl.Todos) != n+1 {
+					t.Logf("Insert changed length %d -> %d", n, len(tl.Todos))
+					return false
+				}
+			case 2:
+				tl.Delete(index)
+				if n > 0 && len(tl.Todos) != n-1 {
+					t.Logf("Delete changed length %d -> %d", n, len(tl.Todos))
+					return false
+				}
+			case 3, 4:
+				tl.Toggle(index)
+			case 5:
+				tl.ToggleHeading(index)
+			case 6:
+				tl.MoveToSection(index, int(op>>8)%2*2-1)
+			}
+
+			if len(tl.Todos) != n && op%7 > 2 {
+				t.Logf("op %d changed length %d -> %d", op%7, n, len(tl.Todos))
+				return false
+			}
+			if err := checkInvariants(tl); err != nil {
+				t.Logf("step %d (op %d): %v", step, op%7, err)
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 300}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestPropertySortStable tests that Sort keeps the relative order within completion groups
+func TestPropertySortStable(t *testing.T) {
+	property := func(flags []bool) bool {
+		tl := &TodoList{filepath: filepath.Join(t.TempDir(), "s.json"), NextID: len(flags) + 1}
+		var open, closed []int
+		for i, completed := range flags {
+			tl.Todos = append(tl.Todos, Todo{ID: i + 1, Completed: completed})
+			if completed {
+				closed = append(closed, i+1)
+			} else {
+				open = append(open, i+1)
+			}
+		}
+		tl.SetDeferredSave(true)
+		tl.Sort()
+
+		want := append(open, closed...)
+		if len(tl.Todos) != len(want) {
+			return false
+		}
+		for i, todo := range tl.Todos {
+			if todo.ID != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestPropertySortIdempotent tests that sorting an already sorted list changes nothing
+func TestPropertySortIdempotent(t *testing.T) {
+	property := func(flags []bool, headings []bool) bool {
+		tl := &TodoList{filepath: filepath.Join(t.TempDir(), "s.json"), NextID: len(flags) + 1}
+		for i, completed := range flags {
+			heading := i < len(headings) && headings[i]
+			tl.Todos = append(tl.Todos, Todo{ID: i + 1, Completed: completed && !heading, Heading: heading})
+		}
+		tl.SetDeferredSave(true)
+		tl.Sort()
+		once := append([]Todo(nil), tl.Todos...)
+		tl.Sort()
+
+		for i := range once {
+			if once[i].ID != tl.Todos[i].ID {
+				return false
+			}
+		}
+		return checkInvariants(tl) == nil
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,64 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestReload tests re-reading a list edited outside the app
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "edit.json")
+	tl := NewTodoList(path)
+	tl.Add("original")
+
+	os.WriteFile(path, []byte(`{"todos": [{"id": 7, "title": "edited"}], "next_id": 8}`), 0644)
+	if err := tl.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(tl.Todos) != 1 || tl.Todos[0].Title != "edited" || tl.NextID != 8 {
+		t.Errorf("Unexpected list after reload: %+v", tl)
+	}
+
+	os.WriteFile(path, []byte(`{"todos": [`), 0644)
+	if err := tl.Reload(); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+	if tl.Todos[0].Title != "edited" {
+		t.Error("Invalid reload should leave the list unchanged")
+	}
+	if _, err := os.Stat(path + ".corrupted"); err == nil {
+		t.Error("Reload should not create a corrupted backup")
+	}
+}
+
+// TestSafeLoad tests that safe mode loading writes nothing, backing up a corrupted file only before it is overwritten
+func TestSafeLoad(t *testing.T) {
+	SetSafe(true)
+	defer SetSafe(false)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "broken.json")
+	os.WriteFile(path, []byte(`{"todos": [`), 0644)
+
+	tl := NewTodoList(path)
+	if _, err := os.Stat(path + ".corrupted"); err == nil {
+		t.Fatal("Expected no backup written while loading")
+	}
+	tl.Add("start over")
+	if data, err := os.ReadFile(path + ".corrupted"); err != nil || string(data) != `{"todos": [` {
+		t.Errorf("Expected the original backed up before saving, got %q, %v", data, err)
+	}
+
+	// A habit list rolling over to a new day is reset in memory only
+	habits := filepath.Join(dir, "habits.json")
+	today := time.Now().Format(dayLayout)
+	os.WriteFile(habits, []byte(`{"kind": "habit", "todos": [{"id": 1, "title": "walk", "completed": true, "history": ["`+today+`"]}], "next_id": 2}`), 0644)
+	before, _ := os.ReadFile(habits)
+	hl := NewTodoList(habits)
+	hl.RefreshHabits(time.Now().AddDate(0, 0, 1))
+	if after, _ := os.ReadFile(habits); hl.Todos[0].Completed || string(after) != string(before) {
+		t.Error("Expected RefreshHabits to reset the checkmark without writing")
+	}
+}
@@ -0,0 +1,144 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that persists as a human-readable string
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string like "1h0m0s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseOffset parses a reminder offset such as "30m", "1h" or "2d"
+func ParseOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid offset %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	return d, nil
+}
+
+// ParseDue parses a due date: "today", "tomorrow", "YYYY-MM-DD" or "YYYY-MM-DD HH:MM".
+// Dates without a time are due at 09:00 local time.
+func ParseDue(s string, now time.Time) (time.Time, error) {
+	return parseDay(s, now, 9, "due date")
+}
+
+// ParseStart parses a start date like ParseDue, but dates without a time start at
+// midnight so the todo shows up first thing that day
+func ParseStart(s string, now time.Time) (time.Time, error) {
+	return parseDay(s, now, 0, "start date")
+}
+
+// parseDay parses the date formats of ParseDue, putting dates without a time at hour
+func parseDay(s string, now time.Time, hour int, what string) (time.Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	at := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, now.Location())
+	}
+
+	switch s {
+	case "today":
+		return at(now), nil
+	case "tomorrow":
+		return at(now.AddDate(0, 0, 1)), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", what, s)
+	}
+	return at(t), nil
+}
+
+// RemindAt returns when the todo's reminder fires, if it has one
+func (t Todo) RemindAt() (time.Time, bool) {
+	if t.Due == nil || t.RemindBefore == nil {
+		return time.Time{}, false
+	}
+	return t.Due.Add(-time.Duration(*t.RemindBefore)), true
+}
+
+// ReminderDue reports whether the todo has an unacknowledged reminder that has fired
+func (t Todo) ReminderDue(now time.Time) bool {
+	at, ok := t.RemindAt()
+	return ok && !t.Completed && !t.ReminderAcked && !now.Before(at)
+}
+
+// SetDue sets or clears (nil) the due date of a todo
+func (tl *TodoList) SetDue(index int, due *time.Time) {
+	if index >= 0 && index < len(tl.Todos) {
+		tl.Todos[index].Due = due
+		tl.Todos[index].ReminderAcked = false
+		tl.persist()
+	}
+}
+
+// SetStart sets or clears (nil) the date a todo is deferred until
+func (tl *TodoList) SetStart(index int, start *time.Time) {
+	if index >= 0 && index < len(tl.Todos) && !tl.Todos[index].Heading {
+		tl.Todos[index].Start = start
+		tl.persist()
+	}
+}
+
+// Deferred reports whether an open todo has a start date still ahead of now, so it
+// isn't actionable yet
+func (t Todo) Deferred(now time.Time) bool {
+	return !t.Completed && !t.Heading && t.Start != nil && now.Before(*t.Start)
+}
+
+// SetReminder sets how long before the due date a todo's reminder fires.
+// A negative offset clears the reminder.
+func (tl *TodoList) SetReminder(index int, before time.Duration) {
+	if index >= 0 && index < len(tl.Todos) {
+		if before < 0 {
+			tl.Todos[index].RemindBefore = nil
+		} else {
+			d := Duration(before)
+			tl.Todos[index].RemindBefore = &d
+		}
+		tl.Todos[index].ReminderAcked = false
+		tl.persist()
+	}
+}
+
+// AcknowledgeReminder marks the reminder of the todo with the given ID as seen
+func (tl *TodoList) AcknowledgeReminder(id int) {
+	if i := tl.IndexOf(id); i >= 0 {
+		tl.Todos[i].ReminderAcked = true
+		tl.persist()
+	}
+}
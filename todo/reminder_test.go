@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestReminderDue tests when reminders fire and stop firing
+func TestReminderDue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.json")
+	tl := NewTodoList(path)
+	tl.Add("dentist")
+
+	due := time.Now().Add(2 * time.Hour)
+	tl.SetDue(0, &due)
+	tl.SetReminder(0, time.Hour)
+
+	if tl.Todos[0].ReminderDue(time.Now()) {
+		t.Error("Reminder should not fire before its offset")
+	}
+	if !tl.Todos[0].ReminderDue(time.Now().Add(90 * time.Minute)) {
+		t.Error("Reminder should fire after its offset")
+	}
+
+	tl.AcknowledgeReminder(tl.Todos[0].ID)
+	if tl.Todos[0].ReminderDue(time.Now().Add(90 * time.Minute)) {
+		t.Error("Acknowledged reminder should not fire")
+	}
+
+	// Offsets persist as readable strings
+	loaded := NewTodoList(path)
+	data, _ := json.Marshal(loaded.Todos[0])
+	var raw map[string]any
+	json.Unmarshal(data, &raw)
+	if raw["remind_before"] != "1h0m0s" {
+		t.Errorf("Expected remind_before 1h0m0s, got %v", raw["remind_before"])
+	}
+}
+
+// TestParseOffset tests reminder offset parsing
+func TestParseOffset(t *testing.T) {
+	cases := map[string]time.Duration{"30m": 30 * time.Minute, "1h": time.Hour, "2d": 48 * time.Hour}
+	for in, want := range cases {
+		got, err := ParseOffset(in)
+		if err != nil || got != want {
+			t.Errorf("ParseOffset(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseOffset("soon"); err == nil {
+		t.Error("Expected error for invalid offset")
+	}
+}
+
+// TestDeferred tests that a todo with a start date ahead isn't actionable until then
+func TestDeferred(t *testing.T) {
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.Local)
+	start, err := ParseStart("2024-03-06", now)
+	if err != nil || start.Hour() != 0 || start.Day() != 6 {
+		t.Fatalf("Expected midnight on March 6, got %v, %v", start, err)
+	}
+	if _, err := ParseStart("someday", now); err == nil {
+		t.Error("Expected an error for an invalid start date")
+	}
+
+	path := filepath.Join(t.TempDir(), "tickler.json")
+	tl := NewTodoList(path)
+	tl.Add("renew passport")
+	tl.SetStart(0, &start)
+	todo := NewTodoList(path).Todos[0]
+	if !todo.Deferred(now) || todo.Deferred(start) {
+		t.Errorf("Expected the todo deferred until its start, got %+v", todo)
+	}
+	tl.Toggle(0)
+	if tl.Todos[0].Deferred(now) {
+		t.Error("Expected a completed todo not to count as deferred")
+	}
+}
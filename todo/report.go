@@ -0,0 +1,52 @@
+package todo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CompletedBetween returns todos completed within [since, until)
+func (tl *TodoList) CompletedBetween(since, until time.Time) []Todo {
+	var done []Todo
+	for _, todo := range tl.Todos {
+		if !todo.Completed || todo.CompletedAt == nil {
+			continue
+		}
+		if !todo.CompletedAt.Before(since) && todo.CompletedAt.Before(until) {
+			done = append(done, todo)
+		}
+	}
+	return done
+}
+
+// DoneReport renders a Markdown report of todos completed within [since, until)
+// across the given files, grouped by file
+func DoneReport(paths []string, since, until time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Done: %s – %s\n", since.Format("Mon Jan 2"), until.Add(-time.Second).Format("Mon Jan 2"))
+
+	total := 0
+	for _, path := range paths {
+		tl := NewTodoList(path)
+		done := tl.CompletedBetween(since, until)
+		if len(done) == 0 {
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		fmt.Fprintf(&b, "\n## %s\n\n", name)
+		for _, todo := range done {
+			fmt.Fprintf(&b, "- [x] %s (%s)\n", todo.Title, todo.CompletedAt.Format("Mon Jan 2"))
+		}
+		total += len(done)
+	}
+
+	if total == 0 {
+		b.WriteString("\nNothing completed in this range.\n")
+	} else {
+		fmt.Fprintf(&b, "\n_%d completed_\n", total)
+	}
+	return b.String()
+}
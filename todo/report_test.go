@@ -0,0 +1,43 @@
+package todo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCompletedBetween tests filtering todos by completion time
+func TestCompletedBetween(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-48 * time.Hour)
+
+	tl := &TodoList{Todos: []Todo{
+		{ID: 1, Title: "recent", Completed: true, CompletedAt: &now},
+		{ID: 2, Title: "old", Completed: true, CompletedAt: &earlier},
+		{ID: 3, Title: "open"},
+		{ID: 4, Title: "legacy", Completed: true},
+	}}
+
+	done := tl.CompletedBetween(now.Add(-time.Hour), now.Add(time.Hour))
+	if len(done) != 1 || done[0].ID != 1 {
+		t.Errorf("Expected only todo 1, got %+v", done)
+	}
+}
+
+// TestDoneReport tests Markdown report generation grouped by file
+func TestDoneReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	tl := NewTodoList(path)
+	tl.Add("ship it")
+	tl.Add("still open")
+	tl.Toggle(1)
+
+	report := DoneReport([]string{path}, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if !strings.Contains(report, "## work") || !strings.Contains(report, "- [x] ship it") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+	if strings.Contains(report, "still open") {
+		t.Errorf("Report should not include open todos:\n%s", report)
+	}
+}
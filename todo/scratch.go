@@ -0,0 +1,38 @@
+package todo
+
+import "slices"
+
+// NewScratchList creates a list that lives only in memory. Saving it does nothing.
+func NewScratchList() *TodoList {
+	return &TodoList{Todos: []Todo{}, NextID: 1}
+}
+
+// IsScratch reports whether the list has no file behind it
+func (tl *TodoList) IsScratch() bool {
+	return tl.filepath == ""
+}
+
+// MoveTo moves the todo at index to the top of dst, where it gets a fresh ID.
+// Both lists are saved in one transaction, so the todo never ends up in both or
+// neither; on failure they are left as they were.
+func (tl *TodoList) MoveTo(index int, dst *TodoList) error {
+	if index < 0 || index >= len(tl.Todos) {
+		return nil
+	}
+	tx := Begin(tl, dst)
+	removed := tl.Todos[index]
+	todo := removed
+	todo.ID = dst.NextID
+	dst.NextID++
+	dst.Todos = slices.Insert(dst.Todos, 0, todo)
+	dst.Sort()
+	tl.Todos = slices.Delete(tl.Todos, index, index+1)
+	tl.counts = nil
+	dst.counts = nil
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	dst.publish(TodoAdded, todo)
+	tl.publish(TodoDeleted, removed)
+	return nil
+}
@@ -0,0 +1,50 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestScratchListStaysInMemory tests that saving a scratch list writes nothing
+func TestScratchListStaysInMemory(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	tl := NewScratchList()
+	tl.Add("jot this down")
+	if err := tl.Save(); err != nil {
+		t.Fatalf("Expected saving a scratch list to succeed, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no files written, got %d", len(entries))
+	}
+	if !tl.IsScratch() || tl.Dirty() {
+		t.Error("Expected a clean scratch list")
+	}
+}
+
+// TestMoveTo tests moving a todo into another list with a fresh ID
+func TestMoveTo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "work.json")
+	dst := NewTodoList(path)
+	dst.Add("existing")
+
+	src := NewScratchList()
+	src.Add("keep me")
+	src.Add("throwaway")
+	src.SetField(1, "ticket", "T-1")
+
+	src.MoveTo(1, dst)
+	if len(src.Todos) != 1 || src.Todos[0].Title != "throwaway" {
+		t.Errorf("Expected only the other todo left behind, got %+v", src.Todos)
+	}
+
+	saved := NewTodoList(path)
+	if len(saved.Todos) != 2 || saved.Todos[0].Title != "keep me" {
+		t.Fatalf("Expected the moved todo on top of the saved list, got %+v", saved.Todos)
+	}
+	if saved.Todos[0].ID == saved.Todos[1].ID || saved.Todos[0].Fields["ticket"] != "T-1" {
+		t.Errorf("Expected a fresh ID and kept fields, got %+v", saved.Todos[0])
+	}
+}
@@ -0,0 +1,102 @@
+package todo
+
+import "slices"
+
+// A section is a heading todo followed by the todos up to the next heading.
+// Todos before the first heading form an implicit, untitled section.
+
+// sectionHeading returns the index of the heading owning index, or -1 if untitled
+func (tl *TodoList) sectionHeading(index int) int {
+	for i := index; i >= 0; i-- {
+		if i < len(tl.Todos) && tl.Todos[i].Heading {
+			return i
+		}
+	}
+	return -1
+}
+
+// SectionStart returns the index where new todos go in the section containing index
+func (tl *TodoList) SectionStart(index int) int {
+	return tl.sectionHeading(index) + 1
+}
+
+// SectionStats returns completed and total counts for the section under a heading
+func (tl *TodoList) SectionStats(heading int) (int, int) {
+	return tl.SectionStatsFunc(heading, func(Todo) bool { return true })
+}
+
+// SectionStatsFunc returns completed and total counts of the todos matching match
+// in the section under a heading
+func (tl *TodoList) SectionStatsFunc(heading int, match func(Todo) bool) (int, int) {
+	completed, total := 0, 0
+	for i := heading + 1; i < len(tl.Todos) && !tl.Todos[i].Heading; i++ {
+		if !match(tl.Todos[i]) {
+			continue
+		}
+		total++
+		if tl.Todos[i].Completed {
+			completed++
+		}
+	}
+	return completed, total
+}
+
+// ToggleHeading turns a todo into a section heading or back into a normal todo
+func (tl *TodoList) ToggleHeading(index int) {
+	if index >= 0 && index < len(tl.Todos) {
+		t := &tl.Todos[index]
+		t.Heading = !t.Heading
+		t.Collapsed = false
+		t.Completed = false
+		t.CompletedAt, t.CompletedBy = nil, ""
+		tl.Sort()
+		tl.persist()
+	}
+}
+
+// ToggleCollapsed collapses or expands the section under a heading
+func (tl *TodoList) ToggleCollapsed(index int) {
+	if index >= 0 && index < len(tl.Todos) && tl.Todos[index].Heading {
+		tl.Todos[index].Collapsed = !tl.Todos[index].Collapsed
+		tl.persist()
+	}
+}
+
+// MoveToSection moves a todo to the top of the next (dir > 0) or previous (dir < 0)
+// section and returns its new index
+func (tl *TodoList) MoveToSection(index int, dir int) int {
+	if index < 0 || index >= len(tl.Todos) || tl.Todos[index].Heading {
+		return index
+	}
+
+	current := tl.sectionHeading(index)
+	target := -2 // -2 means no target section
+	if dir > 0 {
+		for i := index + 1; i < len(tl.Todos); i++ {
+			if tl.Todos[i].Heading {
+				target = i
+				break
+			}
+		}
+	} else if current >= 0 {
+		target = tl.sectionHeading(current - 1)
+	}
+	if target == -2 {
+		return index
+	}
+
+	moved := tl.Todos[index]
+	tl.Todos = slices.Delete(tl.Todos, index, index+1)
+	if target > index {
+		target--
+	}
+	insertAt := target + 1
+	tl.Todos = slices.Insert(tl.Todos, insertAt, moved)
+	tl.Sort()
+	tl.persist()
+
+	if i := tl.IndexOf(moved.ID); i >= 0 {
+		return i
+	}
+	return insertAt
+}
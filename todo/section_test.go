@@ -0,0 +1,115 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// titles returns the titles of a list in order
+func titles(tl *TodoList) []string {
+	var out []string
+	for _, t := range tl.Todos {
+		out = append(out, t.Title)
+	}
+	return out
+}
+
+// TestSortKeepsSections tests that completed todos sink only within their section
+func TestSortKeepsSections(t *testing.T) {
+	tl := &TodoList{filepath: filepath.Join(t.TempDir(), "s.json"), Todos: []Todo{
+		{ID: 1, Title: "a", Completed: true},
+		{ID: 2, Title: "b"},
+		{ID: 3, Title: "Phase 2", Heading: true},
+		{ID: 4, Title: "c", Completed: true},
+		{ID: 5, Title: "d"},
+	}}
+	tl.Sort()
+
+	want := []string{"b", "a", "Phase 2", "d", "c"}
+	got := titles(tl)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Sort = %v, want %v", got, want)
+		}
+	}
+
+	if done, total := tl.SectionStats(2); done != 1 || total != 2 {
+		t.Errorf("SectionStats = %d/%d, want 1/2", done, total)
+	}
+}
+
+// TestMoveToSection tests moving todos between sections
+func TestMoveToSection(t *testing.T) {
+	tl := &TodoList{filepath: filepath.Join(t.TempDir(), "s.json"), Todos: []Todo{
+		{ID: 1, Title: "a"},
+		{ID: 2, Title: "One", Heading: true},
+		{ID: 3, Title: "b"},
+		{ID: 4, Title: "Two", Heading: true},
+	}}
+
+	idx := tl.MoveToSection(0, 1)
+	if idx != 1 || tl.Todos[idx].Title != "a" || tl.Todos[0].Title != "One" {
+		t.Fatalf("Move down: got index %d, list %v", idx, titles(tl))
+	}
+
+	idx = tl.MoveToSection(idx, 1)
+	if idx != 3 || tl.Todos[2].Title != "Two" {
+		t.Fatalf("Move into empty trailing section: got index %d, list %v", idx, titles(tl))
+	}
+
+	idx = tl.MoveToSection(idx, -1)
+	if tl.Todos[idx-1].Title != "One" {
+		t.Fatalf("Move up: got index %d, list %v", idx, titles(tl))
+	}
+
+	tl.Toggle(0)
+	if tl.Todos[0].Completed {
+		t.Error("Headings must not be completable")
+	}
+}
+
+// TestSectionStatsFunc tests counting only the matching todos of a section
+func TestSectionStatsFunc(t *testing.T) {
+	tl := &TodoList{Todos: []Todo{
+		{ID: 1, Title: "Trip", Heading: true},
+		{ID: 2, Title: "book @phone"},
+		{ID: 3, Title: "pack"},
+		{ID: 4, Title: "call @phone", Completed: true},
+		{ID: 5, Title: "Later", Heading: true},
+		{ID: 6, Title: "email @phone", Completed: true},
+	}}
+
+	if completed, total := tl.SectionStats(0); completed != 1 || total != 3 {
+		t.Errorf("SectionStats = %d/%d, want 1/3", completed, total)
+	}
+	phone := func(t Todo) bool { return t.HasContext("phone") }
+	if completed, total := tl.SectionStatsFunc(0, phone); completed != 1 || total != 2 {
+		t.Errorf("SectionStatsFunc = %d/%d, want 1/2", completed, total)
+	}
+}
+
+// TestSortDoesNotSave tests that sorting only reorders in memory while mutations still save
+func TestSortDoesNotSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.json")
+	tl := &TodoList{filepath: path, NextID: 3, Todos: []Todo{
+		{ID: 1, Title: "a", Completed: true},
+		{ID: 2, Title: "b"},
+	}}
+
+	// A transient copy sorts without touching the original or the disk
+	sorted := &TodoList{filepath: path, Todos: slices.Clone(tl.Todos)}
+	sorted.Sort()
+	if titles(sorted)[0] != "b" || titles(tl)[0] != "a" {
+		t.Errorf("Expected only the copy sorted, got %v and %v", titles(sorted), titles(tl))
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("Expected Sort not to write the file")
+	}
+
+	tl.Toggle(0)
+	if loaded := NewTodoList(path); len(loaded.Todos) != 2 || loaded.Todos[1].Completed {
+		t.Errorf("Expected the toggle saved, got %+v", loaded.Todos)
+	}
+}
@@ -0,0 +1,142 @@
+package todo
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// List sorts for ListSettings.Sort
+const (
+	SortManual  = ""        // in the order they were added
+	SortDue     = "due"     // earliest due first, undated last
+	SortTitle   = "title"   // alphabetical
+	SortCreated = "created" // newest first
+)
+
+// ListSettings are a list's own settings, saved in its file ahead of the todos. They
+// take precedence over the global ones, so a groceries list can behave differently
+// from a project backlog.
+type ListSettings struct {
+	Sort        string   `json:"sort,omitempty"`         // order of the todos in each section, see SortDue
+	ManualOrder *bool    `json:"manual_order,omitempty"` // completed todos keep their place, nil follows the global setting
+	Tags        []string `json:"tags,omitempty"`         // contexts added to new todos, without the @
+	Color       string   `json:"color,omitempty"`        // name of the color the list is shown in
+	Template    string   `json:"template,omitempty"`     // template the list was created from
+}
+
+// isZero reports whether the settings change nothing, so the file needs no block
+func (s ListSettings) isZero() bool {
+	return s.Sort == "" && s.ManualOrder == nil && len(s.Tags) == 0 && s.Color == "" && s.Template == ""
+}
+
+// clone returns a copy of the settings sharing nothing with s
+func (s ListSettings) clone() ListSettings {
+	if s.ManualOrder != nil {
+		manual := *s.ManualOrder
+		s.ManualOrder = &manual
+	}
+	s.Tags = slices.Clone(s.Tags)
+	return s
+}
+
+// ListSettings returns the list's own settings, the zero settings when it has none
+func (tl *TodoList) ListSettings() ListSettings {
+	if tl.Settings == nil {
+		return ListSettings{}
+	}
+	return tl.Settings.clone()
+}
+
+// SetSettings replaces the list's own settings and orders its todos by them. The zero
+// settings remove the block from the file.
+func (tl *TodoList) SetSettings(s ListSettings) {
+	if s.isZero() {
+		tl.Settings = nil
+	} else {
+		s = s.clone()
+		tl.Settings = &s
+	}
+	tl.Sort()
+	tl.persist()
+}
+
+// AutoSort reports whether completed todos sink to the bottom of their section, by
+// the list's own setting or else the one given to SetAutoSort
+func (tl *TodoList) AutoSort() bool {
+	if tl.Settings != nil && tl.Settings.ManualOrder != nil {
+		return !*tl.Settings.ManualOrder
+	}
+	return !tl.keepOrder
+}
+
+// sortSections orders the todos in each section by the list's sort, keeping
+// completed todos below the open ones when they sink
+func (tl *TodoList) sortSections() {
+	if tl.Settings == nil || tl.Settings.Sort == SortManual {
+		return
+	}
+	by := tl.Settings.Sort
+	sink := tl.AutoSort()
+	compare := func(a, b Todo) int {
+		if sink && a.Completed != b.Completed {
+			if a.Completed {
+				return 1
+			}
+			return -1
+		}
+		switch by {
+		case SortDue:
+			if a.Due == nil || b.Due == nil {
+				return boolCompare(a.Due == nil, b.Due == nil)
+			}
+			return a.Due.Compare(*b.Due)
+		case SortTitle:
+			return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case SortCreated:
+			return b.CreatedAt.Compare(a.CreatedAt)
+		}
+		return 0
+	}
+
+	start := 0
+	for i := 0; i <= len(tl.Todos); i++ {
+		if i == len(tl.Todos) || tl.Todos[i].Heading {
+			slices.SortStableFunc(tl.Todos[start:i], compare)
+			start = i + 1
+		}
+	}
+}
+
+// boolCompare orders false before true
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// withTags appends the list's tags missing from the title of a new todo
+func (tl *TodoList) withTags(title string) string {
+	if tl.Settings == nil {
+		return title
+	}
+	for _, tag := range tl.Settings.Tags {
+		if !(Todo{Title: title}).HasContext(tag) {
+			title += " @" + tag
+		}
+	}
+	return title
+}
+
+// templateSettings returns the settings a list created from the template tl starts
+// with: the template's own, noting where they came from
+func (tl *TodoList) templateSettings() *ListSettings {
+	s := tl.ListSettings()
+	s.Template = strings.TrimSuffix(filepath.Base(tl.filepath), filepath.Ext(tl.filepath))
+	return &s
+}
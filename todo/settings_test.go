@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestListSettings tests that a list's own settings are saved ahead of its todos and
+// change how it sorts and tags new todos
+func TestListSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backlog.json")
+	tl := NewTodoList(path)
+	for _, title := range []string{"write docs", "fix login", "plan sprint"} {
+		tl.Add(title)
+	}
+	soon, later := time.Now().Add(time.Hour), time.Now().Add(48*time.Hour)
+	tl.SetDue(tl.IndexOf(1), &later)
+	tl.SetDue(tl.IndexOf(2), &soon)
+
+	manual := true
+	tl.SetSettings(ListSettings{Sort: SortDue, ManualOrder: &manual, Tags: []string{"work"}, Color: "teal"})
+	titles := func(tl *TodoList) []string {
+		var titles []string
+		for _, todo := range tl.Todos {
+			titles = append(titles, todo.Title)
+		}
+		return titles
+	}
+	if got, want := titles(tl), []string{"fix login", "write docs", "plan sprint"}; !slices.Equal(got, want) {
+		t.Errorf("Expected todos by due date, got %v", got)
+	}
+	if tl.AutoSort() {
+		t.Error("Expected the list's manual order to win over the global setting")
+	}
+
+	tl.Add("review @work")
+	tl.Add("deploy")
+	if got := tl.Todos[tl.IndexOf(5)].Title; got != "deploy @work" {
+		t.Errorf("Expected the list's tag added, got %q", got)
+	}
+	if got := tl.Todos[tl.IndexOf(4)].Title; got != "review @work" {
+		t.Errorf("Expected a tag already there kept once, got %q", got)
+	}
+	tl.AddMail(Mail{Subject: "invoice"})
+	tl.Import([]Todo{{Title: "imported"}})
+	if _, err := tl.ApplyBulk(nil, []BulkLine{{Title: "pasted"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"invoice @work", "imported @work", "pasted @work"} {
+		if !slices.Contains(titles(tl), title) {
+			t.Errorf("Expected the list's tag on todos added in other ways, got %v", titles(tl))
+		}
+	}
+
+	loaded := NewTodoList(path)
+	if s := loaded.ListSettings(); s.Sort != SortDue || s.ManualOrder == nil || !*s.ManualOrder || s.Color != "teal" {
+		t.Errorf("Expected the settings loaded, got %+v", s)
+	}
+	if h := ReadHeader(path); h.Settings == nil || h.Settings.Color != "teal" {
+		t.Errorf("Expected the settings in the header, got %+v", h.Settings)
+	}
+
+	made, err := loaded.Instantiate(filepath.Join(dir, "next.json"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := made.ListSettings(); s.Template != "backlog" || s.Color != "teal" {
+		t.Errorf("Expected the template's settings and origin, got %+v", s)
+	}
+
+	loaded.SetSettings(ListSettings{})
+	if NewTodoList(path).Settings != nil {
+		t.Error("Expected the zero settings to remove the block")
+	}
+}
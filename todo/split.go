@@ -0,0 +1,84 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SplitTo moves the todos matching match into a new list at path, preserving
+// their order and IDs. Section headings are never moved. Returns the number moved.
+func (tl *TodoList) SplitTo(path string, match func(Todo) bool) (int, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("%s already exists", filepath.Base(path))
+	}
+
+	dst := &TodoList{Todos: []Todo{}, NextID: 1, filepath: path}
+	keep := make([]Todo, 0, len(tl.Todos))
+	for _, todo := range tl.Todos {
+		if !todo.Heading && match(todo) {
+			dst.Todos = append(dst.Todos, todo)
+			if todo.ID >= dst.NextID {
+				dst.NextID = todo.ID + 1
+			}
+		} else {
+			keep = append(keep, todo)
+		}
+	}
+	if len(dst.Todos) == 0 {
+		return 0, fmt.Errorf("no todos to split")
+	}
+
+	// Write both files in one go so a failure can't lose or duplicate todos
+	tx := Begin(tl, dst)
+	tl.Todos = keep
+	tl.counts = nil
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(dst.Todos), nil
+}
+
+// Append adds the todos of src below the list's own, preserving their order but
+// giving them fresh IDs, and saves the list. src is left as it is. Returns the
+// number added.
+func (tl *TodoList) Append(src *TodoList) (int, error) {
+	added := tl.appendTodos(src)
+	if err := tl.Save(); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// Absorb appends the lists at paths like Append and deletes their files, saving
+// the list and deleting them in one transaction: on failure every file and the
+// list are left as they were. Returns the number of todos added.
+func (tl *TodoList) Absorb(paths ...string) (int, error) {
+	tx := Begin(tl)
+	added := 0
+	for _, path := range paths {
+		src := &TodoList{Todos: []Todo{}, NextID: 1, filepath: path}
+		if err := src.Load(); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		added += tl.appendTodos(src)
+		tx.Remove(path)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// appendTodos adds the todos of src below the list's own with fresh IDs
+func (tl *TodoList) appendTodos(src *TodoList) int {
+	for _, todo := range src.Todos {
+		todo.ID = tl.NextID
+		tl.NextID++
+		tl.Todos = append(tl.Todos, todo)
+	}
+	tl.counts = nil
+	tl.Sort() // Keep completed at bottom
+	return len(src.Todos)
+}
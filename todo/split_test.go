@@ -0,0 +1,104 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSplitTo tests moving matching todos into a new file
+func TestSplitTo(t *testing.T) {
+	dir := t.TempDir()
+	src := NewTodoList(filepath.Join(dir, "inbox.json"))
+	src.Add("a @x")
+	src.Add("b")
+	src.Add("c @x")
+
+	dstPath := filepath.Join(dir, "x.json")
+	moved, err := src.SplitTo(dstPath, func(t Todo) bool { return t.HasContext("x") })
+	if err != nil {
+		t.Fatalf("SplitTo failed: %v", err)
+	}
+	if moved != 2 || len(src.Todos) != 1 {
+		t.Fatalf("Expected 2 moved and 1 kept, got %d moved, %d kept", moved, len(src.Todos))
+	}
+
+	dst := NewTodoList(dstPath)
+	if dst.Todos[0].Title != "c @x" || dst.Todos[0].ID != 3 || dst.Todos[1].ID != 1 {
+		t.Errorf("Order or IDs not preserved: %+v", dst.Todos)
+	}
+	if dst.NextID != 4 {
+		t.Errorf("Expected NextID 4, got %d", dst.NextID)
+	}
+
+	reloaded := NewTodoList(filepath.Join(dir, "inbox.json"))
+	if len(reloaded.Todos) != 1 {
+		t.Errorf("Source not saved after split: %+v", reloaded.Todos)
+	}
+
+	if _, err := src.SplitTo(dstPath, func(Todo) bool { return true }); err == nil {
+		t.Error("Expected error when destination exists")
+	}
+}
+
+// TestAppend tests adding the todos of another list with fresh IDs
+func TestAppend(t *testing.T) {
+	dir := t.TempDir()
+	dst := NewTodoList(filepath.Join(dir, "work.json"))
+	dst.Add("mine")
+	src := NewTodoList(filepath.Join(dir, "q3.json"))
+	src.Add("second")
+	src.Add("first")
+
+	added, err := dst.Append(src)
+	if err != nil || added != 2 {
+		t.Fatalf("Expected 2 added, got %d, %v", added, err)
+	}
+	reloaded := NewTodoList(filepath.Join(dir, "work.json"))
+	var titles []string
+	for _, todo := range reloaded.Todos {
+		titles = append(titles, todo.Title)
+	}
+	if len(titles) != 3 || titles[0] != "mine" || titles[1] != "first" || titles[2] != "second" {
+		t.Errorf("Expected own todos first, then src's in order, got %v", titles)
+	}
+	if reloaded.Todos[1].ID != 2 || reloaded.Todos[2].ID != 3 || reloaded.NextID != 4 {
+		t.Errorf("Expected fresh IDs 2 and 3, got %+v", reloaded.Todos)
+	}
+	if len(NewTodoList(filepath.Join(dir, "q3.json")).Todos) != 2 {
+		t.Error("Expected src left as it is")
+	}
+}
+
+// TestAbsorb tests merging lists into one, their files deleted, or nothing at all
+// when one of them can't be read
+func TestAbsorb(t *testing.T) {
+	dir := t.TempDir()
+	dst := NewTodoList(filepath.Join(dir, "work.json"))
+	dst.Add("mine")
+	for _, name := range []string{"q3.json", "q4.json"} {
+		NewTodoList(filepath.Join(dir, name)).Add(name)
+	}
+	broken := filepath.Join(dir, "broken.json")
+	os.WriteFile(broken, []byte("{not json"), 0644)
+
+	if _, err := dst.Absorb(filepath.Join(dir, "q3.json"), broken); err == nil {
+		t.Fatal("Expected an unreadable list to stop the merge")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "q3.json")); err != nil || len(dst.Todos) != 1 {
+		t.Errorf("Expected nothing merged, got %+v, %v", dst.Todos, err)
+	}
+
+	added, err := dst.Absorb(filepath.Join(dir, "q3.json"), filepath.Join(dir, "q4.json"))
+	if err != nil || added != 2 {
+		t.Fatalf("Expected 2 added, got %d, %v", added, err)
+	}
+	if got := len(NewTodoList(filepath.Join(dir, "work.json")).Todos); got != 3 {
+		t.Errorf("Expected 3 todos saved, got %d", got)
+	}
+	for _, name := range []string{"q3.json", "q4.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s deleted, got %v", name, err)
+		}
+	}
+}
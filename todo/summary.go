@@ -0,0 +1,116 @@
+package todo
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Summary counts open todos across lists and picks the most pressing ones
+type Summary struct {
+	Open      int
+	Overdue   int
+	DueToday  int
+	Completed int // completed since the start of the day
+	Top       []SummaryItem
+}
+
+// SummaryItem is a pressing open todo and the list holding it
+type SummaryItem struct {
+	List string
+	Todo Todo
+}
+
+// urgency ranks an open todo: overdue, flagged, due today, dated, then everything else
+func urgency(t Todo, now, tomorrow time.Time) int {
+	switch {
+	case t.Due != nil && t.Due.Before(now):
+		return 0
+	case t.Flagged:
+		return 1
+	case t.Due != nil && t.Due.Before(tomorrow):
+		return 2
+	case t.Due != nil:
+		return 3
+	}
+	return 4
+}
+
+// Summarize summarizes the given lists at now, keeping up to top pressing todos.
+// Habit lists are skipped since their checkmarks reset every day.
+func Summarize(paths []string, now time.Time, top int) Summary {
+	today := Midnight(now)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	var s Summary
+	var pressing []SummaryItem
+	for _, path := range paths {
+		tl := NewTodoList(path)
+		if tl.IsHabit() {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, t := range tl.Todos {
+			switch {
+			case t.Heading:
+			case t.Completed:
+				if t.CompletedAt != nil && !t.CompletedAt.Before(today) {
+					s.Completed++
+				}
+			default:
+				s.Open++
+				rank := urgency(t, now, tomorrow)
+				if rank == 0 {
+					s.Overdue++
+				} else if t.Due != nil && t.Due.Before(tomorrow) {
+					s.DueToday++
+				}
+				if rank < 4 {
+					pressing = append(pressing, SummaryItem{List: name, Todo: t})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(pressing, func(i, j int) bool {
+		a, b := pressing[i].Todo, pressing[j].Todo
+		if ra, rb := urgency(a, now, tomorrow), urgency(b, now, tomorrow); ra != rb {
+			return ra < rb
+		}
+		if a.Due != nil && b.Due != nil && !a.Due.Equal(*b.Due) {
+			return a.Due.Before(*b.Due)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	s.Top = pressing[:min(top, len(pressing))]
+	return s
+}
+
+// Text renders the summary as a short plain text message for chat
+func (s Summary) Text(now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "justdoit summary for %s\n", now.Format("Mon Jan 2"))
+	fmt.Fprintf(&b, "%d open · %d overdue · %d due today · %d completed today\n", s.Open, s.Overdue, s.DueToday, s.Completed)
+	for _, item := range s.Top {
+		t := item.Todo
+		var tags []string
+		switch {
+		case t.Due != nil && t.Due.Before(now):
+			tags = append(tags, "overdue since "+t.Due.Format("Jan 2"))
+		case t.Due != nil:
+			tags = append(tags, "due "+t.Due.Format("Jan 2 15:04"))
+		}
+		if t.Flagged {
+			tags = append(tags, "flagged")
+		}
+		title, _ := SplitTitle(t.Title, 100)
+		fmt.Fprintf(&b, "• %s (%s", title, item.List)
+		if len(tags) > 0 {
+			fmt.Fprintf(&b, ", %s", strings.Join(tags, ", "))
+		}
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
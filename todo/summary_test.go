@@ -0,0 +1,50 @@
+package todo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSummarize tests the counts and the order of the most pressing todos
+func TestSummarize(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 20, 12, 0, 0, 0, time.Local)
+	yesterday, tonight, nextWeek := now.AddDate(0, 0, -1), now.Add(6*time.Hour), now.AddDate(0, 0, 7)
+
+	work := NewTodoList(filepath.Join(dir, "work.json"))
+	work.Add("someday")
+	work.Add("ship release")
+	work.Add("review PR")
+	work.Add("done this morning")
+	work.Todos[0].Completed = true
+	work.Todos[0].CompletedAt = &now
+	work.Todos[1].Due = &tonight
+	work.Todos[2].Flagged = true
+	work.Save()
+
+	home := NewTodoList(filepath.Join(dir, "home.json"))
+	home.Add("pay rent")
+	home.Add("book flights")
+	home.Todos[0].Due = &nextWeek
+	home.Todos[1].Due = &yesterday
+	home.Save()
+
+	s := Summarize([]string{filepath.Join(dir, "work.json"), filepath.Join(dir, "home.json")}, now, 3)
+	if s.Open != 5 || s.Overdue != 1 || s.DueToday != 1 || s.Completed != 1 {
+		t.Errorf("Expected 5 open, 1 overdue, 1 due today, 1 completed, got %+v", s)
+	}
+	var titles []string
+	for _, item := range s.Top {
+		titles = append(titles, item.Todo.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "pay rent, ship release, review PR" {
+		t.Errorf("Expected overdue, flagged, then due today, got %s", got)
+	}
+
+	text := s.Text(now)
+	if !strings.Contains(text, "• pay rent (home, overdue since Mar 19)") {
+		t.Errorf("Expected the overdue todo with its list, got:\n%s", text)
+	}
+}
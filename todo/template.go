@@ -0,0 +1,71 @@
+package todo
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// placeholderPattern matches {{name}} placeholders in template titles
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([\w-]+)\s*\}\}`)
+
+// Placeholders returns the distinct placeholder names in the list's titles, in order of first use
+func (tl *TodoList) Placeholders() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, todo := range tl.Todos {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(todo.Title, -1) {
+			if name := match[1]; !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// FillPlaceholders replaces {{name}} placeholders with their values.
+// Placeholders without a value are left as they are.
+func FillPlaceholders(title string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(title, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// Instantiate writes a fresh copy of the list to path with placeholders filled in.
+// Todos start open and undated; order, IDs, headings and flags are kept.
+func (tl *TodoList) Instantiate(path string, values map[string]string) (*TodoList, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", filepath.Base(path))
+	}
+
+	now := time.Now()
+	dst := &TodoList{
+		Todos:    make([]Todo, 0, len(tl.Todos)),
+		NextID:   tl.NextID,
+		Kind:     tl.Kind,
+		Settings: tl.templateSettings(),
+		filepath: path,
+	}
+	for _, todo := range tl.Todos {
+		dst.Todos = append(dst.Todos, Todo{
+			ID:        todo.ID,
+			Title:     FillPlaceholders(todo.Title, values),
+			CreatedAt: now,
+			Heading:   todo.Heading,
+			Flagged:   todo.Flagged,
+			Fields:    maps.Clone(todo.Fields),
+		})
+	}
+	if err := dst.Save(); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
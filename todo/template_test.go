@@ -0,0 +1,72 @@
+package todo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestPlaceholders tests collecting distinct placeholder names in order
+func TestPlaceholders(t *testing.T) {
+	tl := &TodoList{Todos: []Todo{
+		{Title: "Release {{ version }}", Heading: true},
+		{Title: "Tag {{version}} on {{date}}"},
+		{Title: "Announce {{project}} {{version}}"},
+		{Title: "No placeholders {here}"},
+	}}
+
+	want := []string{"version", "date", "project"}
+	if got := tl.Placeholders(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders = %v, want %v", got, want)
+	}
+}
+
+// TestFillPlaceholders tests substitution, leaving unknown placeholders alone
+func TestFillPlaceholders(t *testing.T) {
+	values := map[string]string{"city": "Lisbon", "date": ""}
+	got := FillPlaceholders("Pack for {{city}} {{ date }} ({{nights}} nights)", values)
+	if want := "Pack for Lisbon  ({{nights}} nights)"; got != want {
+		t.Errorf("FillPlaceholders = %q, want %q", got, want)
+	}
+}
+
+// TestInstantiate tests that a template becomes a fresh, filled-in list
+func TestInstantiate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := NewTodoList(filepath.Join(dir, "release.json"))
+	tmpl.Add("Publish {{version}}")
+	tmpl.Add("Tag {{version}}")
+	tmpl.Add("Release {{version}}")
+	tmpl.ToggleHeading(0)
+	tmpl.Toggle(tmpl.IndexOf(2))
+	tmpl.ToggleFlag(tmpl.IndexOf(1))
+
+	path := filepath.Join(dir, "release-1.2.json")
+	tl, err := tmpl.Instantiate(path, map[string]string{"version": "1.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewTodoList(path)
+	if len(loaded.Todos) != len(tl.Todos) || loaded.NextID != tmpl.NextID {
+		t.Fatalf("Expected the saved copy to match, got %+v", loaded)
+	}
+	for i, todo := range loaded.Todos {
+		if todo.ID != tmpl.Todos[i].ID || todo.Heading != tmpl.Todos[i].Heading {
+			t.Errorf("Todo %d: expected order and headings kept, got %+v", i, todo)
+		}
+		if todo.Completed {
+			t.Errorf("Expected %q to start open", todo.Title)
+		}
+	}
+	if loaded.Todos[0].Title != "Release 1.2" || loaded.Todos[loaded.IndexOf(2)].Title != "Tag 1.2" {
+		t.Errorf("Expected filled titles, got %+v", loaded.Todos)
+	}
+	if !loaded.Todos[loaded.IndexOf(1)].Flagged {
+		t.Error("Expected flags to be kept")
+	}
+
+	if _, err := tmpl.Instantiate(path, nil); err == nil {
+		t.Error("Expected an error when the file already exists")
+	}
+}
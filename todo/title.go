@@ -0,0 +1,35 @@
+package todo
+
+import "strings"
+
+// SplitTitle cuts a title longer than max runes, preferring a word boundary, and
+// returns the shortened title (ending in …) and the text that was cut off
+func SplitTitle(title string, max int) (string, string) {
+	runes := []rune(title)
+	if max <= 0 || len(runes) <= max {
+		return title, ""
+	}
+
+	cut := max - 1 // leave room for the ellipsis
+	for i := cut; i > cut/2; i-- {
+		if runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	head := strings.TrimRight(string(runes[:cut]), " ")
+	return head + "…", strings.TrimSpace(string(runes[cut:]))
+}
+
+// fitTitle moves the part of a todo's title beyond the length limit into its notes
+func (tl *TodoList) fitTitle(t *Todo) {
+	title, overflow := SplitTitle(t.Title, tl.maxTitle)
+	if overflow == "" {
+		return
+	}
+	t.Title = title
+	if t.Notes != "" {
+		overflow += "\n\n" + t.Notes
+	}
+	t.Notes = overflow
+}
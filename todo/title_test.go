@@ -0,0 +1,49 @@
+package todo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSplitTitle tests cutting long titles at a word boundary when one is near
+func TestSplitTitle(t *testing.T) {
+	tests := []struct {
+		title, head, rest string
+		max               int
+	}{
+		{"short", "short", "", 10},
+		{"no limit at all", "no limit at all", "", 0},
+		{"call the plumber about the sink", "call the…", "plumber about the sink", 12},
+		{"supercalifragilistic", "superca…", "lifragilistic", 8},
+		{"日本語のタイトルです", "日本語の…", "タイトルです", 5},
+	}
+	for _, tt := range tests {
+		head, rest := SplitTitle(tt.title, tt.max)
+		if head != tt.head || rest != tt.rest {
+			t.Errorf("SplitTitle(%q, %d) = %q, %q; want %q, %q", tt.title, tt.max, head, rest, tt.head, tt.rest)
+		}
+	}
+}
+
+// TestTitleOverflowMovesToNotes tests that saving a long title keeps the rest in the notes
+func TestTitleOverflowMovesToNotes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "long.json")
+	tl := NewTodoList(path)
+	tl.SetMaxTitleLength(20)
+
+	tl.Add(strings.Repeat("word ", 10))
+	todo := NewTodoList(path).Todos[0]
+	if n := len([]rune(todo.Title)); n > 20 {
+		t.Errorf("Expected title cut to 20 runes, got %d: %q", n, todo.Title)
+	}
+	if todo.Notes == "" || !strings.HasSuffix(todo.Notes, "word") {
+		t.Errorf("Expected the overflow in notes, got %q", todo.Notes)
+	}
+
+	// Editing keeps earlier notes after the new overflow
+	tl.Update(0, strings.Repeat("x", 25))
+	if notes := tl.Todos[0].Notes; !strings.HasPrefix(notes, "xxxxxx\n\n") || !strings.HasSuffix(notes, todo.Notes) {
+		t.Errorf("Expected new overflow before the old notes, got %q", notes)
+	}
+}
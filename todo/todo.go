@@ -4,24 +4,73 @@ package todo
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
-	"path/filepath"
+	"slices"
 	"time"
 )
 
 // Todo represents a single todo item
 type Todo struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Completed bool      `json:"completed"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	Due           *time.Time `json:"due,omitempty"`
+	Start         *time.Time `json:"start,omitempty"` // deferred until then, see Deferred
+	RemindBefore  *Duration  `json:"remind_before,omitempty"`
+	ReminderAcked bool       `json:"reminder_acked,omitempty"`
+
+	Heading   bool `json:"heading,omitempty"`   // section header rather than a task
+	Collapsed bool `json:"collapsed,omitempty"` // heading's section is folded in the UI
+
+	Flagged  bool `json:"flagged,omitempty"`  // marked as a priority
+	Progress int  `json:"progress,omitempty"` // percent done, 0 to 100, for todos done bit by bit
+
+	AddedBy     string `json:"added_by,omitempty"`     // author who added it, see SetAuthor
+	CompletedBy string `json:"completed_by,omitempty"` // author who completed it
+
+	History []string `json:"history,omitempty"` // sorted days a habit was done (habit lists only)
+
+	Fields map[string]string `json:"fields,omitempty"` // user-defined metadata like ticket=JIRA-123
+	Check  string            `json:"check,omitempty"`  // shell command whose success marks the todo done
+	Notes  string            `json:"notes,omitempty"`  // free text; receives the overflow of long titles
 }
 
 // TodoList holds all todos and manages persistence
 type TodoList struct {
-	Todos    []Todo `json:"todos"`
-	NextID   int    `json:"next_id"`
+	Version  int           `json:"version,omitempty"`  // FormatVersion when saved, 0 for older files
+	Title    string        `json:"title,omitempty"`    // shown instead of the filename, see SetTitle
+	Archived time.Time     `json:"archived,omitzero"`  // when the list was archived, see SetArchived
+	Original string        `json:"original,omitempty"` // filename before archiving renamed it
+	Settings *ListSettings `json:"settings,omitempty"` // the list's own settings, see SetSettings
+	Todos    []Todo        `json:"todos"`
+	NextID   int           `json:"next_id"`
+	Kind     string        `json:"kind,omitempty"` // "" or KindHabit
 	filepath string
+
+	keepOrder bool // don't move completed todos to the bottom
+	maxTitle  int  // longer titles spill into the notes, 0 for no limit
+	deferSave bool // mutations mark the list dirty instead of saving
+	dirty     bool // unsaved changes pending (deferred saving only)
+
+	index   map[int]int // ID to position, rebuilt on a stale lookup
+	done    []Todo      // scratch buffer reused by Sort
+	counts  *counts     // cached by Counts, reset on every change
+	corrupt []byte      // unparsable file contents, backed up before the first save (safe mode)
+	foreign bool        // the file isn't a todo list and is never written
+	author  string      // recorded on added and completed todos, see SetAuthor
+}
+
+// safe keeps loading from writing to disk, see SetSafe
+var safe bool
+
+// SetSafe turns safe mode on or off. In safe mode loading never writes anything:
+// a corrupted file is backed up only right before a change would overwrite it.
+func SetSafe(enabled bool) {
+	safe = enabled
 }
 
 // NewTodoList creates a new TodoList
@@ -39,44 +88,85 @@ func NewTodoList(filepath string) *TodoList {
 func (tl *TodoList) Add(title string) {
 	todo := Todo{
 		ID:        tl.NextID,
-		Title:     title,
+		Title:     tl.withTags(title),
 		Completed: false,
 		CreatedAt: time.Now(),
+		AddedBy:   tl.author,
 	}
-	// Insert at beginning
-	tl.Todos = append([]Todo{todo}, tl.Todos...)
+	tl.fitTitle(&todo)
+	// Insert at beginning, shifting in place when capacity allows
+	tl.Todos = slices.Insert(tl.Todos, 0, todo)
 	tl.NextID++
 	tl.Sort() // Keep completed at bottom
+	tl.persist()
+	tl.publish(TodoAdded, todo)
 }
 
-// Insert inserts a new todo at the top (always)
+// Insert inserts a new todo at the top of the section containing index
 func (tl *TodoList) Insert(index int, title string) {
 	todo := Todo{
 		ID:        tl.NextID,
-		Title:     title,
+		Title:     tl.withTags(title),
 		Completed: false,
 		CreatedAt: time.Now(),
+		AddedBy:   tl.author,
 	}
+	tl.fitTitle(&todo)
 	tl.NextID++
 
-	// Always insert at top
-	tl.Todos = append([]Todo{todo}, tl.Todos...)
+	// Insert at top of the section (top of the list when there are no headings)
+	at := tl.SectionStart(index)
+	tl.Todos = slices.Insert(tl.Todos, at, todo)
 	tl.Sort() // Keep completed at bottom
+	tl.persist()
+	tl.publish(TodoAdded, todo)
 }
 
 // Delete removes a todo by index
 func (tl *TodoList) Delete(index int) {
 	if index >= 0 && index < len(tl.Todos) {
-		tl.Todos = append(tl.Todos[:index], tl.Todos[index+1:]...)
-		tl.Save()
+		removed := tl.Todos[index]
+		tl.Todos = slices.Delete(tl.Todos, index, index+1)
+		if removed.Heading {
+			tl.Sort() // Removing a heading merges two sections
+			tl.persist()
+			return
+		}
+		tl.persist()
+		tl.publish(TodoDeleted, removed)
 	}
 }
 
 // Toggle toggles the completion status of a todo
 func (tl *TodoList) Toggle(index int) {
-	if index >= 0 && index < len(tl.Todos) {
+	if index >= 0 && index < len(tl.Todos) && !tl.Todos[index].Heading {
 		tl.Todos[index].Completed = !tl.Todos[index].Completed
+		now := time.Now()
+		tl.Todos[index].CompletedBy = ""
+		if tl.Todos[index].Completed {
+			tl.Todos[index].CompletedAt = &now
+			tl.Todos[index].CompletedBy = tl.author
+		} else {
+			tl.Todos[index].CompletedAt = nil
+			if tl.Todos[index].Progress == 100 {
+				tl.Todos[index].Progress = 0 // done by progress, undone from scratch
+			}
+		}
+		if tl.IsHabit() {
+			tl.Todos[index].recordHabit(now, tl.Todos[index].Completed)
+		}
+		toggled := tl.Todos[index]
 		tl.Sort() // Auto-sort after toggling
+		tl.persist()
+		tl.publishToggled(toggled)
+	}
+}
+
+// ToggleFlag flags or unflags a todo as a priority
+func (tl *TodoList) ToggleFlag(index int) {
+	if index >= 0 && index < len(tl.Todos) && !tl.Todos[index].Heading {
+		tl.Todos[index].Flagged = !tl.Todos[index].Flagged
+		tl.persist()
 	}
 }
 
@@ -84,70 +174,116 @@ func (tl *TodoList) Toggle(index int) {
 func (tl *TodoList) Update(index int, title string) {
 	if index >= 0 && index < len(tl.Todos) {
 		tl.Todos[index].Title = title
-		tl.Save()
+		tl.fitTitle(&tl.Todos[index])
+		tl.persist()
 	}
 }
 
-// Sort sorts todos so completed ones are at the bottom
+// Sort moves completed todos to the bottom of their section, unless auto-sort is off.
+// It only reorders the list in memory; saving is up to the caller.
 func (tl *TodoList) Sort() {
-	// Stable sort: incomplete todos first, completed todos last
-	// Preserves order within each group
-	var incomplete []Todo
-	var completed []Todo
+	if tl.AutoSort() {
+		tl.arrange()
+	}
+	tl.sortSections()
+}
+
+// Resort sorts the list again after its sort settings changed, saving the new order
+func (tl *TodoList) Resort() {
+	tl.Sort()
+	tl.persist()
+}
 
+// arrange moves completed todos below incomplete ones in each section without saving
+func (tl *TodoList) arrange() {
+	// Stable sort: incomplete todos first, completed todos last
+	// Preserves order within each group; headings stay at the top of their section.
+	// Incomplete todos are compacted in place and only completed ones are buffered.
+	w := 0
+	completed := tl.done[:0]
 	for _, todo := range tl.Todos {
-		if todo.Completed {
+		switch {
+		case todo.Heading:
+			w += copy(tl.Todos[w:], completed)
+			completed = completed[:0]
+			tl.Todos[w] = todo
+			w++
+		case todo.Completed:
 			completed = append(completed, todo)
-		} else {
-			incomplete = append(incomplete, todo)
+		default:
+			tl.Todos[w] = todo
+			w++
 		}
 	}
+	copy(tl.Todos[w:], completed)
 
-	tl.Todos = append(incomplete, completed...)
-	tl.Save()
+	clear(completed) // don't keep todos alive through the buffer
+	tl.done = completed[:0]
 }
 
-// Save persists the todo list to disk using atomic writes
-func (tl *TodoList) Save() error {
-	// Marshal data to JSON
-	data, err := json.MarshalIndent(tl, "", "  ")
-	if err != nil {
-		return err
-	}
+// SetAutoSort controls whether completed todos move to the bottom of their section
+func (tl *TodoList) SetAutoSort(enabled bool) {
+	tl.keepOrder = !enabled
+}
 
-	// Create a temporary file in the same directory
-	dir := filepath.Dir(tl.filepath)
-	tmpFile, err := os.CreateTemp(dir, ".tui_todo_*.tmp")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
+// SetMaxTitleLength limits titles to n runes, moving the rest into the notes (0 for no limit)
+func (tl *TodoList) SetMaxTitleLength(n int) {
+	tl.maxTitle = n
+}
 
-	// Write data to temp file
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to write to temp file: %w", err)
+// SetDeferredSave controls whether mutations save immediately or wait for Flush
+func (tl *TodoList) SetDeferredSave(deferred bool) {
+	tl.deferSave = deferred
+	if !deferred {
+		tl.Flush()
 	}
+}
+
+// Dirty reports whether the list has changes not yet written to disk
+func (tl *TodoList) Dirty() bool {
+	return tl.dirty
+}
 
-	// Close the temp file
-	if err := tmpFile.Close(); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to close temp file: %w", err)
+// Flush saves pending changes, if any
+func (tl *TodoList) Flush() error {
+	if !tl.dirty {
+		return nil
 	}
+	return tl.Save()
+}
 
-	// Atomically rename temp file to actual file
-	// If this fails, the original file is unchanged
-	if err := os.Rename(tmpPath, tl.filepath); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename temp file: %w", err)
+// persist saves after a mutation, or marks the list dirty when saving is deferred
+func (tl *TodoList) persist() {
+	tl.counts = nil
+	if tl.deferSave {
+		tl.dirty = true
+		return
 	}
+	tl.Save()
+}
 
-	return nil
+// Save persists the todo list to disk using atomic writes
+func (tl *TodoList) Save() error {
+	w, err := tl.encode()
+	if err != nil || w == nil {
+		if err != nil && !tl.IsScratch() {
+			logFileOp("save", tl.filepath, len(tl.Todos), time.Now(), err)
+		}
+		return err
+	}
+	return w.run()
 }
 
 // Load loads the todo list from disk with error recovery
 func (tl *TodoList) Load() error {
+	start := time.Now()
+	err := tl.load()
+	logFileOp("load", tl.filepath, len(tl.Todos), start, err)
+	return err
+}
+
+// load does the work of Load
+func (tl *TodoList) load() error {
 	data, err := os.ReadFile(tl.filepath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -156,15 +292,154 @@ func (tl *TodoList) Load() error {
 		return fmt.Errorf("failed to read todo file: %w", err)
 	}
 
-	// Try to parse the JSON
-	if err := json.Unmarshal(data, tl); err != nil {
+	// Leave files that aren't todo lists alone
+	if err := tl.shapeError(data); err != nil {
+		tl.Version, tl.Title, tl.Archived, tl.Original, tl.Settings = 0, "", time.Time{}, "", nil
+		tl.Todos, tl.NextID, tl.Kind = []Todo{}, 1, ""
+		tl.index, tl.counts = nil, nil
+		tl.foreign = true
+		return err
+	}
+	tl.foreign = false
+
+	// Try to parse the JSON into a fresh list so a failure leaves tl untouched
+	fresh := TodoList{Todos: []Todo{}, NextID: 1}
+	if err := json.Unmarshal(data, &fresh); err != nil {
 		// If parsing fails, backup the corrupted file
 		backupPath := tl.filepath + ".corrupted"
+		if safe {
+			tl.corrupt = data
+			return fmt.Errorf("corrupted todo file, backed up to %s before it is overwritten: %w", backupPath, err)
+		}
 		if backupErr := os.WriteFile(backupPath, data, 0644); backupErr == nil {
 			return fmt.Errorf("corrupted todo file backed up to %s: %w", backupPath, err)
 		}
 		return fmt.Errorf("corrupted todo file (backup failed): %w", err)
 	}
 
+	fresh.normalize()
+	fresh.localize()
+	tl.Version = fresh.Version
+	tl.Title = fresh.Title
+	tl.Archived = fresh.Archived
+	tl.Original = fresh.Original
+	tl.Settings = fresh.Settings
+	tl.Todos = fresh.Todos
+	tl.NextID = fresh.NextID
+	tl.Kind = fresh.Kind
+	tl.index = nil
+	tl.counts = nil
+	tl.corrupt = nil
+	if tl.resetHabits(time.Now()) && tl.AutoSort() {
+		tl.arrange()
+	}
+	return nil
+}
+
+// maxID bounds IDs and NextID so incrementing can never overflow
+const maxID = math.MaxInt / 2
+
+// normalize repairs hand-edited or hostile data: a missing todo slice,
+// duplicate or out-of-range IDs, and a NextID that would reuse an ID.
+// Repeated titles are interned so they share one string.
+func (tl *TodoList) normalize() {
+	if tl.Todos == nil {
+		tl.Todos = []Todo{}
+	}
+
+	titles := make(map[string]string)
+	for i := range tl.Todos {
+		title := tl.Todos[i].Title
+		if shared, ok := titles[title]; ok {
+			tl.Todos[i].Title = shared
+		} else {
+			titles[title] = title
+		}
+	}
+
+	seen := make(map[int]bool, len(tl.Todos))
+	highest := 0
+	var fix []int // indices of todos needing a fresh ID
+	for i, todo := range tl.Todos {
+		if todo.ID <= 0 || todo.ID > maxID || seen[todo.ID] {
+			fix = append(fix, i)
+			continue
+		}
+		seen[todo.ID] = true
+		if todo.ID > highest {
+			highest = todo.ID
+		}
+	}
+
+	if tl.NextID <= highest || tl.NextID > maxID {
+		tl.NextID = highest + 1
+	}
+	for _, i := range fix {
+		tl.Todos[i].ID = tl.NextID
+		tl.NextID++
+	}
+}
+
+// Reload re-reads the list from disk, leaving it unchanged if the file is invalid
+func (tl *TodoList) Reload() error {
+	start := time.Now()
+	err := tl.reload()
+	logFileOp("reload", tl.filepath, len(tl.Todos), start, err)
+	return err
+}
+
+// reload does the work of Reload
+func (tl *TodoList) reload() error {
+	data, err := os.ReadFile(tl.filepath)
+	if err != nil {
+		return fmt.Errorf("failed to read todo file: %w", err)
+	}
+
+	if err := tl.shapeError(data); err != nil {
+		return err
+	}
+	fresh := TodoList{Todos: []Todo{}, NextID: 1}
+	if err := json.Unmarshal(data, &fresh); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	fresh.normalize()
+	fresh.localize()
+
+	tl.Version = fresh.Version
+	tl.Title = fresh.Title
+	tl.Archived = fresh.Archived
+	tl.Original = fresh.Original
+	tl.Settings = fresh.Settings
+	tl.Todos = fresh.Todos
+	tl.NextID = fresh.NextID
+	tl.Kind = fresh.Kind
+	tl.index = nil
+	tl.counts = nil
+	tl.foreign = false
+	if tl.resetHabits(time.Now()) && tl.AutoSort() {
+		tl.arrange()
+	}
 	return nil
 }
+
+// IndexOf returns the position of the todo with the given ID, or -1
+func (tl *TodoList) IndexOf(id int) int {
+	if i, ok := tl.index[id]; ok && i < len(tl.Todos) && tl.Todos[i].ID == id {
+		return i
+	}
+
+	// Positions shift on every insert, delete and sort, so rebuild lazily
+	tl.index = make(map[int]int, len(tl.Todos))
+	for i, todo := range tl.Todos {
+		tl.index[todo.ID] = i
+	}
+	if i, ok := tl.index[id]; ok {
+		return i
+	}
+	return -1
+}
+
+// Path returns the file the list is stored in
+func (tl *TodoList) Path() string {
+	return tl.filepath
+}
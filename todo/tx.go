@@ -0,0 +1,294 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// journalName is the file a transaction keeps beside its files while it replaces
+// them. Finding one means a run stopped halfway, and Recover undoes what it did.
+const journalName = ".justdoit-tx.json"
+
+// Tx changes several list files together: a commit either replaces all of them or
+// leaves all of them as they were, even when the program dies halfway through
+type Tx struct {
+	lists   []*TodoList
+	saved   []listState // the lists as they began, for rolling back
+	removed []string    // files deleted by the commit
+}
+
+// listState is what a rollback restores of a list
+type listState struct {
+	todos   []Todo
+	nextID  int
+	dirty   bool
+	corrupt []byte
+}
+
+// txEntry is one file a commit replaces or deletes, as recorded in the journal
+type txEntry struct {
+	Path   string `json:"path"`
+	Staged string `json:"staged,omitempty"` // the new contents, "" when the file is deleted
+	Backup string `json:"backup,omitempty"` // the old contents, "" when there was no file
+}
+
+// Begin starts a transaction saving lists, remembering them as they are so a failed
+// commit can put them back
+func Begin(lists ...*TodoList) *Tx {
+	tx := &Tx{}
+	for _, tl := range lists {
+		tx.lists = append(tx.lists, tl)
+		tx.saved = append(tx.saved, listState{slices.Clone(tl.Todos), tl.NextID, tl.dirty, tl.corrupt})
+	}
+	return tx
+}
+
+// Remove deletes the list file at path as part of the commit
+func (tx *Tx) Remove(path string) {
+	tx.removed = append(tx.removed, path)
+}
+
+// Rollback puts the lists back as they were when the transaction began
+func (tx *Tx) Rollback() {
+	for i, tl := range tx.lists {
+		s := tx.saved[i]
+		tl.Todos, tl.NextID, tl.dirty, tl.corrupt = s.todos, s.nextID, s.dirty, s.corrupt
+		tl.index = nil
+		tl.counts = nil
+	}
+}
+
+// Commit saves the lists and deletes the removed files as one change. The new
+// contents are written beside the files first, then a journal naming them and
+// copies of the old contents, and only then are the files replaced. On failure
+// nothing is changed, on disk or in the lists.
+func (tx *Tx) Commit() error {
+	paths := slices.Clone(tx.removed)
+	for _, tl := range tx.lists {
+		if !tl.IsScratch() {
+			paths = append(paths, tl.filepath)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	slices.Sort(paths)
+	for _, path := range slices.Compact(paths) {
+		lock := lockFor(path)
+		lock.Lock()
+		defer lock.Unlock()
+	}
+
+	// Encoding under the locks makes saves of these files still in flight stale
+	var staged []*fileWrite
+	for _, tl := range tx.lists {
+		w, err := tl.encode()
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if w != nil {
+			staged = append(staged, w)
+		}
+	}
+
+	start := time.Now()
+	entries, err := tx.stage(staged)
+	if err == nil {
+		err = applyTx(entries)
+	}
+	if err != nil {
+		tx.Rollback()
+		slog.Error("transaction", "files", len(entries), "took", time.Since(start), "err", err)
+		return err
+	}
+
+	seq := nextSeq()
+	for _, e := range entries {
+		land(e.Path, seq)
+	}
+	writes.Lock()
+	for _, w := range staged {
+		delete(writes.failed, w.path)
+	}
+	writes.Unlock()
+	slog.Debug("transaction", "files", len(entries), "took", time.Since(start))
+	for _, e := range entries {
+		if e.Staged != "" {
+			publishEvent(Event{Kind: FileSaved, Path: e.Path})
+		} else {
+			publishEvent(Event{Kind: FileDeleted, Path: e.Path})
+		}
+	}
+	return nil
+}
+
+// stage writes the new contents and backups of every file, then the journal. On
+// failure it removes what it wrote.
+func (tx *Tx) stage(staged []*fileWrite) ([]txEntry, error) {
+	var entries []txEntry
+	fail := func(err error) ([]txEntry, error) {
+		cleanTx(entries)
+		return nil, err
+	}
+
+	for _, w := range staged {
+		if w.corrupt != nil {
+			if err := os.WriteFile(w.path+".corrupted", w.corrupt, 0644); err != nil {
+				return fail(fmt.Errorf("failed to back up corrupted file: %w", err))
+			}
+		}
+		e := txEntry{Path: w.path}
+		var err error
+		if e.Staged, err = writeTemp(filepath.Dir(w.path), w.data); err != nil {
+			return fail(err)
+		}
+		entries = append(entries, e)
+	}
+	for _, path := range tx.removed {
+		entries = append(entries, txEntry{Path: path})
+	}
+	for i := range entries {
+		data, err := os.ReadFile(entries[i].Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			entries[i].Backup, err = writeTemp(filepath.Dir(entries[i].Path), data)
+		}
+		if err != nil {
+			return fail(fmt.Errorf("failed to back up %s: %w", filepath.Base(entries[i].Path), err))
+		}
+	}
+
+	dir := filepath.Dir(entries[0].Path)
+	if _, err := os.Stat(filepath.Join(dir, journalName)); err == nil {
+		return fail(fmt.Errorf("an earlier change to %s wasn't finished, restart to put it back", dir))
+	}
+	journal, err := json.Marshal(entries)
+	if err == nil {
+		err = writeJournal(dir, journal)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("failed to write the journal: %w", err))
+	}
+	return entries, nil
+}
+
+// applyTx replaces and deletes the files of a staged transaction. Removing the
+// journal after is what commits it; on failure the files are put back instead.
+func applyTx(entries []txEntry) error {
+	journal := filepath.Join(filepath.Dir(entries[0].Path), journalName)
+	for _, e := range entries {
+		var err error
+		if e.Staged != "" {
+			err = os.Rename(e.Staged, e.Path)
+		} else if err = os.Remove(e.Path); os.IsNotExist(err) {
+			err = nil
+		}
+		if err != nil {
+			undoErr := undoTx(entries)
+			if undoErr == nil {
+				os.Remove(journal)
+			}
+			return errors.Join(fmt.Errorf("failed to replace %s: %w", filepath.Base(e.Path), err), undoErr)
+		}
+	}
+	if err := os.Remove(journal); err != nil {
+		return errors.Join(fmt.Errorf("failed to commit: %w", err), undoTx(entries))
+	}
+	cleanTx(entries)
+	return nil
+}
+
+// undoTx puts back the files of a transaction as they were before it
+func undoTx(entries []txEntry) error {
+	var errs []error
+	for _, e := range entries {
+		var err error
+		if e.Backup != "" {
+			err = os.Rename(e.Backup, e.Path)
+		} else {
+			err = os.Remove(e.Path)
+		}
+		if err != nil && !os.IsNotExist(err) { // gone when put back before
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", filepath.Base(e.Path), err))
+		}
+		if e.Staged != "" {
+			os.Remove(e.Staged)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanTx removes the staged contents and backups a transaction left
+func cleanTx(entries []txEntry) {
+	for _, e := range entries {
+		for _, tmp := range []string{e.Staged, e.Backup} {
+			if tmp != "" {
+				os.Remove(tmp)
+			}
+		}
+	}
+}
+
+// Recover undoes a transaction that was interrupted in dir, putting its files back
+// as they were before it began. It reports how many files it restored.
+func Recover(dir string) (int, error) {
+	journal := filepath.Join(dir, journalName)
+	data, err := os.ReadFile(journal)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var entries []txEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, fmt.Errorf("unreadable journal %s: %w", journal, err)
+	}
+	if err := undoTx(entries); err != nil {
+		return 0, err
+	}
+	slog.Warn("recovered transaction", "files", len(entries))
+	return len(entries), os.Remove(journal)
+}
+
+// writeTemp writes data to a new hidden file in dir and flushes it to the disk,
+// returning its path
+func writeTemp(dir string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, ".tui_todo_*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// writeJournal records a staged transaction in dir, replacing the journal whole
+func writeJournal(dir string, data []byte) error {
+	tmp, err := writeTemp(dir, data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, journalName)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// titlesIn returns the titles saved in the list file at path
+func titlesIn(path string) []string {
+	var titles []string
+	for _, t := range NewTodoList(path).Todos {
+		titles = append(titles, t.Title)
+	}
+	return titles
+}
+
+// leftovers returns the hidden files a transaction may leave in dir
+func leftovers(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, ".*"))
+	return matches
+}
+
+// TestTxCommit tests that a commit saves every list and deletes the removed files,
+// leaving nothing behind
+func TestTxCommit(t *testing.T) {
+	dir := t.TempDir()
+	a := NewTodoList(filepath.Join(dir, "a.json"))
+	a.Add("one")
+	b := NewTodoList(filepath.Join(dir, "b.json"))
+	gone := filepath.Join(dir, "gone.json")
+	NewTodoList(gone).Add("old")
+
+	tx := Begin(a, b)
+	b.Todos = append(b.Todos, a.Todos[0])
+	a.Todos = nil
+	tx.Remove(gone)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := titlesIn(a.Path()); len(got) != 0 {
+		t.Errorf("Expected a emptied, got %v", got)
+	}
+	if got := titlesIn(b.Path()); len(got) != 1 || got[0] != "one" {
+		t.Errorf("Expected the todo in b, got %v", got)
+	}
+	if _, err := os.Stat(gone); !os.IsNotExist(err) {
+		t.Errorf("Expected gone.json deleted, got %v", err)
+	}
+	if files := leftovers(t, dir); len(files) != 0 {
+		t.Errorf("Expected no journal or temp files left, got %v", files)
+	}
+}
+
+// TestTxFailure tests that a commit that can't write one of its files changes none
+// of them, on disk or in memory
+func TestTxFailure(t *testing.T) {
+	dir := t.TempDir()
+	src := NewTodoList(filepath.Join(dir, "src.json"))
+	src.Add("keep me")
+	dst := NewTodoList(filepath.Join(dir, "missing", "dst.json"))
+
+	if err := src.MoveTo(0, dst); err == nil {
+		t.Fatal("Expected the move to fail")
+	}
+	if len(src.Todos) != 1 || len(dst.Todos) != 0 || dst.NextID != 1 {
+		t.Errorf("Expected the lists rolled back, got %+v and %+v", src.Todos, dst.Todos)
+	}
+	if got := titlesIn(src.Path()); len(got) != 1 {
+		t.Errorf("Expected src.json untouched, got %v", got)
+	}
+	if files := leftovers(t, dir); len(files) != 0 {
+		t.Errorf("Expected no journal or temp files left, got %v", files)
+	}
+}
+
+// TestRecover tests that a transaction interrupted halfway is undone at the next start
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	a := NewTodoList(filepath.Join(dir, "a.json"))
+	a.Add("moving")
+	b := NewTodoList(filepath.Join(dir, "b.json"))
+
+	// Stage moving the todo, then die after replacing only the first file
+	tx := Begin(a, b)
+	b.Todos = append(b.Todos, a.Todos[0])
+	a.Todos = nil
+	var staged []*fileWrite
+	for _, tl := range []*TodoList{a, b} {
+		w, _ := tl.encode()
+		staged = append(staged, w)
+	}
+	entries, err := tx.stage(staged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Rename(entries[0].Staged, entries[0].Path)
+
+	n, err := Recover(dir)
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 files put back, got %d, %v", n, err)
+	}
+	if got := titlesIn(a.Path()); len(got) != 1 || got[0] != "moving" {
+		t.Errorf("Expected the todo back in a, got %v", got)
+	}
+	if got := titlesIn(b.Path()); len(got) != 0 {
+		t.Errorf("Expected b without it, got %v", got)
+	}
+	if files := leftovers(t, dir); len(files) != 0 {
+		t.Errorf("Expected no journal or temp files left, got %v", files)
+	}
+	if n, err := Recover(dir); n != 0 || err != nil {
+		t.Errorf("Expected nothing to recover twice, got %d, %v", n, err)
+	}
+}
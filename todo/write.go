@@ -0,0 +1,252 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// writes orders the saves of each file: one that was encoded earlier never lands
+// after a later one, or after the file was moved or removed
+var writes = struct {
+	sync.Mutex
+	seq     uint64
+	written map[string]uint64      // newest write (or move) that reached each file
+	locks   map[string]*sync.Mutex // held while a file is written or moved
+	failed  map[string]failedWrite // newest write of each file, if it failed
+	pending sync.WaitGroup         // background saves not finished yet
+}{written: map[string]uint64{}, locks: map[string]*sync.Mutex{}, failed: map[string]failedWrite{}}
+
+// failedWrite is a save that didn't reach the disk, kept to try again
+type failedWrite struct {
+	w   *fileWrite
+	err error
+}
+
+// fileWrite is a list encoded for saving, waiting to be written
+type fileWrite struct {
+	path    string
+	data    []byte
+	corrupt []byte // unparsable contents to back up first
+	todos   int
+	seq     uint64
+}
+
+// nextSeq numbers a write or move of a file
+func nextSeq() uint64 {
+	writes.Lock()
+	defer writes.Unlock()
+	writes.seq++
+	return writes.seq
+}
+
+// lockFor returns the lock held while path is written or moved
+func lockFor(path string) *sync.Mutex {
+	writes.Lock()
+	defer writes.Unlock()
+	lock := writes.locks[path]
+	if lock == nil {
+		lock = &sync.Mutex{}
+		writes.locks[path] = lock
+	}
+	return lock
+}
+
+// land records that the write or move numbered seq reached path, reporting false
+// if a later one got there first
+func land(path string, seq uint64) bool {
+	writes.Lock()
+	defer writes.Unlock()
+	if writes.written[path] > seq {
+		return false
+	}
+	writes.written[path] = seq
+	return true
+}
+
+// encode marshals the list for saving and marks it clean. It returns nil for the
+// scratchpad, which has nowhere to write.
+func (tl *TodoList) encode() (*fileWrite, error) {
+	if tl.IsScratch() {
+		tl.dirty = false
+		return nil, nil
+	}
+	if tl.foreign {
+		return nil, fmt.Errorf("%s: %w, left untouched", tl.filepath, ErrForeign)
+	}
+
+	// Marshal data to JSON, in UTC
+	tl.Version = FormatVersion
+	data, err := json.MarshalIndent(tl.utc(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	w := &fileWrite{path: tl.filepath, data: data, corrupt: tl.corrupt, todos: len(tl.Todos)}
+	w.seq = nextSeq()
+	tl.corrupt = nil
+	tl.dirty = false
+	return w, nil
+}
+
+// run writes the encoded list, unless a later save or a move of the file came first
+func (w *fileWrite) run() error {
+	start := time.Now()
+	lock := lockFor(w.path)
+	lock.Lock()
+	defer lock.Unlock()
+	if !land(w.path, w.seq) {
+		return nil // stale, the file already holds newer todos or is gone
+	}
+	err := w.write()
+	logFileOp("save", w.path, w.todos, start, err)
+	writes.Lock()
+	if err != nil {
+		writes.failed[w.path] = failedWrite{w, err}
+	} else {
+		delete(writes.failed, w.path)
+	}
+	writes.Unlock()
+	if err == nil {
+		publishEvent(Event{Kind: FileSaved, Path: w.path})
+	}
+	return err
+}
+
+// write backs up a corrupted file if needed, then replaces it atomically
+func (w *fileWrite) write() error {
+	if w.corrupt != nil {
+		if err := os.WriteFile(w.path+".corrupted", w.corrupt, 0644); err != nil {
+			return fmt.Errorf("failed to back up corrupted file: %w", err)
+		}
+	}
+
+	// Create a temporary file in the same directory, which safe mode doesn't make at startup
+	if safe {
+		os.MkdirAll(filepath.Dir(w.path), 0755)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(w.path), ".tui_todo_*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	// Write data to temp file
+	if _, err := tmpFile.Write(w.data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write to temp file: %w", err)
+	}
+
+	// Close the temp file
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	// Atomically rename temp file to actual file
+	// If this fails, the original file is unchanged
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
+
+// SaveAsync encodes the list and writes it in the background, so the caller doesn't
+// wait on the disk. The returned channel receives the result. Saves of a file land
+// in the order they were made; Settle waits for all of them.
+func (tl *TodoList) SaveAsync() <-chan error {
+	done := make(chan error, 1)
+	w, err := tl.encode()
+	if err != nil || w == nil {
+		done <- err
+		return done
+	}
+	writes.pending.Add(1)
+	go func() {
+		defer writes.pending.Done()
+		done <- w.run()
+	}()
+	return done
+}
+
+// Settle waits for the background saves started so far to finish
+func Settle() {
+	writes.pending.Wait()
+}
+
+// Unsaved returns the files whose newest save failed, with the error it failed with.
+// Files moved or removed since don't count.
+func Unsaved() map[string]error {
+	writes.Lock()
+	defer writes.Unlock()
+	unsaved := map[string]error{}
+	for path, f := range writes.failed {
+		if writes.written[path] == f.w.seq {
+			unsaved[path] = f.err
+		}
+	}
+	return unsaved
+}
+
+// RetryUnsaved writes the files whose newest save failed once more
+func RetryUnsaved() error {
+	writes.Lock()
+	var retry []*fileWrite
+	for _, f := range writes.failed {
+		retry = append(retry, f.w)
+	}
+	writes.Unlock()
+
+	var errs []error
+	for _, w := range retry {
+		if err := w.run(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rename moves a list file. Saves still in flight to either name are dropped rather
+// than writing the old name back or replacing what was moved in. Subscribers hear of
+// src deleted and dst saved.
+func Rename(src, dst string) error {
+	if src == dst {
+		return nil
+	}
+	seq := nextSeq()
+	for _, path := range []string{src, dst} {
+		lock := lockFor(path)
+		lock.Lock()
+		defer lock.Unlock()
+		land(path, seq)
+	}
+	if safe {
+		os.MkdirAll(filepath.Dir(dst), 0755) // the archive may not exist yet
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	publishEvent(Event{Kind: FileDeleted, Path: src})
+	publishEvent(Event{Kind: FileSaved, Path: dst})
+	return nil
+}
+
+// Remove deletes a list file. Saves of it still in flight are dropped rather than
+// bringing it back.
+func Remove(path string) error {
+	seq := nextSeq()
+	lock := lockFor(path)
+	lock.Lock()
+	defer lock.Unlock()
+	land(path, seq)
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	publishEvent(Event{Kind: FileDeleted, Path: path})
+	return nil
+}
@@ -0,0 +1,81 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSaveAsync tests that background saves land in order and never bring back a
+// file that was moved or removed
+func TestSaveAsync(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "work.json")
+	tl := NewTodoList(path)
+	tl.SetDeferredSave(true)
+
+	var results []<-chan error
+	for _, title := range []string{"one", "two", "three"} {
+		tl.Add(title)
+		results = append(results, tl.SaveAsync())
+	}
+	for _, done := range results {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(NewTodoList(path).Todos); got != 3 {
+		t.Errorf("Expected the last save to win with 3 todos, got %d", got)
+	}
+	if tl.Dirty() {
+		t.Error("Expected the list clean once its save was started")
+	}
+
+	tl.Add("four")
+	tl.SaveAsync()
+	if err := Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	Settle()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected a save in flight not to bring the removed file back, got %v", err)
+	}
+
+	tl.Add("five")
+	tl.SaveAsync()
+	moved := filepath.Join(dir, "moved.json")
+	if err := Rename(path, moved); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	Settle()
+	_, errOld := os.Stat(path)
+	_, errNew := os.Stat(moved)
+	if errOld == nil && errNew == nil {
+		t.Error("Expected the save to land before the move or be dropped, not both")
+	}
+}
+
+// TestUnsaved tests that a failed save is kept to retry until a later one lands
+func TestUnsaved(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lists")
+	path := filepath.Join(dir, "work.json")
+	tl := NewTodoList(path)
+	tl.SetDeferredSave(true)
+	tl.Add("one")
+
+	if err := <-tl.SaveAsync(); err == nil {
+		t.Fatal("Expected the save to fail without its directory")
+	}
+	if _, ok := Unsaved()[path]; !ok {
+		t.Fatalf("Expected %s unsaved, got %v", path, Unsaved())
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RetryUnsaved(); err != nil {
+		t.Fatal(err)
+	}
+	if len(Unsaved()) != 0 || len(NewTodoList(path).Todos) != 1 {
+		t.Errorf("Expected the retry to write the todo, unsaved: %v", Unsaved())
+	}
+}
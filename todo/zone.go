@@ -0,0 +1,70 @@
+package todo
+
+import "time"
+
+// Midnight returns the start of t's day in t's timezone. Days are counted on the
+// calendar rather than as 24 hours, so the day a DST change falls on is 23 or 25
+// hours long and the next one still starts at midnight.
+func Midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Tomorrow returns the start of the day after t's, where "today" ends
+func Tomorrow(t time.Time) time.Time {
+	return Midnight(t).AddDate(0, 0, 1)
+}
+
+// inZone returns t in loc, leaving the zero time alone
+func inZone(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(loc)
+}
+
+// inZonePtr is inZone for optional timestamps, returning a new pointer so the
+// original is left as it was
+func inZonePtr(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	in := inZone(*t, loc)
+	return &in
+}
+
+// in returns the todo with its timestamps in loc
+func (t Todo) in(loc *time.Location) Todo {
+	t.CreatedAt = inZone(t.CreatedAt, loc)
+	t.CompletedAt = inZonePtr(t.CompletedAt, loc)
+	t.Due = inZonePtr(t.Due, loc)
+	t.Start = inZonePtr(t.Start, loc)
+	return t
+}
+
+// localize moves the timestamps of a loaded list into the local timezone, which dates
+// are shown and days are counted in
+func (tl *TodoList) localize() {
+	tl.Archived = inZone(tl.Archived, time.Local)
+	for i := range tl.Todos {
+		tl.Todos[i] = tl.Todos[i].in(time.Local)
+	}
+}
+
+// utc returns a copy of the list to save, with its timestamps in UTC so the file
+// reads the same whichever timezone it was written in
+func (tl *TodoList) utc() *TodoList {
+	saved := &TodoList{
+		Version:  tl.Version,
+		Title:    tl.Title,
+		Archived: inZone(tl.Archived, time.UTC),
+		Original: tl.Original,
+		Settings: tl.Settings,
+		Todos:    make([]Todo, len(tl.Todos)),
+		NextID:   tl.NextID,
+		Kind:     tl.Kind,
+	}
+	for i, t := range tl.Todos {
+		saved.Todos[i] = t.in(time.UTC)
+	}
+	return saved
+}
@@ -0,0 +1,71 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// TestMidnight tests that days start at local midnight on both sides of a DST change
+func TestMidnight(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		now      time.Time
+		midnight string
+		tomorrow string
+	}{
+		{time.Date(2026, 3, 8, 12, 0, 0, 0, ny), "2026-03-08 00:00 EST", "2026-03-09 00:00 EDT"},   // 23 hours
+		{time.Date(2026, 11, 1, 23, 30, 0, 0, ny), "2026-11-01 00:00 EDT", "2026-11-02 00:00 EST"}, // 25 hours
+		{time.Date(2026, 3, 9, 0, 0, 0, 0, ny), "2026-03-09 00:00 EDT", "2026-03-10 00:00 EDT"},
+	} {
+		const layout = "2006-01-02 15:04 MST"
+		if got := Midnight(tc.now).Format(layout); got != tc.midnight {
+			t.Errorf("Midnight(%v) = %s, want %s", tc.now, got, tc.midnight)
+		}
+		if got := Tomorrow(tc.now).Format(layout); got != tc.tomorrow {
+			t.Errorf("Tomorrow(%v) = %s, want %s", tc.now, got, tc.tomorrow)
+		}
+	}
+}
+
+// TestSavedInUTC tests that timestamps are written in UTC and loaded in the local timezone
+func TestSavedInUTC(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := time.Local
+	time.Local = berlin
+	t.Cleanup(func() { time.Local = local })
+
+	path := filepath.Join(t.TempDir(), "trip.json")
+	tl := NewTodoList(path)
+	tl.Add("book train")
+	due := time.Date(2026, 7, 1, 9, 0, 0, 0, berlin)
+	tl.SetDue(0, &due)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"due": "2026-07-01T07:00:00Z"`) {
+		t.Errorf("Expected the due date saved in UTC, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "+02:00") {
+		t.Errorf("Expected no local offsets in the file, got:\n%s", data)
+	}
+
+	got := NewTodoList(path).Todos[0]
+	if got.Due.Location() != berlin || got.Due.Format("15:04") != "09:00" || !got.Due.Equal(due) {
+		t.Errorf("Expected the due date loaded as 09:00 local, got %v", got.Due)
+	}
+	if got.CreatedAt.Location() != berlin {
+		t.Errorf("Expected the creation time loaded in the local timezone, got %v", got.CreatedAt)
+	}
+}
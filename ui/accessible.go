@@ -0,0 +1,294 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	"justdoit/i18n"
+)
+
+// colorProfile is the terminal's profile from before accessible mode turned colors off
+var colorProfile *termenv.Profile
+
+// SetMonochrome turns colors off for accessible mode, or restores them
+func SetMonochrome(on bool) {
+	if on {
+		if colorProfile == nil {
+			p := lipgloss.ColorProfile()
+			colorProfile = &p
+		}
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else if colorProfile != nil {
+		lipgloss.SetColorProfile(*colorProfile)
+	}
+}
+
+// accessibleView reports whether the screen uses the line-oriented layout.
+// Overlays like settings and pickers keep their boxes, without colors and icons.
+func (m Model) accessibleView() bool {
+	if !m.Behavior.Accessible {
+		return false
+	}
+	switch m.Dialog {
+	case ContextSwitcher, KeybindingEditor, SettingsScreen, ListSettingScreen, TodayScreen, TemplatePicker, ConflictResolver, StatsScreen, DebugLogScreen, PreviewScreen,
+		ComparePicker, CompareScreen, FilePicker:
+		return m.Mode != EditMode
+	}
+	return true
+}
+
+// stripGlyphs removes Nerd Font icons, and the space following each, from s
+func stripGlyphs(s string) string {
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		if unicode.In(r, unicode.Co) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// renderAccessible renders the main screen as plain lines: a header for the active panel,
+// one line per row with text markers instead of colors and icons, then input, status and keys
+func (m Model) renderAccessible() string {
+	var lines []string
+	if banner := strings.TrimSpace(m.renderBanners()); banner != "" {
+		for _, line := range strings.Split(banner, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if m.ActivePanel == FilePanel {
+		lines = append(lines, m.accessibleFiles()...)
+	} else {
+		lines = append(lines, m.accessibleTodos()...)
+	}
+	lines = append(lines, "")
+
+	if d, ok := m.openModal(); ok && d.input == "" {
+		lines = append(lines, i18n.Tf("Enter: %s", strings.TrimSpace(d.choices[m.focusedChoice(d)].label)))
+	} else if m.Mode == EditMode {
+		lines = append(lines, m.accessibleInputLabel()+": "+m.InputText)
+	}
+	if m.Mode == NormalMode {
+		for _, p := range m.shownProgress() {
+			lines = append(lines, progressText(p))
+		}
+		for _, t := range m.Toasts {
+			lines = append(lines, t.Text)
+		}
+	} else if m.StatusMessage != "" {
+		lines = append(lines, m.StatusMessage)
+	}
+	lines = append(lines, strings.TrimSpace(m.renderHints()))
+
+	// Wrap rather than cut, so nothing is hidden from screen readers
+	return ansi.Wordwrap(stripGlyphs(strings.Join(lines, "\n")), m.Width, "")
+}
+
+// accessibleInputLabel names what the input line is for
+func (m Model) accessibleInputLabel() string {
+	switch m.Dialog {
+	case DuePrompt:
+		return i18n.T("Due")
+	case StartPrompt:
+		return i18n.T("Start")
+	case ListTagsPrompt:
+		return i18n.T("Tags")
+	case ReminderPrompt:
+		return i18n.T("Remind before")
+	case FieldPrompt:
+		return i18n.T("Field")
+	case CheckPrompt:
+		return i18n.T("Check")
+	case SearchPrompt:
+		if m.SearchRegex {
+			return i18n.T("Regex search")
+		}
+		return i18n.T("Search")
+	case FilterNamePrompt:
+		return i18n.T("Filter name")
+	case TitlePrompt:
+		return i18n.T("List title")
+	case JiraPrompt:
+		return i18n.T("Jira issue")
+	case ArchiveNamePrompt:
+		return m.archiveNameLabel()
+	case TemplatePrompt:
+		if field := m.templateField(); field != "" {
+			return fmt.Sprintf("{{%s}}", field)
+		}
+	}
+	return i18n.T("Input")
+}
+
+// accessibleArchive lists the archived files by month, marking the cursor
+func (m Model) accessibleArchive() []string {
+	lines := []string{i18n.Tf("Archived files: %d", len(m.ArchivedFiles))}
+	for i, row := range m.archiveRows() {
+		line := "  " + m.archiveLabel(row.file)
+		if row.file == "" {
+			line = i18n.Tf("%s: %d files", monthLabel(row.month), row.count)
+			if row.folded {
+				line += " " + i18n.T("[FOLDED]")
+			}
+		}
+		lines = append(lines, accessibleRow(line, i == m.FileCursor))
+	}
+	return lines
+}
+
+// accessibleFiles lists the files, marking the cursor and the open list
+func (m Model) accessibleFiles() []string {
+	if m.ShowingArchive {
+		return m.accessibleArchive()
+	}
+	header := i18n.Tf("Files: %d", len(m.Files))
+	if len(m.ArchivedFiles) > 0 {
+		header += ", " + i18n.Tf("%d archived", len(m.ArchivedFiles))
+	}
+
+	lines := []string{header}
+	if recent := m.accessibleRecent(); recent != "" {
+		lines = append(lines, recent)
+	}
+	for i, file := range m.Files {
+		line := m.displayName(file)
+		if file == m.CurrentFile && m.virtual == nil {
+			line += " " + i18n.T("[OPEN]")
+		}
+		if m.Marked[file] {
+			line += " " + i18n.T("[MARKED]")
+		}
+		lines = append(lines, accessibleRow(line, i == m.FileCursor))
+	}
+	for i, f := range m.Filters {
+		line := i18n.Tf("Filter %s", f.Name)
+		if m.virtual != nil && m.virtual.name == f.Name {
+			line += " " + i18n.T("[OPEN]")
+		}
+		lines = append(lines, accessibleRow(line, len(m.Files)+i == m.FileCursor))
+	}
+	return lines
+}
+
+// accessibleTodos lists the visible todos of the open list under a header with its filters
+func (m Model) accessibleTodos() []string {
+	header := i18n.Tf("List: %s", m.listName())
+	if m.Loading == "" {
+		completed, total := m.TodoList.CountFunc(m.matchesFilter)
+		header += ", " + i18n.Tf("%d of %d done", completed, total)
+	}
+	if m.ActiveContext != "" {
+		header += ", @" + m.ActiveContext
+	}
+	if m.FieldFilter != "" {
+		header += ", " + i18n.Tf("field %s", m.FieldFilter)
+	}
+	if m.Search != "" {
+		header += ", " + i18n.Tf("search %s", m.Search)
+	}
+	if m.Focus {
+		header += ", " + i18n.T("focus mode")
+	}
+	if m.TodoList.IsHabit() {
+		header += ", " + i18n.T("habit list")
+	}
+
+	lines := []string{header}
+	visible := m.visibleIndices()
+	switch {
+	case m.Loading != "":
+		return append(lines, i18n.Tf("Loading %s…", filepath.Base(m.Loading)))
+	case len(m.TodoList.Todos) == 0:
+		return append(lines, i18n.T("No todos yet"), strings.TrimSpace(i18n.T("  Press 'a' to add one")))
+	case len(visible) == 0:
+		return append(lines, i18n.T("Nothing matches the filters"))
+	}
+
+	start, end := m.todoWindow(len(visible))
+	for _, i := range visible[start:end] {
+		lines = append(lines, accessibleRow(m.accessibleTodo(i), i == m.TodoCursor))
+	}
+	return lines
+}
+
+// accessibleTodo describes a todo or heading with text markers
+func (m Model) accessibleTodo(i int) string {
+	t := m.TodoList.Todos[i]
+	if t.Heading {
+		completed, total := m.TodoList.SectionStatsFunc(i, m.matchesFilter)
+		line := i18n.Tf("Section %s, %d of %d done", t.Title, completed, total)
+		if t.Collapsed {
+			line += " " + i18n.T("[FOLDED]")
+		}
+		return line
+	}
+
+	line := i18n.T("[ ]") + " " + t.Title
+	if t.Completed {
+		line = i18n.T("[DONE]") + " " + t.Title
+	}
+	if t.Flagged {
+		line += " " + i18n.T("[FLAGGED]")
+	}
+	if t.Notes != "" {
+		line += " " + i18n.T("[NOTES]")
+	}
+	if t.Check != "" {
+		line += " " + i18n.Tf("[CHECK %s]", t.Check)
+	}
+	if !t.Completed && t.Progress > 0 {
+		line += " " + i18n.Tf("[%d%%]", t.Progress)
+	}
+	if author := m.otherAuthor(t); author != "" {
+		line += " " + i18n.Tf("[BY %s]", author)
+	}
+	if t.Deferred(time.Now()) {
+		line += " " + i18n.Tf("[STARTS %s]", t.Start.Format("Jan 2"))
+	}
+	if t.Due != nil {
+		due := t.Due.Format("Jan 2 15:04")
+		if !t.Completed && time.Now().After(*t.Due) {
+			line += " " + i18n.Tf("[OVERDUE %s]", due)
+		} else {
+			line += " " + i18n.Tf("[DUE %s]", due)
+		}
+		if t.RemindBefore != nil && !t.ReminderAcked {
+			line += " " + i18n.T("[REMINDER]")
+		}
+	}
+	for _, key := range t.FieldKeys() {
+		line += " " + key + "=" + t.Fields[key]
+	}
+	if m.TodoList.IsHabit() {
+		if n := t.Streak(time.Now()); n > 0 {
+			line += " " + i18n.Tf("[STREAK %d]", n)
+		}
+	}
+	return line
+}
+
+// accessibleRow prefixes a row with the cursor marker
+func accessibleRow(line string, selected bool) string {
+	if selected {
+		return "> " + line
+	}
+	return "  " + line
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// archiveRow is a line of the archive view: a month header, or a file below it
+type archiveRow struct {
+	month  string // "2006-01"
+	file   string // "" for the month header
+	count  int    // files archived in the month, on headers
+	folded bool   // the month's files are hidden, on headers
+}
+
+// archiveHeader returns the metadata of an archived file in dir. Files archived
+// before the date was recorded get their modification time instead.
+func archiveHeader(dir, file string) todo.Header {
+	path := filepath.Join(dir, file)
+	h := todo.ReadHeader(path)
+	if h.Archived.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			h.Archived = info.ModTime()
+		}
+	}
+	return h
+}
+
+// noteArchived adds a file just moved to the archive to the archived files, without
+// reading the archive again
+func (m *Model) noteArchived(file string, h todo.Header) {
+	m.archiveGen++
+	archived := maps.Clone(m.Archived)
+	if archived == nil {
+		archived = map[string]todo.Header{}
+	}
+	archived[file] = h
+	m.Archived = archived
+
+	files := slices.DeleteFunc(slices.Clone(m.ArchivedFiles), func(f string) bool { return f == file })
+	files = append(files, file)
+	titles := map[string]string{}
+	for f, h := range archived {
+		titles[f] = h.Title
+	}
+	sortFiles(files, titles)
+	m.ArchivedFiles = files
+}
+
+// noteUnarchived drops a file just moved out of the archive from the archived files
+func (m *Model) noteUnarchived(file string) {
+	m.archiveGen++
+	archived := maps.Clone(m.Archived)
+	delete(archived, file)
+	m.Archived = archived
+	m.ArchivedFiles = slices.DeleteFunc(slices.Clone(m.ArchivedFiles), func(f string) bool { return f == file })
+}
+
+// archiveMonth returns the month an archived file is grouped under
+func (m Model) archiveMonth(file string) string {
+	return m.Archived[file].Archived.Format("2006-01")
+}
+
+// archiveRows groups the archived files by the month they were archived in, newest
+// first. Only the newest month starts unfolded, files of folded months are left out.
+func (m Model) archiveRows() []archiveRow {
+	files := slices.Clone(m.ArchivedFiles)
+	slices.SortStableFunc(files, func(a, b string) int {
+		return m.Archived[b].Archived.Compare(m.Archived[a].Archived)
+	})
+	var rows []archiveRow
+	header := -1
+	for _, f := range files {
+		month := m.archiveMonth(f)
+		if header < 0 || rows[header].month != month {
+			folded := (header >= 0) != m.archiveToggled[month]
+			rows = append(rows, archiveRow{month: month, folded: folded})
+			header = len(rows) - 1
+		}
+		rows[header].count++
+		if !rows[header].folded {
+			rows = append(rows, archiveRow{month: month, file: f})
+		}
+	}
+	return rows
+}
+
+// selectArchiveRow unarchives the file under the cursor, or folds its month
+func (m *Model) selectArchiveRow() tea.Cmd {
+	rows := m.archiveRows()
+	if m.FileCursor >= len(rows) {
+		return nil
+	}
+	row := rows[m.FileCursor]
+	if row.file == "" {
+		toggled := maps.Clone(m.archiveToggled)
+		if toggled == nil {
+			toggled = map[string]bool{}
+		}
+		toggled[row.month] = !toggled[row.month]
+		m.archiveToggled = toggled
+		return nil
+	}
+	return m.startUnarchive(row.file)
+}
+
+// archiveLabel returns what an archived file is shown as
+func (m Model) archiveLabel(file string) string {
+	return fileLabel(file, map[string]string{file: m.Archived[file].Title})
+}
+
+// renderArchive renders the archived files grouped by month
+func (m Model) renderArchive() string {
+	content := m.Styles.Separator.Render(i18n.T("  ─── archived ───")) + "\n\n"
+	for i, row := range m.archiveRows() {
+		var text string
+		if row.file == "" {
+			icon := "▾"
+			if row.folded {
+				icon = "▸"
+			}
+			text = fmt.Sprintf("%s %s (%d)", icon, monthLabel(row.month), row.count)
+		} else {
+			text = "󰃨 " + m.archiveLabel(row.file)
+		}
+		switch {
+		case m.ActivePanel == FilePanel && i == m.FileCursor:
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+text+" ") + "\n"
+		case row.file == "":
+			content += m.Styles.Subtitle.Render(" "+text) + "\n"
+		default:
+			content += m.Styles.Dimmed.Render("   "+text) + "\n"
+		}
+	}
+	return content
+}
+
+// monthLabel turns a "2006-01" month into "Jan 2006"
+func monthLabel(month string) string {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return month
+	}
+	return t.Format("Jan 2006")
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/todo"
+)
+
+// otherAuthor returns who completed or added the todo when that was someone else,
+// "" for your own todos and those with no author recorded
+func (m Model) otherAuthor(t todo.Todo) string {
+	if author := t.Author(); !strings.EqualFold(author, m.Author) {
+		return author
+	}
+	return ""
+}
+
+// renderAuthor renders the initial of whoever else completed or added the todo, in
+// a color of their own so people sharing a list are told apart
+func (m Model) renderAuthor(t todo.Todo) string {
+	author := m.otherAuthor(t)
+	if author == "" {
+		return ""
+	}
+	var sum int
+	for _, r := range strings.ToLower(author) {
+		sum += int(r)
+	}
+	color := confettiColors[sum%len(confettiColors)]
+	return "  " + lipgloss.NewStyle().Foreground(color).Render(" "+todo.Initial(author))
+}
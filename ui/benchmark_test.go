@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/todo"
+)
+
+// benchmarkModel returns a model showing a list of n todos in a 120x40 terminal
+func benchmarkModel(b *testing.B, n int) Model {
+	b.Helper()
+
+	tl := &todo.TodoList{NextID: n + 1}
+	for i := 0; i < n; i++ {
+		tl.Todos = append(tl.Todos, todo.Todo{ID: i + 1, Title: fmt.Sprintf("Todo item number %d", i+1), Completed: i%3 == 0})
+	}
+	tl.SetDeferredSave(true)
+
+	return Model{
+		TodoList:    tl,
+		ActivePanel: TodoPanel,
+		TodoDir:     filepath.Join(b.TempDir(), "todos"),
+		CurrentFile: "large.json",
+		Styles:      NewStyles(),
+		Keys:        DefaultKeymap(),
+		Width:       120,
+		Height:      40,
+		TodoCursor:  n / 2,
+	}
+}
+
+// BenchmarkView_Large renders a 50,000 todo list through the scroll window
+func BenchmarkView_Large(b *testing.B) {
+	m := benchmarkModel(b, 50000)
+	m.scrollTodos()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.View()
+	}
+}
+
+// BenchmarkView_Idle shows the same 50,000 todo list after mouse motion, which reuses the last frame
+func BenchmarkView_Idle(b *testing.B) {
+	m := benchmarkModel(b, 50000)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = next.(Model)
+	m.View()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next, _ := m.Update(tea.MouseMsg{Action: tea.MouseActionMotion})
+		next.View()
+	}
+}
+
+// BenchmarkRenderTodoList_Unwindowed renders every row of a 50,000 todo list for comparison
+func BenchmarkRenderTodoList_Unwindowed(b *testing.B) {
+	m := benchmarkModel(b, 50000)
+	m.Height = 0 // no window before the first resize
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.renderTodoList()
+	}
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+)
+
+const (
+	celebrateFrames   = 12 // frames shown for a completed todo, doubled for a completed list
+	celebrateInterval = 80 * time.Millisecond
+	confettiWidth     = 24
+)
+
+var (
+	confettiChars  = []rune("*+·•°✦✧~")
+	confettiColors = []lipgloss.Color{ColorPink, ColorYellow, ColorGreen, ColorSky, ColorMauve, ColorPeach}
+)
+
+// celebrateChoices are the effects the settings screen cycles through
+var celebrateChoices = []string{config.CelebrateOff, config.CelebrateConfetti, config.CelebrateBell, config.CelebrateBoth}
+
+// celebrateTickMsg advances the confetti animation
+type celebrateTickMsg struct {
+	gen int // ignored once a newer celebration started
+}
+
+// celebrate plays the configured effect for a completed todo, bigger when
+// the whole list is done
+func (m *Model) celebrate(listDone bool) tea.Cmd {
+	effect := m.Behavior.Celebrate
+	var cmds []tea.Cmd
+
+	if effect == config.CelebrateBell || effect == config.CelebrateBoth {
+		rings := 1
+		if listDone {
+			rings = 2
+		}
+		cmds = append(cmds, func() tea.Msg {
+			fmt.Fprint(os.Stdout, strings.Repeat("\a", rings))
+			return nil
+		})
+	}
+
+	if (effect == config.CelebrateConfetti || effect == config.CelebrateBoth) && !m.Behavior.Accessible {
+		m.celebrateGen++
+		m.celebrateFrame = celebrateFrames
+		if listDone {
+			m.celebrateFrame *= 2
+		}
+		cmds = append(cmds, tickCelebrate(m.celebrateGen))
+	}
+	cmds = append(cmds, m.playSound(listDone))
+	return tea.Batch(cmds...)
+}
+
+// tickCelebrate schedules the next confetti frame
+func tickCelebrate(gen int) tea.Cmd {
+	return tea.Tick(celebrateInterval, func(time.Time) tea.Msg {
+		return celebrateTickMsg{gen: gen}
+	})
+}
+
+// handleCelebrateTick advances the confetti until it runs out of frames
+func (m Model) handleCelebrateTick(msg celebrateTickMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.celebrateGen || m.celebrateFrame <= 0 {
+		return m, nil
+	}
+	m.celebrateFrame--
+	if m.celebrateFrame == 0 {
+		return m, nil
+	}
+	return m, tickCelebrate(msg.gen)
+}
+
+// renderConfetti renders the current confetti frame, or "" when idle
+func (m Model) renderConfetti() string {
+	if m.celebrateFrame <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < confettiWidth; i++ {
+		// Sparse, drifting pattern that thins out as the animation ends
+		if (i*5+m.celebrateFrame*3)%7 >= min(m.celebrateFrame, 5) {
+			b.WriteByte(' ')
+			continue
+		}
+		c := confettiChars[(i*7+m.celebrateFrame*3)%len(confettiChars)]
+		color := confettiColors[(i+m.celebrateFrame)%len(confettiColors)]
+		b.WriteString(lipgloss.NewStyle().Foreground(color).Render(string(c)))
+	}
+	return b.String()
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/config"
+	"justdoit/i18n"
+)
+
+// checkTimeout bounds how long a check command may run before it counts as failed
+const checkTimeout = 10 * time.Second
+
+// maxParallelChecks bounds how many check commands run at once
+const maxParallelChecks = 4
+
+// checksDoneMsg carries the outcome of a run of check commands
+type checksDoneMsg struct {
+	path    string        // list the checks belong to
+	results map[int]error // todo ID to nil for a pass
+}
+
+// runCheck runs a check command through the shell in dir, without stdin and with a
+// timeout, returning nil when it exits 0
+func runCheck(dir, command string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = time.Second // don't wait on children holding the output open
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		// the last line of output usually says what went wrong
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			return fmt.Errorf("%w: %s", err, last)
+		}
+		return err
+	}
+	return nil
+}
+
+// runChecks runs the given check commands of a list in the background
+func runChecks(path string, checks map[int]string) tea.Cmd {
+	dir := filepath.Dir(path)
+	return runProgress(i18n.T("Running checks"), func(report func(done, total int)) tea.Msg {
+		results := make(map[int]error, len(checks))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, maxParallelChecks)
+		for id, command := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				slots <- struct{}{}
+				err := runCheck(dir, command, checkTimeout)
+				<-slots
+				mu.Lock()
+				results[id] = err
+				report(len(results), len(checks))
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return checksDoneMsg{path: path, results: results}
+	})
+}
+
+// pendingChecks are check commands of a list waiting for the user to trust them
+type pendingChecks struct {
+	path   string
+	checks map[int]string // todo ID to command
+}
+
+// untrusted returns the commands among checks not trusted to run yet, sorted
+func (m Model) untrusted(checks map[int]string) []string {
+	var commands []string
+	for _, command := range checks {
+		if !slices.Contains(m.TrustedChecks, command) && !slices.Contains(commands, command) {
+			commands = append(commands, command)
+		}
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// startChecks runs the check commands of the open list, or of one todo when id >= 0.
+// Commands come with the list file, so any not trusted here yet are asked about first.
+func (m *Model) startChecks(id int) tea.Cmd {
+	checks := m.TodoList.Checks()
+	if id >= 0 {
+		checks = map[int]string{id: checks[id]}
+	}
+	if len(checks) == 0 {
+		m.toast(SeverityInfo, i18n.T("No check todos in this list, set a command with c"))
+		return nil
+	}
+	if commands := m.untrusted(checks); len(commands) > 0 {
+		m.checks = &pendingChecks{path: m.TodoList.Path(), checks: checks}
+		m.openDialog(ConfirmChecks)
+		m.toast(SeverityWarning, i18n.Tf("Run %d commands from this list? (y/n)", len(commands)))
+		return nil
+	}
+	m.toast(SeverityInfo, i18n.Tf("Running %d checks…", len(checks)))
+	return runChecks(m.TodoList.Path(), checks)
+}
+
+// trustChecks adds commands to the trusted ones and saves them to the config
+func (m *Model) trustChecks(commands []string) error {
+	m.TrustedChecks = append(slices.Clip(m.TrustedChecks), commands...)
+	if m.ConfigPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg.Checks = m.TrustedChecks
+	return config.Save(m.ConfigPath, cfg)
+}
+
+// handleConfirmChecks runs the pending check commands once the user trusts them
+func (m Model) handleConfirmChecks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.checks
+	switch msg.String() {
+	case "y", "Y":
+		m.closeDialog()
+		m.checks = nil
+		if err := m.trustChecks(m.untrusted(p.checks)); err != nil {
+			m.toast(SeverityError, i18n.Tf("Trusting the commands failed: %v", err))
+			return m, nil
+		}
+		m.toast(SeverityInfo, i18n.Tf("Running %d checks…", len(p.checks)))
+		return m, runChecks(p.path, p.checks)
+	case "n", "N", "esc":
+		m.closeDialog()
+		m.checks = nil
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// checksModal lists the commands a list wants to run that aren't trusted yet
+func (m Model) checksModal() modal {
+	d := modal{
+		variant:  modalDanger,
+		title:    i18n.T("Run check commands?"),
+		subject:  filepath.Base(m.checks.path),
+		question: i18n.T("These run in your shell. Trust them from now on?"),
+		choices: []choice{
+			{"y", i18n.T(" Run and trust")},
+			{"n", i18n.T(" Cancel")},
+		},
+		safe: 1,
+	}
+	d.details = m.untrusted(m.checks.checks)
+	return d
+}
+
+// handleChecksDone marks check todos done or open by their results and reports failures
+func (m Model) handleChecksDone(msg checksDoneMsg) (tea.Model, tea.Cmd) {
+	tl := m.TodoList
+	if tl.Path() != msg.path {
+		tl = m.newList(msg.path) // switched lists while the checks ran
+	}
+
+	ids := make([]int, 0, len(msg.results))
+	for id := range msg.results {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	passed := 0
+	var failure string
+	for _, id := range ids {
+		err := msg.results[id]
+		tl.SetChecked(id, err == nil)
+		if err == nil {
+			passed++
+		} else if i := tl.IndexOf(id); failure == "" && i >= 0 {
+			failure = fmt.Sprintf("%s: %v", tl.Todos[i].Title, err)
+		}
+	}
+	m.clampTodoCursor()
+	var cmd tea.Cmd
+	if tl != m.TodoList && tl.Dirty() {
+		cmd = saveTodoList(tl)
+	}
+
+	status := i18n.Tf("Checks: %d passed, %d failed", passed, len(ids)-passed)
+	if failure != "" {
+		m.toast(SeverityWarning, status+" · "+failure)
+	} else {
+		m.toast(SeverityInfo, status)
+	}
+	return m, cmd
+}
+
+// submitCheckPrompt sets or clears the check command of the current todo
+func (m Model) submitCheckPrompt() (tea.Model, tea.Cmd) {
+	m.TodoList.SetCheck(m.TodoCursor, m.InputText)
+	m.closeDialog()
+	if strings.TrimSpace(m.InputText) == "" {
+		m.toast(SeverityInfo, i18n.T("Check command removed"))
+		return m, nil
+	}
+	t := m.TodoList.Todos[m.TodoCursor]
+	if err := m.trustChecks(m.untrusted(map[int]string{t.ID: t.Check})); err != nil { // typed here, so trusted
+		m.toast(SeverityError, i18n.Tf("Trusting the command failed: %v", err))
+		return m, nil
+	}
+	cmd := m.startChecks(t.ID)
+	return m, cmd
+}
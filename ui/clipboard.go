@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// clipboardWrite copies text to the system clipboard; replaced in tests
+var clipboardWrite = writeClipboard
+
+// clipboardRead returns the system clipboard's text; replaced in tests
+var clipboardRead = readClipboard
+
+// copyCommands lists the tools tried, in order, to write the system clipboard
+func copyCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	default:
+		return [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+}
+
+// writeClipboard copies text with the platform clipboard tool, falling back to
+// an OSC 52 escape sequence that the terminal forwards (this also works over SSH)
+func writeClipboard(text string) error {
+	for _, args := range copyCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			return nil
+		}
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	}
+	if _, err := seq.WriteTo(os.Stdout); err != nil {
+		return errors.New("no clipboard available")
+	}
+	return nil
+}
+
+// pasteCommands lists the tools tried, in order, to read the system clipboard
+func pasteCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}}
+	default:
+		return [][]string{
+			{"wl-paste", "--no-newline"},
+			{"xclip", "-selection", "clipboard", "-out"},
+			{"xsel", "--clipboard", "--output"},
+		}
+	}
+}
+
+// readClipboard reads text with the platform clipboard tool
+func readClipboard() (string, error) {
+	for _, args := range pasteCommands() {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(args[0], args[1:]...).Output()
+		if err == nil {
+			return strings.TrimRight(string(out), "\r\n"), nil
+		}
+	}
+	return "", errors.New("no clipboard tool found (use your terminal's paste instead)")
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// collision is an archive or unarchive waiting for the user to decide what happens
+// to the file that already has the name
+type collision struct {
+	unarchive bool   // moving back out of the archive
+	file      string // file being moved
+	name      string // name it would get, taken in the target directory
+	rename    string // free name offered instead
+}
+
+// dir returns the directory the file is moved into
+func (c collision) dir(m Model) string {
+	if c.unarchive {
+		return m.TodoDir
+	}
+	return m.ArchiveDir
+}
+
+// exists reports whether dir has a file called name
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+// startArchive archives the current file, or asks first if the archive already has
+// a file by its name
+func (m *Model) startArchive() tea.Cmd {
+	name := m.archiveName(m.CurrentFile, time.Now())
+	if exists(m.ArchiveDir, name) {
+		m.askCollision(collision{file: m.CurrentFile, name: name})
+		return nil
+	}
+	return m.finishMove(collision{file: m.CurrentFile, name: name})
+}
+
+// startUnarchive unarchives a file, or asks first if the todo directory already has
+// a file by the name it gets back
+func (m *Model) startUnarchive(archived string) tea.Cmd {
+	name := m.restoreName(archived)
+	if exists(m.TodoDir, name) {
+		m.askCollision(collision{unarchive: true, file: archived, name: name})
+		return nil
+	}
+	return m.finishMove(collision{unarchive: true, file: archived, name: name})
+}
+
+// askCollision opens the prompt for a taken name, over the archive question if it
+// came from there
+func (m *Model) askCollision(c collision) {
+	c.rename = freeName(c.dir(*m), c.name)
+	m.collision = &c
+	m.pushDialog(NameCollision)
+	if c.unarchive {
+		m.toast(SeverityInfo, i18n.Tf("%s already exists: r renames the restored file, e edits the name, o overwrites, c cancels", c.name))
+	} else {
+		m.toast(SeverityInfo, i18n.Tf("%s is already archived: r renames the new archive, e edits the name, o overwrites, c cancels", c.name))
+	}
+}
+
+// finishMove archives or unarchives a file under the name decided on
+func (m *Model) finishMove(c collision) tea.Cmd {
+	m.collision = nil
+	m.closeDialogs()
+	if c.unarchive {
+		return m.unarchiveFile(c.file, c.name)
+	}
+	return m.archiveCurrentFile(c.name)
+}
+
+// handleCollision handles input in the name collision prompt
+func (m Model) handleCollision(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := *m.collision
+	switch msg.String() {
+	case "r", "R":
+		c.name = c.rename
+		cmd := m.finishMove(c)
+		return m, cmd
+	case "e", "E":
+		m.pushDialog(ArchiveNamePrompt)
+		m.InputText = strings.TrimSuffix(c.rename, ".json")
+		m.toast(SeverityInfo, i18n.T("Type a free name (Enter to save, Esc to go back)"))
+	case "o", "O":
+		cmd := m.finishMove(c)
+		return m, cmd
+	case "c", "C", "n", "N":
+		m.collision = nil
+		m.closeDialogs()
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	case "esc":
+		// Back to the archive question, if it asked
+		m.collision = nil
+		m.closeDialog()
+		if m.Mode == NormalMode {
+			m.toast(SeverityInfo, i18n.T("Cancelled"))
+		}
+	}
+	return m, nil
+}
+
+// submitArchiveName archives or unarchives under the name typed in the prompt
+func (m Model) submitArchiveName() (tea.Model, tea.Cmd) {
+	c := *m.collision
+	name := todo.NormalizeListName(m.InputText, m.Behavior.LowercaseNames)
+	if err := todo.ValidateListName(name); err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Invalid name: %v", err))
+		return m, nil
+	}
+	c.name = name + ".json"
+	if exists(c.dir(m), c.name) {
+		m.toast(SeverityInfo, i18n.Tf("%s is taken too", c.name))
+		return m, nil
+	}
+	cmd := m.finishMove(c)
+	return m, cmd
+}
+
+// archiveNameLabel names the prompt for a free archive or unarchive name
+func (m Model) archiveNameLabel() string {
+	if m.collision != nil && m.collision.unarchive {
+		return i18n.T("Restore as")
+	}
+	return i18n.T("Archive as")
+}
+
+// collisionModal asks what happens to the file that already has the name
+func (m Model) collisionModal() modal {
+	c := m.collision
+	d := modal{
+		variant:  modalWarning,
+		title:    i18n.T("󰈅 Name already taken"),
+		subject:  c.name,
+		question: i18n.T("The archive already has a file by this name"),
+		choices: []choice{
+			{"r", i18n.Tf(" Rename to %s", c.rename)},
+			{"e", i18n.T(" Edit name")},
+			{"o", i18n.T(" Overwrite")},
+			{"c", i18n.T(" Cancel")},
+		},
+		safe: 3,
+	}
+	if c.unarchive {
+		d.question = i18n.T("A list by this name already exists")
+	}
+	if m.Dialog == ArchiveNamePrompt {
+		d.input = m.archiveNameLabel() + ": " + m.InputText + "█.json"
+	}
+	return d
+}
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// compareView is a second list shown beside the open one, for moving todos between them
+type compareView struct {
+	files  []string       // choices shown in the picker
+	pick   int            // selected choice in the picker
+	file   string         // the second list's file name
+	list   *todo.TodoList // the second list, saved on every change
+	cursor int            // selected todo of the second list
+	right  bool           // the second list has the cursor
+}
+
+// openComparePicker asks for the list to show beside the open one
+func (m *Model) openComparePicker() {
+	var files []string
+	for _, f := range m.Files {
+		if (f != m.CurrentFile || m.TodoList.IsScratch()) && !m.Foreign[f] && !IsHiddenFile(f) {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		m.toast(SeverityInfo, i18n.T("No other list to compare with"))
+		return
+	}
+	m.compare = &compareView{files: files}
+	m.openDialog(ComparePicker)
+	m.toast(SeverityInfo, i18n.Tf("Compare %s with", m.listName()))
+}
+
+// handleComparePicker handles input in the picker of the second list
+func (m Model) handleComparePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.compare
+	switch msg.String() {
+	case "j", "down":
+		if c.pick < len(c.files)-1 {
+			c.pick++
+		}
+	case "k", "up":
+		if c.pick > 0 {
+			c.pick--
+		}
+	case "enter", " ":
+		c.file = c.files[c.pick]
+		c.list = todo.NewTodoList(filepath.Join(m.TodoDir, c.file))
+		c.list.SetAutoSort(m.Behavior.SortCompleted)
+		c.list.SetMaxTitleLength(m.Behavior.MaxTitleLength)
+		c.list.SetAuthor(m.Author)
+		m.closeDialog()
+		m.openDialog(CompareScreen)
+		m.toast(SeverityInfo, i18n.Tf("Comparing %s with %s", m.listName(), m.displayName(c.file)))
+	case "esc", "q":
+		m.closeDialog()
+		m.compare = nil
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// compareSide returns the list and rows of one side of the compare screen, with a
+// pointer to its cursor. The open list shows the rows its filters leave.
+func (m *Model) compareSide(right bool) (*todo.TodoList, []int, *int) {
+	if right {
+		tl := m.compare.list
+		rows := make([]int, len(tl.Todos))
+		for i := range rows {
+			rows[i] = i
+		}
+		return tl, rows, &m.compare.cursor
+	}
+	return m.TodoList, m.visibleIndices(), &m.TodoCursor
+}
+
+// handleCompare handles input in the compare screen
+func (m Model) handleCompare(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.compare
+	tl, rows, cursor := m.compareSide(c.right)
+	pos := max(slices.Index(rows, *cursor), 0)
+
+	switch msg.String() {
+	case "j", "down":
+		if pos < len(rows)-1 {
+			*cursor = rows[pos+1]
+		}
+	case "k", "up":
+		if pos > 0 {
+			*cursor = rows[pos-1]
+		}
+	case "tab", "h", "l", "left", "right":
+		c.right = !c.right
+	case "m", "enter":
+		m.moveCompared(tl, rows, cursor)
+	case "x", " ":
+		if pos < len(rows) && !tl.Todos[*cursor].Heading {
+			tl.Toggle(*cursor)
+		}
+	case "esc", "q":
+		m.TodoList.Flush()
+		m.closeDialog()
+		m.compare = nil
+		m.clampTodoCursor()
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// moveCompared moves the selected todo of the side with the cursor to the top of the
+// other list
+func (m *Model) moveCompared(src *todo.TodoList, rows []int, cursor *int) {
+	if len(rows) == 0 || src.Todos[*cursor].Heading {
+		return
+	}
+	dst, dstName := m.compare.list, m.displayName(m.compare.file)
+	if m.compare.right {
+		dst, dstName = m.TodoList, m.listName()
+	}
+	if err := src.MoveTo(*cursor, dst); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+
+	// Stay on the row the moved todo left, or the one above at the end
+	_, rows, _ = m.compareSide(m.compare.right)
+	if pos := sort.SearchInts(rows, *cursor); pos < len(rows) {
+		*cursor = rows[pos]
+	} else if len(rows) > 0 {
+		*cursor = rows[len(rows)-1]
+	}
+	m.toast(SeverityInfo, i18n.Tf("Moved to %s", dstName))
+}
+
+// renderCompare renders the open list and the second list side by side in place of
+// the todo panel
+func (m Model) renderCompare(width, height int) string {
+	left := (width - 2) / 2
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderCompareSide(false, m.listName(), left, height),
+		m.renderCompareSide(true, m.displayName(m.compare.file), width-2-left, height),
+	)
+}
+
+// renderCompareSide renders one list of the compare screen, its cursor shown while
+// it has the focus
+func (m Model) renderCompareSide(right bool, name string, width, height int) string {
+	tl, rows, cursor := m.compareSide(right)
+	active := m.compare.right == right
+
+	// Keep the cursor inside the window
+	window := max(height-4, 1)
+	pos := max(slices.Index(rows, *cursor), 0)
+	start := max(0, min(pos-window+1, len(rows)-window))
+	end := min(len(rows), start+window)
+
+	content := ""
+	if len(rows) == 0 {
+		content = m.Styles.Dimmed.Italic(true).Render(i18n.Tf("  %s  No todos yet", "󰄱"))
+	}
+	for _, i := range rows[start:end] {
+		t := tl.Todos[i]
+		var line string
+		switch {
+		case t.Heading:
+			line = m.Styles.Muted.Bold(true).Render(t.Title)
+		case t.Completed:
+			line = m.renderCheckbox(t) + "  " + m.Styles.Completed.Render(t.Title)
+		default:
+			line = m.renderCheckbox(t) + "  " + m.renderTitle(t.Title, m.titleStyle(t, m.Styles.Normal))
+		}
+		if active && i == *cursor {
+			mark := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			line = m.Styles.Selected.Render(" " + mark + " " + line + " ")
+		} else {
+			line = "  " + line
+		}
+		content += line + "\n"
+	}
+
+	borderStyle := m.Styles.Border
+	if active {
+		borderStyle = m.Styles.ActiveBorder
+	}
+	completed, total := tl.Counts()
+	title := m.Styles.Title.Render(fmt.Sprintf(" 󰄲 %s ", name)) + " " +
+		m.Styles.Badge.Render(fmt.Sprintf(" %d/%d ", completed, total))
+	return borderStyle.
+		Width(width).
+		Height(height).
+		Padding(1, 1).
+		Render(title + "\n\n" + fitLines(content, width-2))
+}
+
+// renderComparePicker renders the picker of the list shown beside the open one
+func (m Model) renderComparePicker() string {
+	pickerStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(1, 4)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.Tf(" Compare %s with", m.listName()))
+
+	content := title + "\n\n"
+	for i, file := range m.compare.files {
+		label := m.displayName(file)
+		if i == m.compare.pick {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+label+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+label) + "\n"
+		}
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		pickerStyle.Render(content),
+	)
+}
@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
+	"justdoit/remote"
+	"justdoit/todo"
+)
+
+// resolver holds a sync conflict being merged todo by todo
+type resolver struct {
+	rel    string         // file in conflict, relative to the data directory
+	local  *todo.TodoList // this machine's version
+	server *todo.TodoList // the server's version, kept under remote.ConflictDir
+	items  []todo.MergeItem
+	cursor int
+}
+
+// openResolver starts merging the first file in conflict
+func (m *Model) openResolver() {
+	if len(m.Conflicts) == 0 {
+		m.toast(SeverityInfo, i18n.T("No sync conflicts"))
+		return
+	}
+	m.TodoList.Flush()
+
+	rel := m.Conflicts[0]
+	local := todo.NewTodoList(filepath.Join(m.TodoDir, filepath.FromSlash(rel)))
+	server := todo.NewTodoList(filepath.Join(m.TodoDir, remote.ConflictDir, filepath.FromSlash(rel)))
+	m.resolving = &resolver{rel: rel, local: local, server: server, items: todo.Diverging(local, server)}
+
+	m.openDialog(ConflictResolver)
+	m.toast(SeverityInfo, i18n.Tf("Merging %s: %d todos differ", rel, len(m.resolving.items)))
+}
+
+// handleResolver handles input in the conflict resolver
+func (m Model) handleResolver(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.resolving
+	switch msg.String() {
+	case "j", "down":
+		if r.cursor < len(r.items)-1 {
+			r.cursor++
+		}
+	case "k", "up":
+		if r.cursor > 0 {
+			r.cursor--
+		}
+	case "h", "left":
+		r.choose(todo.PickLocal)
+	case "l", "right":
+		r.choose(todo.PickRemote)
+	case "b":
+		r.choose(todo.KeepBoth)
+	case "enter", "w":
+		return m.writeMerge()
+	case "esc", "q":
+		m.closeDialog()
+		m.resolving = nil
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// choose sets the choice for the selected todo
+func (r *resolver) choose(c todo.MergeChoice) {
+	if r.cursor < len(r.items) {
+		r.items[r.cursor].Choice = c
+	}
+}
+
+// writeMerge saves the merged list, marks the conflict settled and moves on to the next one
+func (m Model) writeMerge() (tea.Model, tea.Cmd) {
+	r := m.resolving
+	if err := r.local.ApplyMerge(r.server, r.items); err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, nil
+	}
+	if err := remote.Resolve(m.TodoDir, r.rel); err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, nil
+	}
+
+	m.Conflicts = remote.Conflicts(m.TodoDir)
+	m.loadFiles()
+	if m.TodoList.Path() == r.local.Path() {
+		m.TodoList.Reload()
+		m.clampTodoCursor()
+	}
+
+	m.closeDialog()
+	m.resolving = nil
+	if len(m.Conflicts) > 0 {
+		m.openResolver()
+		return m, m.scanArchive()
+	}
+	m.toast(SeverityInfo, i18n.Tf("Merged %s, it goes to the server on the next sync", r.rel))
+	return m, m.scanArchive()
+}
+
+// renderConflictBanner tells about files waiting for a merge
+func (m Model) renderConflictBanner() string {
+	if len(m.Conflicts) == 0 {
+		return ""
+	}
+	text := i18n.Tf("󰕚 %d sync conflicts", len(m.Conflicts))
+	if keys := m.Keys.Keys(ActionResolve); len(keys) > 0 {
+		text += i18n.Tf("  ·  %s to merge", keyLabel(keys[0]))
+	}
+	bannerStyle := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorMaroon).
+		Bold(true).
+		Padding(0, 1)
+	return bannerStyle.Render(text) + "\n\n"
+}
+
+// renderResolver renders the local, remote and merged versions side by side
+func (m Model) renderResolver() string {
+	r := m.resolving
+	paneWidth := max((m.Width-2)/3, 12)
+	rows := max(m.Height-10, 3)
+	start := max(0, r.cursor-rows+1)
+	end := min(len(r.items), start+rows)
+
+	pane := func(title string, color lipgloss.Color, lines []string) string {
+		heading := lipgloss.NewStyle().Foreground(color).Bold(true).Render(title)
+		content := heading + "\n\n"
+		for _, line := range lines {
+			content += line + "\n"
+		}
+		return lipgloss.NewStyle().
+			Border(ThickBorder).
+			BorderForeground(color).
+			Padding(0, 1).
+			Width(paneWidth - 2).
+			Height(rows + 2).
+			Render(content)
+	}
+	fit := func(s string) string {
+		return ansi.Truncate(s, paneWidth-6, "…")
+	}
+
+	var left, middle []string
+	for i, it := range r.items[start:end] {
+		selected := start+i == r.cursor
+		left = append(left, m.resolverRow(fit(conflictText(it.Local)), selected, it.Choice == todo.PickLocal || it.Choice == todo.KeepBoth))
+		middle = append(middle, m.resolverRow(fit(conflictText(it.Remote)), selected, it.Choice == todo.PickRemote || it.Choice == todo.KeepBoth))
+	}
+	if len(r.items) == 0 {
+		left = append(left, m.Styles.Muted.Render(i18n.T("Only the order differs")))
+	}
+
+	var right []string
+	merged := todo.Merge(r.local, r.server, r.items)
+	for _, t := range merged[:min(len(merged), rows)] {
+		right = append(right, m.Styles.Normal.Render("   "+fit(conflictText(&t))))
+	}
+	if len(merged) > rows {
+		right = append(right, m.Styles.Muted.Render(i18n.Tf(" +%d more", len(merged)-rows)))
+	}
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		pane(i18n.Tf("Local · %s", r.rel), ColorPeach, left),
+		pane(i18n.T("Remote"), ColorSky, middle),
+		pane(i18n.T("Merged"), ColorGreen, right),
+	)
+	return lipgloss.Place(m.Width, m.Height-4, lipgloss.Center, lipgloss.Center, panes)
+}
+
+// resolverRow renders one side of a diverging todo, dimmed unless the merge keeps it
+func (m Model) resolverRow(text string, selected, kept bool) string {
+	style := m.Styles.Dimmed
+	if kept {
+		style = m.Styles.Normal
+	}
+	if selected {
+		cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+		return m.Styles.Selected.Render(" " + cursor + " " + style.Render(text))
+	}
+	return "   " + style.Render(text)
+}
+
+// conflictText describes a todo version compactly, or its absence
+func conflictText(t *todo.Todo) string {
+	if t == nil {
+		return i18n.T("(not there)")
+	}
+	text := "[ ] " + t.Title
+	if t.Completed {
+		text = "[x] " + t.Title
+	}
+	if t.Due != nil {
+		text += " · " + t.Due.Format("Jan 2")
+	}
+	if t.Flagged {
+		text += " !"
+	}
+	return text
+}
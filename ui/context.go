@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// matchesFilter reports whether a todo passes the active context filter and focus mode,
+// and isn't deferred while those are hidden. Section headings match outside focus mode
+// so the list keeps its structure.
+func (m Model) matchesFilter(t todo.Todo) bool {
+	if m.Behavior.HideDeferred && t.Deferred(time.Now()) {
+		return false
+	}
+	if m.Focus {
+		return !t.Heading && inFocus(t) && m.matchesContext(t)
+	}
+	return t.Heading || m.matchesContext(t)
+}
+
+// matchesContext reports whether a todo passes the active context, field and search filters
+func (m Model) matchesContext(t todo.Todo) bool {
+	return (m.ActiveContext == "" || t.HasContext(m.ActiveContext)) &&
+		(m.FieldFilter == "" || t.MatchesField(m.FieldFilter)) && m.matchesSearch(t)
+}
+
+// inFocus reports whether a todo belongs on the focus list: open, not deferred and
+// either flagged or due by the end of today
+func inFocus(t todo.Todo) bool {
+	now := time.Now()
+	if t.Completed || t.Deferred(now) {
+		return false
+	}
+	if t.Flagged {
+		return true
+	}
+	return t.Due != nil && t.Due.Before(todo.Tomorrow(now))
+}
+
+// visibleIndices returns the indices of todos that pass the active filters
+// and are not folded away in a collapsed section
+func (m Model) visibleIndices() []int {
+	indices := make([]int, 0, len(m.TodoList.Todos))
+	collapsed := false
+	for i, t := range m.TodoList.Todos {
+		if t.Heading {
+			collapsed = t.Collapsed && !m.Focus // focus mode shows a flat list
+		} else if collapsed {
+			continue
+		}
+		if m.matchesFilter(t) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// shownTodos returns the IDs of the todos the todo panel shows, without headings
+func (m Model) shownTodos() map[int]bool {
+	shown := make(map[int]bool)
+	for _, i := range m.visibleIndices() {
+		if t := m.TodoList.Todos[i]; !t.Heading {
+			shown[t.ID] = true
+		}
+	}
+	return shown
+}
+
+// moveTodoCursor moves the todo cursor by delta visible rows
+func (m *Model) moveTodoCursor(delta int) {
+	visible := m.visibleIndices()
+	for pos, i := range visible {
+		if i == m.TodoCursor {
+			pos = min(max(pos+delta, 0), len(visible)-1)
+			m.TodoCursor = visible[pos]
+			return
+		}
+	}
+	m.clampTodoCursor()
+}
+
+// clampTodoCursor keeps the todo cursor in range and on a visible todo
+func (m *Model) clampTodoCursor() {
+	if m.TodoCursor >= len(m.TodoList.Todos) {
+		m.TodoCursor = len(m.TodoList.Todos) - 1
+	}
+	if m.TodoCursor < 0 {
+		m.TodoCursor = 0
+	}
+
+	visible := m.visibleIndices()
+	if len(visible) == 0 {
+		return
+	}
+	for _, i := range visible {
+		if i >= m.TodoCursor {
+			m.TodoCursor = i
+			return
+		}
+	}
+	m.TodoCursor = visible[len(visible)-1]
+}
+
+// openContextSwitcher collects contexts across active files and shows the switcher
+func (m *Model) openContextSwitcher() {
+	lists := []*todo.TodoList{m.TodoList}
+	for _, file := range m.Files {
+		if file != m.CurrentFile {
+			lists = append(lists, todo.NewTodoList(filepath.Join(m.TodoDir, file)))
+		}
+	}
+
+	// First entry clears the filter
+	m.Contexts = append([]string{""}, todo.AllContexts(lists...)...)
+	m.ContextCursor = 0
+	for i, c := range m.Contexts {
+		if c == m.ActiveContext {
+			m.ContextCursor = i
+		}
+	}
+
+	m.openDialog(ContextSwitcher)
+	m.toast(SeverityInfo, i18n.T("Switch context"))
+}
+
+// filtering reports whether filters, focus mode or a non-default sort change what the todo panel shows
+func (m Model) filtering() bool {
+	return m.ActiveContext != "" || m.FieldFilter != "" || m.Search != "" || m.Focus ||
+		m.Dialog == FieldFilterPrompt || m.Dialog == SearchPrompt || m.manualOrder() || m.listSort() != "" ||
+		m.Behavior.HideDeferred
+}
+
+// clearableFilters reports whether any of the filters clearFilters drops is set
+func (m Model) clearableFilters() bool {
+	return m.ActiveContext != "" || m.FieldFilter != "" || m.Search != "" || m.Focus
+}
+
+// manualOrder reports whether completed todos in the todo panel keep their place, by
+// the open list's own setting or else the global one
+func (m Model) manualOrder() bool {
+	if m.TodoList == nil {
+		return !m.Behavior.SortCompleted
+	}
+	return !m.TodoList.AutoSort()
+}
+
+// listSort returns the open list's own sort, "" when its todos are in the order added
+func (m Model) listSort() string {
+	if m.TodoList == nil || m.TodoList.Settings == nil {
+		return todo.SortManual
+	}
+	return m.TodoList.Settings.Sort
+}
+
+// clearFilters drops the context, field and search filters and leaves focus mode
+func (m *Model) clearFilters() {
+	if !m.clearableFilters() {
+		m.toast(SeverityInfo, i18n.T("No filters to clear"))
+		return
+	}
+	m.ActiveContext = ""
+	m.FieldFilter = ""
+	m.Search = ""
+	m.Focus = false
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.T("Filters cleared"))
+}
+
+// renderFilterRow renders the chips explaining why todos are hidden or reordered,
+// or "" when the panel shows the whole list in its default order
+func (m Model) renderFilterRow() string {
+	if !m.filtering() {
+		return ""
+	}
+	row := m.renderContextChip() + m.renderFocusChip() + m.renderFieldChip() + m.renderSearchChip() + m.renderSortChip() + m.renderDeferredChip()
+	if keys := m.Keys.Keys(ActionClearFilters); len(keys) > 0 && m.clearableFilters() {
+		row += "  " + m.Styles.Muted.Render(i18n.Tf("%s clears all", keyLabel(keys[0])))
+	}
+	return row
+}
+
+// renderSortChip notes when the open list is sorted, or completed todos keep their
+// place instead of sinking to the bottom
+func (m Model) renderSortChip() string {
+	var text string
+	switch {
+	case m.listSort() != todo.SortManual:
+		text = i18n.Tf("⇅ %s", m.listSettingValue(0))
+	case m.manualOrder():
+		text = i18n.T("⇅ manual order")
+	default:
+		return ""
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorLavender).
+		Bold(true).
+		Padding(0, 1).
+		Render(text)
+	return " " + chip
+}
+
+// renderDeferredChip notes when todos before their start date are hidden, with how many
+func (m Model) renderDeferredChip() string {
+	if !m.Behavior.HideDeferred {
+		return ""
+	}
+	now := time.Now()
+	hidden := 0
+	for _, t := range m.TodoList.Todos {
+		if t.Deferred(now) {
+			hidden++
+		}
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorLavender).
+		Bold(true).
+		Padding(0, 1).
+		Render(i18n.Tf("󰔟 %d deferred hidden", hidden))
+	return " " + chip
+}
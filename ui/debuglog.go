@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
+)
+
+// debugLogTail is how much of the end of the debug log the viewer reads
+const debugLogTail = 256 << 10
+
+// slowRender is the render time above which a frame is logged
+const slowRender = 16 * time.Millisecond
+
+// logView holds the debug log lines shown in the viewer
+type logView struct {
+	lines  []string
+	scroll int // lines scrolled up from the end
+}
+
+// logUpdate records key presses, how long they took and the status they left in the debug log
+func logUpdate(msg tea.Msg, before, after Model, took time.Duration) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		slog.Debug("key", "key", key.String(), "mode", int(before.Mode), "dialog", int(before.Dialog),
+			"panel", int(before.ActivePanel), "file", before.CurrentFile, "took", took)
+	}
+	if after.StatusMessage != before.StatusMessage && after.StatusMessage != "" {
+		slog.Debug("status", "message", after.StatusMessage)
+	}
+}
+
+// logFileErr records a file operation done by the UI, at error level if it failed
+func logFileErr(op, path string, err error) {
+	if err != nil {
+		slog.Error(op, "path", path, "err", err)
+		return
+	}
+	slog.Debug(op, "path", path)
+}
+
+// logSlowRender records frames that took long to render in the debug log
+func logSlowRender(start time.Time, width, height int) {
+	if took := time.Since(start); took > slowRender {
+		slog.Warn("slow render", "took", took, "width", width, "height", height)
+	}
+}
+
+// CrashSummary describes the model's state for a crash report. It counts todos and
+// files but leaves out their titles and names.
+func (m Model) CrashSummary() string {
+	todos := 0
+	if m.TodoList != nil {
+		todos = len(m.TodoList.Todos)
+	}
+	return fmt.Sprintf("mode=%d dialog=%d panel=%d files=%d archive=%t todos=%d cursor=%d size=%dx%d filter=%t search=%t safe=%t",
+		m.Mode, m.Dialog, m.ActivePanel, len(m.Files), m.ShowingArchive, todos, m.TodoCursor,
+		m.Width, m.Height, m.virtual != nil, m.Search != "", m.Safe)
+}
+
+// readLogTail returns the last complete lines of a log file, up to debugLogTail bytes
+func readLogTail(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-debugLogTail, 0)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	if offset > 0 && len(lines) > 1 {
+		lines = lines[1:] // cut mid-line
+	}
+	return lines, nil
+}
+
+// openDebugLog shows the end of the debug log
+func (m *Model) openDebugLog() {
+	if m.DebugLog == "" {
+		m.toast(SeverityInfo, i18n.T("No debug log, start with --debug to record one"))
+		return
+	}
+	lines, err := readLogTail(m.DebugLog)
+	if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Cannot read the debug log: %v", err))
+		return
+	}
+	m.logView = &logView{lines: lines}
+	m.openDialog(DebugLogScreen)
+	m.toast(SeverityInfo, m.DebugLog)
+}
+
+// handleDebugLog handles input in the debug log viewer
+func (m Model) handleDebugLog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.logView
+	switch msg.String() {
+	case "k", "up":
+		v.scroll = min(v.scroll+1, max(len(v.lines)-m.logRows(), 0))
+	case "j", "down":
+		v.scroll = max(v.scroll-1, 0)
+	case "g":
+		v.scroll = max(len(v.lines)-m.logRows(), 0)
+	case "G":
+		v.scroll = 0
+	case "r":
+		m.openDebugLog()
+	case "esc", "q", "L":
+		m.closeDialog()
+		m.logView = nil
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// logRows is how many log lines fit in the viewer
+func (m Model) logRows() int {
+	return max(m.Height-10, 1)
+}
+
+// renderDebugLog renders the latest debug log lines, errors in red and warnings in yellow
+func (m Model) renderDebugLog() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(0, 1)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.T("󰌱 Debug log"))
+
+	v := m.logView
+	width := max(m.Width-8, 20)
+	end := len(v.lines) - v.scroll
+	start := max(end-m.logRows(), 0)
+	var rows []string
+	for _, line := range v.lines[start:end] {
+		style := m.Styles.Normal
+		switch {
+		case strings.Contains(line, "level=ERROR"):
+			style = lipgloss.NewStyle().Foreground(ColorRed)
+		case strings.Contains(line, "level=WARN"):
+			style = lipgloss.NewStyle().Foreground(ColorYellow)
+		case strings.Contains(line, "level=DEBUG"):
+			style = m.Styles.Muted
+		}
+		rows = append(rows, style.Render(ansi.Truncate(line, width, "…")))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, m.Styles.Muted.Render(i18n.T("The log is empty")))
+	}
+
+	content := title + "\n\n" + strings.Join(rows, "\n")
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
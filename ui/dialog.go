@@ -0,0 +1,91 @@
+package ui
+
+import "slices"
+
+// Dialog is the prompt, picker or screen open in EditMode
+type Dialog int
+
+const (
+	NoDialog          Dialog = iota
+	AddTodo                  // title of a new todo
+	EditTodo                 // title of the todo at EditingIndex
+	NewFile                  // name of a new list
+	ConfirmArchive           // archive the current file (y/n)
+	ConfirmDelete            // delete the current file (y/n)
+	DuePrompt                // due date of the current todo
+	ReminderPrompt           // reminder offset of the current todo
+	ContextSwitcher          // @context picker
+	SplitPrompt              // name of the list the filtered todos move to
+	KeybindingEditor         // keybinding editor
+	SettingsScreen           // settings screen
+	TodayScreen              // todos due today across all lists
+	TemplatePicker           // template a new list starts from
+	TemplatePrompt           // values filled into the template
+	FieldPrompt              // custom field of the current todo
+	FieldFilterPrompt        // custom field filter
+	GotoPrompt               // line to go to
+	CheckPrompt              // check command of the current todo
+	ConflictResolver         // todos that differ between sync copies
+	StatsScreen              // completion stats
+	SearchPrompt             // search query
+	FilterNamePrompt         // name the current filters are saved under
+	ConfirmBulkDelete        // delete the todos removed in the editor (y/n)
+	DebugLogScreen           // debug log viewer
+	TitlePrompt              // display title of the list
+	NameCollision            // archive or unarchive onto a taken name
+	ArchiveNamePrompt        // name typed for an archive whose name is taken
+	ConfirmBatch             // operation on the marked files (y/n)
+	ConfirmQuit              // quit with changes that couldn't be saved
+	PreviewScreen            // what the question under it would change
+	StartPrompt              // start date of the current todo
+	ListSettingScreen        // settings of the open list
+	ListTagsPrompt           // tags added to the open list's new todos
+	JiraPrompt               // key of the Jira issue to import
+	ComparePicker            // list shown beside the open one
+	CompareScreen            // open list and a second one side by side
+	FilePicker               // list to open, found by typing part of its name
+	ConfirmChecks            // run check commands not trusted yet
+)
+
+// dialogFrame is a dialog left open under a nested one, with its prompt and input
+type dialogFrame struct {
+	dialog Dialog
+	status string
+	input  string
+}
+
+// openDialog opens d in place of the open dialog, if any
+func (m *Model) openDialog(d Dialog) {
+	m.Mode = EditMode
+	m.Dialog = d
+	m.modalFocus = 0
+}
+
+// pushDialog opens d over the open dialog, which is back as it was once d closes
+func (m *Model) pushDialog(d Dialog) {
+	if m.Dialog != NoDialog {
+		frame := dialogFrame{dialog: m.Dialog, status: m.StatusMessage, input: m.InputText}
+		m.dialogs = append(slices.Clip(m.dialogs), frame)
+	}
+	m.openDialog(d)
+}
+
+// closeDialog closes the open dialog, going back to the one it was opened over, or
+// to NormalMode
+func (m *Model) closeDialog() {
+	m.modalFocus = 0
+	if n := len(m.dialogs); n > 0 {
+		frame := m.dialogs[n-1]
+		m.dialogs = m.dialogs[:n-1]
+		m.Dialog, m.StatusMessage, m.InputText = frame.dialog, frame.status, frame.input
+		return
+	}
+	m.Mode = NormalMode
+	m.Dialog = NoDialog
+}
+
+// closeDialogs closes the open dialog and every one under it
+func (m *Model) closeDialogs() {
+	m.dialogs = nil
+	m.closeDialog()
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// editorFinishedMsg is sent when the external editor exits
+type editorFinishedMsg struct {
+	err error
+}
+
+// bulkEditedMsg is sent when the editor opened for a bulk edit exits
+type bulkEditedMsg struct {
+	path   string // temporary file holding the edited text
+	listed []int  // IDs of the todos written to it
+	err    error
+}
+
+// bulkEdit holds a bulk edit waiting for its deletions to be confirmed
+type bulkEdit struct {
+	listed []int
+	lines  []todo.BulkLine
+}
+
+// editorCommand runs $VISUAL or $EDITOR (vi when unset) on path
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// openInEditor suspends the TUI and opens the current file in $EDITOR
+func (m Model) openInEditor() tea.Cmd {
+	return tea.ExecProcess(editorCommand(m.TodoList.Path()), func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// openBulkEditor writes the todos shown in the todo panel to a temporary text file
+// and opens it in $EDITOR, one line per todo
+func (m *Model) openBulkEditor() tea.Cmd {
+	var todos []todo.Todo
+	var listed []int
+	for _, i := range m.visibleIndices() {
+		if t := m.TodoList.Todos[i]; !t.Heading {
+			todos = append(todos, t)
+			listed = append(listed, t.ID)
+		}
+	}
+
+	f, err := os.CreateTemp("", "justdoit-*.md")
+	if err != nil {
+		m.toast(SeverityError, i18n.Tf("Bulk edit failed: %v", err))
+		return nil
+	}
+	_, err = f.WriteString(todo.BulkText(m.listName(), todos))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		m.toast(SeverityError, i18n.Tf("Bulk edit failed: %v", err))
+		return nil
+	}
+
+	path := f.Name()
+	return tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		return bulkEditedMsg{path: path, listed: listed, err: err}
+	})
+}
+
+// handleBulkEdited reads back a bulk edit, asking before deleting todos whose lines were removed
+func (m Model) handleBulkEdited(msg bulkEditedMsg) (tea.Model, tea.Cmd) {
+	data, err := os.ReadFile(msg.path)
+	os.Remove(msg.path)
+	if msg.err != nil {
+		m.toast(SeverityError, i18n.Tf("Editor failed: %v", msg.err))
+		return m, nil
+	}
+	if err != nil {
+		m.toast(SeverityError, i18n.Tf("Bulk edit failed: %v", err))
+		return m, nil
+	}
+	lines, err := todo.ParseBulk(string(data))
+	if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Bulk edit not applied: %v", err))
+		return m, nil
+	}
+
+	m.bulk = &bulkEdit{listed: msg.listed, lines: lines}
+	if removed := len(todo.BulkRemoved(msg.listed, lines)); removed > 0 {
+		m.openDialog(ConfirmBulkDelete)
+		m.toast(SeverityInfo, i18n.Tf("Delete %d todos removed in the editor? (y/n)", removed))
+		return m, nil
+	}
+	return m.applyBulk(false)
+}
+
+// bulkModal asks before deleting the todos removed in the editor. Keeping them still
+// applies the other edits.
+func (m Model) bulkModal() modal {
+	removed := len(todo.BulkRemoved(m.bulk.listed, m.bulk.lines))
+	return modal{
+		variant:  modalDanger,
+		title:    i18n.T("Bulk edit"),
+		question: i18n.Tf("Delete %d todos removed in the editor?", removed),
+		choices: []choice{
+			{"y", i18n.T(" Yes, delete")},
+			{"n", i18n.T(" No, keep them")},
+			{"p", i18n.T(" Preview")},
+			{"esc", i18n.T(" Discard edit")},
+		},
+		safe: 1,
+	}
+}
+
+// applyBulk applies the pending bulk edit, deleting removed todos if confirmed
+func (m Model) applyBulk(deleteRemoved bool) (tea.Model, tea.Cmd) {
+	m.closeDialog()
+	bulk := m.bulk
+	m.bulk = nil
+	if bulk == nil {
+		return m, nil
+	}
+	result, err := m.TodoList.ApplyBulk(bulk.listed, bulk.lines, deleteRemoved)
+	if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Bulk edit not applied: %v", err))
+		return m, nil
+	}
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Bulk edit: %d added, %d updated, %d deleted", result.Added, result.Updated, result.Deleted))
+	return m, nil
+}
+
+// handleEditorFinished reloads the current file after editing
+func (m Model) handleEditorFinished(msg editorFinishedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.toast(SeverityError, i18n.Tf("Editor failed: %v", msg.err))
+		return m, nil
+	}
+
+	if err := m.TodoList.Reload(); err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Not reloaded, fix the file and press e again: %v", err))
+		return m, nil
+	}
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Reloaded: %s", filepath.Base(m.TodoList.Path())))
+	return m, checkReminders(m.TodoDir, false)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// CompileEscalations parses config escalation rules.
+// Invalid rules are skipped and reported in the returned error.
+func CompileEscalations(rules []config.EscalationRule) ([]todo.Escalation, error) {
+	var escalations []todo.Escalation
+	var firstErr error
+	for i, rule := range rules {
+		e, err := compileEscalation(rule)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid escalation rule %d: %w", i+1, err)
+			}
+			continue
+		}
+		escalations = append(escalations, e)
+	}
+	return escalations, firstErr
+}
+
+// compileEscalation parses the thresholds and action of one rule
+func compileEscalation(rule config.EscalationRule) (todo.Escalation, error) {
+	var e todo.Escalation
+	var err error
+	if rule.OpenFor != "" {
+		if e.OpenFor, err = todo.ParseOffset(rule.OpenFor); err != nil {
+			return e, err
+		}
+	}
+	if rule.OverdueFor != "" {
+		if e.OverdueFor, err = todo.ParseOffset(rule.OverdueFor); err != nil {
+			return e, err
+		}
+	}
+	if e.OpenFor == 0 && e.OverdueFor == 0 {
+		return e, fmt.Errorf("set open_for or overdue_for")
+	}
+	if rule.Field != "" {
+		if e.FieldKey, e.FieldValue, err = todo.ParseField(rule.Field); err != nil {
+			return e, err
+		}
+		if e.FieldValue == "" {
+			return e, fmt.Errorf("field %s needs a value", e.FieldKey)
+		}
+	}
+	e.Flag = rule.Flag
+	if !e.Flag && e.FieldKey == "" {
+		return e, fmt.Errorf("set flag or field")
+	}
+	return e, nil
+}
+
+// EscalateLists applies the rules to every list in dir except habit lists,
+// returning a summary of what changed or "" if nothing did
+func EscalateLists(dir string, rules []todo.Escalation) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	now := time.Now()
+	total := 0
+	var lists []string
+	for _, file := range LoadTodoFiles(dir) {
+		tl := todo.NewTodoList(filepath.Join(dir, file))
+		if tl.IsHabit() {
+			continue
+		}
+		if n := tl.Escalate(rules, now); n > 0 {
+			total += n
+			lists = append(lists, strings.TrimSuffix(file, ".json"))
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	return i18n.Tf("Escalated %d neglected todos in %s", total, strings.Join(lists, ", "))
+}
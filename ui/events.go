@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/todo"
+)
+
+// listEvents queues the list events the model reacts to until the update that caused
+// them returns
+var listEvents struct {
+	sync.Mutex
+	queue []todo.Event
+}
+
+func init() {
+	todo.Subscribe(func(e todo.Event) {
+		listEvents.Lock()
+		defer listEvents.Unlock()
+		listEvents.queue = append(listEvents.queue, e)
+	}, todo.TodoAdded, todo.TodoCompleted, todo.TodoReopened, todo.TodoDeleted, todo.ListCompleted,
+		todo.FileSaved, todo.FileDeleted)
+}
+
+// handleEvents reacts to the list events published since the last update: a completed
+// todo is celebrated, bigger when it was the last one of its list, and an open stats
+// view follows the changes, its heatmap once they're on disk. Events of lists outside
+// the data directory belong to another model.
+func (m *Model) handleEvents() tea.Cmd {
+	listEvents.Lock()
+	events := listEvents.queue
+	listEvents.queue = nil
+	listEvents.Unlock()
+
+	completed, listDone := false, false
+	changed, saved := false, false
+	for _, e := range events {
+		if e.Path != "" && !strings.HasPrefix(e.Path, m.TodoDir+string(filepath.Separator)) {
+			continue
+		}
+		switch e.Kind {
+		case todo.TodoCompleted:
+			completed = true
+		case todo.ListCompleted:
+			listDone = true
+		case todo.FileSaved, todo.FileDeleted:
+			saved = true
+			continue
+		}
+		changed = changed || m.TodoList != nil && e.Path == m.TodoList.Path()
+	}
+
+	var cmds []tea.Cmd
+	if m.stats != nil {
+		if changed {
+			m.refreshStats()
+		}
+		if saved {
+			cmds = append(cmds, m.scanHeatmap())
+		}
+	}
+	if completed {
+		cmds = append(cmds, m.celebrate(listDone))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// submitFieldPrompt sets or removes the custom field entered for the current todo
+func (m Model) submitFieldPrompt() (tea.Model, tea.Cmd) {
+	key, value, err := todo.ParseField(m.InputText)
+	if err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, nil
+	}
+	m.TodoList.SetField(m.TodoCursor, key, value)
+	if value == "" {
+		m.toast(SeverityInfo, i18n.Tf("Removed %s", key))
+	} else {
+		m.toast(SeverityInfo, i18n.Tf("Set %s=%s", key, value))
+	}
+	m.closeDialog()
+	m.clampTodoCursor()
+	return m, nil
+}
+
+// submitFieldFilter applies the field filter entered (empty clears it)
+func (m Model) submitFieldFilter() (tea.Model, tea.Cmd) {
+	if m.InputText != "" && !todo.ValidFieldFilter(m.InputText) {
+		m.toast(SeverityInfo, i18n.T("Filter with key or key=value"))
+		return m, nil
+	}
+	m.FieldFilter = m.InputText
+	m.closeDialog()
+	m.clampTodoCursor()
+	if m.FieldFilter == "" {
+		m.toast(SeverityInfo, i18n.T("Field filter cleared"))
+	} else {
+		m.toast(SeverityInfo, i18n.Tf("Showing todos with %s", m.FieldFilter))
+	}
+	return m, nil
+}
+
+// renderFields renders a todo's custom fields as muted key=value badges
+func (m Model) renderFields(t todo.Todo) string {
+	var badges string
+	for _, key := range t.FieldKeys() {
+		badges += "  " + m.Styles.Muted.Render(key+"="+t.Fields[key])
+	}
+	return badges
+}
+
+// renderFieldChip shows the field filter, or its prompt, in the todo panel title
+func (m Model) renderFieldChip() string {
+	text := m.FieldFilter
+	if m.Dialog == FieldFilterPrompt {
+		text = m.InputText + "█"
+	} else if text == "" {
+		return ""
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorSky).
+		Bold(true).
+		Padding(0, 1).
+		Render("= " + text)
+	return " " + chip
+}
@@ -1,14 +1,183 @@
 package ui
 
 import (
+	"cmp"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
 	"justdoit/todo"
 )
 
-// LoadTodoFiles loads all .json todo files from a directory
+// internalSuffixes end the names of files justdoit keeps next to the lists, such as
+// backups of corrupted lists and half-written saves
+var internalSuffixes = []string{".corrupted", ".tmp", ".bak", ".lock", ".index", ".cache"}
+
+// IsHiddenFile reports whether a file in a todo directory is internal rather than a
+// list. Names starting with a dot are hidden, so new internal files only need one.
+func IsHiddenFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadTodoFiles loads all .json todo files from a directory, leaving out hidden files.
+// They are sorted naturally by the name they are shown under.
 func LoadTodoFiles(dir string) []string {
+	return readListing(dir, false).files
+}
+
+// ScanTodoFiles loads the todo files in dir like LoadTodoFiles, along with what the
+// file panel shows of them
+func ScanTodoFiles(dir string) ([]string, FileInfo) {
+	l := readListing(dir, false)
+	return l.files, l.info
+}
+
+// FileInfo is what the file panel shows of the listed files besides their names,
+// keyed by filename
+type FileInfo struct {
+	Titles  map[string]string // display titles, files without one left out
+	Colors  map[string]string // color names, files without one left out
+	Foreign map[string]bool   // files that aren't todo lists, hidden files included
+}
+
+// readFileInfo reads the header of each listed file in dir, once, for what the file
+// panel shows of it. Hidden files aren't read, they count as foreign so they are only
+// ever shown read-only.
+func readFileInfo(dir string, files []string) FileInfo {
+	info := FileInfo{Titles: map[string]string{}, Colors: map[string]string{}, Foreign: map[string]bool{}}
+	for _, f := range files {
+		if IsHiddenFile(f) {
+			info.Foreign[f] = true
+			continue
+		}
+		h := todo.ReadHeader(filepath.Join(dir, f))
+		if h.Title != "" {
+			info.Titles[f] = h.Title
+		}
+		if h.Settings != nil && h.Settings.Color != "" {
+			info.Colors[f] = h.Settings.Color
+		}
+		if h.Foreign {
+			info.Foreign[f] = true
+		}
+	}
+	return info
+}
+
+// fileLabel returns what a file is shown as: its title, or its name without .json.
+// Hidden files keep their full name.
+func fileLabel(file string, titles map[string]string) string {
+	if title := titles[file]; title != "" {
+		return title
+	}
+	if IsHiddenFile(file) {
+		return file
+	}
+	return strings.TrimSuffix(file, ".json")
+}
+
+// sortFiles orders files naturally by label, so week2 comes before week10
+func sortFiles(files []string, titles map[string]string) {
+	slices.SortStableFunc(files, func(a, b string) int {
+		if c := naturalCompare(fileLabel(a, titles), fileLabel(b, titles)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// naturalCompare compares two names ignoring case, with runs of digits compared by
+// their value
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitRun(a), digitRun(b)
+			ta, tb := strings.TrimLeft(a[:na], "0"), strings.TrimLeft(b[:nb], "0")
+			if c := cmp.Compare(len(ta), len(tb)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			a, b = a[na:], b[nb:]
+			continue
+		}
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if c := cmp.Compare(ra, rb); c != 0 {
+			return c
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// digitRun returns how many digits s starts with
+func digitRun(s string) int {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return n
+}
+
+// openTitlePrompt asks for the title the open list is shown under
+func (m *Model) openTitlePrompt() {
+	if m.ShowingArchive || m.TodoList.IsScratch() {
+		return
+	}
+	m.openDialog(TitlePrompt)
+	m.InputText = m.TodoList.Title
+	m.toast(SeverityInfo, i18n.Tf("Title for %s (empty shows the filename)", m.CurrentFile))
+}
+
+// submitTitle stores the typed title in the open list and sorts the files again
+func (m Model) submitTitle() (tea.Model, tea.Cmd) {
+	m.TodoList.SetTitle(m.InputText)
+	m.TodoList.Flush()
+	m.loadFiles()
+	if i := slices.Index(m.Files, m.CurrentFile); i >= 0 && m.ActivePanel == FilePanel {
+		m.FileCursor = i
+	}
+	m.closeDialog()
+	if m.TodoList.Title == "" {
+		m.toast(SeverityInfo, i18n.Tf("Showing %s by its filename", m.CurrentFile))
+	} else {
+		m.toast(SeverityInfo, i18n.Tf("Showing %s as %s", m.CurrentFile, m.TodoList.Title))
+	}
+	return m, nil
+}
+
+// displayName returns what a listed file is shown as in the UI. Files that aren't
+// todo lists keep their full name.
+func (m Model) displayName(file string) string {
+	if m.Foreign[file] {
+		return file
+	}
+	return fileLabel(file, m.Titles)
+}
+
+// listFiles lists the todo files in dir, and the hidden files too when asked
+func listFiles(dir string, hidden bool) []string {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return []string{}
@@ -16,20 +185,131 @@ func LoadTodoFiles(dir string) []string {
 
 	var files []string
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			files = append(files, entry.Name())
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if IsHiddenFile(name) {
+			if hidden {
+				files = append(files, name)
+			}
+		} else if filepath.Ext(name) == ".json" {
+			files = append(files, name)
 		}
 	}
 	return files
 }
 
-// deleteCurrentFile deletes the currently active file
-func (m *Model) deleteCurrentFile() {
+// loadFiles lists the todo files again, noting those that aren't todo lists
+func (m *Model) loadFiles() {
+	m.setListing(readListing(m.TodoDir, m.ShowHidden))
+}
+
+// toggleHidden shows or hides the internal files in the file panel
+func (m *Model) toggleHidden() {
+	m.ShowHidden = !m.ShowHidden
+	m.loadFiles()
+	if !m.ShowHidden && IsHiddenFile(m.CurrentFile) && len(m.Files) > 0 {
+		m.setList(filepath.Join(m.TodoDir, m.Files[0]))
+		m.CurrentFile = m.Files[0]
+		m.TodoCursor = 0
+	}
+	m.FileCursor = 0
+	for i, f := range m.Files {
+		if f == m.CurrentFile {
+			m.FileCursor = i
+		}
+	}
+	if m.ShowHidden {
+		m.toast(SeverityInfo, i18n.T("Showing hidden files, read-only"))
+	} else {
+		m.toast(SeverityInfo, i18n.T("Hidden files are hidden again"))
+	}
+}
+
+// foreignAllows reports whether an action can run while a file that isn't a todo
+// list is open. It is shown read-only, so nothing may add to, move or delete it.
+func foreignAllows(action Action, panel Panel) bool {
+	switch action {
+	case ActionDelete, ActionArchive, ActionHabit, ActionEditor, ActionBulkEdit, ActionSplit, ActionPromote, ActionTitle,
+		ActionProgressUp, ActionProgressDown, ActionCopyLink, ActionListSettings, ActionJira, ActionCompare:
+		return false
+	case ActionAdd:
+		return panel == FilePanel
+	}
+	return true
+}
+
+// hiddenAllows reports whether an action can run while a hidden file is open. Only
+// moving around and looking are allowed, the file belongs to justdoit.
+func hiddenAllows(action Action, panel Panel) bool {
+	switch action {
+	case ActionOpen, ActionSelect, ActionAdd, ActionShowArchive:
+		return panel == FilePanel
+	case ActionQuit, ActionBack, ActionLeft, ActionRight, ActionSwitchPanel, ActionDown, ActionUp,
+		ActionGoto, ActionGotoPrompt, ActionSectionDown, ActionSectionUp, ActionFold, ActionFollowLink,
+		ActionCopy, ActionCopyList, ActionSearch, ActionClearFilters, ActionContext, ActionFieldFilter,
+		ActionShrinkFiles, ActionGrowFiles, ActionMaximize, ActionZen, ActionKeybindings, ActionSettings,
+		ActionToday, ActionStats, ActionDebugLog, ActionHiddenFiles:
+		return true
+	}
+	return false
+}
+
+// newFileName returns the file name typed in the new file or split prompt, tidied up,
+// or why it can't be used
+func (m Model) newFileName() (string, error) {
+	name := todo.NormalizeListName(m.InputText, m.Behavior.LowercaseNames)
+	if err := todo.ValidateListName(name); err != nil {
+		return "", err
+	}
+	filename := name + ".json"
+	if slices.Contains(m.Files, filename) {
+		return "", fmt.Errorf("%s already exists", filename)
+	}
+	if _, err := os.Stat(filepath.Join(m.ArchiveDir, filename)); err == nil {
+		return "", fmt.Errorf("%s is already in the archive", filename)
+	}
+	return filename, nil
+}
+
+// renderFileNameCheck renders below the new file prompt why the typed name can't be used,
+// or the name it will be saved under when that differs from what was typed
+func (m Model) renderFileNameCheck(width int) string {
+	if m.InputText == "" {
+		return ""
+	}
+	filename, err := m.newFileName()
+	switch {
+	case err != nil:
+		text := ansi.Wrap("✗ "+err.Error(), max(width-2, minInputWidth), "")
+		return lipgloss.NewStyle().Foreground(ColorRed).Render("  "+strings.ReplaceAll(text, "\n", "\n  ")) + "\n"
+	case filename != m.InputText+".json":
+		return m.Styles.Muted.Render("  → "+filename) + "\n"
+	}
+	return ""
+}
+
+// fileDeletedMsg reports the open file deleted in the background
+type fileDeletedMsg struct {
+	listing fileListing
+}
+
+// deleteCurrentFile deletes the currently active file in the background
+func (m *Model) deleteCurrentFile() tea.Cmd {
 	filePath := filepath.Join(m.TodoDir, m.CurrentFile)
-	os.Remove(filePath)
+	dir, hidden := m.TodoDir, m.ShowHidden
+	return m.startMove(i18n.Tf("Deleting %s", m.CurrentFile), func(func(done, total int)) tea.Msg {
+		logFileErr("delete file", filePath, todo.Remove(filePath))
+		return fileDeletedMsg{listing: readListing(dir, hidden)}
+	})
+}
 
-	// Reload file lists
-	m.Files = LoadTodoFiles(m.TodoDir)
+// handleFileDeleted loads the next file once the open one is deleted, or creates a
+// default one
+func (m Model) handleFileDeleted(msg fileDeletedMsg) (tea.Model, tea.Cmd) {
+	m.moving = false
+	m.setListing(msg.listing)
 
 	// Load next file or create default
 	if len(m.Files) > 0 {
@@ -37,81 +317,160 @@ func (m *Model) deleteCurrentFile() {
 			m.FileCursor = len(m.Files) - 1
 		}
 		m.CurrentFile = m.Files[m.FileCursor]
-		m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
+		m.TodoList = m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
 	} else {
 		m.CurrentFile = "default.json"
-		m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
+		m.TodoList = m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
 		m.TodoList.Save()
-		m.Files = LoadTodoFiles(m.TodoDir)
+		m.loadFiles()
 		m.FileCursor = 0
 	}
 	m.TodoCursor = 0
+	m.toast(SeverityInfo, i18n.T("File deleted!"))
+	return m, nil
 }
 
-// archiveCurrentFile moves the current file to the archive directory
-func (m *Model) archiveCurrentFile() {
+// freeName returns name, or name with -2, -3 and so on added before .json if dir
+// already has a file by that name
+func freeName(dir, name string) string {
+	base := strings.TrimSuffix(name, ".json")
+	for i := 2; exists(dir, name); i++ {
+		name = fmt.Sprintf("%s-%d.json", base, i)
+	}
+	return name
+}
+
+// archiveName returns the name a file gets in the archive: its own, or with the
+// archive date when dated archives are on
+func (m Model) archiveName(file string, now time.Time) string {
+	name := file
+	if m.Behavior.DatedArchives {
+		name = strings.TrimSuffix(name, ".json") + "_" + now.Format(time.DateOnly) + ".json"
+	}
+	return name
+}
+
+// fileArchivedMsg reports the open file moved to the archive in the background
+type fileArchivedMsg struct {
+	name    string      // name in the archive
+	header  todo.Header // what it was archived with
+	listing fileListing
+}
+
+// archiveCurrentFile moves the current file to the archive directory under name in
+// the background, replacing a file there by that name
+func (m *Model) archiveCurrentFile(name string) tea.Cmd {
+	now := time.Now()
 	srcPath := filepath.Join(m.TodoDir, m.CurrentFile)
-	dstPath := filepath.Join(m.ArchiveDir, m.CurrentFile)
+	dstPath := filepath.Join(m.ArchiveDir, name)
 
-	os.Rename(srcPath, dstPath)
+	original := ""
+	if name != m.CurrentFile {
+		original = m.CurrentFile
+	}
+	m.TodoList.SetArchived(now, original)
+	m.TodoList.Flush()
+
+	h := todo.Header{Title: m.TodoList.Title, Archived: now, Original: original}
+	dir, hidden := m.TodoDir, m.ShowHidden
+	return m.startMove(i18n.Tf("Archiving %s", m.CurrentFile), func(func(done, total int)) tea.Msg {
+		logFileErr("archive file", dstPath, todo.Rename(srcPath, dstPath))
+		return fileArchivedMsg{name: name, header: h, listing: readListing(dir, hidden)}
+	})
+}
 
-	// Reload file lists
-	m.Files = LoadTodoFiles(m.TodoDir)
-	m.ArchivedFiles = LoadTodoFiles(m.ArchiveDir)
+// handleFileArchived loads the first file once the open one is archived, or creates
+// a default one
+func (m Model) handleFileArchived(msg fileArchivedMsg) (tea.Model, tea.Cmd) {
+	m.moving = false
+	m.setListing(msg.listing)
+	m.noteArchived(msg.name, msg.header)
 
 	// Load next file or create default
 	if len(m.Files) > 0 {
 		m.FileCursor = 0
 		m.CurrentFile = m.Files[0]
-		m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
+		m.TodoList = m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
 	} else {
 		m.CurrentFile = "default.json"
-		m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
+		m.TodoList = m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
 		m.TodoList.Save()
-		m.Files = LoadTodoFiles(m.TodoDir)
+		m.loadFiles()
 	}
 	m.TodoCursor = 0
+	m.ActivePanel = FilePanel // Go back to file panel
+	m.toast(SeverityInfo, i18n.T("File archived!"))
+	return m, nil
+}
+
+// restoreName returns the name an archived file gets back: the one it had before
+// archiving renamed it, or its own
+func (m Model) restoreName(archived string) string {
+	return cmp.Or(todo.ReadHeader(filepath.Join(m.ArchiveDir, archived)).Original, archived)
+}
+
+// fileUnarchivedMsg reports a file moved back out of the archive in the background
+type fileUnarchivedMsg struct {
+	archived string // name it had in the archive
+	name     string // name it got back
+	listing  fileListing
 }
 
 // unarchiveFile moves a file from the archive directory back to the main directory
-func (m *Model) unarchiveFile(filename string) {
-	srcPath := filepath.Join(m.ArchiveDir, filename)
+// under filename in the background, replacing a file there by that name
+func (m *Model) unarchiveFile(archived, filename string) tea.Cmd {
+	srcPath := filepath.Join(m.ArchiveDir, archived)
 	dstPath := filepath.Join(m.TodoDir, filename)
 
-	os.Rename(srcPath, dstPath)
+	m.TodoList.Flush() // before a replaced list could be written over the restored one
+	dir, hidden := m.TodoDir, m.ShowHidden
+	return m.startMove(i18n.Tf("Restoring %s", archived), func(func(done, total int)) tea.Msg {
+		if h := todo.ReadHeader(srcPath); !h.Archived.IsZero() || h.Original != "" {
+			todo.NewTodoList(srcPath).SetArchived(time.Time{}, "")
+		}
+		logFileErr("unarchive file", dstPath, todo.Rename(srcPath, dstPath))
+		return fileUnarchivedMsg{archived: archived, name: filename, listing: readListing(dir, hidden)}
+	})
+}
 
-	// Reload file lists
-	m.Files = LoadTodoFiles(m.TodoDir)
-	m.ArchivedFiles = LoadTodoFiles(m.ArchiveDir)
+// handleFileUnarchived switches to a file once it's back out of the archive
+func (m Model) handleFileUnarchived(msg fileUnarchivedMsg) (tea.Model, tea.Cmd) {
+	m.moving = false
+	m.setListing(msg.listing)
+	m.noteUnarchived(msg.archived)
 
 	// Switch to the unarchived file
-	m.CurrentFile = filename
-	m.TodoList = todo.NewTodoList(dstPath)
+	m.CurrentFile = msg.name
+	cmd := m.openList(filepath.Join(m.TodoDir, msg.name))
 	m.ShowingArchive = false
+	m.ActivePanel = TodoPanel
 
 	// Find cursor position
 	for i, f := range m.Files {
-		if f == filename {
+		if f == msg.name {
 			m.FileCursor = i
 			break
 		}
 	}
+	m.toast(SeverityInfo, i18n.Tf("Unarchived: %s", m.CurrentFile))
+	return m, cmd
 }
 
 // previewFile loads a file for preview without switching the active panel
-func (m *Model) previewFile() {
+func (m *Model) previewFile() tea.Cmd {
 	var filename string
 	var dir string
 
 	if m.ShowingArchive {
-		if m.FileCursor >= len(m.ArchivedFiles) {
-			return
+		rows := m.archiveRows()
+		if m.FileCursor >= len(rows) || rows[m.FileCursor].file == "" {
+			return nil
 		}
-		filename = m.ArchivedFiles[m.FileCursor]
+		filename = rows[m.FileCursor].file
 		dir = m.ArchiveDir
 	} else {
 		if m.FileCursor >= len(m.Files) {
-			return
+			return nil
 		}
 		filename = m.Files[m.FileCursor]
 		dir = m.TodoDir
@@ -119,19 +478,51 @@ func (m *Model) previewFile() {
 
 	// Load the file for preview (without switching activePanel)
 	previewPath := filepath.Join(dir, filename)
-	m.TodoList = todo.NewTodoList(previewPath)
+	cmd := m.openList(previewPath)
 	m.TodoCursor = 0
+	m.clampTodoCursor()
+	return cmd
 }
 
 // allTodosCompleted checks if all todos in the current list are completed
 func (m *Model) allTodosCompleted() bool {
-	if len(m.TodoList.Todos) == 0 {
-		return false
+	completed, total := m.TodoList.Counts()
+	return total > 0 && completed == total
+}
+
+// quickToggleKey returns n when key is an unbound digit 1-9 pressed in the file panel
+func (m Model) quickToggleKey(key string) (int, bool) {
+	if m.ActivePanel != FilePanel || len(key) != 1 || key[0] < '1' || key[0] > '9' || m.Keys.Action(key) != "" {
+		return 0, false
 	}
-	for _, todo := range m.TodoList.Todos {
-		if !todo.Completed {
-			return false
+	return int(key[0] - '0'), true
+}
+
+// quickToggle toggles the nth visible todo (headings aside) of the previewed file
+// without leaving the file panel
+func (m *Model) quickToggle(n int) tea.Cmd {
+	if m.ShowingArchive || m.Loading != "" || m.TodoList.IsScratch() || m.FileCursor >= len(m.Files) {
+		return nil
+	}
+	var rows []int
+	for _, i := range m.visibleIndices() {
+		if !m.TodoList.Todos[i].Heading {
+			rows = append(rows, i)
 		}
 	}
-	return true
+	file := m.Files[m.FileCursor]
+	if n > len(rows) {
+		m.toast(SeverityInfo, i18n.Tf("No todo %d in %s", n, file))
+		return nil
+	}
+
+	// The previewed file is the one being changed, and archived if it's now done
+	m.CurrentFile = file
+	m.TodoCursor = rows[n-1]
+	title := m.TodoList.Todos[m.TodoCursor].Title
+	cmd := m.toggleTodoWithArchivePrompt()
+	if m.Mode == NormalMode {
+		m.toast(SeverityInfo, i18n.Tf("Toggled: %s", title))
+	}
+	return cmd
 }
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+// virtualList is a saved filter opened in the todo panel. Its rows are copies of
+// todos across all lists, grouped under a heading per list unless the filter is sorted.
+type virtualList struct {
+	name    string
+	sources map[int]search.Hit // row ID -> the todo it was copied from
+	flat    bool               // sorted across lists, each row naming its list
+}
+
+// openSearchPrompt asks for a search query to filter the open list with
+func (m *Model) openSearchPrompt() {
+	m.openDialog(SearchPrompt)
+	m.InputText = m.Search
+	m.searchHelp()
+}
+
+// searchHelp describes the search syntax in use in the status bar
+func (m *Model) searchHelp() {
+	if m.SearchRegex {
+		m.toast(SeverityInfo, i18n.T("Regex search: RE2 syntax, (?i) ignores case, ctrl+r for queries (empty clears)"))
+		return
+	}
+	m.toast(SeverityInfo, i18n.T("Search: words, is:open, tag:home, due:<7d, key:value, OR, NOT, ctrl+r for regex (empty clears)"))
+}
+
+// toggleSearchRegex switches the search prompt between queries and regular expressions
+func (m *Model) toggleSearchRegex() {
+	m.SearchRegex = !m.SearchRegex
+	m.searchHelp()
+}
+
+// submitSearch applies the query entered, keeping the prompt open if it doesn't parse
+func (m Model) submitSearch() (tea.Model, tea.Cmd) {
+	if strings.TrimSpace(m.InputText) == "" {
+		m.Search = ""
+		m.closeDialog()
+		m.clampTodoCursor()
+		m.toast(SeverityInfo, i18n.T("Search cleared"))
+		return m, nil
+	}
+	q, err := search.Compile(m.InputText, m.SearchRegex)
+	if err != nil && m.SearchRegex {
+		m.toast(SeverityInfo, i18n.Tf("Invalid pattern: %v", err))
+		return m, nil
+	} else if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Invalid query: %v", err))
+		return m, nil
+	}
+	m.Search, m.query = m.InputText, q
+	m.closeDialog()
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Showing matches for %s", m.Search))
+	return m, nil
+}
+
+// matchesSearch reports whether a todo passes the search filter
+func (m Model) matchesSearch(t todo.Todo) bool {
+	return m.Search == "" || m.query.Match(t, time.Now())
+}
+
+// openFilterNamePrompt asks for a name to save the active search under
+func (m *Model) openFilterNamePrompt() {
+	if m.Search == "" {
+		m.toast(SeverityInfo, i18n.T("Search first (/), then save it as a filter"))
+		return
+	}
+	m.openDialog(FilterNamePrompt)
+	m.InputText = ""
+	m.toast(SeverityInfo, i18n.Tf("Save %s as a filter", m.Search))
+}
+
+// submitFilterName saves the active search as a filter, replacing one with the same name
+func (m Model) submitFilterName() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.InputText)
+	if name == "" {
+		m.toast(SeverityInfo, i18n.T("Cannot be empty"))
+		return m, nil
+	}
+	filters := slices.DeleteFunc(slices.Clone(m.Filters), func(f config.Filter) bool { return f.Name == name })
+	filter := config.Filter{Name: name, Query: m.Search, Regex: m.query.Regexp()}
+	if i := slices.IndexFunc(m.Filters, func(f config.Filter) bool { return f.Name == name }); i >= 0 {
+		filter.View = m.Filters[i].View // keep how it was sorted and shown
+	}
+	filters = append(filters, filter)
+	if err := m.saveFilters(filters); err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, nil
+	}
+	m.Filters = filters
+	m.closeDialog()
+	m.toast(SeverityInfo, i18n.Tf("Saved filter %s", name))
+	return m, nil
+}
+
+// deleteFilter removes the saved filter at i
+func (m *Model) deleteFilter(i int) {
+	name := m.Filters[i].Name
+	filters := slices.Delete(slices.Clone(m.Filters), i, i+1)
+	if err := m.saveFilters(filters); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+	m.Filters = filters
+	m.FileCursor = min(m.FileCursor, len(m.Files)+len(m.Filters)-1)
+	m.toast(SeverityInfo, i18n.Tf("Removed filter %s", name))
+}
+
+// saveFilters writes the saved filters to the config file, keeping its other sections
+func (m Model) saveFilters(filters []config.Filter) error {
+	if m.ConfigPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg.Filters = filters
+	return config.Save(m.ConfigPath, cfg)
+}
+
+// openFilter shows the todos of all active lists matching a saved filter
+func (m *Model) openFilter(i int) {
+	f := m.Filters[i]
+	q, err := search.Compile(f.Query, f.Regex)
+	if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Filter %s: %v", f.Name, err))
+		return
+	}
+	m.TodoList.Flush()
+
+	vl := todo.NewScratchList()
+	v := &virtualList{name: f.Name, sources: map[int]search.Hit{}, flat: f.Sort != ""}
+	now := time.Now()
+	matches := 0
+	for _, file := range m.Files {
+		path := filepath.Join(m.TodoDir, file)
+		tl := todo.NewTodoList(path)
+		heading := v.flat
+		for _, t := range tl.Todos {
+			if !q.Match(t, now) {
+				continue
+			}
+			if !heading {
+				vl.Todos = append(vl.Todos, todo.Todo{ID: vl.NextID, Title: m.displayName(file), Heading: true})
+				vl.NextID++
+				heading = true
+			}
+			v.sources[vl.NextID] = search.Hit{File: path, ID: t.ID, Title: t.Title}
+			t.ID = vl.NextID
+			vl.Todos = append(vl.Todos, t)
+			vl.NextID++
+			matches++
+		}
+	}
+	sortTodos(vl.Todos, f.Sort)
+
+	m.loadGen++ // drop any background load
+	m.Loading = ""
+	m.TodoList = vl
+	m.virtual = v
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Filter %s: %d todos, Enter opens one in its list", f.Name, matches))
+}
+
+// openVirtualTodo leaves the saved filter for the list holding the selected todo
+func (m *Model) openVirtualTodo() {
+	if m.TodoCursor >= len(m.TodoList.Todos) {
+		return
+	}
+	if hit, ok := m.virtual.sources[m.TodoList.Todos[m.TodoCursor].ID]; ok {
+		m.jumpTo(hit)
+	}
+}
+
+// virtualAllows reports whether an action can run while a saved filter is open.
+// Its rows are copies, so changes are made in the list holding each todo.
+func virtualAllows(action Action, panel Panel) bool {
+	switch action {
+	case ActionEdit, ActionDue, ActionStart, ActionRemind, ActionHeading, ActionSectionDown, ActionSectionUp,
+		ActionSplit, ActionEditor, ActionFlag, ActionHabit, ActionField, ActionCheck, ActionRunChecks,
+		ActionPromote, ActionScratch, ActionFold, ActionBulkEdit, ActionTitle, ActionProgressUp,
+		ActionProgressDown, ActionListSettings, ActionJira, ActionCompare:
+		return false
+	case ActionAdd, ActionDelete, ActionMerge:
+		return panel == FilePanel
+	}
+	return true
+}
+
+// renderVirtualSource names the list a row of a sorted saved filter comes from
+func (m Model) renderVirtualSource(t todo.Todo) string {
+	if m.virtual == nil || !m.virtual.flat {
+		return ""
+	}
+	hit, ok := m.virtual.sources[t.ID]
+	if !ok {
+		return ""
+	}
+	return "  " + m.Styles.Muted.Render(m.displayName(filepath.Base(hit.File)))
+}
+
+// renderFilterEntries renders the saved filters below the files in the file panel
+func (m Model) renderFilterEntries() string {
+	if len(m.Filters) == 0 {
+		return ""
+	}
+	content := "\n" + m.Styles.Separator.Render(i18n.T("  ─── filters ───")) + "\n"
+	for i, f := range m.Filters {
+		switch {
+		case m.ActivePanel == FilePanel && len(m.Files)+i == m.FileCursor:
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+f.Name+" ") + "\n"
+		case m.virtual != nil && m.virtual.name == f.Name:
+			content += m.Styles.CurrentFile.Render("󰍉 "+f.Name) + "\n"
+		default:
+			content += m.Styles.Normal.Render("  󰍉 "+f.Name) + "\n"
+		}
+	}
+	return content
+}
+
+// renderSearchChip shows the search filter, or its prompt, in the todo panel title.
+// While a pattern is typed in regex mode it is compiled on each key, and errors shown beside it.
+func (m Model) renderSearchChip() string {
+	text, regex := m.Search, m.query.Regexp()
+	prompt := m.Dialog == SearchPrompt
+	if prompt {
+		text, regex = m.InputText+"█", m.SearchRegex
+	} else if text == "" {
+		return ""
+	}
+	icon := "󰍉 "
+	if regex {
+		icon += ".* "
+	}
+	chip := " " + lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorYellow).
+		Bold(true).
+		Padding(0, 1).
+		Render(icon+text)
+	if prompt && regex && m.InputText != "" {
+		if _, err := search.ParseRegexp(m.InputText); err != nil {
+			chip += " " + lipgloss.NewStyle().Foreground(ColorRed).Render("✗ "+err.Error())
+		}
+	}
+	return chip
+}
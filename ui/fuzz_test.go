@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FuzzEditInput types arbitrary text into the add prompt and checks the buffer stays valid
+func FuzzEditInput(f *testing.F) {
+	f.Add("buy milk")
+	f.Add("héllo wörld 🎉")
+	f.Add("\x00\x1b[A\x7f\t\r\n")
+	f.Add("\xff\xfe broken utf8")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		m := newTestModel(t)
+		m.ActivePanel = TodoPanel
+		m.openDialog(AddTodo)
+
+		// Type the text rune by rune, then paste it whole; 0x7f acts as backspace
+		var model tea.Model = m
+		for _, r := range text {
+			key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+			if r == 0x7f {
+				key = tea.KeyMsg{Type: tea.KeyBackspace}
+			}
+			model, _ = model.(Model).handleEditMode(key)
+		}
+		model, _ = model.(Model).handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+
+		input := model.(Model).InputText
+		if !utf8.ValidString(input) {
+			t.Fatalf("input is not valid UTF-8: %q", input)
+		}
+		if n := utf8.RuneCountInString(input); n > maxInputLength {
+			t.Fatalf("input has %d runes, max %d", n, maxInputLength)
+		}
+		model.(Model).View()
+	})
+}
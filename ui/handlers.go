@@ -1,140 +1,199 @@
 package ui
 
 import (
-	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
 	"justdoit/todo"
 )
 
 // handleNormalMode handles keyboard input in normal mode
 func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
-		return m, tea.Quit
+	// ctrl+c always quits, whatever the keymap says
+	if msg.String() == "ctrl+c" {
+		cmd := m.quit()
+		return m, cmd
+	}
 
-	case "esc":
-		// Go back to file panel from todo panel
+	// Collect multi-key sequences such as "z a" in the todo panel
+	key := msg.String()
+	if m.pendingKey != "" {
+		key = m.pendingKey + " " + key
+		m.pendingKey = ""
+	}
+	if m.ActivePanel == TodoPanel && m.Keys.IsPrefix(key) {
+		m.pendingKey = key
+		return m, nil
+	}
+	if n, ok := m.quickToggleKey(key); ok {
+		cmd := m.quickToggle(n)
+		return m, cmd
+	}
+	if m.countPrefix(key) {
+		m.count += key
+		return m, nil
+	}
+	count := m.takeCount()
+
+	if m.Loading != "" && !loadingAllows(m.Keys.Action(key), m.ActivePanel) {
+		m.toast(SeverityInfo, i18n.T("Still loading, please wait"))
+		return m, nil
+	}
+	if m.moving && !movingAllows(m.Keys.Action(key)) {
+		m.toast(SeverityInfo, i18n.T("Still moving files, please wait"))
+		return m, nil
+	}
+	if m.virtual != nil && !virtualAllows(m.Keys.Action(key), m.ActivePanel) {
+		m.toast(SeverityInfo, i18n.T("Saved filters are read-only, Enter opens the todo in its list"))
+		return m, nil
+	}
+	if m.TodoList != nil && m.TodoList.Foreign() && !foreignAllows(m.Keys.Action(key), m.ActivePanel) {
+		m.toast(SeverityInfo, i18n.T("Not a todo list, the file is shown read-only"))
+		return m, nil
+	}
+	if !m.ShowingArchive && IsHiddenFile(m.CurrentFile) && !hiddenAllows(m.Keys.Action(key), m.ActivePanel) {
+		m.toast(SeverityInfo, i18n.T("Hidden file, shown read-only"))
+		return m, nil
+	}
+	if m.virtual != nil && m.ActivePanel == TodoPanel {
+		switch m.Keys.Action(key) {
+		case ActionOpen, ActionSelect, ActionToggle:
+			m.openVirtualTodo()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.Keys.Action(key) {
+	case ActionQuit:
+		cmd = m.quit()
+
+	case ActionBack:
+		// Go back to file panel from todo panel, or unmark the marked files
 		if m.ActivePanel == TodoPanel {
 			m.ActivePanel = FilePanel
+		} else if len(m.Marked) > 0 {
+			m.clearMarks()
 		}
 
-	case "h", "left":
+	case ActionLeft:
 		// Go to left panel (file panel)
 		m.ActivePanel = FilePanel
 		// Preview current file when entering file panel
-		m.previewFile()
+		cmd = m.previewFile()
 
-	case "l", "right":
+	case ActionRight:
 		// Go to right panel (todo panel)
 		m.ActivePanel = TodoPanel
 
-	case "tab":
+	case ActionSwitchPanel:
 		// Switch between file and todo panel
 		if m.ActivePanel == FilePanel {
 			m.ActivePanel = TodoPanel
 		} else {
 			m.ActivePanel = FilePanel
 			// Preview current file when entering file panel
-			m.previewFile()
+			cmd = m.previewFile()
 		}
 
-	case "j", "down":
+	case ActionDown:
 		if m.ActivePanel == FilePanel {
-			maxFiles := len(m.Files)
+			maxFiles := len(m.Files) + len(m.Filters)
 			if m.ShowingArchive {
-				maxFiles = len(m.ArchivedFiles)
+				maxFiles = len(m.archiveRows())
 			}
 			if m.FileCursor < maxFiles-1 {
 				m.FileCursor++
 				// Preview file on cursor move
-				m.previewFile()
+				cmd = m.previewFile()
 			}
 		} else {
-			if m.TodoCursor < len(m.TodoList.Todos)-1 {
-				m.TodoCursor++
-			}
+			m.moveTodoCursor(max(count, 1))
 		}
 
-	case "k", "up":
+	case ActionUp:
 		if m.ActivePanel == FilePanel {
 			if m.FileCursor > 0 {
 				m.FileCursor--
 				// Preview file on cursor move
-				m.previewFile()
+				cmd = m.previewFile()
 			}
 		} else {
-			if m.TodoCursor > 0 {
-				m.TodoCursor--
-			}
+			m.moveTodoCursor(-max(count, 1))
 		}
 
-	case "enter":
+	case ActionOpen:
 		// Select file from file panel
 		if m.ActivePanel == FilePanel {
-			if m.ShowingArchive && m.FileCursor < len(m.ArchivedFiles) {
-				// Unarchive the selected file
-				m.unarchiveFile(m.ArchivedFiles[m.FileCursor])
-				m.ActivePanel = TodoPanel
-				m.StatusMessage = fmt.Sprintf("Unarchived: %s", m.CurrentFile)
+			if m.ShowingArchive {
+				// Unarchive the selected file, or fold its month
+				cmd = m.selectArchiveRow()
 			} else if !m.ShowingArchive && m.FileCursor < len(m.Files) {
 				// Open selected file
-				m.CurrentFile = m.Files[m.FileCursor]
-				m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
-				m.ActivePanel = TodoPanel
-				m.TodoCursor = 0
-				m.StatusMessage = fmt.Sprintf("Opened: %s", m.CurrentFile)
+				cmd = m.openFile(m.Files[m.FileCursor])
+			} else if !m.ShowingArchive && m.FileCursor < len(m.Files)+len(m.Filters) {
+				m.openFilter(m.FileCursor - len(m.Files))
 			}
 		}
 
-	case "z":
+	case ActionShowArchive:
 		// Toggle archive view (only in file panel)
 		if m.ActivePanel == FilePanel {
 			m.ShowingArchive = !m.ShowingArchive
 			m.FileCursor = 0
 			if m.ShowingArchive {
-				m.StatusMessage = "Showing archived files"
+				m.toast(SeverityInfo, i18n.T("Showing archived files"))
 			} else {
-				m.StatusMessage = "Showing active files"
+				m.toast(SeverityInfo, i18n.T("Showing active files"))
 			}
 		}
 
-	case "a":
+	case ActionAdd:
 		switch m.ActivePanel {
 		case FilePanel:
 			// Create new file (only in file panel, not in archive view)
 			if !m.ShowingArchive {
-				m.Mode = EditMode
-				m.EditingIndex = -2 // Special value for new file
+				m.openDialog(NewFile)
 				m.InputText = ""
-				m.StatusMessage = "Enter filename (without .json)"
+				m.toast(SeverityInfo, i18n.T("Enter filename (without .json)"))
 			}
 		case TodoPanel:
 			// Add new todo (only in todo panel)
-			m.Mode = EditMode
-			m.EditingIndex = -1
+			m.openDialog(AddTodo)
 			m.InputText = ""
-			m.TodoCursor = 0
-			m.StatusMessage = "Adding new todo (Enter to save, Esc to cancel)"
+			m.suggestions = nil
+			m.toast(SeverityInfo, i18n.T("Adding new todo (Enter to save, Esc to cancel)"))
+			cmd = m.loadSuggestions()
 		}
 
-	case "i":
+	case ActionEdit:
 		// Edit current todo (only in todo panel)
 		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
-			m.Mode = EditMode
+			m.openDialog(EditTodo)
 			m.EditingIndex = m.TodoCursor
 			m.InputText = m.TodoList.Todos[m.TodoCursor].Title
-			m.StatusMessage = "Editing todo (Enter to save, Esc to cancel)"
+			m.toast(SeverityInfo, i18n.Tf("Editing todo #%d (Enter to save, Esc to cancel)", m.TodoList.Todos[m.TodoCursor].ID))
 		}
 
-	case "d":
+	case ActionDelete:
 		if m.ActivePanel == FilePanel {
 			// Delete file (only in file panel, not in archive view)
-			if !m.ShowingArchive && m.FileCursor < len(m.Files) {
-				m.Mode = EditMode
-				m.EditingIndex = -4 // Special value for delete file confirmation
-				m.StatusMessage = "Delete this file? (y/n)"
+			if !m.ShowingArchive && len(m.markedFiles()) > 0 {
+				m.startBatch(ActionDelete)
+			} else if !m.ShowingArchive && m.FileCursor < len(m.Files) {
+				if !m.Behavior.ConfirmDeletes {
+					cmd = m.deleteCurrentFile()
+					break
+				}
+				m.openDialog(ConfirmDelete)
+				m.toast(SeverityInfo, i18n.T("Delete this file? (y/n)"))
+			} else if !m.ShowingArchive && m.FileCursor < len(m.Files)+len(m.Filters) {
+				m.deleteFilter(m.FileCursor - len(m.Files))
 			}
 		} else if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
 			// Delete current todo (only in todo panel)
@@ -142,49 +201,391 @@ func (m Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.TodoCursor >= len(m.TodoList.Todos) && m.TodoCursor > 0 {
 				m.TodoCursor--
 			}
-			m.StatusMessage = "Deleted todo"
+			m.clampTodoCursor()
+			m.toast(SeverityInfo, i18n.T("Deleted todo"))
 		}
 
-	case " ":
-		// Space key - toggle in todo panel, open file in file panel
+	case ActionSelect:
+		// Space key - toggle in todo panel, mark file in file panel
 		if m.ActivePanel == FilePanel {
-			// Same as Enter in file panel
-			if m.ShowingArchive && m.FileCursor < len(m.ArchivedFiles) {
-				m.unarchiveFile(m.ArchivedFiles[m.FileCursor])
-				m.ActivePanel = TodoPanel
-				m.StatusMessage = fmt.Sprintf("Unarchived: %s", m.CurrentFile)
+			if m.ShowingArchive {
+				cmd = m.selectArchiveRow()
 			} else if !m.ShowingArchive && m.FileCursor < len(m.Files) {
-				m.CurrentFile = m.Files[m.FileCursor]
-				m.TodoList = todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile))
-				m.ActivePanel = TodoPanel
-				m.TodoCursor = 0
-				m.StatusMessage = fmt.Sprintf("Opened: %s", m.CurrentFile)
+				cmd = m.toggleMark()
+			} else if !m.ShowingArchive && m.FileCursor < len(m.Files)+len(m.Filters) {
+				m.openFilter(m.FileCursor - len(m.Files))
 			}
 		} else if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
-			// Toggle completion in todo panel
-			m.toggleTodoWithArchivePrompt()
+			// Toggle completion in todo panel (collapse/expand on headings)
+			cmd = m.toggleTodoWithArchivePrompt()
+		}
+
+	case ActionToggle:
+		// Toggle completion (only in todo panel, collapse/expand on headings)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			cmd = m.toggleTodoWithArchivePrompt()
+		}
+
+	case ActionHeading:
+		// Turn the current todo into a section heading or back (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			m.TodoList.ToggleHeading(m.TodoCursor)
+			m.clampTodoCursor()
+			if m.TodoList.Todos[m.TodoCursor].Heading {
+				m.toast(SeverityInfo, i18n.T("Marked as section heading"))
+			} else {
+				m.toast(SeverityInfo, i18n.T("Unmarked section heading"))
+			}
+		}
+
+	case ActionFold:
+		// Fold the section containing the cursor (only in todo panel)
+		if m.ActivePanel == TodoPanel {
+			heading := m.TodoList.SectionStart(m.TodoCursor) - 1
+			if heading < 0 {
+				m.toast(SeverityInfo, i18n.T("Not in a section"))
+				break
+			}
+			m.TodoList.ToggleCollapsed(heading)
+			m.TodoCursor = heading
+			m.clampTodoCursor()
+			m.toast(SeverityInfo, i18n.T("Toggled section"))
+		}
+
+	case ActionSectionDown, ActionSectionUp:
+		// Move the current todo to the next/previous section (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			dir := 1
+			if m.Keys.Action(key) == ActionSectionUp {
+				dir = -1
+			}
+			moved := m.TodoList.MoveToSection(m.TodoCursor, dir)
+			if moved != m.TodoCursor {
+				m.TodoCursor = moved
+				m.toast(SeverityInfo, i18n.T("Moved to section"))
+			}
+		}
+
+	case ActionDue:
+		// Set due date (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			m.openDialog(DuePrompt)
+			m.InputText = ""
+			if due := m.TodoList.Todos[m.TodoCursor].Due; due != nil {
+				m.InputText = due.Format("2006-01-02 15:04")
+			}
+			m.toast(SeverityInfo, i18n.T("Due date: today, tomorrow, YYYY-MM-DD [HH:MM] (empty clears)"))
+		}
+
+	case ActionStart:
+		// Set start date (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			m.openDialog(StartPrompt)
+			m.InputText = ""
+			if start := m.TodoList.Todos[m.TodoCursor].Start; start != nil {
+				m.InputText = start.Format("2006-01-02 15:04")
+			}
+			m.toast(SeverityInfo, i18n.T("Start date: today, tomorrow, YYYY-MM-DD [HH:MM] (empty clears)"))
 		}
 
-	case "x":
-		// Toggle completion (only in todo panel)
+	case ActionRemind:
+		// Set reminder offset (only in todo panel, requires a due date)
 		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
-			m.toggleTodoWithArchivePrompt()
+			t := m.TodoList.Todos[m.TodoCursor]
+			if t.Due == nil {
+				m.toast(SeverityInfo, i18n.T("Set a due date first (D)"))
+				break
+			}
+			m.openDialog(ReminderPrompt)
+			m.InputText = ""
+			if t.RemindBefore != nil {
+				m.InputText = time.Duration(*t.RemindBefore).String()
+			}
+			m.toast(SeverityInfo, i18n.T("Remind before due: e.g. 30m, 1h, 1d (empty clears)"))
+		}
+
+	case ActionFlag:
+		// Flag the current todo as a priority (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) && !m.TodoList.Todos[m.TodoCursor].Heading {
+			m.TodoList.ToggleFlag(m.TodoCursor)
+			if m.TodoList.Todos[m.TodoCursor].Flagged {
+				m.toast(SeverityInfo, i18n.T("Flagged todo"))
+			} else {
+				m.toast(SeverityInfo, i18n.T("Unflagged todo"))
+			}
+			m.clampTodoCursor()
 		}
 
-	case "A":
+	case ActionFocus:
+		// Show only flagged and due-today todos (session only)
+		m.Focus = !m.Focus
+		m.clampTodoCursor()
+		if m.Focus {
+			m.toast(SeverityInfo, i18n.T("Focus mode: flagged and due-today todos only"))
+		} else {
+			m.toast(SeverityInfo, i18n.T("Focus mode off"))
+		}
+
+	case ActionField:
+		// Set a key=value field on the current todo (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) && !m.TodoList.Todos[m.TodoCursor].Heading {
+			m.openDialog(FieldPrompt)
+			m.InputText = ""
+			m.toast(SeverityInfo, i18n.T("Field: key=value (key= removes it)"))
+		}
+
+	case ActionCheck:
+		// Set the shell command deciding whether the current todo is done (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) && !m.TodoList.Todos[m.TodoCursor].Heading {
+			m.openDialog(CheckPrompt)
+			m.InputText = m.TodoList.Todos[m.TodoCursor].Check
+			m.toast(SeverityInfo, i18n.T("Check command: done when it exits 0 (empty removes it)"))
+		}
+
+	case ActionRunChecks:
+		cmd = m.startChecks(-1)
+
+	case ActionResolve:
+		m.openResolver()
+
+	case ActionStats:
+		cmd = m.openStats()
+
+	case ActionDebugLog:
+		m.openDebugLog()
+
+	case ActionHiddenFiles:
+		m.toggleHidden()
+
+	case ActionTitle:
+		m.openTitlePrompt()
+
+	case ActionPrevious:
+		cmd = m.openPrevious()
+
+	case ActionSearch:
+		m.openSearchPrompt()
+
+	case ActionViewSort:
+		m.cycleViewSort()
+
+	case ActionViewColumns:
+		m.cycleViewColumns()
+
+	case ActionSaveFilter:
+		m.openFilterNamePrompt()
+
+	case ActionFieldFilter:
+		m.openDialog(FieldFilterPrompt)
+		m.InputText = m.FieldFilter
+		m.toast(SeverityInfo, i18n.T("Filter by field: key or key=value (empty clears)"))
+
+	case ActionCopy:
+		// Copy the current todo's title (only in todo panel)
+		if m.ActivePanel == TodoPanel && m.TodoCursor < len(m.TodoList.Todos) {
+			if err := clipboardWrite(m.TodoList.Todos[m.TodoCursor].Title); err != nil {
+				m.toast(SeverityError, err.Error())
+			} else {
+				m.toast(SeverityInfo, i18n.T("Copied todo to clipboard"))
+			}
+		}
+
+	case ActionCopyList:
+		// Copy the whole list as Markdown, or every marked list
+		if m.ActivePanel == FilePanel && !m.ShowingArchive && len(m.markedFiles()) > 0 {
+			m.startBatch(ActionCopyList)
+			break
+		}
+		name := m.listName()
+		if err := clipboardWrite(m.TodoList.Markdown(name)); err != nil {
+			m.toast(SeverityError, err.Error())
+		} else {
+			m.toast(SeverityInfo, i18n.Tf("Copied %s as Markdown", m.CurrentFile))
+		}
+
+	case ActionCopyLink:
+		if m.ActivePanel == TodoPanel {
+			m.copyLink()
+		}
+
+	case ActionFollowLink:
+		if m.ActivePanel == TodoPanel {
+			m.followLink()
+		}
+
+	case ActionTemplate:
+		if m.ActivePanel == FilePanel && !m.ShowingArchive {
+			m.openTemplatePicker()
+		}
+
+	case ActionHabit:
+		// Turn the current list into a habit list or back
+		if m.TodoList.IsHabit() {
+			m.TodoList.SetKind("")
+			m.toast(SeverityInfo, i18n.T("Normal list"))
+		} else {
+			m.TodoList.SetKind(todo.KindHabit)
+			m.toast(SeverityInfo, i18n.T("Habit list: checkmarks reset every day"))
+		}
+		m.clampTodoCursor()
+
+	case ActionAckReminders:
+		// Acknowledge fired reminders
+		if len(m.Reminders) > 0 {
+			m.acknowledgeReminders()
+			m.toast(SeverityInfo, i18n.T("Reminders acknowledged"))
+		}
+
+	case ActionSplit:
+		// Split the todos shown by the filters into a new file (only in todo panel)
+		if m.ActivePanel == TodoPanel {
+			if !m.clearableFilters() {
+				m.toast(SeverityInfo, i18n.T("Filter the list before splitting"))
+				break
+			}
+			m.openDialog(SplitPrompt)
+			m.InputText = m.ActiveContext
+			m.toast(SeverityInfo, i18n.Tf("Move the %d shown todos to new file (without .json)", len(m.shownTodos())))
+		}
+
+	case ActionBulkEdit:
+		// Edit the shown todos as lines of text in $EDITOR
+		return m, m.openBulkEditor()
+
+	case ActionEditor:
+		// Edit the raw JSON of the current (or previewed) file in $EDITOR
+		if m.TodoList.IsScratch() {
+			m.toast(SeverityInfo, i18n.T("The scratchpad has no file to edit"))
+			return m, nil
+		}
+		return m, m.openInEditor()
+
+	case ActionContext:
+		// Open the context switcher
+		m.openContextSwitcher()
+
+	case ActionKeybindings:
+		// Open the keybinding editor
+		m.openKeybindings()
+
+	case ActionSettings:
+		// Open the settings screen
+		m.openSettings()
+
+	case ActionListSettings:
+		// Open the settings of the open list
+		m.openListSettings()
+
+	case ActionToday:
+		// Show todos due today and overdue across all files
+		m.TodoList.Flush()
+		m.toast(SeverityInfo, i18n.T("Scanning for todos due today..."))
+		return m, m.scanToday(true)
+
+	case ActionArchive:
 		// Manual archive (shift+a, only in file panel)
+		if m.ActivePanel == FilePanel && !m.ShowingArchive && len(m.markedFiles()) > 0 {
+			m.startBatch(ActionArchive)
+		} else if m.ActivePanel == FilePanel && !m.ShowingArchive && m.FileCursor < len(m.Files) {
+			m.openDialog(ConfirmArchive)
+			m.toast(SeverityInfo, i18n.T("Archive this file? (y/n)"))
+		}
+
+	case ActionMerge:
+		// Merge the marked files into one
 		if m.ActivePanel == FilePanel && !m.ShowingArchive {
-			m.Mode = EditMode
-			m.EditingIndex = -3
-			m.StatusMessage = "Archive this file? (y/n)"
+			m.startBatch(ActionMerge)
+		}
+
+	case ActionProgressUp:
+		// Raise the current todo's progress (only in todo panel)
+		if m.ActivePanel == TodoPanel {
+			cmd = m.stepProgress(todo.ProgressStep)
+		}
+
+	case ActionProgressDown:
+		// Lower the current todo's progress (only in todo panel)
+		if m.ActivePanel == TodoPanel {
+			cmd = m.stepProgress(-todo.ProgressStep)
+		}
+
+	case ActionShrinkFiles:
+		m.resizePanels(-filePanelStep)
+
+	case ActionGrowFiles:
+		m.resizePanels(filePanelStep)
+
+	case ActionMaximize:
+		m.Maximized = !m.Maximized
+		if m.Maximized {
+			m.ActivePanel = TodoPanel
+		}
+
+	case ActionGoto:
+		// Jump to the counted line, or the last one without a count
+		if m.ActivePanel == TodoPanel {
+			if count == 0 {
+				count = len(m.visibleIndices())
+			}
+			m.jumpToLine(count)
+		}
+
+	case ActionGotoPrompt:
+		if m.ActivePanel == TodoPanel {
+			m.openGotoPrompt()
+		}
+
+	case ActionJira:
+		if m.ActivePanel == TodoPanel {
+			m.openJiraPrompt()
+		}
+
+	case ActionCompare:
+		m.openComparePicker()
+
+	case ActionScratch:
+		m.toggleScratch()
+
+	case ActionPromote:
+		if m.ActivePanel == TodoPanel {
+			m.promoteScratch()
+		}
+
+	case ActionClearFilters:
+		m.clearFilters()
+
+	case ActionZen:
+		// Hide everything but the open list (session only)
+		m.Zen = !m.Zen
+		if m.Zen {
+			m.ActivePanel = TodoPanel
+			m.toast(SeverityInfo, i18n.T("Zen mode: Z or Esc to leave"))
+		} else {
+			m.StatusMessage = ""
 		}
 	}
 
-	return m, nil
+	// Going back to the file panel brings it back into view
+	if m.ActivePanel == FilePanel {
+		m.Maximized = false
+		m.Zen = false
+	}
+	return m, cmd
 }
 
-// toggleTodoWithArchivePrompt toggles a todo and prompts for archiving if all are complete
-func (m *Model) toggleTodoWithArchivePrompt() {
+// toggleTodoWithArchivePrompt toggles a todo and prompts for archiving if all are
+// complete. Completions are celebrated as their events are handled.
+func (m *Model) toggleTodoWithArchivePrompt() tea.Cmd {
+	if m.TodoList.Todos[m.TodoCursor].Heading {
+		m.TodoList.ToggleCollapsed(m.TodoCursor)
+		m.toast(SeverityInfo, i18n.T("Toggled section"))
+		return nil
+	}
+
+	if t := m.TodoList.Todos[m.TodoCursor]; t.Check != "" {
+		return m.startChecks(t.ID) // done is whatever the command says
+	}
+
 	wasCompleted := m.TodoList.Todos[m.TodoCursor].Completed
 	m.TodoList.Toggle(m.TodoCursor)
 
@@ -198,68 +599,243 @@ func (m *Model) toggleTodoWithArchivePrompt() {
 		}
 	}
 
-	// Check if all todos are completed
-	if m.allTodosCompleted() {
-		m.Mode = EditMode
-		m.EditingIndex = -3
-		m.StatusMessage = "All complete! Archive this list? (y/n)"
+	m.clampTodoCursor()
+
+	// Check if all todos are completed (habit lists are never finished)
+	if m.Behavior.ArchivePrompt && !m.TodoList.IsHabit() && !m.TodoList.IsScratch() && m.allTodosCompleted() {
+		m.openDialog(ConfirmArchive)
+		m.toast(SeverityInfo, i18n.T("All complete! Archive this list? (y/n)"))
 	} else {
-		m.StatusMessage = "Toggled todo status"
+		m.toast(SeverityInfo, i18n.T("Toggled todo status"))
 	}
+	return nil
 }
 
 // handleEditMode handles keyboard input in edit mode
 func (m Model) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Modals answer the arrow keys and Enter too
+	if d, ok := m.openModal(); ok {
+		var moved bool
+		if msg, moved = m.focusModal(d, msg); moved {
+			return m, nil
+		}
+	}
+
 	// Handle delete file prompt (y/n)
-	if m.EditingIndex == -4 {
+	if m.Dialog == ConfirmDelete {
 		switch msg.String() {
 		case "y", "Y":
-			m.deleteCurrentFile()
-			m.Mode = NormalMode
+			m.closeDialog()
 			m.ActivePanel = FilePanel
-			m.StatusMessage = "File deleted!"
-			return m, nil
+			cmd := m.deleteCurrentFile()
+			return m, cmd
 		case "n", "N", "esc":
-			m.Mode = NormalMode
-			m.StatusMessage = "Cancelled"
+			m.closeDialog()
+			m.toast(SeverityInfo, i18n.T("Cancelled"))
 			return m, nil
 		}
 		return m, nil
 	}
 
+	// Handle bulk delete prompt: y deletes, n keeps the todos but applies the other edits
+	if m.Dialog == ConfirmBulkDelete {
+		switch msg.String() {
+		case "y", "Y":
+			return m.applyBulk(true)
+		case "n", "N":
+			return m.applyBulk(false)
+		case "p", "P":
+			m.openPreview()
+		case "esc":
+			m.bulk = nil
+			m.closeDialog()
+			m.toast(SeverityInfo, i18n.T("Bulk edit discarded"))
+		}
+		return m, nil
+	}
+
+	if m.Dialog == NameCollision {
+		return m.handleCollision(msg)
+	}
+
+	if m.Dialog == ConfirmBatch {
+		return m.handleBatch(msg)
+	}
+
+	if m.Dialog == ConfirmQuit {
+		return m.handleQuit(msg)
+	}
+
+	if m.Dialog == ConfirmChecks {
+		return m.handleConfirmChecks(msg)
+	}
+
 	// Handle archive prompt (y/n)
-	if m.EditingIndex == -3 {
+	if m.Dialog == ConfirmArchive {
 		switch msg.String() {
 		case "y", "Y":
-			m.archiveCurrentFile()
-			m.Mode = NormalMode
-			m.ActivePanel = FilePanel // Go back to file panel
-			m.StatusMessage = "File archived!"
-			return m, nil
+			cmd := m.startArchive()
+			return m, cmd
 		case "n", "N", "esc":
-			m.Mode = NormalMode
-			m.StatusMessage = "Cancelled"
+			m.closeDialog()
+			m.toast(SeverityInfo, i18n.T("Cancelled"))
 			return m, nil
 		}
 		return m, nil
 	}
 
+	// Handle settings screen
+	if m.Dialog == SettingsScreen {
+		return m.handleSettings(msg)
+	}
+	if m.Dialog == ListSettingScreen {
+		return m.handleListSettings(msg)
+	}
+
+	// Handle keybinding editor
+	if m.Dialog == KeybindingEditor {
+		return m.handleKeybindings(msg)
+	}
+
+	// Handle Today view
+	if m.Dialog == TodayScreen {
+		return m.handleTodayView(msg)
+	}
+
+	if m.Dialog == ConflictResolver {
+		return m.handleResolver(msg)
+	}
+
+	if m.Dialog == StatsScreen {
+		return m.handleStats(msg)
+	}
+
+	if m.Dialog == DebugLogScreen {
+		return m.handleDebugLog(msg)
+	}
+
+	if m.Dialog == PreviewScreen {
+		return m.handlePreview(msg)
+	}
+
+	// Handle context switcher
+	if m.Dialog == TemplatePicker {
+		return m.handleTemplatePicker(msg)
+	}
+
+	if m.Dialog == ComparePicker {
+		return m.handleComparePicker(msg)
+	}
+
+	if m.Dialog == CompareScreen {
+		return m.handleCompare(msg)
+	}
+
+	if m.Dialog == FilePicker {
+		if cmd, handled := m.handleFilePicker(msg); handled {
+			return m, cmd
+		}
+	}
+
+	if m.Dialog == TemplatePrompt && msg.String() == "enter" {
+		return m.submitTemplatePrompt()
+	}
+
+	if m.Dialog == ContextSwitcher {
+		switch msg.String() {
+		case "j", "down":
+			if m.ContextCursor < len(m.Contexts)-1 {
+				m.ContextCursor++
+			}
+		case "k", "up":
+			if m.ContextCursor > 0 {
+				m.ContextCursor--
+			}
+		case "enter", " ":
+			m.ActiveContext = m.Contexts[m.ContextCursor]
+			m.closeDialog()
+			m.clampTodoCursor()
+			if m.ActiveContext == "" {
+				m.toast(SeverityInfo, i18n.T("Showing all contexts"))
+			} else {
+				m.toast(SeverityInfo, i18n.Tf("Context: @%s", m.ActiveContext))
+			}
+		case "esc", "q":
+			m.closeDialog()
+			m.toast(SeverityInfo, i18n.T("Cancelled"))
+		}
+		return m, nil
+	}
+
+	// Handle due date, start date and reminder prompts (empty input clears)
+	if (m.Dialog == DuePrompt || m.Dialog == StartPrompt || m.Dialog == ReminderPrompt) && msg.String() == "enter" {
+		return m.submitSchedulePrompt()
+	}
+
+	// Handle list tags prompt (empty input clears)
+	if m.Dialog == ListTagsPrompt && msg.String() == "enter" {
+		return m.submitListTags()
+	}
+
+	// Handle custom field prompts
+	if m.Dialog == FieldPrompt && msg.String() == "enter" {
+		return m.submitFieldPrompt()
+	}
+	if m.Dialog == FieldFilterPrompt && msg.String() == "enter" {
+		return m.submitFieldFilter()
+	}
+	if m.Dialog == GotoPrompt && msg.String() == "enter" {
+		return m.submitGotoPrompt()
+	}
+	if m.Dialog == JiraPrompt && msg.String() == "enter" {
+		return m.submitJiraPrompt()
+	}
+	if m.Dialog == CheckPrompt && msg.String() == "enter" {
+		return m.submitCheckPrompt()
+	}
+	if m.Dialog == SearchPrompt && msg.String() == "enter" {
+		return m.submitSearch()
+	}
+	if m.Dialog == SearchPrompt && msg.String() == "ctrl+r" {
+		m.toggleSearchRegex()
+		return m, nil
+	}
+	if m.Dialog == FilterNamePrompt && msg.String() == "enter" {
+		return m.submitFilterName()
+	}
+	if m.Dialog == TitlePrompt && msg.String() == "enter" {
+		return m.submitTitle()
+	}
+	if m.Dialog == ArchiveNamePrompt && msg.String() == "enter" {
+		return m.submitArchiveName()
+	}
+
+	// Handle the suggestions below a new todo
+	if m.Dialog == AddTodo && m.handleSuggestionKey(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "esc":
-		m.Mode = NormalMode
-		m.StatusMessage = "Cancelled"
+		m.closeDialog()
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
 		return m, nil
 
 	case "enter":
 		if m.InputText != "" {
-			if m.EditingIndex == -2 {
+			if m.Dialog == NewFile {
 				// Creating new file
-				filename := m.InputText + ".json"
+				filename, err := m.newFileName()
+				if err != nil {
+					m.toast(SeverityInfo, i18n.Tf("Invalid name: %v", err))
+					return m, nil
+				}
 				newPath := filepath.Join(m.TodoDir, filename)
-				m.TodoList = todo.NewTodoList(newPath)
+				m.setList(newPath)
 				m.TodoList.Save() // Force save to create the file
+				m.noteRecent(filename)
 				m.CurrentFile = filename
-				m.Files = LoadTodoFiles(m.TodoDir) // Reload file list after save
+				m.loadFiles() // Reload file list after save
 
 				// Find index of new file
 				for i, f := range m.Files {
@@ -271,38 +847,162 @@ func (m Model) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 				m.ActivePanel = TodoPanel
 				m.TodoCursor = 0
-				m.StatusMessage = fmt.Sprintf("Created: %s", filename)
-			} else if m.EditingIndex == -1 {
-				// Adding new todo at top
-				m.TodoList.Insert(m.TodoCursor, m.InputText)
-				m.TodoCursor = 0
+				m.toast(SeverityInfo, i18n.Tf("Created: %s", filename))
+			} else if m.Dialog == SplitPrompt {
+				// Splitting filtered todos into a new file
+				filename, err := m.newFileName()
+				if err != nil {
+					m.toast(SeverityInfo, i18n.Tf("Invalid name: %v", err))
+					return m, nil
+				}
+				shown := m.shownTodos()
+				moved, err := m.TodoList.SplitTo(filepath.Join(m.TodoDir, filename), func(t todo.Todo) bool { return shown[t.ID] })
+				if err != nil {
+					m.toast(SeverityError, err.Error())
+					return m, nil
+				}
+				m.loadFiles()
+				for i, f := range m.Files {
+					if f == m.CurrentFile {
+						m.FileCursor = i
+						break
+					}
+				}
+				m.clampTodoCursor()
+				m.toast(SeverityInfo, i18n.Tf("Moved %d todos to %s", moved, filename))
+			} else if m.Dialog == AddTodo {
+				// Adding new todo at top, tagged with the active context so it stays visible.
+				// A taken suggestion leaves a space behind for typing on.
+				title := strings.TrimRight(m.InputText, " ")
+				if m.ActiveContext != "" && !(todo.Todo{Title: title}).HasContext(m.ActiveContext) {
+					title += " @" + m.ActiveContext
+				}
+				m.TodoList.Insert(m.TodoCursor, title)
+				m.TodoCursor = m.TodoList.IndexOf(m.TodoList.NextID - 1) // wherever the list's sort put it
 			} else {
 				// Editing existing todo
 				m.TodoList.Update(m.EditingIndex, m.InputText)
+				m.toast(SeverityInfo, i18n.T("Saved"))
 			}
-			m.Mode = NormalMode
-			if m.EditingIndex >= 0 {
-				m.StatusMessage = "Saved"
-			}
+			m.closeDialog()
 		} else {
-			m.StatusMessage = "Cannot be empty"
+			m.toast(SeverityInfo, i18n.T("Cannot be empty"))
 		}
 		return m, nil
 
 	case "backspace":
 		if len(m.InputText) > 0 {
-			m.InputText = m.InputText[:len(m.InputText)-1]
+			_, size := utf8.DecodeLastRuneInString(m.InputText)
+			m.InputText = m.InputText[:len(m.InputText)-size]
 		}
 
+	case "ctrl+v":
+		text, err := clipboardRead()
+		if err != nil {
+			m.toast(SeverityError, err.Error())
+			return m, nil
+		}
+		m.insertInput([]rune(text))
+
 	default:
-		if len(msg.String()) == 1 {
-			m.InputText += msg.String()
+		// Typed keys and bracketed pastes
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.insertInput(msg.Runes)
 		}
 	}
 
+	if m.Dialog == AddTodo {
+		m.suggest()
+	}
+	if m.Dialog == FilePicker {
+		m.filterPicker()
+	}
 	return m, nil
 }
 
+// insertInput appends runes to the edit buffer, warning when they don't all fit
+func (m *Model) insertInput(runes []rune) {
+	var truncated bool
+	m.InputText, truncated = appendInput(m.InputText, runes)
+	if truncated {
+		m.toast(SeverityWarning, i18n.Tf("Input truncated to %d characters", maxInputLength))
+	}
+}
+
+// maxInputLength caps the edit buffer so pasted blobs can't grow it without bound
+const maxInputLength = 4096
+
+// appendInput adds typed or pasted runes to the edit buffer. Line breaks and tabs
+// become spaces, other control characters and invalid runes are dropped, and input
+// stops at maxInputLength, reporting whether anything was cut off.
+func appendInput(input string, runes []rune) (string, bool) {
+	n := utf8.RuneCountInString(input)
+	var b strings.Builder
+	b.WriteString(input)
+	for _, r := range runes {
+		switch {
+		case r == '\n' || r == '\t':
+			r = ' ' // pasted lines join into one title
+		case r == utf8.RuneError || unicode.IsControl(r):
+			continue
+		}
+		if n >= maxInputLength {
+			return b.String(), true
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String(), false
+}
+
+// submitSchedulePrompt applies the due date, start date or reminder entered for the
+// current todo
+func (m Model) submitSchedulePrompt() (tea.Model, tea.Cmd) {
+	switch m.Dialog {
+	case DuePrompt:
+		if m.InputText == "" {
+			m.TodoList.SetDue(m.TodoCursor, nil)
+			m.toast(SeverityInfo, i18n.T("Due date cleared"))
+		} else {
+			due, err := todo.ParseDue(m.InputText, time.Now())
+			if err != nil {
+				m.toast(SeverityError, err.Error())
+				return m, nil
+			}
+			m.TodoList.SetDue(m.TodoCursor, &due)
+			m.toast(SeverityInfo, i18n.Tf("Due %s", due.Format("Mon Jan 2 15:04")))
+		}
+	case StartPrompt:
+		if m.InputText == "" {
+			m.TodoList.SetStart(m.TodoCursor, nil)
+			m.toast(SeverityInfo, i18n.T("Start date cleared"))
+		} else {
+			start, err := todo.ParseStart(m.InputText, time.Now())
+			if err != nil {
+				m.toast(SeverityError, err.Error())
+				return m, nil
+			}
+			m.TodoList.SetStart(m.TodoCursor, &start)
+			m.toast(SeverityInfo, i18n.Tf("Starts %s", start.Format("Mon Jan 2 15:04")))
+		}
+	default:
+		if m.InputText == "" {
+			m.TodoList.SetReminder(m.TodoCursor, -1)
+			m.toast(SeverityInfo, i18n.T("Reminder cleared"))
+		} else {
+			offset, err := todo.ParseOffset(m.InputText)
+			if err != nil {
+				m.toast(SeverityError, err.Error())
+				return m, nil
+			}
+			m.TodoList.SetReminder(m.TodoCursor, offset)
+			m.toast(SeverityInfo, i18n.Tf("Reminder set %s before due", offset))
+		}
+	}
+	m.closeDialog()
+	return m, checkReminders(m.TodoDir, false)
+}
+
 // handleMouse handles mouse input
 func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if msg.Action != tea.MouseActionPress && msg.Action != tea.MouseActionRelease {
@@ -312,13 +1012,31 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	x, y := msg.X, msg.Y
 
 	// Calculate panel boundaries
-	leftWidth := m.Width / 4
-	leftPanelEnd := leftWidth + 2
+	leftWidth, _ := m.panelWidths()
+	leftPanelEnd := 0
+	if !m.Maximized && !m.Zen {
+		leftPanelEnd = leftWidth + 2
+	}
 
 	// Click in left panel (files)
 	if x >= 0 && x < leftPanelEnd {
 		m.ActivePanel = FilePanel
 		clickedLine := y - 3
+		if m.ShowingArchive {
+			clickedLine -= 2 // archived separator
+			if clickedLine >= 0 && clickedLine < len(m.archiveRows()) {
+				m.FileCursor = clickedLine
+			}
+			return m, nil
+		}
+		if recent := m.recentShown(); clickedLine >= 0 && clickedLine < len(recent)+2 {
+			if clickedLine >= 1 && clickedLine <= len(recent) && msg.Action == tea.MouseActionRelease {
+				cmd := m.openFile(recent[clickedLine-1])
+				return m, cmd
+			}
+			return m, nil
+		}
+		clickedLine -= m.recentRows()
 		if clickedLine >= 0 && clickedLine < len(m.Files) {
 			m.FileCursor = clickedLine
 		}
@@ -328,10 +1046,14 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	// Click in right panel (todos)
 	if x >= leftPanelEnd && x < m.Width {
 		m.ActivePanel = TodoPanel
-		clickedLine := y - 3
-		if clickedLine >= 0 && clickedLine < len(m.TodoList.Todos) {
-			m.TodoCursor = clickedLine
-			m.StatusMessage = fmt.Sprintf("Selected: %s", m.TodoList.Todos[clickedLine].Title)
+		clickedLine := y - 3 + m.TodoOffset
+		if m.filtering() {
+			clickedLine-- // filter chips below the title
+		}
+		visible := m.visibleIndices()
+		if clickedLine >= 0 && clickedLine < len(visible) {
+			m.TodoCursor = visible[clickedLine]
+			m.toast(SeverityInfo, i18n.Tf("Selected: %s", m.TodoList.Todos[m.TodoCursor].Title))
 		}
 	}
 
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// Highlight is a compiled highlight rule
+type Highlight struct {
+	pattern *regexp.Regexp
+	field   string // custom field matched instead of the title, "" for the title
+	style   lipgloss.Style
+}
+
+// CompileHighlights compiles config highlight rules into render-ready styles.
+// Invalid rules are skipped and reported in the returned error.
+func CompileHighlights(rules []config.HighlightRule) ([]Highlight, error) {
+	var highlights []Highlight
+	var firstErr error
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid highlight pattern %q: %w", rule.Pattern, err)
+			}
+			continue
+		}
+
+		style := lipgloss.NewStyle().
+			Bold(rule.Bold).
+			Italic(rule.Italic).
+			Faint(rule.Faint).
+			Underline(rule.Underline).
+			Strikethrough(rule.Strikethrough)
+		if rule.Foreground != "" {
+			style = style.Foreground(lipgloss.Color(rule.Foreground))
+		}
+		if rule.Background != "" {
+			style = style.Background(lipgloss.Color(rule.Background))
+		}
+		highlights = append(highlights, Highlight{pattern: re, field: rule.Field, style: style})
+	}
+	return highlights, firstErr
+}
+
+// titleStyle returns the style of the first highlight rule matching a todo, or base
+func (m Model) titleStyle(t todo.Todo, base lipgloss.Style) lipgloss.Style {
+	for _, h := range m.Highlights {
+		if h.field == "" && h.pattern.MatchString(t.Title) {
+			return h.style
+		}
+		if value, ok := t.Fields[h.field]; ok && h.field != "" && h.pattern.MatchString(value) {
+			return h.style
+		}
+	}
+	return base
+}
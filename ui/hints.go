@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// hintKeyNames spells out named keys in the hints bar
+var hintKeyNames = map[string]string{
+	"enter": "Enter",
+	"esc":   "Esc",
+	"tab":   "Tab",
+	" ":     "Space",
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// hintKeys labels the first key bound to each action, like "j/k" for moving down and
+// up, so rebound keys show as bound. It returns "" when one of them is unbound or
+// can't run on the open file.
+func (m Model) hintKeys(actions ...Action) string {
+	labels := make([]string, len(actions))
+	for i, action := range actions {
+		keys := m.Keys.Keys(action)
+		if len(keys) == 0 || !m.hintAllowed(action) {
+			return ""
+		}
+		labels[i] = keys[0]
+		if name, ok := hintKeyNames[keys[0]]; ok {
+			labels[i] = name
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// hintAllowed reports whether action can run on the open file, leaving out of the
+// hints what saved filters, foreign files and hidden files refuse
+func (m Model) hintAllowed(action Action) bool {
+	switch {
+	case m.virtual != nil:
+		return virtualAllows(action, m.ActivePanel)
+	case m.TodoList != nil && m.TodoList.Foreign():
+		return foreignAllows(action, m.ActivePanel)
+	case !m.ShowingArchive && IsHiddenFile(m.CurrentFile):
+		return hiddenAllows(action, m.ActivePanel)
+	}
+	return true
+}
+
+// fitHints joins as many hints as fit the window, in order, ending in more once some
+// are left out. Accessible mode keeps them all, as its lines wrap.
+func (m Model) fitHints(hints []string, sep, more string) string {
+	hints = slices.DeleteFunc(hints, func(h string) bool { return h == "" })
+	result := " " + strings.Join(hints, sep)
+	if m.Width <= 0 || m.Behavior.Accessible || lipgloss.Width(result) <= m.Width {
+		return result
+	}
+	if more == "" {
+		more = m.Styles.Muted.Render("…")
+	}
+	for n := len(hints) - 1; n > 0; n-- {
+		result = " " + strings.Join(append(slices.Clip(hints[:n]), more), sep)
+		if lipgloss.Width(result) <= m.Width {
+			return result
+		}
+	}
+	return " " + more
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
+)
+
+// minInputWidth is the narrowest an inline input wraps at
+const minInputWidth = 10
+
+// inlinePrefix returns what the inline input of the todo panel starts with, and false
+// when nothing is being typed inside the list
+func (m Model) inlinePrefix() (string, bool) {
+	if m.Mode != EditMode {
+		return "", false
+	}
+	switch m.Dialog {
+	case AddTodo:
+		return "  " + m.Styles.Checkbox.Render("") + "  ", true
+	case DuePrompt, StartPrompt, ReminderPrompt, FieldPrompt, CheckPrompt:
+		label := i18n.T("Due")
+		switch m.Dialog {
+		case StartPrompt:
+			label = i18n.T("Start")
+		case ReminderPrompt:
+			label = i18n.T("Remind before")
+		case FieldPrompt:
+			label = i18n.T("Field")
+		case CheckPrompt:
+			label = i18n.T("Check")
+		}
+		return " " + m.Styles.Edit.Render("󰃰") + "  " + label + ": ", true
+	}
+	if m.Dialog == EditTodo {
+		return " " + m.Styles.Edit.Render("") + "  ", true
+	}
+	return "", false
+}
+
+// todoTextWidth returns how many cells a todo row has next to the line numbers
+func (m Model) todoTextWidth() int {
+	width := m.Width - 2
+	if !m.Zen {
+		_, width = m.panelWidths()
+		width -= 2
+	}
+	return width - ansi.StringWidth(m.renderLineNumber(-1, 0, len(m.TodoList.Todos)))
+}
+
+// inputLines wraps the inline input below its prefix so long text stays readable
+func (m Model) inputLines(prefix string) []string {
+	indent := ansi.StringWidth(prefix)
+	width := max(m.todoTextWidth()-indent, minInputWidth)
+	lines := strings.Split(ansi.Wrap(m.InputText+"█", width, ""), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = m.Styles.Edit.Render(prefix + line)
+		} else {
+			lines[i] = m.Styles.Edit.Render(strings.Repeat(" ", indent) + line)
+		}
+	}
+	return lines
+}
+
+// inputRows returns how many rows the inline input takes, 0 when there is none
+func (m Model) inputRows() int {
+	prefix, ok := m.inlinePrefix()
+	if !ok || m.TodoList == nil || m.Height == 0 {
+		return 0
+	}
+	return len(m.inputLines(prefix)) + len(m.suggestionLines(ansi.StringWidth(prefix)))
+}
+
+// renderInlineInput renders the inline input, its wrapped lines behind a blank gutter
+func (m Model) renderInlineInput(gutter, blankGutter string) string {
+	prefix, _ := m.inlinePrefix()
+	lines := m.inputLines(prefix)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = gutter + lines[i]
+		} else {
+			lines[i] = blankGutter + lines[i]
+		}
+	}
+	for _, line := range m.suggestionLines(ansi.StringWidth(prefix)) {
+		lines = append(lines, blankGutter+line)
+	}
+	return strings.Join(lines, "\n")
+}
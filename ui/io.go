@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// listSavedMsg reports how a save running in the background went
+type listSavedMsg struct {
+	path string
+	err  error
+}
+
+// archiveScannedMsg carries the archived files read in the background
+type archiveScannedMsg struct {
+	gen     int // ignored when the archive changed since the scan started
+	files   []string
+	headers map[string]todo.Header
+}
+
+// moves counts the changes to the list files running in the background, which
+// quitting waits for
+var moves sync.WaitGroup
+
+// fileListing is the todo directory as a rescan read it: the files in the order
+// they are shown, and what their headers hold
+type fileListing struct {
+	files []string
+	info  FileInfo
+}
+
+// readListing lists the todo files in dir, the hidden ones too when asked, reading
+// each file's header once
+func readListing(dir string, hidden bool) fileListing {
+	files := listFiles(dir, hidden)
+	info := readFileInfo(dir, files)
+	sortFiles(files, info.Titles)
+	return fileListing{files: files, info: info}
+}
+
+// setListing shows a rescanned todo directory in the file panel
+func (m *Model) setListing(l fileListing) {
+	m.Files, m.Titles, m.Colors, m.Foreign = l.files, l.info.Titles, l.info.Colors, l.info.Foreign
+}
+
+// startMove archives, unarchives, deletes or merges list files in the background,
+// shown under label once it takes a while. Keys that could touch the files wait
+// until the message work returns is handled, see movingAllows.
+func (m *Model) startMove(label string, work func(report func(done, total int)) tea.Msg) tea.Cmd {
+	m.moving = true
+	moves.Add(1)
+	return runProgress(label, func(report func(done, total int)) tea.Msg {
+		defer moves.Done()
+		return work(report)
+	})
+}
+
+// movingAllows reports whether an action may run while list files move in the
+// background. Only the layout can change, anything else could touch the files.
+func movingAllows(action Action) bool {
+	switch action {
+	case ActionShrinkFiles, ActionGrowFiles, ActionMaximize, ActionZen:
+		return true
+	}
+	return false
+}
+
+// saveList writes the open list's pending changes in the background. The list is
+// clean right away; a failed write is reported by the listSavedMsg. Nothing is
+// saved while files move, the open list may be one of them.
+func (m Model) saveList() tea.Cmd {
+	if m.TodoList == nil || !m.TodoList.Dirty() || m.moving {
+		return nil
+	}
+	return saveTodoList(m.TodoList)
+}
+
+// saveTodoList writes a list in the background, reported by a listSavedMsg
+func saveTodoList(tl *todo.TodoList) tea.Cmd {
+	path := tl.Path()
+	done := tl.SaveAsync()
+	return func() tea.Msg {
+		return listSavedMsg{path: path, err: <-done}
+	}
+}
+
+// handleListSaved shows a failed background save in the status bar
+func (m Model) handleListSaved(msg listSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.toast(SeverityError, i18n.Tf("Saving %s failed: %v", filepath.Base(msg.path), msg.err))
+	}
+	return m, nil
+}
+
+// quit writes the open list's pending changes and waits for the saves and moves still
+// running, then quits. If a file couldn't be saved it asks first.
+func (m *Model) quit() tea.Cmd {
+	moves.Wait()
+	if !m.moving { // a list moved away was flushed first, one deleted isn't kept
+		m.TodoList.Flush()
+	}
+	todo.Settle()
+	if len(todo.Unsaved()) == 0 {
+		return tea.Quit
+	}
+	m.openDialog(ConfirmQuit)
+	m.toast(SeverityInfo, i18n.T("Some changes couldn't be saved: r retries, q quits anyway, Esc stays"))
+	return nil
+}
+
+// handleQuit handles input in the prompt about changes that couldn't be saved
+func (m Model) handleQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r", "R":
+		if err := todo.RetryUnsaved(); err != nil {
+			m.toast(SeverityError, i18n.Tf("Still not saved: %v", err))
+			return m, nil
+		}
+		m.closeDialog()
+		return m, tea.Quit
+	case "q", "Q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "n", "N":
+		m.closeDialog()
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// quitModal lists the files that couldn't be saved before quitting
+func (m Model) quitModal() modal {
+	unsaved := todo.Unsaved()
+	d := modal{
+		variant:  modalDanger,
+		title:    i18n.T("Unsaved changes"),
+		question: i18n.T("Quit without saving these?"),
+		choices: []choice{
+			{"r", i18n.T(" Retry")},
+			{"q", i18n.T(" Quit anyway")},
+			{"esc", i18n.T(" Stay")},
+		},
+		safe: 2,
+	}
+	for _, path := range slices.Sorted(maps.Keys(unsaved)) {
+		d.details = append(d.details, filepath.Base(path)+": "+unsaved[path].Error())
+	}
+	return d
+}
+
+// scanArchive lists the archived files and reads their metadata in the background
+func (m Model) scanArchive() tea.Cmd {
+	dir, gen := m.ArchiveDir, m.archiveGen
+	return runProgress(i18n.T("Reading the archive"), func(report func(done, total int)) tea.Msg {
+		files := LoadTodoFiles(dir)
+		headers := make(map[string]todo.Header, len(files))
+		for i, f := range files {
+			headers[f] = archiveHeader(dir, f)
+			report(i+1, len(files))
+		}
+		return archiveScannedMsg{gen: gen, files: files, headers: headers}
+	})
+}
+
+// handleArchiveScanned shows the archived files once they are read
+func (m Model) handleArchiveScanned(msg archiveScannedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.archiveGen {
+		return m, nil
+	}
+	m.ArchivedFiles, m.Archived = msg.files, msg.headers
+	if m.ShowingArchive {
+		m.FileCursor = min(m.FileCursor, max(len(m.archiveRows())-1, 0))
+	}
+	return m, nil
+}
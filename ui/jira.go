@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// jiraTimeout bounds how long fetching an issue may take
+const jiraTimeout = 15 * time.Second
+
+// jiraIssueMsg carries an issue fetched from Jira for the list at path
+type jiraIssueMsg struct {
+	path  string
+	key   string
+	issue todo.Issue
+	err   error
+}
+
+// fetchJiraIssue looks up the issue with key on the Jira server. With an email the
+// token is sent as Jira Cloud expects, otherwise as a Jira Server access token.
+func fetchJiraIssue(jira config.Jira, key string) (todo.Issue, error) {
+	base := strings.TrimRight(jira.URL, "/")
+	req, err := http.NewRequest(http.MethodGet, base+"/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary", nil)
+	if err != nil {
+		return todo.Issue{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if jira.Email != "" {
+		req.SetBasicAuth(jira.Email, jira.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+jira.Token)
+	}
+
+	client := &http.Client{Timeout: jiraTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return todo.Issue{}, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return todo.Issue{}, fmt.Errorf("no issue %s, or the token can't see it", key)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return todo.Issue{}, fmt.Errorf("Jira refused the token (%s)", resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return todo.Issue{}, fmt.Errorf("Jira answered %s", resp.Status)
+	}
+
+	var body struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return todo.Issue{}, fmt.Errorf("unexpected answer from Jira: %w", err)
+	}
+	if body.Key != "" {
+		key = body.Key
+	}
+	summary := strings.TrimSpace(body.Fields.Summary)
+	if summary == "" {
+		summary = key
+	}
+	return todo.Issue{Key: key, Summary: summary, URL: base + "/browse/" + url.PathEscape(key)}, nil
+}
+
+// openJiraPrompt asks for the key of a Jira issue to add to the open list
+func (m *Model) openJiraPrompt() {
+	if m.Jira == nil || m.Jira.URL == "" || m.Jira.Token == "" {
+		m.toast(SeverityInfo, i18n.T("Set jira.url and jira.token in the config to import issues"))
+		return
+	}
+	m.openDialog(JiraPrompt)
+	m.InputText = ""
+	m.toast(SeverityInfo, i18n.T("Jira issue to import, like PROJ-123"))
+}
+
+// submitJiraPrompt fetches the issue typed at the prompt in the background, or
+// moves to its todo when it was imported before
+func (m Model) submitJiraPrompt() (tea.Model, tea.Cmd) {
+	m.closeDialog()
+	key, ok := todo.IssueKey(m.InputText)
+	if !ok {
+		m.toast(SeverityInfo, i18n.Tf("Not an issue key: %s", m.InputText))
+		return m, nil
+	}
+	if i := m.TodoList.IssueIndex(key); i >= 0 {
+		m.TodoCursor = i
+		m.toast(SeverityInfo, i18n.Tf("%s is already in this list", key))
+		return m, nil
+	}
+
+	m.toast(SeverityInfo, i18n.Tf("Fetching %s…", key))
+	jira, path := *m.Jira, m.TodoList.Path()
+	return m, runProgress(i18n.T("Fetching Jira issue"), func(func(done, total int)) tea.Msg {
+		issue, err := fetchJiraIssue(jira, key)
+		return jiraIssueMsg{path: path, key: key, issue: issue, err: err}
+	})
+}
+
+// handleJiraIssue adds a fetched issue to the list it was imported into, tagged with
+// the active context so it stays visible
+func (m Model) handleJiraIssue(msg jiraIssueMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.toast(SeverityError, i18n.Tf("Couldn't import %s: %v", msg.key, msg.err))
+		return m, nil
+	}
+	tl := m.TodoList
+	if tl.Path() != msg.path {
+		tl = todo.NewTodoList(msg.path) // switched lists while the issue was fetched
+		tl.SetAuthor(m.Author)
+	}
+	if tl.IssueIndex(msg.issue.Key) >= 0 {
+		m.toast(SeverityInfo, i18n.Tf("%s is already in this list", msg.issue.Key))
+		return m, nil
+	}
+
+	issue := msg.issue
+	if m.ActiveContext != "" && !(todo.Todo{Title: issue.Summary}).HasContext(m.ActiveContext) {
+		issue.Summary += " @" + m.ActiveContext
+	}
+	tl.AddIssue(issue)
+	if tl == m.TodoList {
+		m.TodoCursor = tl.IndexOf(tl.NextID - 1)
+	}
+	m.toast(SeverityInfo, i18n.Tf("Imported %s", issue.Key))
+	return m, nil
+}
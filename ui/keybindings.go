@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+)
+
+// openKeybindings shows the keybinding editor
+func (m *Model) openKeybindings() {
+	if m.Keys.lookup == nil {
+		m.Keys = DefaultKeymap()
+	}
+	m.openDialog(KeybindingEditor)
+	m.KeyCursor = 0
+	m.CapturingKey = false
+	m.toast(SeverityInfo, i18n.T("Keybindings"))
+}
+
+// handleKeybindings handles input in the keybinding editor
+func (m Model) handleKeybindings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Waiting for the new key of the selected action
+	if m.CapturingKey {
+		m.CapturingKey = false
+		if key == "esc" {
+			m.toast(SeverityInfo, i18n.T("Cancelled"))
+			return m, nil
+		}
+
+		action := actions[m.KeyCursor].action
+		if err := m.Keys.Rebind(action, key); err != nil {
+			m.toast(SeverityError, err.Error())
+			return m, nil
+		}
+		if err := m.saveKeybinding(action); err != nil {
+			m.toast(SeverityError, err.Error())
+			return m, nil
+		}
+		m.toast(SeverityInfo, i18n.Tf("%s bound to %s", action, keyLabel(key)))
+		return m, nil
+	}
+
+	switch key {
+	case "j", "down":
+		if m.KeyCursor < len(actions)-1 {
+			m.KeyCursor++
+		}
+	case "k", "up":
+		if m.KeyCursor > 0 {
+			m.KeyCursor--
+		}
+	case "enter":
+		m.CapturingKey = true
+		m.toast(SeverityInfo, i18n.Tf("Press new key for %s (Esc to cancel)", i18n.T(actions[m.KeyCursor].description)))
+	case "esc", "q":
+		m.closeDialog()
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// saveKeybinding persists an action's keys to the config file
+func (m Model) saveKeybinding(action Action) error {
+	if m.ConfigPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Keys == nil {
+		cfg.Keys = map[string][]string{}
+	}
+	cfg.Keys[string(action)] = m.Keys.Keys(action)
+	return config.Save(m.ConfigPath, cfg)
+}
+
+// renderKeybindings renders the keybinding editor overlay
+func (m Model) renderKeybindings() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorMauve).
+		Padding(1, 3)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorMauve).
+		Bold(true).
+		Render(i18n.T("󰌌 Keybindings"))
+
+	content := title + "\n\n"
+	for i, info := range actions {
+		var labels []string
+		for _, key := range m.Keys.Keys(info.action) {
+			labels = append(labels, keyLabel(key))
+		}
+		keys := strings.Join(labels, ", ")
+		if i == m.KeyCursor && m.CapturingKey {
+			keys = "press a key…"
+		}
+
+		line := fmt.Sprintf("%-26s %s", i18n.T(info.description), keys)
+		if i == m.KeyCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+line+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+line) + "\n"
+		}
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
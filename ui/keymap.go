@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action is a rebindable normal-mode command
+type Action string
+
+const (
+	ActionQuit         Action = "quit"
+	ActionBack         Action = "back"
+	ActionLeft         Action = "left"
+	ActionRight        Action = "right"
+	ActionSwitchPanel  Action = "switch_panel"
+	ActionDown         Action = "down"
+	ActionUp           Action = "up"
+	ActionOpen         Action = "open"
+	ActionSelect       Action = "select"
+	ActionShowArchive  Action = "show_archive"
+	ActionAdd          Action = "add"
+	ActionEdit         Action = "edit"
+	ActionDelete       Action = "delete"
+	ActionToggle       Action = "toggle"
+	ActionDue          Action = "due"
+	ActionRemind       Action = "remind"
+	ActionStart        Action = "start"
+	ActionAckReminders Action = "ack_reminders"
+	ActionHeading      Action = "heading"
+	ActionSectionDown  Action = "section_down"
+	ActionSectionUp    Action = "section_up"
+	ActionSplit        Action = "split"
+	ActionEditor       Action = "editor"
+	ActionBulkEdit     Action = "bulk_edit"
+	ActionContext      Action = "context"
+	ActionArchive      Action = "archive"
+	ActionKeybindings  Action = "keybindings"
+	ActionSettings     Action = "settings"
+	ActionListSettings Action = "list_settings"
+	ActionToday        Action = "today"
+	ActionFlag         Action = "flag"
+	ActionFocus        Action = "focus"
+	ActionHabit        Action = "habit"
+	ActionTemplate     Action = "template"
+	ActionFollowLink   Action = "follow_link"
+	ActionFold         Action = "fold"
+	ActionField        Action = "field"
+	ActionFieldFilter  Action = "field_filter"
+	ActionCopy         Action = "copy"
+	ActionCopyList     Action = "copy_list"
+	ActionCopyLink     Action = "copy_link"
+	ActionShrinkFiles  Action = "shrink_files"
+	ActionGrowFiles    Action = "grow_files"
+	ActionMaximize     Action = "maximize"
+	ActionZen          Action = "zen"
+	ActionClearFilters Action = "clear_filters"
+	ActionGoto         Action = "goto"
+	ActionGotoPrompt   Action = "goto_prompt"
+	ActionScratch      Action = "scratch"
+	ActionPromote      Action = "promote"
+	ActionCheck        Action = "check"
+	ActionRunChecks    Action = "run_checks"
+	ActionResolve      Action = "resolve"
+	ActionStats        Action = "stats"
+	ActionDebugLog     Action = "debug_log"
+	ActionHiddenFiles  Action = "hidden_files"
+	ActionTitle        Action = "title"
+	ActionPrevious     Action = "previous_file"
+	ActionMerge        Action = "merge_files"
+	ActionProgressUp   Action = "progress_up"
+	ActionProgressDown Action = "progress_down"
+	ActionSearch       Action = "search"
+	ActionSaveFilter   Action = "save_filter"
+	ActionViewSort     Action = "view_sort"
+	ActionViewColumns  Action = "view_columns"
+	ActionJira         Action = "jira"
+	ActionCompare      Action = "compare"
+)
+
+// actionInfo describes an action and its default keys
+type actionInfo struct {
+	action      Action
+	description string
+	keys        []string
+}
+
+// actions lists every rebindable action in display order
+var actions = []actionInfo{
+	{ActionQuit, "Quit", []string{"q"}},
+	{ActionBack, "Back to file panel", []string{"esc"}},
+	{ActionLeft, "Go to file panel", []string{"h", "left"}},
+	{ActionRight, "Go to todo panel", []string{"l", "right"}},
+	{ActionSwitchPanel, "Switch panel", []string{"tab"}},
+	{ActionDown, "Move down", []string{"j", "down"}},
+	{ActionUp, "Move up", []string{"k", "up"}},
+	{ActionGoto, "Go to line (count) or last", []string{"G"}},
+	{ActionGotoPrompt, "Go to line number", []string{":"}},
+	{ActionOpen, "Open / unarchive file", []string{"enter"}},
+	{ActionPrevious, "Jump back to the previous file", []string{"ctrl+o", "`", "ctrl+^"}},
+	{ActionSelect, "Mark file / toggle todo", []string{" "}},
+	{ActionShowArchive, "Show archived files", []string{"z"}},
+	{ActionAdd, "Add file / todo", []string{"a"}},
+	{ActionEdit, "Edit todo", []string{"i"}},
+	{ActionDelete, "Delete file / todo", []string{"d"}},
+	{ActionToggle, "Toggle todo", []string{"x"}},
+	{ActionDue, "Set due date", []string{"D"}},
+	{ActionRemind, "Set reminder", []string{"r"}},
+	{ActionStart, "Set start date (defer until)", []string{"w"}},
+	{ActionAckReminders, "Acknowledge reminders", []string{"R"}},
+	{ActionFlag, "Flag as priority", []string{"!"}},
+	{ActionFocus, "Toggle focus mode", []string{"F"}},
+	{ActionFollowLink, "Follow link", []string{"o"}},
+	{ActionCopy, "Copy todo title", []string{"y"}},
+	{ActionCopyList, "Copy list as Markdown", []string{"Y"}},
+	{ActionCopyLink, "Copy link to todo", []string{"g y"}},
+	{ActionField, "Set custom field", []string{"m"}},
+	{ActionJira, "Import Jira issue", []string{"g i"}},
+	{ActionFieldFilter, "Filter by field", []string{"="}},
+	{ActionSearch, "Search the open list", []string{"/"}},
+	{ActionSaveFilter, "Save search as a filter", []string{"W"}},
+	{ActionViewSort, "Sort Today view / saved filter", []string{"v"}},
+	{ActionViewColumns, "Toggle Today view / saved filter columns", []string{"V"}},
+	{ActionCheck, "Set check command", []string{"c"}},
+	{ActionRunChecks, "Run check commands", []string{"u"}},
+	{ActionClearFilters, "Clear all filters", []string{"C"}},
+	{ActionHeading, "Toggle section heading", []string{"H"}},
+	{ActionSectionDown, "Move to next section", []string{"J"}},
+	{ActionSectionUp, "Move to previous section", []string{"K"}},
+	{ActionFold, "Fold / unfold section", []string{"z a"}},
+	{ActionSplit, "Split filtered todos", []string{"S"}},
+	{ActionCompare, "Compare with another list", []string{"|"}},
+	{ActionEditor, "Open in $EDITOR", []string{"e"}},
+	{ActionBulkEdit, "Bulk edit shown todos in $EDITOR", []string{"E"}},
+	{ActionContext, "Switch context", []string{"@"}},
+	{ActionArchive, "Archive file", []string{"A"}},
+	{ActionMerge, "Merge marked files", []string{"&"}},
+	{ActionProgressUp, "Raise progress", []string{"+"}},
+	{ActionProgressDown, "Lower progress", []string{"-"}},
+	{ActionTitle, "Set list title", []string{"t"}},
+	{ActionHabit, "Toggle habit list", []string{"b"}},
+	{ActionScratch, "Toggle scratchpad", []string{"s"}},
+	{ActionPromote, "Move scratchpad todo to the open file", []string{"p"}},
+	{ActionTemplate, "New file from template", []string{"N"}},
+	{ActionResolve, "Merge sync conflicts", []string{"X"}},
+	{ActionKeybindings, "Keybindings", []string{"?", ","}},
+	{ActionSettings, "Settings", []string{"O"}},
+	{ActionListSettings, "List settings", []string{"P"}},
+	{ActionShrinkFiles, "Shrink file panel", []string{"ctrl+h"}},
+	{ActionGrowFiles, "Widen file panel", []string{"ctrl+l"}},
+	{ActionMaximize, "Maximize todo panel", []string{"M"}},
+	{ActionZen, "Toggle zen mode", []string{"Z"}},
+	{ActionToday, "Today view", []string{"T"}},
+	{ActionStats, "Stats and forecast", []string{"I"}},
+	{ActionDebugLog, "Debug log (with --debug)", []string{"L"}},
+	{ActionHiddenFiles, "Show hidden files", []string{"."}},
+}
+
+// Keymap maps keys to actions
+type Keymap struct {
+	bindings map[Action][]string
+	lookup   map[string]Action
+}
+
+// DefaultKeymap returns the built-in key bindings
+func DefaultKeymap() Keymap {
+	km, _ := NewKeymap(nil)
+	return km
+}
+
+// NewKeymap builds a keymap from the defaults plus per-action overrides.
+// Unknown actions and conflicting keys are reported in the returned error.
+func NewKeymap(overrides map[string][]string) (Keymap, error) {
+	km := Keymap{bindings: map[Action][]string{}, lookup: map[string]Action{}}
+	for _, info := range actions {
+		km.bindings[info.action] = info.keys
+	}
+
+	var problems []string
+	for name, keys := range overrides {
+		if !isAction(Action(name)) {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		km.bindings[Action(name)] = keys
+	}
+
+	for _, info := range actions {
+		for _, key := range km.bindings[info.action] {
+			if other, ok := km.lookup[key]; ok {
+				problems = append(problems, fmt.Sprintf("%q bound to both %s and %s", key, other, info.action))
+				continue
+			}
+			km.lookup[key] = info.action
+		}
+	}
+
+	if len(problems) > 0 {
+		return km, fmt.Errorf("keys: %s", strings.Join(problems, "; "))
+	}
+	return km, nil
+}
+
+// Action returns the action bound to key, or "" if none
+func (k Keymap) Action(key string) Action {
+	if k.lookup == nil {
+		return DefaultKeymap().Action(key)
+	}
+	return k.lookup[key]
+}
+
+// IsPrefix reports whether key starts a longer key sequence, like z in "z a".
+// Sequences are bound as space-separated keys.
+func (k Keymap) IsPrefix(key string) bool {
+	if k.lookup == nil {
+		return DefaultKeymap().IsPrefix(key)
+	}
+	for bound := range k.lookup {
+		if strings.HasPrefix(bound, key+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys bound to an action
+func (k Keymap) Keys(action Action) []string {
+	if k.bindings == nil {
+		return DefaultKeymap().Keys(action)
+	}
+	return k.bindings[action]
+}
+
+// Rebind replaces an action's keys with key, failing if key is used elsewhere
+func (k Keymap) Rebind(action Action, key string) error {
+	if other := k.lookup[key]; other != "" && other != action {
+		return fmt.Errorf("%s is already bound to %s", keyLabel(key), other)
+	}
+	for _, old := range k.bindings[action] {
+		delete(k.lookup, old)
+	}
+	k.bindings[action] = []string{key}
+	k.lookup[key] = action
+	return nil
+}
+
+// isAction reports whether a is a known action
+func isAction(a Action) bool {
+	for _, info := range actions {
+		if info.action == a {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLabel formats a key for display
+func keyLabel(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/config"
+	"justdoit/i18n"
+)
+
+// filePanelStep is how many percent ctrl+h/ctrl+l move the split
+const filePanelStep = 5
+
+// Smallest window the panels are drawn in; below it a placeholder is shown
+const (
+	minWidth  = 40
+	minHeight = 10
+)
+
+// panelWidths returns the content widths of the file and todo panels.
+// A maximized todo panel takes the whole window.
+func (m Model) panelWidths() (int, int) {
+	if m.Maximized {
+		return 0, m.Width - 2
+	}
+	percent := m.Behavior.FilePanelWidth
+	if percent == 0 {
+		percent = config.Default().Behavior.FilePanelWidth
+	}
+	left := m.Width * percent / 100
+	return left, max(m.Width-left-4, 0)
+}
+
+// tooSmall reports whether the window is too small to draw the panels in
+func (m Model) tooSmall() bool {
+	return m.Width < minWidth || m.Height < minHeight
+}
+
+// renderTooSmall renders the placeholder shown instead of the panels in a tiny window
+func (m Model) renderTooSmall() string {
+	lines := []string{
+		i18n.T("Window too small"),
+		i18n.Tf("%dx%d, needs %dx%d", m.Width, m.Height, minWidth, minHeight),
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, max(m.Width, 0), "…")
+	}
+	text := m.Styles.Muted.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(max(m.Width, 0), max(m.Height, 0), lipgloss.Center, lipgloss.Center, text)
+}
+
+// resizePanels moves the split between the panels and saves it to the config
+func (m *Model) resizePanels(step int) {
+	m.Maximized = false
+	width := min(max(m.Behavior.FilePanelWidth+step, config.MinFilePanelWidth), config.MaxFilePanelWidth)
+	if width == m.Behavior.FilePanelWidth {
+		return
+	}
+	m.Behavior.FilePanelWidth = width
+	if err := m.saveBehavior(); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+	m.toast(SeverityInfo, i18n.Tf("File panel width: %d%%", width))
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/config"
+	"justdoit/i18n"
+)
+
+// lineNumberChoices are the line number styles the settings screen cycles through
+var lineNumberChoices = []string{config.LineNumbersOff, config.LineNumbersAbsolute, config.LineNumbersRelative}
+
+// cursorLine returns the cursor's 0-based position among the visible rows
+func (m Model) cursorLine(visible []int) int {
+	return sort.SearchInts(visible, m.TodoCursor)
+}
+
+// renderLineNumber renders the gutter for the row at pos, or "" when line numbers are off.
+// Relative numbers count from the cursor, which shows its own absolute number like vim.
+func (m Model) renderLineNumber(pos, cursor, count int) string {
+	mode := m.Behavior.LineNumbers
+	if mode != config.LineNumbersAbsolute && mode != config.LineNumbersRelative {
+		return ""
+	}
+	n := pos + 1
+	if mode == config.LineNumbersRelative && pos != cursor {
+		n = max(pos-cursor, cursor-pos)
+	}
+	width := len(strconv.Itoa(count))
+	if pos < 0 {
+		return strings.Repeat(" ", width+1) // rows that aren't todos, like the add input
+	}
+	return m.Styles.Muted.Render(fmt.Sprintf("%*d ", width, n))
+}
+
+// jumpToLine moves the cursor to the nth visible row, counting from 1
+func (m *Model) jumpToLine(n int) {
+	visible := m.visibleIndices()
+	if n < 1 || n > len(visible) {
+		m.toast(SeverityInfo, i18n.Tf("No line %d", n))
+		return
+	}
+	m.TodoCursor = visible[n-1]
+}
+
+// openGotoPrompt asks for a line number to jump to
+func (m *Model) openGotoPrompt() {
+	m.openDialog(GotoPrompt)
+	m.InputText = ""
+	m.toast(SeverityInfo, i18n.T("Go to line"))
+}
+
+// submitGotoPrompt jumps to the line typed at the : prompt
+func (m Model) submitGotoPrompt() (tea.Model, tea.Cmd) {
+	m.closeDialog()
+	m.StatusMessage = ""
+	n, err := strconv.Atoi(strings.TrimSpace(m.InputText))
+	if err != nil {
+		m.toast(SeverityInfo, i18n.Tf("Not a line number: %s", m.InputText))
+		return m, nil
+	}
+	m.jumpToLine(n)
+	return m, nil
+}
+
+// countPrefix reports whether key extends a vim-style count like the 12 in 12G.
+// Digits count only in the todo panel and only when they aren't bound to an action.
+func (m Model) countPrefix(key string) bool {
+	if m.ActivePanel != TodoPanel || len(key) != 1 || key[0] < '0' || key[0] > '9' {
+		return false
+	}
+	if key == "0" && m.count == "" {
+		return false
+	}
+	return m.Keys.Action(key) == ""
+}
+
+// takeCount returns the typed count, or 0 when none was typed, and resets it
+func (m *Model) takeCount() int {
+	n, _ := strconv.Atoi(m.count)
+	m.count = ""
+	return n
+}
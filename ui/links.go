@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+// followLink opens the first list or todo referenced in the selected todo's title
+func (m *Model) followLink() {
+	if m.TodoCursor >= len(m.TodoList.Todos) {
+		return
+	}
+	links := todo.Links(m.TodoList.Todos[m.TodoCursor].Title)
+	if len(links) == 0 {
+		m.toast(SeverityInfo, i18n.T("No link in this todo"))
+		return
+	}
+	link := links[0]
+
+	path := m.TodoList.Path()
+	name := m.CurrentFile
+	if link.File != "" {
+		name = link.File + ".json"
+		path = filepath.Join(m.TodoDir, name)
+		if _, err := os.Stat(path); err != nil {
+			m.toast(SeverityInfo, i18n.Tf("No list named %s", link.File))
+			return
+		}
+	}
+
+	m.jumpTo(search.Hit{File: path, ID: link.ID})
+	if link.ID > 0 && m.TodoList.IndexOf(link.ID) < 0 {
+		m.toast(SeverityInfo, i18n.Tf("No todo #%d in %s", link.ID, name))
+	}
+}
+
+// copyLink copies a Markdown link to the selected todo, which opens justdoit at it
+// from notes apps and browsers
+func (m *Model) copyLink() {
+	if m.TodoCursor >= len(m.TodoList.Todos) || m.TodoList.Todos[m.TodoCursor].Heading {
+		return
+	}
+	t := m.TodoList.Todos[m.TodoCursor]
+	path, id := m.TodoList.Path(), t.ID
+	if m.virtual != nil {
+		hit, ok := m.virtual.sources[t.ID]
+		if !ok {
+			return
+		}
+		path, id = hit.File, hit.ID
+	}
+	if m.TodoList.IsScratch() || filepath.Dir(path) != filepath.Clean(m.TodoDir) {
+		m.toast(SeverityInfo, i18n.T("Only todos in an open list have links"))
+		return
+	}
+
+	title := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(t.Title)
+	if err := clipboardWrite("[" + title + "](" + todo.URL(filepath.Base(path), id) + ")"); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+	m.toast(SeverityInfo, i18n.T("Copied link to todo"))
+}
+
+// renderTitle renders a title in style with its links underlined and its tags marked
+func (m Model) renderTitle(title string, style lipgloss.Style) string {
+	links := todo.Links(title)
+	if len(links) == 0 {
+		return m.renderTags(title, style)
+	}
+
+	linkStyle := style.Foreground(ColorSapphire).Underline(true)
+	var out string
+	prev := 0
+	for _, link := range links {
+		if link.Start > prev {
+			out += m.renderTags(title[prev:link.Start], style)
+		}
+		out += linkStyle.Render(title[link.Start:link.End])
+		prev = link.End
+	}
+	if prev < len(title) {
+		out += m.renderTags(title[prev:], style)
+	}
+	return out
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// listSettingNames labels the rows of the list settings screen
+var listSettingNames = []string{
+	"Sort todos",
+	"Completed todos",
+	"Tags for new todos",
+	"Color",
+	"Created from template",
+}
+
+// listSorts are the orders the list settings screen cycles through
+var listSorts = []string{todo.SortManual, todo.SortDue, todo.SortTitle, todo.SortCreated}
+
+// listColors are the colors a list can be shown in, by the name saved in its file
+var listColors = map[string]lipgloss.Color{
+	"red":    ColorRed,
+	"peach":  ColorPeach,
+	"yellow": ColorYellow,
+	"green":  ColorGreen,
+	"teal":   ColorTeal,
+	"blue":   ColorBlue,
+	"mauve":  ColorMauve,
+	"pink":   ColorPink,
+}
+
+// listColorNames are the names of listColors in the order they are cycled through,
+// "" for none
+var listColorNames = []string{"", "red", "peach", "yellow", "green", "teal", "blue", "mauve", "pink"}
+
+// openListSettings shows the settings of the open list
+func (m *Model) openListSettings() {
+	if m.TodoList.IsScratch() {
+		m.toast(SeverityInfo, i18n.T("The scratchpad has no settings, it isn't saved"))
+		return
+	}
+	m.openDialog(ListSettingScreen)
+	m.SettingsCursor = 0
+	m.toast(SeverityInfo, i18n.Tf("Settings of %s", m.listName()))
+}
+
+// handleListSettings handles input in the list settings screen
+func (m Model) handleListSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.SettingsCursor < len(listSettingNames)-1 {
+			m.SettingsCursor++
+		}
+	case "k", "up":
+		if m.SettingsCursor > 0 {
+			m.SettingsCursor--
+		}
+	case "enter", " ", "l", "right":
+		m.changeListSetting(1)
+	case "h", "left":
+		m.changeListSetting(-1)
+	case "esc", "q":
+		m.closeDialog()
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// cycle returns the choice step places after current, wrapping around
+func cycle(choices []string, current string, step int) string {
+	i := max(slices.Index(choices, current), 0)
+	return choices[(i+step+len(choices))%len(choices)]
+}
+
+// changeListSetting steps the selected setting of the open list, or asks for its tags
+func (m *Model) changeListSetting(step int) {
+	s := m.TodoList.ListSettings()
+	switch m.SettingsCursor {
+	case 0:
+		s.Sort = cycle(listSorts, s.Sort, step)
+	case 1:
+		// Follow the global setting, then sink, then keep their place
+		switch {
+		case s.ManualOrder == nil:
+			manual := false
+			s.ManualOrder = &manual
+		case !*s.ManualOrder:
+			*s.ManualOrder = true
+		default:
+			s.ManualOrder = nil
+		}
+	case 2:
+		m.pushDialog(ListTagsPrompt)
+		m.InputText = ""
+		for _, tag := range s.Tags {
+			m.InputText += "@" + tag + " "
+		}
+		m.toast(SeverityInfo, i18n.T("Tags added to new todos, like @home @errands (empty clears)"))
+		return
+	case 3:
+		s.Color = cycle(listColorNames, s.Color, step)
+		m.noteColor(s.Color)
+	case 4:
+		m.toast(SeverityInfo, i18n.T("Set when a list is created from a template"))
+		return
+	}
+	m.TodoList.SetSettings(s)
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.T("List settings saved"))
+}
+
+// submitListTags sets the tags typed in the prompt as the open list's tags
+func (m Model) submitListTags() (tea.Model, tea.Cmd) {
+	s := m.TodoList.ListSettings()
+	s.Tags = nil
+	for _, tag := range strings.Fields(strings.ReplaceAll(m.InputText, ",", " ")) {
+		tag = strings.TrimLeft(tag, "@")
+		if tag != "" && !slices.Contains(s.Tags, tag) {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	m.TodoList.SetSettings(s)
+	m.closeDialog()
+	m.toast(SeverityInfo, i18n.T("List settings saved"))
+	return m, nil
+}
+
+// noteColor records the open list's new color for the file panel
+func (m *Model) noteColor(color string) {
+	colors := maps.Clone(m.Colors)
+	if colors == nil {
+		colors = map[string]string{}
+	}
+	if color == "" {
+		delete(colors, m.CurrentFile)
+	} else {
+		colors[m.CurrentFile] = color
+	}
+	m.Colors = colors
+}
+
+// listSettingValue formats the open list's value of a list settings row
+func (m Model) listSettingValue(i int) string {
+	s := m.TodoList.ListSettings()
+	switch i {
+	case 0:
+		switch s.Sort {
+		case todo.SortDue:
+			return i18n.T("by due date")
+		case todo.SortTitle:
+			return i18n.T("by title")
+		case todo.SortCreated:
+			return i18n.T("newest first")
+		}
+		return i18n.T("as added")
+	case 1:
+		switch {
+		case s.ManualOrder == nil:
+			return i18n.T("like all lists")
+		case *s.ManualOrder:
+			return i18n.T("keep their place")
+		}
+		return i18n.T("move to the bottom")
+	case 2:
+		if len(s.Tags) == 0 {
+			return i18n.T("none")
+		}
+		return "@" + strings.Join(s.Tags, " @")
+	case 3:
+		if s.Color == "" {
+			return i18n.T("none")
+		}
+		return lipgloss.NewStyle().Foreground(listColors[s.Color]).Render("● " + s.Color)
+	case 4:
+		if s.Template == "" {
+			return i18n.T("none")
+		}
+		return s.Template
+	}
+	return ""
+}
+
+// renderListSettings renders the list settings screen overlay
+func (m Model) renderListSettings() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorMauve).
+		Padding(1, 3)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorMauve).
+		Bold(true).
+		Render(i18n.Tf(" Settings of %s", m.listName()))
+
+	content := title + "\n\n"
+	for i, name := range listSettingNames {
+		line := fmt.Sprintf("%-24s %s", i18n.T(name), m.listSettingValue(i))
+		if i == m.SettingsCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+line+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+line) + "\n"
+		}
+	}
+	if m.Dialog == ListTagsPrompt {
+		content += "\n" + m.Styles.Edit.Render(i18n.T("Tags")+": "+m.InputText+"█")
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// listColor returns the color the open list's title is shown in
+func (m Model) listColor() (lipgloss.Color, bool) {
+	if m.TodoList == nil || m.TodoList.Settings == nil || m.virtual != nil {
+		return "", false
+	}
+	c, ok := listColors[m.TodoList.Settings.Color]
+	return c, ok
+}
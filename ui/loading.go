@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// asyncLoadSize is the file size above which lists are loaded in the background
+const asyncLoadSize = 256 << 10
+
+// spinnerFrames are drawn in turn while a list loads
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// listLoadedMsg carries a list loaded in the background
+type listLoadedMsg struct {
+	gen  int // ignored when another file was opened since
+	list *todo.TodoList
+}
+
+// openList saves pending changes and switches to another list, loading large
+// files in the background so the file panel stays responsive
+func (m *Model) openList(path string) tea.Cmd {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() < asyncLoadSize {
+		m.setList(path)
+		return nil
+	}
+
+	if m.TodoList != nil {
+		m.TodoList.Flush()
+	}
+	m.loadGen++
+	m.Loading = path
+	m.spinnerFrame = 0
+
+	gen := m.loadGen
+	newList := m.newList // bound to a copy of the model, safe to call in the background
+	// Shown by the todo panel, not the status bar
+	return runProgress("", func(func(done, total int)) tea.Msg {
+		return listLoadedMsg{gen: gen, list: newList(path)}
+	})
+}
+
+// handleListLoaded shows a list once its background load finishes
+func (m Model) handleListLoaded(msg listLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.loadGen || m.Loading == "" {
+		return m, nil
+	}
+	m.TodoList = msg.list
+	m.Loading = ""
+	m.virtual = nil
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+	return m, checkReminders(m.TodoDir, false)
+}
+
+// loadingAllows reports whether an action may run while a list is loading.
+// Only navigation is allowed; anything touching the todos waits for the load.
+func loadingAllows(action Action, panel Panel) bool {
+	switch action {
+	case ActionQuit, ActionBack, ActionLeft, ActionRight, ActionSwitchPanel,
+		ActionShrinkFiles, ActionGrowFiles, ActionMaximize, ActionZen:
+		return true
+	case ActionDown, ActionUp, ActionOpen, ActionSelect, ActionShowArchive:
+		return panel == FilePanel
+	}
+	return false
+}
+
+// renderLoading renders the spinner shown in the todo panel during a load
+func (m Model) renderLoading() string {
+	spinner := m.Styles.Edit.Render(spinnerFrames[m.spinnerFrame])
+	return fmt.Sprintf("  %s  %s", spinner, m.Styles.Muted.Render(i18n.Tf("Loading %s…", filepath.Base(m.Loading))))
+}
@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// batchOp is a change to every marked file, waiting for its count to be confirmed
+type batchOp struct {
+	action Action   // ActionArchive, ActionDelete, ActionMerge or ActionCopyList
+	files  []string // marked files, in the order listed
+	into   string   // file the others are merged into
+}
+
+// markedFiles returns the marked files that are still listed, in the order listed
+func (m Model) markedFiles() []string {
+	var marked []string
+	for _, f := range m.Files {
+		if m.Marked[f] {
+			marked = append(marked, f)
+		}
+	}
+	return marked
+}
+
+// toggleMark marks or unmarks the file under the cursor and moves on to the next one
+func (m *Model) toggleMark() tea.Cmd {
+	if m.FileCursor >= len(m.Files) {
+		return nil
+	}
+	file := m.Files[m.FileCursor]
+	if m.Foreign[file] {
+		m.toast(SeverityInfo, i18n.T("Only todo lists can be marked"))
+		return nil
+	}
+	marked := maps.Clone(m.Marked)
+	if marked == nil {
+		marked = map[string]bool{}
+	}
+	if marked[file] {
+		delete(marked, file)
+	} else {
+		marked[file] = true
+	}
+	m.Marked = marked
+	m.toast(SeverityInfo, i18n.Tf("%d marked", len(m.markedFiles())))
+
+	if m.FileCursor < len(m.Files)-1 {
+		m.FileCursor++
+		return m.previewFile()
+	}
+	return nil
+}
+
+// clearMarks unmarks every file
+func (m *Model) clearMarks() {
+	m.Marked = nil
+	m.toast(SeverityInfo, i18n.T("Marks cleared"))
+}
+
+// startBatch asks before running action on every marked file
+func (m *Model) startBatch(action Action) {
+	files := m.markedFiles()
+	op := batchOp{action: action, files: files}
+	if action == ActionMerge {
+		if len(files) < 2 {
+			m.toast(SeverityInfo, i18n.T("Mark at least two files to merge"))
+			return
+		}
+		op.into = files[0]
+		if m.FileCursor < len(m.Files) && m.Marked[m.Files[m.FileCursor]] {
+			op.into = m.Files[m.FileCursor]
+		}
+	}
+	m.batch = &op
+	m.openDialog(ConfirmBatch)
+	m.toast(SeverityInfo, m.batchQuestion()+" (y/n)")
+}
+
+// batchQuestion asks about the pending batch operation, with its count
+func (m Model) batchQuestion() string {
+	n := len(m.batch.files)
+	switch m.batch.action {
+	case ActionArchive:
+		return i18n.Tf("Archive %d files?", n)
+	case ActionDelete:
+		return i18n.Tf("Permanently delete %d files?", n)
+	case ActionMerge:
+		return i18n.Tf("Merge %d files into %s?", n, m.displayName(m.batch.into))
+	}
+	return i18n.Tf("Copy %d lists as Markdown?", n)
+}
+
+// handleBatch handles input in the batch prompt
+func (m Model) handleBatch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		cmd := m.runBatch()
+		return m, cmd
+	case "p", "P":
+		m.openPreview()
+	case "n", "N", "esc":
+		m.batch = nil
+		m.closeDialog()
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// batchDoneMsg reports a batch operation that ran in the background
+type batchDoneMsg struct {
+	op       batchOp
+	archived map[string]todo.Header // archived files by their name in the archive
+	renamed  int                    // archived files renamed to keep earlier archives
+	err      error                  // why nothing was merged
+	listing  fileListing
+}
+
+// runBatch runs the confirmed batch operation, in the background unless it only
+// copies, and unmarks the files
+func (m *Model) runBatch() tea.Cmd {
+	op := *m.batch
+	m.batch = nil
+	m.closeDialog()
+	m.TodoList.Flush() // the open list may be one of them
+
+	if op.action == ActionCopyList {
+		lists := make([]string, len(op.files))
+		for i, f := range op.files {
+			lists[i] = todo.NewTodoList(filepath.Join(m.TodoDir, f)).Markdown(m.displayName(f))
+		}
+		if err := clipboardWrite(strings.Join(lists, "\n")); err != nil {
+			m.toast(SeverityError, err.Error())
+			return nil // keep the marks to try again
+		}
+		m.toast(SeverityInfo, i18n.Tf("Copied %d lists as Markdown", len(op.files)))
+		m.Marked = nil
+		m.reopenCurrent()
+		return nil
+	}
+
+	now := time.Now()
+	names := make([]string, len(op.files))
+	for i, f := range op.files {
+		names[i] = m.archiveName(f, now)
+	}
+	todoDir, archiveDir, hidden := m.TodoDir, m.ArchiveDir, m.ShowHidden
+	var label string
+	switch op.action {
+	case ActionArchive:
+		label = i18n.Tf("Archiving %d files", len(op.files))
+	case ActionDelete:
+		label = i18n.Tf("Deleting %d files", len(op.files))
+	case ActionMerge:
+		label = i18n.Tf("Merging %d files", len(op.files))
+	}
+	return m.startMove(label, func(report func(done, total int)) tea.Msg {
+		done := batchDoneMsg{op: op}
+		switch op.action {
+		case ActionArchive:
+			done.archived = map[string]todo.Header{}
+			for i, f := range op.files {
+				name := names[i]
+				if free := freeName(archiveDir, name); free != name {
+					name = free
+					done.renamed++
+				}
+				done.archived[name] = archiveFile(todoDir, archiveDir, f, name, now)
+				report(i+1, len(op.files))
+			}
+		case ActionDelete:
+			for i, f := range op.files {
+				path := filepath.Join(todoDir, f)
+				logFileErr("delete file", path, todo.Remove(path))
+				report(i+1, len(op.files))
+			}
+		case ActionMerge:
+			dst := todo.NewTodoList(filepath.Join(todoDir, op.into))
+			var paths []string
+			for _, f := range op.files {
+				if f != op.into {
+					paths = append(paths, filepath.Join(todoDir, f))
+				}
+			}
+			if _, done.err = dst.Absorb(paths...); done.err != nil {
+				logFileErr("merge file", dst.Path(), done.err)
+			}
+		}
+		done.listing = readListing(todoDir, hidden)
+		return done
+	})
+}
+
+// handleBatchDone shows the files after a batch operation and unmarks them
+func (m Model) handleBatchDone(msg batchDoneMsg) (tea.Model, tea.Cmd) {
+	m.moving = false
+	m.setListing(msg.listing)
+
+	op := msg.op
+	switch op.action {
+	case ActionArchive:
+		for name, h := range msg.archived {
+			m.noteArchived(name, h)
+		}
+		m.toast(SeverityInfo, i18n.Tf("Archived %d files", len(op.files)))
+		if msg.renamed > 0 {
+			m.StatusMessage += ", " + i18n.Tf("%d renamed to keep earlier archives", msg.renamed)
+		}
+	case ActionDelete:
+		m.toast(SeverityInfo, i18n.Tf("Deleted %d files", len(op.files)))
+	case ActionMerge:
+		if msg.err != nil {
+			m.toast(SeverityError, i18n.Tf("Nothing merged: %v", msg.err))
+			return m, nil // keep the marks to try again
+		}
+		m.toast(SeverityInfo, i18n.Tf("Merged %d files into %s", len(op.files), m.displayName(op.into)))
+	}
+
+	m.Marked = nil
+	m.reopenCurrent()
+	return m, nil
+}
+
+// archiveFile moves a file in todoDir that isn't necessarily open to archiveDir under
+// name, returning what it was archived with
+func archiveFile(todoDir, archiveDir, file, name string, now time.Time) todo.Header {
+	src := filepath.Join(todoDir, file)
+	dst := filepath.Join(archiveDir, name)
+	original := ""
+	if name != file {
+		original = file
+	}
+	tl := todo.NewTodoList(src)
+	tl.SetArchived(now, original)
+	logFileErr("archive file", dst, todo.Rename(src, dst))
+	return todo.Header{Title: tl.Title, Archived: now, Original: original}
+}
+
+// reopenCurrent loads the current file again after a batch operation, or the first
+// file when it's gone
+func (m *Model) reopenCurrent() {
+	if !slices.Contains(m.Files, m.CurrentFile) {
+		if len(m.Files) == 0 {
+			m.CurrentFile = "default.json"
+			todo.NewTodoList(filepath.Join(m.TodoDir, m.CurrentFile)).Save()
+			m.loadFiles()
+		}
+		m.CurrentFile = m.Files[0]
+		m.TodoCursor = 0
+	}
+	m.TodoList = m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
+	m.clampTodoCursor()
+	m.ActivePanel = FilePanel
+	m.FileCursor = slices.Index(m.Files, m.CurrentFile)
+}
+
+// batchModal asks before running the batch operation, naming a few of the files
+func (m Model) batchModal() modal {
+	d := modal{
+		title:    i18n.T("󰒆 Marked files"),
+		question: m.batchQuestion(),
+		choices:  []choice{{"y", i18n.T(" Yes")}, {"n", i18n.T(" No, cancel")}, {"p", i18n.T(" Preview")}},
+		safe:     1,
+	}
+	switch m.batch.action {
+	case ActionDelete:
+		d.variant, d.choices[0].label = modalDanger, i18n.T(" Yes, delete")
+	case ActionArchive:
+		d.choices[0].label = i18n.T(" Yes, archive")
+	case ActionMerge:
+		d.choices[0].label = i18n.T(" Yes, merge")
+	}
+
+	// Name a few, the count says how many there are
+	const shown = 5
+	for _, f := range m.batch.files[:min(len(m.batch.files), shown)] {
+		d.details = append(d.details, m.displayName(f))
+	}
+	if more := len(m.batch.files) - shown; more > 0 {
+		d.details = append(d.details, i18n.Tf(" +%d more", more))
+	}
+	return d
+}
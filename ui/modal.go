@@ -0,0 +1,158 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// modalVariant is the color a modal is drawn in, by how much its answer changes
+type modalVariant int
+
+const (
+	modalInfo    modalVariant = iota // nothing is lost, like archiving
+	modalWarning                     // something is in the way, like a taken name
+	modalDanger                      // something is deleted for good
+)
+
+// color returns the border and title color of the variant
+func (v modalVariant) color() lipgloss.Color {
+	switch v {
+	case modalWarning:
+		return ColorYellow
+	case modalDanger:
+		return ColorRed
+	}
+	return ColorSapphire
+}
+
+// choice is a key answering a modal and what it does
+type choice struct {
+	key   string
+	label string // starts with a space, to stand apart from the key
+}
+
+// modal is a dialog box drawn over the panels, asking about something
+type modal struct {
+	variant  modalVariant
+	icon     string // shown before the title, "" for none
+	title    string
+	subject  string   // highlighted below the title, like a filename; "" for none
+	details  []string // muted lines below the subject
+	question string
+	choices  []choice
+	safe     int    // choice focused when the modal opens, the one changing the least
+	input    string // shown instead of the choices while a prompt is open over the modal
+}
+
+// openModal returns the modal the open dialog is shown as, false for dialogs that aren't one
+func (m Model) openModal() (modal, bool) {
+	switch m.Dialog {
+	case ConfirmDelete:
+		return m.deleteModal(), true
+	case ConfirmArchive:
+		return m.archiveModal(), true
+	case NameCollision, ArchiveNamePrompt:
+		return m.collisionModal(), true
+	case ConfirmBatch:
+		return m.batchModal(), true
+	case ConfirmQuit:
+		return m.quitModal(), true
+	case ConfirmBulkDelete:
+		return m.bulkModal(), true
+	case ConfirmChecks:
+		return m.checksModal(), true
+	}
+	return modal{}, false
+}
+
+// focusedChoice returns the choice Enter answers the modal with
+func (m Model) focusedChoice(d modal) int {
+	if i := m.modalFocus - 1; i >= 0 && i < len(d.choices) {
+		return i
+	}
+	return d.safe
+}
+
+// focusModal moves the focus between the choices of the open modal with the arrow
+// keys, h/l and tab, reporting true when it did. Enter is turned into the key of the
+// focused choice, so the modal answers it like the key itself.
+func (m *Model) focusModal(d modal, msg tea.KeyMsg) (tea.KeyMsg, bool) {
+	if d.input != "" || len(d.choices) == 0 {
+		return msg, false
+	}
+	i := m.focusedChoice(d)
+	switch msg.String() {
+	case "left", "h", "shift+tab":
+		m.modalFocus = (i-1+len(d.choices))%len(d.choices) + 1
+		return msg, true
+	case "right", "l", "tab":
+		m.modalFocus = (i+1)%len(d.choices) + 1
+		return msg, true
+	case "enter":
+		if d.choices[i].key == "esc" {
+			return tea.KeyMsg{Type: tea.KeyEscape}, false
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(d.choices[i].key)}, false
+	}
+	return msg, false
+}
+
+// renderModal renders a modal centered over where the panels are
+func (m Model) renderModal(d modal) string {
+	color := d.variant.color()
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(color).
+		Padding(2, 4).
+		Align(lipgloss.Center)
+
+	title := d.title
+	if d.icon != "" {
+		title = d.icon + " " + title
+	}
+	lines := []string{
+		lipgloss.NewStyle().Foreground(color).Bold(true).Render(title),
+		"",
+	}
+	if d.subject != "" {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(ColorLavender).
+			Background(ColorCrust).
+			Bold(true).
+			Padding(0, 1).
+			Render(d.subject))
+	}
+	for _, detail := range d.details {
+		lines = append(lines, m.Styles.Muted.Render(detail))
+	}
+	if len(d.details) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.Styles.Normal.Render(d.question), "")
+
+	if d.input != "" {
+		lines = append(lines, m.Styles.Edit.Render(d.input), m.Styles.Muted.Render(m.StatusMessage))
+	} else {
+		var options []string
+		focused := m.focusedChoice(d)
+		for i, c := range d.choices {
+			if i > 0 {
+				options = append(options, "    ")
+			}
+			label := m.Styles.Hint.Render(c.label)
+			if i == focused {
+				label = m.Styles.Selected.Bold(true).Render(c.label + " ")
+			}
+			options = append(options, m.Styles.HintKey.Render(" "+c.key+" "), label)
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, options...))
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(lipgloss.JoinVertical(lipgloss.Center, lines...)),
+	)
+}
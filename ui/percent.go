@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// percentCells is how many cells the bar of a partly done todo has
+const percentCells = 5
+
+// stepProgress moves the percent done of the todo under the cursor by step. Reaching
+// 100% completes it, and going below reopens it, just like toggling it.
+func (m *Model) stepProgress(step int) tea.Cmd {
+	if m.TodoCursor >= len(m.TodoList.Todos) || m.TodoList.Todos[m.TodoCursor].Heading {
+		return nil
+	}
+	if m.TodoList.IsHabit() {
+		m.toast(SeverityInfo, i18n.T("Habits are done or not, without progress"))
+		return nil
+	}
+	t := m.TodoList.Todos[m.TodoCursor]
+	percent := min(max(t.Percent()+step, 0), 100)
+	if percent == t.Percent() {
+		return nil
+	}
+	m.TodoList.SetProgress(m.TodoCursor, percent)
+	if (percent == 100) != t.Completed {
+		return m.toggleTodoWithArchivePrompt()
+	}
+	m.toast(SeverityInfo, i18n.Tf("Progress: %d%%", percent))
+	return nil
+}
+
+// renderPercent renders a bar of how far along a todo that isn't done yet is
+func (m Model) renderPercent(t todo.Todo) string {
+	if t.Completed || t.Progress == 0 {
+		return ""
+	}
+	filled := (t.Progress*percentCells + 50) / 100
+	bar := lipgloss.NewStyle().Foreground(ColorTeal).Render(strings.Repeat("▰", filled)) +
+		m.Styles.Muted.Render(strings.Repeat("▱", percentCells-filled))
+	return "  " + bar + m.Styles.Muted.Render(fmt.Sprintf(" %d%%", t.Progress))
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+)
+
+// pickerRows is how many matching lists the file picker shows at once
+const pickerRows = 10
+
+// filePicker holds the lists matching the name typed in the file picker
+type filePicker struct {
+	matches []string // best match first
+	cursor  int      // selected match
+}
+
+// OpenFilePicker asks for the list to open, found by typing part of its name.
+// Recently opened lists come first until something is typed.
+func (m *Model) OpenFilePicker() {
+	m.picker = &filePicker{}
+	m.openDialog(FilePicker)
+	m.InputText = ""
+	m.filterPicker()
+	m.toast(SeverityInfo, i18n.T("Find a list to open by typing part of its name"))
+}
+
+// pickerFiles returns the lists the file picker chooses from, the recent ones first
+func (m Model) pickerFiles() []string {
+	var files []string
+	for _, f := range m.Recent {
+		if slices.Contains(m.Files, f) {
+			files = append(files, f)
+		}
+	}
+	for _, f := range m.Files {
+		if !IsHiddenFile(f) && !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// filterPicker lists the files matching the typed name, best match first, and selects it
+func (m *Model) filterPicker() {
+	type match struct {
+		file  string
+		score int
+	}
+	var matches []match
+	for _, f := range m.pickerFiles() {
+		score, ok := fuzzyScore(m.InputText, strings.TrimSuffix(f, ".json"))
+		if title, found := m.Titles[f]; found {
+			if s, titleOK := fuzzyScore(m.InputText, title); titleOK && (!ok || s > score) {
+				score, ok = s, true
+			}
+		}
+		if ok {
+			matches = append(matches, match{f, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return b.score - a.score })
+
+	m.picker.matches = m.picker.matches[:0]
+	for _, match := range matches {
+		m.picker.matches = append(m.picker.matches, match.file)
+	}
+	m.picker.cursor = 0
+}
+
+// fuzzyScore reports whether the letters of query appear in name in order, ignoring
+// case, and scores the match: letters in a row and at the start of words count more
+func fuzzyScore(query, name string) (int, bool) {
+	q := []rune(strings.ToLower(strings.TrimSpace(query)))
+	if len(q) == 0 {
+		return 0, true
+	}
+	score, i, prev := 0, 0, -2
+	runes := []rune(strings.ToLower(name))
+	for j, r := range runes {
+		if i == len(q) {
+			break
+		}
+		if r != q[i] {
+			continue
+		}
+		score++
+		if prev == j-1 {
+			score += 2
+		}
+		if j == 0 || !unicode.IsLetter(runes[j-1]) && !unicode.IsDigit(runes[j-1]) {
+			score += 3
+		}
+		prev = j
+		i++
+	}
+	return score, i == len(q)
+}
+
+// handleFilePicker handles the keys moving through the file picker and choosing a
+// list. Anything else edits the typed name, and it reports false.
+func (m *Model) handleFilePicker(msg tea.KeyMsg) (tea.Cmd, bool) {
+	p := m.picker
+	switch msg.String() {
+	case "down", "ctrl+n", "ctrl+j":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case "up", "ctrl+p", "ctrl+k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "enter":
+		if len(p.matches) == 0 {
+			m.toast(SeverityInfo, i18n.Tf("No list matches %q", m.InputText))
+			return nil, true
+		}
+		file := p.matches[p.cursor]
+		m.closeDialog()
+		m.picker = nil
+		return m.openFile(file), true
+	case "esc":
+		m.closeDialog()
+		m.picker = nil
+		m.StatusMessage = ""
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// renderFilePicker renders the file picker overlay, keeping the selected match in view
+func (m Model) renderFilePicker() string {
+	pickerStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(1, 4)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.T("󰈔 Open List"))
+
+	content := title + "\n\n" + m.Styles.Edit.Render("  "+m.InputText+"█") + "\n\n"
+	p := m.picker
+	if len(p.matches) == 0 {
+		content += m.Styles.Dimmed.Italic(true).Render(i18n.T("   No matching list")) + "\n"
+	}
+	start := max(0, min(p.cursor-pickerRows+1, len(p.matches)-pickerRows))
+	for i := start; i < min(len(p.matches), start+pickerRows); i++ {
+		label := m.displayName(p.matches[i])
+		if i == p.cursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+label+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+label) + "\n"
+		}
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		pickerStyle.Render(content),
+	)
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// previewView lists what a confirmed operation would change, before it runs
+type previewView struct {
+	title  string
+	rows   []previewRow
+	scroll int // rows scrolled down from the top
+}
+
+// previewRow is a line of the preview: a group heading, or an item marked with how
+// it changes ('-' gone, '+' added, '~' changed, '>' moved)
+type previewRow struct {
+	mark byte // 0 on group headings
+	text string
+}
+
+// openPreview shows what the question open in the dialog would change if answered
+// with y, over the question
+func (m *Model) openPreview() {
+	var v previewView
+	switch m.Dialog {
+	case ConfirmBulkDelete:
+		p, err := m.TodoList.PreviewBulk(m.bulk.listed, m.bulk.lines, true)
+		if err != nil {
+			m.toast(SeverityInfo, i18n.Tf("Bulk edit not applied: %v", err))
+			return
+		}
+		v = bulkPreview(p)
+	case ConfirmBatch:
+		v = m.batchPreview()
+	default:
+		return
+	}
+	m.preview = &v
+	m.pushDialog(PreviewScreen)
+	m.toast(SeverityInfo, v.title)
+}
+
+// bulkPreview lists the todos a bulk edit deletes, updates and adds
+func bulkPreview(p todo.BulkPreview) previewView {
+	v := previewView{title: i18n.Tf("Bulk edit: %d added, %d updated, %d deleted", len(p.Added), len(p.Updated), len(p.Deleted))}
+	if len(p.Deleted) > 0 {
+		v.rows = append(v.rows, previewRow{text: i18n.Tf("Deleted: %d todos", len(p.Deleted))})
+		for _, t := range p.Deleted {
+			v.rows = append(v.rows, previewRow{'-', t.Title})
+		}
+	}
+	if len(p.Updated) > 0 {
+		v.rows = append(v.rows, previewRow{text: i18n.Tf("Updated: %d todos", len(p.Updated))})
+		for _, u := range p.Updated {
+			text := u.Before.Title
+			if u.After.Title != text {
+				text += " → " + u.After.Title
+			}
+			switch {
+			case u.After.Completed && !u.Before.Completed:
+				text += i18n.T(" (done)")
+			case !u.After.Completed && u.Before.Completed:
+				text += i18n.T(" (not done)")
+			}
+			v.rows = append(v.rows, previewRow{'~', text})
+		}
+	}
+	if len(p.Added) > 0 {
+		v.rows = append(v.rows, previewRow{text: i18n.Tf("Added: %d todos", len(p.Added))})
+		for _, l := range p.Added {
+			v.rows = append(v.rows, previewRow{'+', l.Title})
+		}
+	}
+	return v
+}
+
+// batchPreview lists the marked files with the todos the batch operation deletes,
+// archives, merges or copies
+func (m Model) batchPreview() previewView {
+	op := m.batch
+	v := previewView{title: m.batchQuestion()}
+	now := time.Now()
+	for _, f := range op.files {
+		if op.action == ActionMerge && f == op.into {
+			continue
+		}
+		tl := todo.NewTodoList(filepath.Join(m.TodoDir, f))
+		var todos []todo.Todo
+		for _, t := range tl.Todos {
+			if !t.Heading {
+				todos = append(todos, t)
+			}
+		}
+
+		name := m.displayName(f)
+		var heading string
+		var mark byte
+		switch op.action {
+		case ActionDelete:
+			heading, mark = i18n.Tf("%s, deleted with %d todos", name, len(todos)), '-'
+		case ActionArchive:
+			archived := freeName(m.ArchiveDir, m.archiveName(f, now))
+			heading, mark = i18n.Tf("%s, archived as %s with %d todos", name, archived, len(todos)), '>'
+		case ActionMerge:
+			heading, mark = i18n.Tf("%s, merged into %s with %d todos", name, m.displayName(op.into), len(todos)), '+'
+		default:
+			heading, mark = i18n.Tf("%s, copied with %d todos", name, len(todos)), ' '
+		}
+		v.rows = append(v.rows, previewRow{text: heading})
+		for _, t := range todos {
+			v.rows = append(v.rows, previewRow{mark, t.Title})
+		}
+	}
+	return v
+}
+
+// handlePreview handles input in the preview: y answers the question below it
+func (m Model) handlePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.preview
+	last := max(len(v.rows)-m.previewRows(), 0)
+	switch msg.String() {
+	case "j", "down":
+		v.scroll = min(v.scroll+1, last)
+	case "k", "up":
+		v.scroll = max(v.scroll-1, 0)
+	case "pgdown", "ctrl+d":
+		v.scroll = min(v.scroll+m.previewRows(), last)
+	case "pgup", "ctrl+u":
+		v.scroll = max(v.scroll-m.previewRows(), 0)
+	case "g":
+		v.scroll = 0
+	case "G":
+		v.scroll = last
+	case "y", "Y":
+		m.preview = nil
+		m.closeDialog()
+		return m.handleEditMode(msg)
+	case "esc", "q", "p":
+		m.preview = nil
+		m.closeDialog()
+	}
+	return m, nil
+}
+
+// previewRows is how many rows fit in the preview
+func (m Model) previewRows() int {
+	return max(m.Height-12, 1)
+}
+
+// renderPreview renders the rows of the preview that fit, with how many are left out
+func (m Model) renderPreview() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorYellow).
+		Padding(0, 1)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorYellow).
+		Bold(true).
+		Render(i18n.T("󰈈 Preview") + ": " + m.preview.title)
+
+	v := m.preview
+	width := max(m.Width-8, 20)
+	end := min(v.scroll+m.previewRows(), len(v.rows))
+	var rows []string
+	for _, row := range v.rows[v.scroll:end] {
+		style := m.Styles.Normal
+		text := "  " + string(row.mark) + " " + row.text
+		switch row.mark {
+		case 0:
+			style, text = m.Styles.Subtitle, row.text
+		case '-':
+			style = lipgloss.NewStyle().Foreground(ColorRed)
+		case '+':
+			style = lipgloss.NewStyle().Foreground(ColorGreen)
+		case '~', '>':
+			style = lipgloss.NewStyle().Foreground(ColorYellow)
+		}
+		rows = append(rows, style.Render(ansi.Truncate(text, width, "…")))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, m.Styles.Muted.Render(i18n.T("Nothing changes")))
+	}
+	if more := len(v.rows) - end; more > 0 {
+		rows = append(rows, m.Styles.Muted.Render(i18n.Tf("↓ %d more", more)))
+	}
+
+	content := title + "\n\n" + strings.Join(rows, "\n")
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// RenderList renders a list the way the todo panel shows it, under its name and
+// progress but without the panel around it or a cursor, for printing to a terminal
+func RenderList(tl *todo.TodoList, file string, behavior config.Behavior, highlights []Highlight) string {
+	m := Model{
+		TodoList:    tl,
+		CurrentFile: file,
+		Titles:      map[string]string{file: tl.Title},
+		Styles:      NewStyles(),
+		Behavior:    behavior,
+		Highlights:  highlights,
+		ActivePanel: FilePanel,
+	}
+
+	completed, total := tl.Counts()
+	title := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		m.Styles.Title.Render(fmt.Sprintf("   %s ", m.listName())),
+		" ",
+		m.Styles.Badge.Render(fmt.Sprintf(" %d/%d ", completed, total)),
+		m.renderHabitChip(),
+	)
+	content := m.renderTodoList()
+	if len(tl.Todos) == 0 {
+		content = m.Styles.Dimmed.Italic(true).Render(i18n.Tf("  %s  No todos yet", "󰄱")) + "\n"
+	}
+
+	out := title + "\n\n" + content
+	if behavior.Accessible {
+		return stripGlyphs(out)
+	}
+	return out
+}
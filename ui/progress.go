@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// progressDelay is how long an operation runs before it's shown, so quick ones
+// don't flash by
+const progressDelay = 250 * time.Millisecond
+
+// progressBarWidth is how many cells the bar of an operation with a known total takes
+const progressBarWidth = 20
+
+// progressIDs numbers the operations started with runProgress
+var progressIDs atomic.Int64
+
+// progress is an operation running in the background
+type progress struct {
+	id    int64
+	label string // "" for operations shown elsewhere, like a list loading in the todo panel
+	done  int
+	total int // 0 while unknown, only the spinner turns
+}
+
+// progressMsg reports how far a background operation got, or that it finished
+// with result
+type progressMsg struct {
+	progress
+	finished bool
+	result   tea.Msg
+	updates  <-chan progressMsg
+}
+
+// progressTickMsg turns the spinner of the running operations
+type progressTickMsg struct {
+	gen int
+}
+
+// runProgress runs work in the background, shown under label once it takes a while.
+// work calls report with how far it got, total 0 when it can't tell; what it returns
+// is handled as a message of its own.
+func runProgress(label string, work func(report func(done, total int)) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		updates := make(chan progressMsg, 1)
+		p := progress{id: progressIDs.Add(1), label: label}
+		var mu sync.Mutex
+		shown, finished := false, false
+
+		// Only the newest report waits to be read, older ones are dropped
+		send := func(msg progressMsg) {
+			msg.updates = updates
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- msg
+		}
+		timer := time.AfterFunc(progressDelay, func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if !finished {
+				shown = true
+				send(progressMsg{progress: p})
+			}
+		})
+		report := func(done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			changed := total == 0 || p.total == 0 || done*100/total != p.done*100/p.total
+			p.done, p.total = done, total
+			if shown && changed {
+				send(progressMsg{progress: p})
+			}
+		}
+
+		go func() {
+			result := work(report)
+			timer.Stop()
+			mu.Lock()
+			defer mu.Unlock()
+			finished = true
+			send(progressMsg{progress: p, finished: true, result: result})
+		}()
+		return <-updates
+	}
+}
+
+// waitProgress waits for the next report of an operation
+func waitProgress(updates <-chan progressMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// tickProgress schedules the next spinner frame
+func tickProgress(gen int) tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return progressTickMsg{gen: gen}
+	})
+}
+
+// handleProgress shows how far an operation got, and hands on its result once it's finished
+func (m Model) handleProgress(msg progressMsg) (tea.Model, tea.Cmd) {
+	running := slices.Clone(m.running)
+	i := slices.IndexFunc(running, func(p progress) bool { return p.id == msg.id })
+	if msg.finished {
+		if i >= 0 {
+			running = slices.Delete(running, i, i+1)
+		}
+		m.running = running
+		if msg.result == nil {
+			return m, nil
+		}
+		return m, func() tea.Msg { return msg.result }
+	}
+
+	var tick tea.Cmd
+	if i >= 0 {
+		running[i] = msg.progress
+	} else {
+		if len(running) == 0 {
+			m.progressGen++
+			tick = tickProgress(m.progressGen)
+		}
+		running = append(running, msg.progress)
+	}
+	m.running = running
+	return m, tea.Batch(tick, waitProgress(msg.updates))
+}
+
+// handleProgressTick turns the spinner while operations are running
+func (m Model) handleProgressTick(msg progressTickMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.progressGen || len(m.running) == 0 {
+		return m, nil
+	}
+	m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+	return m, tickProgress(msg.gen)
+}
+
+// shownProgress returns the running operations shown in the status bar
+func (m Model) shownProgress() []progress {
+	return slices.DeleteFunc(slices.Clone(m.running), func(p progress) bool { return p.label == "" })
+}
+
+// progressText describes how far an operation got: "Indexing lists 42%", or just
+// its label while the total is unknown
+func progressText(p progress) string {
+	if p.total <= 0 {
+		return p.label
+	}
+	return fmt.Sprintf("%s %d%%", p.label, min(p.done*100/p.total, 100))
+}
+
+// renderProgress renders the running operations, one line each with a spinner and,
+// when the total is known, a bar
+func (m Model) renderProgress() string {
+	var lines []string
+	for _, p := range m.shownProgress() {
+		line := m.Styles.Edit.Render(spinnerFrames[m.spinnerFrame]) + " " + progressText(p)
+		if p.total > 0 {
+			filled := min(p.done*progressBarWidth/p.total, progressBarWidth)
+			line += "  " + m.Styles.Edit.Render(strings.Repeat("━", filled)) +
+				m.Styles.Muted.Render(strings.Repeat("─", progressBarWidth-filled))
+		}
+		lines = append(lines, " "+line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+)
+
+// maxRecent is how many recently opened files are remembered
+const maxRecent = 5
+
+// recentFile holds the recently opened files in the todo directory. The dot keeps it
+// out of the file panel, backups and sync, it belongs to this machine.
+const recentFile = ".recent.json"
+
+// LoadRecent returns the recently opened files in dir, most recent first, leaving
+// out those that are gone
+func LoadRecent(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, recentFile))
+	if err != nil {
+		return nil
+	}
+	var recent []string
+	if json.Unmarshal(data, &recent) != nil {
+		return nil
+	}
+	return slices.DeleteFunc(recent, func(f string) bool {
+		_, err := os.Stat(filepath.Join(dir, f))
+		return err != nil || IsHiddenFile(f) || strings.ContainsAny(f, `/\`)
+	})
+}
+
+// noteRecent moves the file being left and the one opened to the front of the
+// recent files and saves them, unless in safe mode, where opening files writes nothing
+func (m *Model) noteRecent(opened string) {
+	recent := slices.Clone(m.Recent)
+	for _, f := range []string{m.CurrentFile, opened} {
+		if f == "" || IsHiddenFile(f) {
+			continue
+		}
+		recent = slices.DeleteFunc(recent, func(r string) bool { return r == f })
+		recent = slices.Insert(recent, 0, f)
+	}
+	m.Recent = recent[:min(len(recent), maxRecent)]
+	if m.Safe {
+		return
+	}
+
+	data, err := json.Marshal(m.Recent)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(m.TodoDir, recentFile), data, 0644)
+	}
+	if err != nil {
+		logFileErr("save recent files", filepath.Join(m.TodoDir, recentFile), err)
+	}
+}
+
+// recentShown returns the recent files listed above the files, leaving out the open one
+func (m Model) recentShown() []string {
+	if m.ShowingArchive {
+		return nil
+	}
+	var shown []string
+	for _, f := range m.Recent {
+		if f != m.CurrentFile && slices.Contains(m.Files, f) {
+			shown = append(shown, f)
+		}
+	}
+	return shown
+}
+
+// recentRows returns how many rows the recent group takes at the top of the file panel
+func (m Model) recentRows() int {
+	if n := len(m.recentShown()); n > 0 {
+		return n + 2 // header and a blank line below
+	}
+	return 0
+}
+
+// renderRecent renders the recent group shown above the files
+func (m Model) renderRecent() string {
+	shown := m.recentShown()
+	if len(shown) == 0 {
+		return ""
+	}
+	content := m.Styles.Separator.Render(i18n.T("  ─── recent ───")) + "\n"
+	for _, f := range shown {
+		content += m.Styles.Muted.Render("  󰋚 "+m.displayName(f)) + "\n"
+	}
+	return content + "\n"
+}
+
+// openFile opens a listed file in the todo panel
+func (m *Model) openFile(file string) tea.Cmd {
+	m.noteRecent(file)
+	m.CurrentFile = file
+	if i := slices.Index(m.Files, file); i >= 0 {
+		m.FileCursor = i
+	}
+	cmd := m.openList(filepath.Join(m.TodoDir, file))
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Opened: %s", file))
+	return cmd
+}
+
+// openPrevious jumps back to the file open before the current one, so pressing it
+// again flips between the two. Each keeps the todo it was left at.
+func (m *Model) openPrevious() tea.Cmd {
+	if m.ShowingArchive {
+		return nil
+	}
+	for _, f := range m.Recent {
+		if f != m.CurrentFile && slices.Contains(m.Files, f) {
+			m.noteSwitchedFrom()
+			cmd := m.openFile(f)
+			if id, ok := m.switchedFrom[f]; ok && m.Loading == "" {
+				if i := m.TodoList.IndexOf(id); i >= 0 {
+					m.TodoCursor = i
+					m.clampTodoCursor()
+					m.scrollTodos()
+				}
+			}
+			return cmd
+		}
+	}
+	m.toast(SeverityInfo, i18n.T("No other file opened yet"))
+	return nil
+}
+
+// noteSwitchedFrom remembers the selected todo of the open file before switching away
+func (m *Model) noteSwitchedFrom() {
+	if m.virtual != nil || m.TodoList.IsScratch() || m.Loading != "" || m.TodoCursor >= len(m.TodoList.Todos) {
+		return
+	}
+	if m.switchedFrom == nil {
+		m.switchedFrom = map[string]int{}
+	}
+	m.switchedFrom[m.CurrentFile] = m.TodoList.Todos[m.TodoCursor].ID
+}
+
+// accessibleRecent lists the recent files for the accessible view
+func (m Model) accessibleRecent() string {
+	shown := m.recentShown()
+	if len(shown) == 0 {
+		return ""
+	}
+	names := make([]string, len(shown))
+	for i, f := range shown {
+		names[i] = m.displayName(f)
+	}
+	return i18n.Tf("Recent: %s", strings.Join(names, ", "))
+}
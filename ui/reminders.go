@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/todo"
+)
+
+// reminderInterval is how often the reminder engine scans for fired reminders
+const reminderInterval = 30 * time.Second
+
+// ReminderAlert is a fired, unacknowledged reminder
+type ReminderAlert struct {
+	File  string
+	ID    int
+	Title string
+	Due   time.Time
+}
+
+// reminderTickMsg triggers a reminder scan
+type reminderTickMsg struct{}
+
+// remindersMsg carries the result of a reminder scan
+type remindersMsg struct {
+	alerts     []ReminderAlert
+	reschedule bool // true when the scan came from the periodic tick
+}
+
+// scheduleReminderCheck waits for the next reminder scan
+func scheduleReminderCheck() tea.Cmd {
+	return tea.Tick(reminderInterval, func(time.Time) tea.Msg {
+		return reminderTickMsg{}
+	})
+}
+
+// checkReminders scans all active files for fired reminders.
+// reschedule keeps the periodic scan going; one-off scans pass false.
+func checkReminders(dir string, reschedule bool) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		var alerts []ReminderAlert
+		for _, file := range LoadTodoFiles(dir) {
+			tl := todo.NewTodoList(filepath.Join(dir, file))
+			for _, t := range tl.Todos {
+				if t.ReminderDue(now) {
+					alerts = append(alerts, ReminderAlert{File: file, ID: t.ID, Title: t.Title, Due: *t.Due})
+				}
+			}
+		}
+		return remindersMsg{alerts: alerts, reschedule: reschedule}
+	}
+}
+
+// handleReminders stores fired reminders and sends desktop notifications for new ones
+func (m Model) handleReminders(msg remindersMsg) (tea.Model, tea.Cmd) {
+	if m.notified == nil {
+		m.notified = map[string]bool{}
+	}
+	for _, alert := range msg.alerts {
+		key := fmt.Sprintf("%s:%d", alert.File, alert.ID)
+		if !m.notified[key] {
+			m.notified[key] = true
+			if m.DesktopNotify {
+				notify("justdoit reminder", fmt.Sprintf("%s (due %s)", alert.Title, alert.Due.Format("Jan 2 15:04")))
+			}
+		}
+	}
+	m.Reminders = msg.alerts
+	if msg.reschedule {
+		return m, scheduleReminderCheck()
+	}
+	return m, nil
+}
+
+// acknowledgeReminders marks all fired reminders as seen
+func (m *Model) acknowledgeReminders() {
+	for _, alert := range m.Reminders {
+		if alert.File == m.CurrentFile {
+			m.TodoList.AcknowledgeReminder(alert.ID)
+			continue
+		}
+		tl := todo.NewTodoList(filepath.Join(m.TodoDir, alert.File))
+		tl.AcknowledgeReminder(alert.ID)
+	}
+	m.Reminders = nil
+}
+
+// notify sends a best-effort desktop notification
+func notify(title, body string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("notify-send", title, body)
+	default:
+		return
+	}
+	if cmd.Start() == nil {
+		go cmd.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// frameCache holds the last rendered frame, shared by the copies of a model
+type frameCache struct {
+	view string
+	gen  int // frame generation the view was rendered for
+	next int // last generation handed out
+}
+
+// quiet reports whether a message left everything on screen as it was, so the
+// previous frame can be shown again. Only background messages qualify: mouse
+// motion and ticks that had nothing to do. wasDirty is whether the open list had
+// unsaved changes before the message.
+func quiet(msg tea.Msg, before, after Model, wasDirty bool) bool {
+	if after.StatusMessage != before.StatusMessage || len(after.Toasts) != len(before.Toasts) || after.toastSeq != before.toastSeq {
+		return false
+	}
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return msg.Action == tea.MouseActionMotion
+	case progressTickMsg:
+		return after.spinnerFrame == before.spinnerFrame
+	case celebrateTickMsg:
+		return after.celebrateFrame == before.celebrateFrame
+	case autosaveTickMsg:
+		return !wasDirty
+	case remindersMsg:
+		return reflect.DeepEqual(after.Reminders, before.Reminders)
+	case listSavedMsg:
+		return msg.err == nil
+	case toastExpiredMsg:
+		return true // already dropped for newer ones
+	}
+	return false
+}
+
+// nextFrame stamps the model with a new frame generation after a message that may
+// have changed what is shown, or keeps the previous one after a quiet message
+func (m *Model) nextFrame(msg tea.Msg, before Model, wasDirty bool) {
+	if m.frame == nil {
+		m.frame = &frameCache{}
+	}
+	m.reuseFrame = m.frameGen != 0 && quiet(msg, before, *m, wasDirty)
+	if !m.reuseFrame {
+		m.frame.next++
+		m.frameGen = m.frame.next
+	}
+}
+
+// cachedView returns the previous frame when the last message changed nothing on screen
+func (m Model) cachedView() (string, bool) {
+	if m.frame == nil || !m.reuseFrame || m.frame.gen != m.frameGen {
+		return "", false
+	}
+	return m.frame.view, true
+}
+
+// storeView keeps a rendered frame for the model's generation
+func (m Model) storeView(view string) string {
+	if m.frame != nil && m.frameGen != 0 {
+		m.frame.view, m.frame.gen = view, m.frameGen
+	}
+	return view
+}
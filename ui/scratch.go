@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// listName names the list shown in the todo panel
+func (m Model) listName() string {
+	if m.virtual != nil {
+		return m.virtual.name
+	}
+	if m.TodoList != nil && m.TodoList.IsScratch() {
+		return i18n.T("scratchpad")
+	}
+	return m.displayName(m.CurrentFile)
+}
+
+// toggleScratch swaps the todo panel between the open file and the session's scratchpad.
+// The scratchpad is created on first use and kept in memory until quitting.
+func (m *Model) toggleScratch() {
+	if m.TodoList.IsScratch() {
+		m.setList(filepath.Join(m.TodoDir, m.CurrentFile))
+		m.TodoCursor = 0
+		m.clampTodoCursor()
+		m.toast(SeverityInfo, i18n.Tf("Opened: %s", m.CurrentFile))
+		return
+	}
+
+	if m.Scratch == nil {
+		m.Scratch = todo.NewScratchList()
+		m.Scratch.SetAuthor(m.Author)
+	}
+	m.TodoList.Flush()
+	m.loadGen++ // drop any background load
+	m.Loading = ""
+	m.TodoList = m.Scratch
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Scratchpad: not saved, p moves a todo to %s", m.CurrentFile))
+}
+
+// promoteScratch moves the selected scratchpad todo to the top of the open file
+func (m *Model) promoteScratch() {
+	if !m.TodoList.IsScratch() || m.TodoCursor >= len(m.TodoList.Todos) {
+		return
+	}
+	dst := m.loadList(filepath.Join(m.TodoDir, m.CurrentFile))
+	if err := m.TodoList.MoveTo(m.TodoCursor, dst); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Moved to %s", m.CurrentFile))
+}
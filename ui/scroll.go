@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// scrollMargin is how many rows are kept between the cursor and the window edge
+const scrollMargin = 2
+
+// panelHeight returns the height of the two main panels
+func (m Model) panelHeight() int {
+	height := m.Height - 4
+	if n := m.statusLines(); n > 0 {
+		height -= 2 + n // Account for status bar extra lines
+	}
+	height -= strings.Count(m.renderBanners(), "\n")
+	return max(height, 0)
+}
+
+// todoRows returns how many todo rows fit in the todo panel, or 0 before the first resize
+func (m Model) todoRows() int {
+	if m.Height == 0 {
+		return 0
+	}
+	rows := m.panelHeight() - 4 // padding, title and the blank line below it
+	if m.filtering() {
+		rows-- // filter chips below the title
+	}
+	if m.tableColumns() != nil {
+		rows-- // column labels above the table
+	}
+	if n := m.inputRows(); n > 0 {
+		// The new todo's input is a row of its own, an edited line wraps when long
+		if m.Dialog == AddTodo {
+			rows -= n
+		} else {
+			rows -= n - 1
+		}
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// todoWindow returns the range of visible rows to render
+func (m Model) todoWindow(count int) (int, int) {
+	rows := m.todoRows()
+	if rows == 0 || count <= rows {
+		return 0, count
+	}
+	start := min(max(m.TodoOffset, 0), count-rows)
+	return start, start + rows
+}
+
+// scrollTodos moves the scroll offset so the cursor stays inside the window
+func (m *Model) scrollTodos() {
+	rows := m.todoRows()
+	if rows == 0 {
+		return
+	}
+
+	// The window stays put while adding; the input is pinned above it when the
+	// top of the section is out of view
+	target := m.TodoCursor
+	visible := m.visibleIndices()
+	pos := sort.SearchInts(visible, target)
+	margin := min(scrollMargin, (rows-1)/2)
+
+	if pos < m.TodoOffset+margin {
+		m.TodoOffset = pos - margin
+	}
+	if pos > m.TodoOffset+rows-1-margin {
+		m.TodoOffset = pos - rows + 1 + margin
+	}
+	m.TodoOffset = max(min(m.TodoOffset, len(visible)-rows), 0)
+}
+
+// fitLines cuts every line of content to width cells so long titles can't wrap and
+// break the panel layout. Input lines keep their end (where the cursor is) visible.
+func fitLines(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		w := ansi.StringWidth(line)
+		if w <= width {
+			continue
+		}
+		if strings.Contains(line, "█") {
+			lines[i] = ansi.TruncateLeft(line, w-width+1, "…")
+		} else {
+			lines[i] = ansi.Truncate(line, width, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
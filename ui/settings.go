@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// autosaveChoices are the intervals the settings screen cycles through
+var autosaveChoices = []int{0, 5, 15, 30, 60}
+
+// startupChoices are the lists opened at startup the settings screen cycles through
+var startupChoices = []string{config.StartupFirst, config.StartupLast, config.StartupPicker}
+
+// titleLengthChoices are the title limits the settings screen cycles through
+var titleLengthChoices = []int{0, 100, 200, 500, 1000}
+
+// settingNames labels the rows of the settings screen
+var settingNames = []string{
+	"Confirm file deletes",
+	"Offer to archive completed lists",
+	"Move completed todos to the bottom",
+	"Autosave interval",
+	"Celebrate completions",
+	"Max title length",
+	"Accessible mode",
+	"Show title bar",
+	"Line numbers",
+	"Lowercase new list names",
+	"Date archived file names",
+	"Hide todos until their start date",
+	"Sound on completed todos",
+	"Sound on completed lists",
+	"Todo layout",
+	"Open at startup",
+}
+
+// autosaveTickMsg triggers a flush of pending changes
+type autosaveTickMsg struct {
+	gen int // ignored when the interval has changed since scheduling
+}
+
+// loadList opens a todo list with the current behavior settings applied,
+// superseding any background load
+func (m *Model) loadList(path string) *todo.TodoList {
+	m.loadGen++
+	m.Loading = ""
+	m.virtual = nil
+	return m.newList(path)
+}
+
+// newList opens a todo list with the current behavior settings applied, its
+// changes saved in the background, see saveList
+func (m Model) newList(path string) *todo.TodoList {
+	tl := todo.NewTodoList(path)
+	tl.SetAutoSort(m.Behavior.SortCompleted)
+	tl.SetMaxTitleLength(m.Behavior.MaxTitleLength)
+	tl.SetAuthor(m.Author)
+	tl.SetDeferredSave(true)
+	return tl
+}
+
+// setList saves pending changes to the current list and switches to another
+func (m *Model) setList(path string) {
+	if m.TodoList != nil {
+		m.TodoList.Flush()
+	}
+	m.TodoList = m.loadList(path)
+}
+
+// scheduleAutosave waits for the next autosave flush, if autosave is enabled
+func (m Model) scheduleAutosave() tea.Cmd {
+	if m.Behavior.AutosaveSeconds <= 0 {
+		return nil
+	}
+	gen := m.autosaveGen
+	return tea.Tick(time.Duration(m.Behavior.AutosaveSeconds)*time.Second, func(time.Time) tea.Msg {
+		return autosaveTickMsg{gen: gen}
+	})
+}
+
+// handleAutosave flushes pending changes and schedules the next flush
+func (m Model) handleAutosave(msg autosaveTickMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.autosaveGen {
+		return m, nil
+	}
+	return m, tea.Batch(m.saveList(), m.scheduleAutosave())
+}
+
+// openSettings shows the settings screen
+func (m *Model) openSettings() {
+	m.openDialog(SettingsScreen)
+	m.SettingsCursor = 0
+	m.toast(SeverityInfo, i18n.T("Settings"))
+}
+
+// handleSettings handles input in the settings screen
+func (m Model) handleSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.SettingsCursor < len(settingNames)-1 {
+			m.SettingsCursor++
+		}
+	case "k", "up":
+		if m.SettingsCursor > 0 {
+			m.SettingsCursor--
+		}
+	case "enter", " ", "l", "right":
+		return m.changeSetting(1)
+	case "h", "left":
+		return m.changeSetting(-1)
+	case "esc", "q":
+		m.closeDialog()
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// changeSetting toggles the selected flag or steps the autosave interval, then persists
+func (m Model) changeSetting(step int) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	b := &m.Behavior
+	switch m.SettingsCursor {
+	case 0:
+		b.ConfirmDeletes = !b.ConfirmDeletes
+	case 1:
+		b.ArchivePrompt = !b.ArchivePrompt
+	case 2:
+		b.SortCompleted = !b.SortCompleted
+		m.TodoList.SetAutoSort(b.SortCompleted)
+		if b.SortCompleted {
+			m.TodoList.Resort() // written by saveList like any other change
+		}
+	case 3:
+		i := 0
+		for j, s := range autosaveChoices {
+			if s == b.AutosaveSeconds {
+				i = j
+			}
+		}
+		i = (i + step + len(autosaveChoices)) % len(autosaveChoices)
+		b.AutosaveSeconds = autosaveChoices[i]
+		m.autosaveGen++
+		cmd = m.scheduleAutosave()
+	case 4:
+		i := 0
+		for j, c := range celebrateChoices {
+			if c == b.Celebrate {
+				i = j
+			}
+		}
+		i = (i + step + len(celebrateChoices)) % len(celebrateChoices)
+		b.Celebrate = celebrateChoices[i]
+	case 5:
+		i := 0
+		for j, n := range titleLengthChoices {
+			if n == b.MaxTitleLength {
+				i = j
+			}
+		}
+		i = (i + step + len(titleLengthChoices)) % len(titleLengthChoices)
+		b.MaxTitleLength = titleLengthChoices[i]
+		m.TodoList.SetMaxTitleLength(b.MaxTitleLength)
+	case 6:
+		b.Accessible = !b.Accessible
+		SetMonochrome(b.Accessible)
+	case 7:
+		b.TitleBar = !b.TitleBar
+	case 8:
+		i := 0
+		for j, c := range lineNumberChoices {
+			if c == b.LineNumbers {
+				i = j
+			}
+		}
+		i = (i + step + len(lineNumberChoices)) % len(lineNumberChoices)
+		b.LineNumbers = lineNumberChoices[i]
+	case 9:
+		b.LowercaseNames = !b.LowercaseNames
+	case 10:
+		b.DatedArchives = !b.DatedArchives
+	case 11:
+		b.HideDeferred = !b.HideDeferred
+		m.clampTodoCursor()
+	case 12:
+		b.SoundTodo = !b.SoundTodo
+	case 13:
+		b.SoundList = !b.SoundList
+	case 14:
+		if b.TodoLayout == config.LayoutTable {
+			b.TodoLayout = config.LayoutList
+		} else {
+			b.TodoLayout = config.LayoutTable
+		}
+		m.scrollTodos()
+	case 15:
+		i := max(slices.Index(startupChoices, b.StartupFile), 0)
+		i = (i + step + len(startupChoices)) % len(startupChoices)
+		b.StartupFile = startupChoices[i]
+	}
+
+	if err := m.saveBehavior(); err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, cmd
+	}
+	m.toast(SeverityInfo, i18n.T("Settings saved"))
+	return m, cmd
+}
+
+// saveBehavior writes the behavior settings to the config file, keeping its other sections
+func (m Model) saveBehavior() error {
+	if m.ConfigPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg.Behavior = m.Behavior
+	return config.Save(m.ConfigPath, cfg)
+}
+
+// settingValue formats the current value of a settings row
+func (m Model) settingValue(i int) string {
+	onOff := func(v bool) string {
+		if v {
+			return i18n.T("on")
+		}
+		return i18n.T("off")
+	}
+	switch i {
+	case 0:
+		return onOff(m.Behavior.ConfirmDeletes)
+	case 1:
+		return onOff(m.Behavior.ArchivePrompt)
+	case 2:
+		return onOff(m.Behavior.SortCompleted)
+	case 3:
+		if m.Behavior.AutosaveSeconds == 0 {
+			return i18n.T("on every change")
+		}
+		return i18n.Tf("every %ds", m.Behavior.AutosaveSeconds)
+	case 4:
+		return m.Behavior.Celebrate
+	case 5:
+		if m.Behavior.MaxTitleLength == 0 {
+			return i18n.T("no limit")
+		}
+		return i18n.Tf("%d characters", m.Behavior.MaxTitleLength)
+	case 6:
+		return onOff(m.Behavior.Accessible)
+	case 7:
+		return onOff(m.Behavior.TitleBar)
+	case 8:
+		return i18n.T(m.Behavior.LineNumbers)
+	case 9:
+		return onOff(m.Behavior.LowercaseNames)
+	case 10:
+		return onOff(m.Behavior.DatedArchives)
+	case 11:
+		return onOff(m.Behavior.HideDeferred)
+	case 12:
+		return onOff(m.Behavior.SoundTodo)
+	case 13:
+		return onOff(m.Behavior.SoundList)
+	case 14:
+		if m.Behavior.TodoLayout == config.LayoutTable {
+			return i18n.T("table")
+		}
+		return i18n.T("list")
+	case 15:
+		switch m.Behavior.StartupFile {
+		case config.StartupLast:
+			return i18n.T("last opened list")
+		case config.StartupPicker:
+			return i18n.T("pick a list")
+		}
+		return i18n.T("first list")
+	}
+	return ""
+}
+
+// renderSettings renders the settings screen overlay
+func (m Model) renderSettings() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorMauve).
+		Padding(1, 3)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorMauve).
+		Bold(true).
+		Render(i18n.T(" Settings"))
+
+	content := title + "\n\n"
+	for i, name := range settingNames {
+		line := fmt.Sprintf("%-36s %s", i18n.T(name), m.settingValue(i))
+		if i == m.SettingsCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+line+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+line) + "\n"
+		}
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
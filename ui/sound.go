@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/i18n"
+)
+
+// soundTimeout bounds how long a sound command may play before it is stopped
+const soundTimeout = 10 * time.Second
+
+// soundFailedMsg reports a sound command that couldn't be run or exited non-zero
+type soundFailedMsg struct {
+	err error
+}
+
+// playSound plays the completion sound for a completed todo, or for a completed list
+// when that one is enabled. It is silent unless the event is turned on.
+func (m Model) playSound(listDone bool) tea.Cmd {
+	event := ""
+	switch {
+	case listDone && m.Behavior.SoundList:
+		event = "list"
+	case m.Behavior.SoundTodo:
+		event = "todo"
+	default:
+		return nil
+	}
+
+	command := m.Behavior.SoundCommand
+	if command == "" {
+		rings := 1
+		if event == "list" {
+			rings = 2
+		}
+		return func() tea.Msg {
+			fmt.Fprint(os.Stdout, strings.Repeat("\a", rings))
+			return nil
+		}
+	}
+	return func() tea.Msg {
+		// The event is passed on so one script can play a different sound for each
+		ctx, cancel := context.WithTimeout(context.Background(), soundTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(os.Environ(), "JUSTDOIT_EVENT="+event)
+		cmd.WaitDelay = time.Second // don't wait on children holding the output open
+		if err := cmd.Run(); err != nil {
+			return soundFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// handleSoundFailed warns that the sound command didn't work
+func (m Model) handleSoundFailed(msg soundFailedMsg) (tea.Model, tea.Cmd) {
+	m.toast(SeverityWarning, i18n.Tf("Sound command failed: %v", msg.err))
+	return m, nil
+}
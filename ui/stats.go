@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// forecastDays is how far back the stats view measures a list's pace
+const forecastDays = 14
+
+// burndownHeight is the number of rows of the burndown chart
+const burndownHeight = 4
+
+// heatmapWeeks is how many weeks the completion heatmap covers
+const heatmapWeeks = 53
+
+// heatmapShades draw a day by its completions relative to the busiest day
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// statsView holds the statistics of the list the stats view was opened on
+type statsView struct {
+	list     string
+	forecast todo.Forecast
+	heatmap  *todo.Heatmap // completions across all lists, nil until scanned
+	week     int           // selected week of the heatmap
+
+	breakdowns []todo.Breakdown
+	cursor     int // selected breakdown row
+}
+
+// heatmapMsg carries the completion heatmap across all lists
+type heatmapMsg struct {
+	heatmap todo.Heatmap
+}
+
+// openStats shows the stats view for the open list and scans all lists for the heatmap
+func (m *Model) openStats() tea.Cmd {
+	m.stats = &statsView{
+		list:       m.listName(),
+		forecast:   m.TodoList.Forecast(time.Now(), forecastDays),
+		breakdowns: m.TodoList.Breakdowns(time.Now()),
+	}
+	m.openDialog(StatsScreen)
+	m.toast(SeverityInfo, i18n.Tf("Stats: %s", m.stats.list))
+	m.TodoList.Flush()
+	return m.scanHeatmap()
+}
+
+// refreshStats recomputes the open list's statistics after it changed
+func (m *Model) refreshStats() {
+	s := m.stats
+	s.forecast = m.TodoList.Forecast(time.Now(), forecastDays)
+	s.breakdowns = m.TodoList.Breakdowns(time.Now())
+	s.cursor = max(0, min(s.cursor, len(s.breakdowns)-1))
+}
+
+// scanHeatmap scans the lists and the archive for the completion heatmap
+func (m Model) scanHeatmap() tea.Cmd {
+	dirs := []string{m.TodoDir, m.ArchiveDir}
+	return func() tea.Msg {
+		var paths []string
+		for _, dir := range dirs {
+			for _, f := range LoadTodoFiles(dir) {
+				paths = append(paths, filepath.Join(dir, f))
+			}
+		}
+		return heatmapMsg{heatmap: todo.CompletionHeatmap(paths, time.Now(), heatmapWeeks)}
+	}
+}
+
+// handleHeatmap shows the scanned heatmap, with the current week selected the
+// first time
+func (m Model) handleHeatmap(msg heatmapMsg) (tea.Model, tea.Cmd) {
+	if m.stats != nil {
+		if m.stats.heatmap == nil || m.stats.week >= msg.heatmap.Weeks() {
+			m.stats.week = msg.heatmap.Weeks() - 1
+		}
+		m.stats.heatmap = &msg.heatmap
+	}
+	return m, nil
+}
+
+// handleStats handles input in the stats view
+func (m Model) handleStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.stats
+	switch msg.String() {
+	case "h", "left":
+		if s.week > 0 {
+			s.week--
+		}
+	case "l", "right":
+		if s.heatmap != nil && s.week < s.heatmap.Weeks()-1 {
+			s.week++
+		}
+	case "j", "down":
+		if s.cursor < len(s.breakdowns)-1 {
+			s.cursor++
+		}
+	case "k", "up":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "enter":
+		if s.cursor < len(s.breakdowns) {
+			m.drillDown(s.breakdowns[s.cursor])
+		}
+	case "esc", "q":
+		m.closeDialog()
+		m.stats = nil
+		m.StatusMessage = ""
+	}
+	return m, nil
+}
+
+// drillDown closes the stats view and filters the open list to a breakdown row
+func (m *Model) drillDown(b todo.Breakdown) {
+	m.ActiveContext, m.FieldFilter, m.Focus = "", "", false
+	switch b.Group {
+	case todo.GroupContext:
+		m.ActiveContext = b.Key
+		m.toast(SeverityInfo, i18n.Tf("Context: @%s", b.Key))
+	case todo.GroupField:
+		m.FieldFilter = b.Key
+		m.toast(SeverityInfo, i18n.Tf("Showing todos with %s", b.Key))
+	case todo.GroupPriority:
+		m.Focus = true
+		m.toast(SeverityInfo, i18n.T("Focus mode: flagged and due-today todos only"))
+	}
+	m.closeDialog()
+	m.stats = nil
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+}
+
+// forecastText sums up when the list runs out at its current pace
+func forecastText(f todo.Forecast) string {
+	switch {
+	case f.Open == 0:
+		return i18n.T("Nothing left to do")
+	case f.Finish == nil:
+		return i18n.T("Not shrinking at the current pace")
+	}
+	return i18n.Tf("At the current pace this list finishes ~%s", f.Finish.Format("Jan 2"))
+}
+
+// burndownChart draws the open todos at the end of each day as columns of blocks
+func burndownChart(counts []int, height int) []string {
+	top := 1
+	for _, n := range counts {
+		top = max(top, n)
+	}
+	blocks := []rune(" ▁▂▃▄▅▆▇█")
+	lines := make([]string, height)
+	for row := range height {
+		var b strings.Builder
+		floor := (height - row - 1) * 8 // eighths below this row
+		for _, n := range counts {
+			fill := min(max(n*height*8/top-floor, 0), 8)
+			b.WriteString(strings.Repeat(string(blocks[fill]), 2))
+			b.WriteByte(' ')
+		}
+		lines[row] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// renderStats renders the stats view of the open list
+func (m Model) renderStats() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(0, 2)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.Tf("󰄨 Stats: %s", m.stats.list))
+
+	f := m.stats.forecast
+	pace := m.Styles.Normal.Render(i18n.Tf("%d open, %d done and %d added in the last %d days", f.Open, f.Done, f.Added, f.Days)) + "\n"
+	pace += m.Styles.Normal.Render(i18n.Tf("Pace: %.1f todos closed a day", f.Pace())) + "\n"
+	pace += m.Styles.Normal.Render(forecastText(f)) + "\n\n"
+
+	pace += m.Styles.Muted.Render(i18n.T("Burndown (open todos at the end of each day)")) + "\n"
+	top := 0
+	for _, n := range f.Burndown {
+		top = max(top, n)
+	}
+	chartStyle := lipgloss.NewStyle().Foreground(ColorTeal)
+	for i, line := range burndownChart(f.Burndown, burndownHeight) {
+		axis := "    "
+		if i == 0 {
+			axis = fmt.Sprintf("%3d ", top)
+		}
+		pace += m.Styles.Muted.Render(axis) + chartStyle.Render(line) + "\n"
+	}
+	today := time.Now()
+	first := today.AddDate(0, 0, -f.Days+1).Format("Jan 2")
+	last := today.Format("Jan 2")
+	gap := max(f.Days*3-1-len(first)-len(last), 1)
+	pace += m.Styles.Muted.Render("    " + first + strings.Repeat(" ", gap) + last)
+
+	breakdowns := m.renderBreakdowns(lipgloss.Height(pace))
+	content := title + "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, pace, "   ", breakdowns) + "\n\n"
+	content += m.renderHeatmap()
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		boxStyle.Render(content),
+	)
+}
+
+// renderHeatmap renders the completions across all lists as one column per week and one row
+// per weekday, with month labels on top and the selected week's total below.
+// Only the latest weeks are shown when the terminal is too narrow for all of them.
+func (m Model) renderHeatmap() string {
+	title := m.Styles.Muted.Render(i18n.T("Completions across all lists")) + "\n"
+	h := m.stats.heatmap
+	if h == nil {
+		return title + m.Styles.Muted.Render(i18n.T("Loading..."))
+	}
+
+	shown := min(h.Weeks(), max(m.Width-20, 10))
+	first := h.Weeks() - shown
+	if m.stats.week < first {
+		first = m.stats.week
+	}
+	weekStart := func(w int) time.Time { return h.Start.AddDate(0, 0, 7*w) }
+
+	// Month labels where a month's first week begins, if there's room
+	months := []rune(strings.Repeat(" ", shown+1))
+	free := 0
+	for w := first; w < first+shown; w++ {
+		start := weekStart(w)
+		if w > first && start.Month() == weekStart(w-1).Month() {
+			continue
+		}
+		label := []rune(start.Format("Jan"))
+		if col := w - first; col >= free && col+len(label) <= len(months) {
+			copy(months[col:], label)
+			free = col + len(label) + 1
+		}
+	}
+	content := title + "    " + m.Styles.Muted.Render(strings.TrimRight(string(months), " ")) + "\n"
+
+	busiest := max(h.Busiest(), 1)
+	cellStyle := lipgloss.NewStyle().Foreground(ColorTeal)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorBase).Background(ColorTeal)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		label := "    "
+		if day%2 == 1 {
+			label = day.String()[:3] + " "
+		}
+		row := m.Styles.Muted.Render(label)
+		for w := first; w < first+shown; w++ {
+			n := h.Day(w, day)
+			shade := heatmapShades[(n*(len(heatmapShades)-1)+busiest-1)/busiest]
+			style := cellStyle
+			if n == 0 {
+				style = m.Styles.Muted
+			}
+			if w == m.stats.week {
+				style = selectedStyle
+			}
+			row += style.Render(shade)
+		}
+		content += row + "\n"
+	}
+
+	week := m.stats.week
+	summary := i18n.Tf("Week of %s: %d completed", weekStart(week).Format("Jan 2"), h.Week(week))
+	return content + "    " + m.Styles.Normal.Render(summary)
+}
+
+// breakdownGroups labels the groups of the breakdown table
+var breakdownGroups = map[string]string{
+	todo.GroupContext:  "Contexts",
+	todo.GroupField:    "Fields",
+	todo.GroupPriority: "Priority",
+}
+
+// renderBreakdowns renders the open and completed counts and open age per context, field and flag
+// in at most height lines, scrolled to keep the selected row visible
+func (m Model) renderBreakdowns(height int) string {
+	s := m.stats
+	header := m.Styles.Muted.Render(fmt.Sprintf("%-18s %5s %5s %5s", i18n.T("Breakdown"), i18n.T("Open"), i18n.T("Done"), i18n.T("Age")))
+	if len(s.breakdowns) == 0 {
+		return header + "\n" + m.Styles.Muted.Render(i18n.T("No contexts, fields or flags"))
+	}
+
+	var lines []string
+	selected := 0
+	group := ""
+	for i, b := range s.breakdowns {
+		if b.Group != group {
+			group = b.Group
+			lines = append(lines, m.Styles.Muted.Render(i18n.T(breakdownGroups[group])))
+		}
+		name := b.Key
+		if b.Group == todo.GroupContext {
+			name = "@" + name
+		}
+		name = ansi.Truncate(name, 16, "…")
+		line := fmt.Sprintf("%-16s %5d %5d %5s", name, b.Open, b.Completed, formatAge(b.OpenAge, b.Open))
+		if i == s.cursor {
+			selected = len(lines)
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			lines = append(lines, m.Styles.Selected.Render(cursor+" "+line))
+		} else {
+			lines = append(lines, m.Styles.Normal.Render("  "+line))
+		}
+	}
+
+	rows := max(height-1, 1)
+	start := max(0, min(selected-rows+1, len(lines)-rows))
+	end := min(len(lines), start+rows)
+	return header + "\n" + strings.Join(lines[start:end], "\n")
+}
+
+// formatAge formats the average age of open todos in days, or hours when under a day
+func formatAge(age time.Duration, open int) string {
+	switch {
+	case open == 0:
+		return "–"
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
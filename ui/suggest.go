@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+const (
+	maxSuggestions    = 5 // rows of the dropdown below a new todo
+	minSuggestPhrase  = 2 // characters typed before earlier titles are suggested
+	suggestionsMargin = 3 // cells around each suggestion for its marker and padding
+)
+
+// suggestIndexMsg carries the search index refreshed for suggestions
+type suggestIndexMsg struct {
+	idx *search.Index
+}
+
+// loadSuggestions refreshes the search index in the background, for suggesting
+// contexts and earlier titles while a todo is added. In safe mode it isn't saved.
+func (m Model) loadSuggestions() tea.Cmd {
+	indexPath, dir, safe := m.IndexPath, m.TodoDir, m.Safe
+	return func() tea.Msg {
+		idx := search.Open(indexPath)
+		if err := idx.Refresh(dir); err != nil {
+			return nil // suggestions are a convenience, the list itself still works
+		}
+		if !safe {
+			idx.Save()
+		}
+		return suggestIndexMsg{idx: idx}
+	}
+}
+
+// handleSuggestIndex keeps the refreshed index and suggests from it right away
+func (m Model) handleSuggestIndex(msg suggestIndexMsg) (tea.Model, tea.Cmd) {
+	m.suggestIndex = msg.idx
+	if m.Dialog == AddTodo {
+		m.suggest()
+	}
+	return m, nil
+}
+
+// suggest fills the dropdown for the typed todo: the contexts starting with a
+// @word being typed, or else earlier titles matching what was typed
+func (m *Model) suggest() {
+	m.suggestions = nil
+	m.suggestCursor = -1
+
+	text := m.InputText
+	fields := strings.Fields(text)
+	if len(fields) > 0 && !strings.HasSuffix(text, " ") && strings.HasPrefix(fields[len(fields)-1], "@") {
+		prefix := strings.ToLower(strings.TrimPrefix(fields[len(fields)-1], "@"))
+		contexts := todo.AllContexts(m.TodoList)
+		if m.suggestIndex != nil {
+			contexts = append(contexts, m.suggestIndex.Contexts(m.TodoDir)...)
+		}
+		slices.Sort(contexts)
+		for _, c := range slices.Compact(contexts) {
+			if strings.HasPrefix(c, prefix) && c != prefix && len(m.suggestions) < maxSuggestions {
+				m.suggestions = append(m.suggestions, "@"+c)
+			}
+		}
+		return
+	}
+
+	if len([]rune(strings.TrimSpace(text))) >= minSuggestPhrase && m.suggestIndex != nil {
+		m.suggestions = m.suggestIndex.Phrases(text, maxSuggestions, m.TodoDir)
+	}
+}
+
+// handleSuggestionKey moves through the dropdown and takes the selected
+// suggestion, reporting whether the key was used
+func (m *Model) handleSuggestionKey(msg tea.KeyMsg) bool {
+	if len(m.suggestions) == 0 {
+		return false
+	}
+	switch msg.String() {
+	case "down", "ctrl+n":
+		m.suggestCursor = min(m.suggestCursor+1, len(m.suggestions)-1)
+	case "up", "ctrl+p":
+		m.suggestCursor = max(m.suggestCursor-1, -1)
+	case "tab":
+		m.acceptSuggestion(max(m.suggestCursor, 0))
+	case "enter":
+		if m.suggestCursor < 0 {
+			return false // saves the todo as typed
+		}
+		m.acceptSuggestion(m.suggestCursor)
+	case "esc":
+		if m.suggestCursor < 0 {
+			return false
+		}
+		m.suggestCursor = -1
+	default:
+		return false
+	}
+	return true
+}
+
+// acceptSuggestion puts the suggestion at i into the input: a context replaces the
+// @word being typed, an earlier title the whole input
+func (m *Model) acceptSuggestion(i int) {
+	s := m.suggestions[i]
+	if strings.HasPrefix(s, "@") {
+		at := strings.LastIndex(m.InputText, "@")
+		m.InputText = m.InputText[:at] + s + " "
+	} else {
+		m.InputText = s
+	}
+	m.suggestions = nil // until more is typed
+	m.suggestCursor = -1
+}
+
+// suggestionLines renders the dropdown below the new todo's input, the selected
+// suggestion highlighted
+func (m Model) suggestionLines(indent int) []string {
+	if m.Dialog != AddTodo || len(m.suggestions) == 0 {
+		return nil
+	}
+	width := max(m.todoTextWidth()-indent-suggestionsMargin, minInputWidth)
+	pad := strings.Repeat(" ", indent)
+	lines := make([]string, len(m.suggestions))
+	for i, s := range m.suggestions {
+		s = ansi.Truncate(s, width, "…")
+		if i == m.suggestCursor {
+			lines[i] = pad + m.Styles.Selected.Render(" ▸ "+s+" ")
+		} else {
+			lines[i] = pad + m.Styles.Muted.Render("   "+s)
+		}
+	}
+	return lines
+}
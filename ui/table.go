@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// tableWidths are the widths of the table columns without one in the config. The
+// title takes what the others leave, but no less than minTableTitle.
+var tableWidths = map[string]int{
+	config.ColumnStatus:   1,
+	config.ColumnTags:     14,
+	config.ColumnDue:      12,
+	config.ColumnPriority: 9,
+	config.ColumnAge:      4,
+}
+
+const (
+	minTableTitle = 10 // cells the title keeps in a narrow panel
+	tableGap      = 2  // cells between columns
+)
+
+// tableColumn is a column of the table layout with its width worked out
+type tableColumn struct {
+	name  string
+	width int
+}
+
+// tableColumns returns the columns of the table layout as they fit the todo panel,
+// or nil in the list layout. Habit lists keep the list layout for their streaks.
+func (m Model) tableColumns() []tableColumn {
+	if m.Behavior.TodoLayout != config.LayoutTable || m.TodoList == nil || m.TodoList.IsHabit() {
+		return nil
+	}
+	configured := m.Table
+	if len(configured) == 0 {
+		for _, name := range config.TableColumns {
+			configured = append(configured, config.TableColumn{Name: name})
+		}
+	}
+
+	var columns []tableColumn
+	used, title := 0, -1
+	for _, c := range configured {
+		if !slices.Contains(config.TableColumns, c.Name) || slices.ContainsFunc(columns, func(t tableColumn) bool { return t.name == c.Name }) {
+			continue
+		}
+		width := c.Width
+		if width <= 0 {
+			width = tableWidths[c.Name]
+		}
+		if c.Name == config.ColumnTitle && c.Width <= 0 {
+			title = len(columns)
+		}
+		columns = append(columns, tableColumn{name: c.Name, width: width})
+		used += width + tableGap
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	if title >= 0 {
+		// The row is framed by the cursor, like the list layout's lines
+		columns[title].width = max(m.todoTextWidth()-4-used+tableGap, minTableTitle)
+	}
+	return columns
+}
+
+// renderTableHeader renders the labels above the table columns
+func (m Model) renderTableHeader(columns []tableColumn) string {
+	style := m.Styles.Muted.Bold(true)
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = tableCell(style.Render(columnHeader(c.name)), c.width)
+	}
+	return strings.Join(cells, strings.Repeat(" ", tableGap))
+}
+
+// columnHeader labels a table column
+func columnHeader(column string) string {
+	switch column {
+	case config.ColumnTitle:
+		return i18n.T("Title")
+	case config.ColumnTags:
+		return i18n.T("Tags")
+	case config.ColumnDue:
+		return i18n.T("Due")
+	case config.ColumnPriority:
+		return i18n.T("Priority")
+	case config.ColumnAge:
+		return i18n.T("Age")
+	}
+	return ""
+}
+
+// renderTableRow renders a todo as a row of the table layout
+func (m Model) renderTableRow(t todo.Todo, columns []tableColumn) string {
+	tags := slices.ContainsFunc(columns, func(c tableColumn) bool { return c.name == config.ColumnTags })
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		var cell string
+		switch c.name {
+		case config.ColumnStatus:
+			cell = m.renderCheckbox(t)
+		case config.ColumnTitle:
+			title := t.Title
+			if tags {
+				title = withoutTags(title) // they have a column of their own
+			}
+			if t.Completed {
+				cell = m.Styles.Completed.Render(title)
+			} else {
+				base := m.Styles.Normal
+				if t.Deferred(time.Now()) {
+					base = m.Styles.Muted
+				}
+				cell = m.renderTitle(title, m.titleStyle(t, base))
+			}
+		case config.ColumnTags:
+			if contexts := todo.Contexts(t.Title); len(contexts) > 0 {
+				cell = m.renderTags("@"+strings.Join(contexts, " @"), m.Styles.Muted)
+			}
+		case config.ColumnDue:
+			if t.Due != nil {
+				style := m.Styles.Muted
+				if !t.Completed && time.Now().After(*t.Due) {
+					style = lipgloss.NewStyle().Foreground(ColorRed)
+				}
+				cell = style.Render(t.Due.Format("Jan 2 15:04"))
+			}
+		case config.ColumnPriority:
+			var parts []string
+			if t.Flagged {
+				parts = append(parts, lipgloss.NewStyle().Foreground(ColorRed).Render("󰈻"))
+			}
+			if p := t.Fields["priority"]; p != "" {
+				parts = append(parts, m.Styles.Muted.Render(p))
+			}
+			cell = strings.Join(parts, " ")
+		case config.ColumnAge:
+			if !t.CreatedAt.IsZero() {
+				cell = m.Styles.Muted.Render(formatAge(time.Since(t.CreatedAt), 1))
+			}
+		}
+		cells[i] = tableCell(cell, c.width)
+	}
+	return strings.Join(cells, strings.Repeat(" ", tableGap))
+}
+
+// tableCell fits rendered text to exactly width cells
+func tableCell(s string, width int) string {
+	s = ansi.Truncate(s, width, "…")
+	return s + strings.Repeat(" ", max(width-ansi.StringWidth(s), 0))
+}
+
+// withoutTags returns a title with its @tags taken out
+func withoutTags(title string) string {
+	spans := todo.ContextIndexes(title)
+	for i := len(spans) - 1; i >= 0; i-- {
+		title = title[:spans[i][0]] + title[spans[i][1]:]
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/todo"
+)
+
+// genericTagIcon marks the tags without an icon of their own
+const genericTagIcon = ""
+
+// TagBadge is the icon and color a @tag is shown with
+type TagBadge struct {
+	icon  string
+	color lipgloss.Color
+}
+
+// CompileTagIcons turns the configured tag icons into badges, keyed by the lowercased
+// tag. A color is a list color name or anything lipgloss takes.
+func CompileTagIcons(icons map[string]config.TagIcon) map[string]TagBadge {
+	if len(icons) == 0 {
+		return nil
+	}
+	badges := make(map[string]TagBadge, len(icons))
+	for tag, icon := range icons {
+		badge := TagBadge{icon: icon.Icon, color: ColorOverlay1}
+		if badge.icon == "" {
+			badge.icon = genericTagIcon
+		}
+		if c, ok := listColors[icon.Color]; ok {
+			badge.color = c
+		} else if icon.Color != "" {
+			badge.color = lipgloss.Color(icon.Color)
+		}
+		badges[strings.ToLower(strings.TrimLeft(tag, "@#"))] = badge
+	}
+	return badges
+}
+
+// renderTags renders text in style with an icon before each @tag. Once any tag has
+// an icon, the others get the generic one so all of them stand out.
+func (m Model) renderTags(text string, style lipgloss.Style) string {
+	spans := todo.ContextIndexes(text)
+	if len(m.TagIcons) == 0 || len(spans) == 0 {
+		return style.Render(text)
+	}
+
+	var out string
+	prev := 0
+	for _, span := range spans {
+		if span[0] > prev {
+			out += style.Render(text[prev:span[0]])
+		}
+		tag := text[span[0]:span[1]]
+		badge, ok := m.TagIcons[strings.ToLower(tag[1:])]
+		if !ok {
+			badge = TagBadge{icon: genericTagIcon, color: ColorOverlay1}
+		}
+		out += style.Foreground(badge.color).Render(badge.icon + " " + tag)
+		prev = span[1]
+	}
+	if prev < len(text) {
+		out += style.Render(text[prev:])
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// openTemplatePicker lists the templates and shows the picker
+func (m *Model) openTemplatePicker() {
+	m.Templates = LoadTodoFiles(m.TemplateDir)
+	if len(m.Templates) == 0 {
+		m.toast(SeverityInfo, i18n.Tf("No templates in %s", m.TemplateDir))
+		return
+	}
+	m.TemplateCursor = 0
+	m.openDialog(TemplatePicker)
+	m.toast(SeverityInfo, i18n.T("New list from template"))
+}
+
+// handleTemplatePicker handles input in the template picker
+func (m Model) handleTemplatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.TemplateCursor < len(m.Templates)-1 {
+			m.TemplateCursor++
+		}
+	case "k", "up":
+		if m.TemplateCursor > 0 {
+			m.TemplateCursor--
+		}
+	case "enter", " ":
+		tmpl := todo.NewTodoList(filepath.Join(m.TemplateDir, m.Templates[m.TemplateCursor]))
+		m.TemplateFields = tmpl.Placeholders()
+		m.TemplateValues = make(map[string]string, len(m.TemplateFields))
+		m.openDialog(TemplatePrompt)
+		m.promptTemplateValue()
+	case "esc", "q":
+		m.closeDialog()
+		m.toast(SeverityInfo, i18n.T("Cancelled"))
+	}
+	return m, nil
+}
+
+// templateField returns the placeholder being asked for, or "" once only the filename is left
+func (m Model) templateField() string {
+	if len(m.TemplateValues) < len(m.TemplateFields) {
+		return m.TemplateFields[len(m.TemplateValues)]
+	}
+	return ""
+}
+
+// promptTemplateValue asks for the next placeholder value, then for the new file's name
+func (m *Model) promptTemplateValue() {
+	field := m.templateField()
+	switch field {
+	case "":
+		m.InputText = fillFilename(strings.TrimSuffix(m.Templates[m.TemplateCursor], ".json"), m.TemplateValues)
+		m.toast(SeverityInfo, i18n.T("Enter filename (without .json)"))
+		return
+	case "date":
+		m.InputText = time.Now().Format("2006-01-02")
+	default:
+		m.InputText = ""
+	}
+	m.toast(SeverityInfo, i18n.Tf("Value for {{%s}} (%d/%d)", field, len(m.TemplateValues)+1, len(m.TemplateFields)))
+}
+
+// fillFilename fills placeholders in a template's name, keeping the result a plain filename
+func fillFilename(name string, values map[string]string) string {
+	return strings.ReplaceAll(todo.FillPlaceholders(name, values), string(filepath.Separator), "-")
+}
+
+// submitTemplatePrompt records a placeholder value or, at the last step, creates the list
+func (m Model) submitTemplatePrompt() (tea.Model, tea.Cmd) {
+	if field := m.templateField(); field != "" {
+		m.TemplateValues[field] = m.InputText
+		m.promptTemplateValue()
+		return m, nil
+	}
+
+	if m.InputText == "" {
+		m.toast(SeverityInfo, i18n.T("Cannot be empty"))
+		return m, nil
+	}
+	filename := m.InputText + ".json"
+	path := filepath.Join(m.TodoDir, filename)
+	tmpl := todo.NewTodoList(filepath.Join(m.TemplateDir, m.Templates[m.TemplateCursor]))
+	if _, err := tmpl.Instantiate(path, m.TemplateValues); err != nil {
+		m.toast(SeverityError, err.Error())
+		return m, nil
+	}
+
+	m.setList(path)
+	m.CurrentFile = filename
+	m.loadFiles()
+	for i, f := range m.Files {
+		if f == filename {
+			m.FileCursor = i
+			break
+		}
+	}
+	m.closeDialog()
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = 0
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Created %s from %s", filename, m.Templates[m.TemplateCursor]))
+	return m, nil
+}
+
+// renderTemplatePrompt renders the current template prompt above the file list
+func (m Model) renderTemplatePrompt() string {
+	var content string
+	if field := m.templateField(); field != "" {
+		label := m.Styles.Muted.Render(fmt.Sprintf("  {{%s}} ", field))
+		content = label + m.Styles.Edit.Render(m.InputText+"█") + "\n"
+	} else {
+		content = m.Styles.Edit.Render("  "+m.InputText+"█.json") + "\n"
+	}
+	for _, file := range m.Files {
+		content += m.Styles.Normal.Render("  󰈔 "+file) + "\n"
+	}
+	return content
+}
+
+// renderTemplatePicker renders the template picker overlay
+func (m Model) renderTemplatePicker() string {
+	pickerStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(1, 4)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.T("󰈙 New From Template"))
+
+	content := title + "\n\n"
+	for i, name := range m.Templates {
+		label := strings.TrimSuffix(name, ".json")
+		if i == m.TemplateCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+label+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+label) + "\n"
+		}
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		pickerStyle.Render(content),
+	)
+}
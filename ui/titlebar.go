@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/i18n"
+	"justdoit/todo"
+)
+
+// renderTitleBar renders the data directory, the open file and its save state above the panels
+func (m Model) renderTitleBar() string {
+	if !m.Behavior.TitleBar || m.TodoList == nil {
+		return ""
+	}
+
+	file := m.listName()
+	if !m.TodoList.IsScratch() {
+		if rel, err := filepath.Rel(m.TodoDir, m.TodoList.Path()); err == nil {
+			file = rel
+		}
+	}
+	crumbs := []string{shortenHome(m.TodoDir)}
+	crumbs = append(crumbs, strings.Split(filepath.ToSlash(file), "/")...)
+	left := m.Styles.Muted.Render(" 󰉋 ") + m.Styles.Normal.Render(strings.Join(crumbs, " › "))
+
+	var state string
+	switch {
+	case m.Loading != "":
+		state = m.Styles.Muted.Render(i18n.T("loading"))
+	case len(todo.Unsaved()) > 0:
+		state = lipgloss.NewStyle().Foreground(ColorRed).Render(i18n.T("⚠ not saved"))
+	case m.TodoList.Dirty():
+		state = lipgloss.NewStyle().Foreground(ColorYellow).Render(i18n.T("● unsaved changes"))
+	default:
+		state = lipgloss.NewStyle().Foreground(ColorGreen).Render(i18n.T("✓ saved"))
+	}
+	state += " "
+
+	gap := max(m.Width-lipgloss.Width(left)-lipgloss.Width(state), 1)
+	return fitLines(left+strings.Repeat(" ", gap)+state, m.Width) + "\n"
+}
+
+// renderSaveChip marks the todo panel title while changes aren't on disk, unless the
+// title bar shows it
+func (m Model) renderSaveChip() string {
+	if m.Behavior.TitleBar || m.Loading != "" {
+		return ""
+	}
+	style := lipgloss.NewStyle().Foreground(ColorBase).Bold(true).Padding(0, 1)
+	switch {
+	case len(todo.Unsaved()) > 0:
+		return " " + style.Background(ColorRed).Render(i18n.T("⚠ not saved"))
+	case m.TodoList.Dirty():
+		return " " + style.Background(ColorYellow).Render(i18n.T("● unsaved"))
+	}
+	return ""
+}
+
+// shortenHome writes paths inside the home directory with a leading ~
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
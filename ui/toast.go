@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Severity is how much a toast needs the user's attention
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// Toast is a status message stacked above the bottom of the screen until it times out
+type Toast struct {
+	ID       int
+	Text     string
+	Severity Severity
+}
+
+// maxToasts is how many toasts are stacked at once, older ones are dropped
+const maxToasts = 3
+
+// toastLifetime returns how long a toast stays up, longer the more it matters
+func toastLifetime(s Severity) time.Duration {
+	switch s {
+	case SeverityWarning:
+		return 6 * time.Second
+	case SeverityError:
+		return 10 * time.Second
+	}
+	return 4 * time.Second
+}
+
+// toastExpiredMsg dismisses the toast with id
+type toastExpiredMsg struct{ id int }
+
+// toast sets the status message with a severity. It's queued as a toast once the
+// message is handled, each time even when the text repeats, or stays the status
+// line of the dialog left open.
+func (m *Model) toast(s Severity, text string) {
+	m.StatusMessage = text
+	m.severity = s
+	m.toasted = true
+}
+
+// Notify queues a toast right away, for messages from before the first update
+func (m *Model) Notify(s Severity, text string) {
+	m.toastSeq++
+	toasts := append(slices.Clip(m.Toasts), Toast{ID: m.toastSeq, Text: text, Severity: s})
+	m.Toasts = toasts[max(len(toasts)-maxToasts, 0):]
+}
+
+// queueStatus queues the status message toasted while handling a message, unless a
+// dialog is open, and returns the timers of the toasts queued since before
+func (m *Model) queueStatus(before Model) tea.Cmd {
+	if m.toasted && m.Mode == NormalMode && m.StatusMessage != "" {
+		m.Notify(m.severity, m.StatusMessage)
+	}
+	m.severity, m.toasted = SeverityInfo, false
+	var cmds []tea.Cmd
+	for _, t := range m.Toasts {
+		if t.ID > before.toastSeq {
+			cmds = append(cmds, expireToast(t))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// expireToast returns a command dismissing t once its time is up
+func expireToast(t Toast) tea.Cmd {
+	return tea.Tick(toastLifetime(t.Severity), func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: t.ID}
+	})
+}
+
+// expireToasts returns the timers of every queued toast
+func (m Model) expireToasts() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range m.Toasts {
+		cmds = append(cmds, expireToast(t))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleToastExpired dismisses a toast whose time is up
+func (m Model) handleToastExpired(msg toastExpiredMsg) (tea.Model, tea.Cmd) {
+	m.Toasts = slices.DeleteFunc(slices.Clone(m.Toasts), func(t Toast) bool { return t.ID == msg.id })
+	return m, nil
+}
+
+// statusLines returns how many lines the status bar takes below the hints: the
+// running operations and stacked toasts, or the status line of the open dialog
+func (m Model) statusLines() int {
+	if m.Mode == NormalMode {
+		return len(m.shownProgress()) + len(m.Toasts)
+	}
+	if m.StatusMessage != "" && m.Dialog != ConfirmArchive && m.Dialog != ConfirmDelete {
+		return 1
+	}
+	return 0
+}
+
+// renderToasts renders the stacked toasts, the newest at the bottom
+func (m Model) renderToasts() string {
+	lines := make([]string, len(m.Toasts))
+	for i, t := range m.Toasts {
+		icon, color := "󰙎 ", ColorGreen
+		switch t.Severity {
+		case SeverityWarning:
+			icon, color = "󰀪 ", ColorYellow
+		case SeverityError:
+			icon, color = "󰅚 ", ColorRed
+		}
+		lines[i] = lipgloss.NewStyle().
+			Foreground(color).
+			Background(ColorMantle).
+			Padding(0, 1).
+			Bold(true).
+			Render(icon + t.Text)
+	}
+	if confetti := m.renderConfetti(); confetti != "" && len(lines) > 0 {
+		lines[len(lines)-1] += " " + confetti
+	}
+	return strings.Join(lines, "\n")
+}
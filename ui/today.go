@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+// todayBannerDuration is how long the startup summary stays visible
+const todayBannerDuration = 10 * time.Second
+
+// todayMsg carries the open todos due by the end of today across all lists
+type todayMsg struct {
+	hits []search.Hit
+	open bool // show the Today view once scanned
+	err  error
+}
+
+// todayBannerExpiredMsg hides the startup summary
+type todayBannerExpiredMsg struct{}
+
+// scanToday refreshes the search index and collects todos due today or overdue.
+// In safe mode the refreshed index isn't saved.
+func (m Model) scanToday(open bool) tea.Cmd {
+	indexPath, dir, safe := m.IndexPath, m.TodoDir, m.Safe
+	return runProgress(i18n.T("Indexing lists"), func(report func(done, total int)) tea.Msg {
+		idx := search.Open(indexPath)
+		if err := idx.RefreshProgress(report, dir); err != nil {
+			return todayMsg{open: open, err: err}
+		}
+		if !safe {
+			idx.Save() // cache only, a failed write just makes the next scan slower
+		}
+
+		return todayMsg{hits: idx.DueBefore(todo.Tomorrow(time.Now()), dir), open: open}
+	})
+}
+
+// handleToday shows the scan result as a banner or in the Today view
+func (m Model) handleToday(msg todayMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.toast(SeverityError, i18n.Tf("Today scan failed: %v", msg.err))
+		return m, nil
+	}
+	m.Today = msg.hits
+	sortHits(m.Today, m.TodayView.Sort)
+
+	if msg.open {
+		m.TodayBanner = false
+		m.openDialog(TodayScreen)
+		m.TodayCursor = 0
+		m.toast(SeverityInfo, i18n.T("Today"))
+		return m, nil
+	}
+	if len(m.Today) == 0 {
+		return m, nil
+	}
+	m.TodayBanner = true
+	return m, tea.Tick(todayBannerDuration, func(time.Time) tea.Msg {
+		return todayBannerExpiredMsg{}
+	})
+}
+
+// handleTodayView handles input in the Today view
+func (m Model) handleTodayView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "j", "down":
+		if m.TodayCursor < len(m.Today)-1 {
+			m.TodayCursor++
+		}
+	case "k", "up":
+		if m.TodayCursor > 0 {
+			m.TodayCursor--
+		}
+	case "enter", " ":
+		if m.TodayCursor < len(m.Today) {
+			m.jumpTo(m.Today[m.TodayCursor])
+		}
+	case "esc", "q", "T":
+		m.closeDialog()
+		m.StatusMessage = ""
+	default:
+		switch m.Keys.Action(msg.String()) {
+		case ActionViewSort:
+			m.cycleViewSort()
+		case ActionViewColumns:
+			m.cycleViewColumns()
+		}
+	}
+	return m, nil
+}
+
+// jumpTo opens the file holding a hit and selects its todo
+func (m *Model) jumpTo(hit search.Hit) {
+	m.closeDialog()
+	m.ShowingArchive = false
+	m.noteRecent(filepath.Base(hit.File))
+	m.CurrentFile = filepath.Base(hit.File)
+	m.setList(hit.File)
+	for i, f := range m.Files {
+		if f == m.CurrentFile {
+			m.FileCursor = i
+		}
+	}
+
+	m.ActivePanel = TodoPanel
+	m.TodoCursor = max(m.TodoList.IndexOf(hit.ID), 0)
+	if m.TodoCursor < len(m.TodoList.Todos) && !m.matchesFilter(m.TodoList.Todos[m.TodoCursor]) {
+		// don't land on a todo the filters hide
+		m.ActiveContext = ""
+		m.Focus = false
+	}
+	m.clampTodoCursor()
+	m.toast(SeverityInfo, i18n.Tf("Opened: %s", m.CurrentFile))
+}
+
+// renderTodayBanner renders the startup summary of due and overdue todos
+func (m Model) renderTodayBanner() string {
+	if !m.TodayBanner || len(m.Today) == 0 {
+		return ""
+	}
+
+	overdue := 0
+	now := time.Now()
+	for _, hit := range m.Today {
+		if hit.Due.Before(now) {
+			overdue++
+		}
+	}
+	text := i18n.Tf("󰃰 %d due today", len(m.Today)-overdue)
+	if overdue > 0 {
+		text += i18n.Tf(", %d overdue", overdue)
+	}
+	text += i18n.T("  ·  T for Today view")
+
+	bannerStyle := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorTeal).
+		Bold(true).
+		Padding(0, 1)
+	return bannerStyle.Render(text) + "\n\n"
+}
+
+// renderToday renders the Today view listing overdue and due todos
+func (m Model) renderToday() string {
+	todayStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(1, 4)
+
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.T("󰃰 Today"))
+
+	content := title + "\n"
+	now := time.Now()
+	group := ""
+	for i, hit := range m.Today {
+		heading := i18n.T("Due today")
+		if hit.Due.Before(now) {
+			heading = i18n.T("Overdue")
+		}
+		if m.TodayView.Sort != "" {
+			heading = "" // sorted across both groups
+		}
+		if heading != group || i == 0 {
+			group = heading
+			content += "\n"
+			if heading != "" {
+				content += m.Styles.Muted.Render(heading) + "\n"
+			}
+		}
+
+		when := hit.Due.Format("15:04")
+		if hit.Due.Before(now) && hit.Due.YearDay() != now.YearDay() {
+			when = hit.Due.Format("Jan 2 15:04")
+		}
+		var columns []string
+		if !m.TodayView.Hides(config.ColumnList) {
+			columns = append(columns, m.displayName(filepath.Base(hit.File)))
+		}
+		if !m.TodayView.Hides(config.ColumnDue) {
+			columns = append(columns, when)
+		}
+		detail := ""
+		if len(columns) > 0 {
+			detail = m.Styles.Muted.Render("  " + strings.Join(columns, " · "))
+		}
+		if i == m.TodayCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+hit.Title+" ") + detail + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+hit.Title) + detail + "\n"
+		}
+	}
+	if len(m.Today) == 0 {
+		content += "\n" + m.Styles.Muted.Render(i18n.T("Nothing due today"))
+	}
+
+	return lipgloss.Place(
+		m.Width,
+		m.Height-4,
+		lipgloss.Center,
+		lipgloss.Center,
+		todayStyle.Render(content),
+	)
+}
@@ -2,7 +2,11 @@
 package ui
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"justdoit/config"
+	"justdoit/search"
 	"justdoit/todo"
 )
 
@@ -28,36 +32,216 @@ type Model struct {
 	ActivePanel    Panel
 	FileCursor     int
 	TodoCursor     int
+	TodoOffset     int // first visible row of the todo list
 	Mode           Mode
 	InputText      string
-	EditingIndex   int // -1 means adding new, >= 0 means editing existing, -2 means new file, -3 means archive prompt, -4 means delete prompt
+	Dialog         Dialog // open prompt or screen, NoDialog in NormalMode
+	EditingIndex   int    // todo being edited in EditTodo
 	Width          int
 	Height         int
 	StatusMessage  string
+	Toasts         []Toast // status messages stacked at the bottom, oldest first
 	Files          []string
+	Foreign        map[string]bool   // listed files that aren't todo lists, shown read-only
+	Titles         map[string]string // display titles of listed files, see readFileInfo
+	Colors         map[string]string // color names of listed files, see readFileInfo
+	ShowHidden     bool              // list internal files too, for debugging
+	Recent         []string          // recently opened files, most recent first, see LoadRecent
+	Marked         map[string]bool   // files marked with space for a batch operation
 	ArchivedFiles  []string
+	Archived       map[string]todo.Header // metadata of the archived files, see archiveHeader
 	TodoDir        string
 	ArchiveDir     string
 	CurrentFile    string
 	ShowingArchive bool
 	Styles         Styles
+	DesktopNotify  bool
+	Reminders      []ReminderAlert
+	ActiveContext  string   // @context filter applied to every view, "" for all
+	FieldFilter    string   // "key" or "key=value" custom field filter, "" for all
+	Contexts       []string // choices shown in the context switcher
+	ContextCursor  int
+	Highlights     []Highlight
+	TagIcons       map[string]TagBadge // icons of @tags in titles, see CompileTagIcons
+	Keys           Keymap
+	ConfigPath     string
+	KeyCursor      int  // selected action in the keybinding editor
+	CapturingKey   bool // keybinding editor is waiting for a new key
+	Behavior       config.Behavior
+	Table          []config.TableColumn
+	Author         string // recorded on the todos added and completed, see config.Config.Author
+	SettingsCursor int
+	Loading        string // path of the list being loaded in the background, "" when idle
+	IndexPath      string // search index file, "" keeps the index in memory
+	Today          []search.Hit
+	TodayCursor    int
+	TodayBanner    bool // show the due-today summary above the panels
+	Focus          bool // show only flagged and due-today todos, not saved
+	Maximized      bool // todo panel takes the full width, not saved
+	Zen            bool // only the open list, without panels or hints, not saved
+	TemplateDir    string
+	Templates      []string // choices shown in the template picker
+	TemplateCursor int
+	TemplateFields []string          // placeholders of the chosen template
+	TemplateValues map[string]string // values entered so far
+	Scratch        *todo.TodoList    // session-only list, nil until first opened
+	Conflicts      []string          // files changed both here and on the sync server
+	Search         string            // search query filtering the open list, "" for none
+	SearchRegex    bool              // Search is a regular expression rather than a query
+	Filters        []config.Filter   // saved searches listed below the files
+	Jira           *config.Jira      // server Jira issues are imported from, nil when not set up
+	TodayView      config.View       // how the Today view is sorted and shown
+	DebugLog       string            // file --debug logs to, "" when not debugging
+	Safe           bool              // write nothing that isn't a change made by the user
+	TrustedChecks  []string          // check commands run without asking, see config.Config.Checks
+
+	notified       map[string]bool // reminders already sent as desktop notifications
+	switchedFrom   map[string]int  // todo ID selected in each file left with openPrevious
+	resolving      *resolver       // conflict being merged
+	stats          *statsView      // shown in the stats view
+	query          search.Query    // parsed Search
+	virtual        *virtualList    // saved filter open in the todo panel, nil for a list
+	bulk           *bulkEdit       // bulk edit waiting for its deletions to be confirmed
+	logView        *logView        // shown in the debug log viewer
+	preview        *previewView    // shown over a question, see openPreview
+	archiveToggled map[string]bool // archive months folded or unfolded against their default
+	archiveGen     int             // bumped whenever the archived files change
+	collision      *collision      // archive or unarchive waiting on a taken name
+	batch          *batchOp        // change to the marked files waiting for confirmation
+	checks         *pendingChecks  // check commands waiting to be trusted
+	compare        *compareView    // second list shown beside the open one
+	picker         *filePicker     // lists matching what is typed in the file picker
+	suggestIndex   *search.Index   // search index the suggestions for a new todo come from
+	suggestions    []string        // dropdown below a new todo, see suggest
+	suggestCursor  int             // selected suggestion, -1 for none
+	dialogs        []dialogFrame   // dialogs under the open one, reopened as it closes
+	modalFocus     int             // 1 + the choice focused in the open modal, 0 for its safe one
+	severity       Severity        // of the status message set by toast, until it is queued
+	toasted        bool            // toast was called while handling the message
+	toastSeq       int             // ID of the newest toast
+	frame          *frameCache     // last rendered frame
+	frameGen       int             // frame generation of this model, 0 before the first message
+	reuseFrame     bool            // the last message changed nothing on screen
+	autosaveGen    int             // bumped when the autosave interval changes
+	loadGen        int             // bumped whenever a different list is opened
+	spinnerFrame   int
+	running        []progress // operations running in the background, see runProgress
+	moving         bool       // list files are moving in the background, see startMove
+	progressGen    int        // bumped when the spinner starts turning
+	celebrateGen   int        // bumped for each new celebration
+	celebrateFrame int        // confetti frames left, 0 when idle
+	pendingKey     string     // first keys of a multi-key sequence typed so far
+	count          string     // digits of a count prefix like the 12 in 12G
 }
 
 // Init initializes the model (Bubble Tea interface)
 func (m Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(checkReminders(m.TodoDir, true), m.scheduleAutosave(), m.scanToday(false), m.scanArchive(), m.expireToasts())
 }
 
 // Update handles messages and updates the model (Bubble Tea interface)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	start := time.Now()
+	wasDirty := m.TodoList != nil && m.TodoList.Dirty()
+	model, cmd := m.update(msg)
+	if next, ok := model.(Model); ok {
+		cmd = tea.Batch(cmd, next.handleEvents())
+		if next.Behavior.AutosaveSeconds <= 0 {
+			cmd = tea.Batch(cmd, next.saveList()) // without autosave every change is written
+		}
+		cmd = tea.Batch(cmd, next.queueStatus(m))
+		next.nextFrame(msg, m, wasDirty)
+		if !next.reuseFrame {
+			next.scrollTodos()
+		}
+		logUpdate(msg, m, next, time.Since(start))
+		return next, cmd
+	}
+	return model, cmd
+}
+
+// update dispatches a message to its handler
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
 		return m, nil
 
+	case reminderTickMsg:
+		m.TodoList.RefreshHabits(time.Now())
+		m.clampTodoCursor()
+		return m, checkReminders(m.TodoDir, true)
+
+	case remindersMsg:
+		return m.handleReminders(msg)
+
+	case autosaveTickMsg:
+		return m.handleAutosave(msg)
+
+	case listLoadedMsg:
+		return m.handleListLoaded(msg)
+
+	case toastExpiredMsg:
+		return m.handleToastExpired(msg)
+
+	case listSavedMsg:
+		return m.handleListSaved(msg)
+
+	case archiveScannedMsg:
+		return m.handleArchiveScanned(msg)
+
+	case fileDeletedMsg:
+		return m.handleFileDeleted(msg)
+
+	case fileArchivedMsg:
+		return m.handleFileArchived(msg)
+
+	case fileUnarchivedMsg:
+		return m.handleFileUnarchived(msg)
+
+	case batchDoneMsg:
+		return m.handleBatchDone(msg)
+
+	case todayMsg:
+		return m.handleToday(msg)
+
+	case heatmapMsg:
+		return m.handleHeatmap(msg)
+
+	case todayBannerExpiredMsg:
+		m.TodayBanner = false
+		return m, nil
+
+	case celebrateTickMsg:
+		return m.handleCelebrateTick(msg)
+
+	case suggestIndexMsg:
+		return m.handleSuggestIndex(msg)
+
+	case soundFailedMsg:
+		return m.handleSoundFailed(msg)
+
+	case progressMsg:
+		return m.handleProgress(msg)
+
+	case progressTickMsg:
+		return m.handleProgressTick(msg)
+
+	case editorFinishedMsg:
+		return m.handleEditorFinished(msg)
+
+	case bulkEditedMsg:
+		return m.handleBulkEdited(msg)
+
+	case checksDoneMsg:
+		return m.handleChecksDone(msg)
+
+	case jiraIssueMsg:
+		return m.handleJiraIssue(msg)
+
 	case tea.MouseMsg:
-		if m.Mode == NormalMode {
+		if m.Mode == NormalMode && !m.moving {
 			return m.handleMouse(msg)
 		}
 
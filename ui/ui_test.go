@@ -0,0 +1,2224 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/remote"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+// TestMain renders without colors so golden files are plain text, and drops log records
+func TestMain(m *testing.M) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// newTestModel creates a model backed by a temp data directory holding one list
+func newTestModel(t *testing.T, titles ...string) Model {
+	t.Helper()
+
+	todoDir := t.TempDir()
+	archiveDir := filepath.Join(todoDir, "archive")
+	os.MkdirAll(archiveDir, 0755)
+
+	tl := todo.NewTodoList(filepath.Join(todoDir, "work.json"))
+	for i := len(titles) - 1; i >= 0; i-- {
+		tl.Add(titles[i])
+	}
+	tl.Save()
+
+	return Model{
+		TodoList:    tl,
+		ActivePanel: FilePanel,
+		Mode:        NormalMode,
+		Files:       LoadTodoFiles(todoDir),
+		TodoDir:     todoDir,
+		ArchiveDir:  archiveDir,
+		CurrentFile: "work.json",
+		Styles:      NewStyles(),
+		Keys:        DefaultKeymap(),
+		Behavior:    config.Default().Behavior,
+	}
+}
+
+// runKeys drives the model with a key script and returns the final model
+func runKeys(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(100, 24))
+	for _, k := range keys {
+		tm.Send(k)
+	}
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	m = tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(Model)
+	todo.Settle() // saves still running in the background
+	return m
+}
+
+// runMove drives the model with a key script whose last key moves files in the
+// background, and shows the files once they're moved
+func runMove(t *testing.T, m Model, keys ...tea.KeyMsg) Model {
+	t.Helper()
+
+	m = runKeys(t, m, keys[:len(keys)-1]...)
+	model, cmd := m.update(keys[len(keys)-1])
+	if !model.(Model).moving {
+		t.Fatalf("Expected %q to move files", keys[len(keys)-1])
+	}
+	model, _ = model.Update(result(cmd))
+	return model.(Model)
+}
+
+// keys converts a script of single-character keys into key messages
+func keys(script string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, r := range script {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+// script joins key sequences into one
+func script(parts ...[]tea.KeyMsg) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, p := range parts {
+		msgs = append(msgs, p...)
+	}
+	return msgs
+}
+
+// result runs a command and, for an operation reporting progress, waits for what it returns
+func result(cmd tea.Cmd) tea.Msg {
+	msg := cmd()
+	for {
+		p, ok := msg.(progressMsg)
+		if !ok {
+			return msg
+		}
+		if p.finished {
+			return p.result
+		}
+		msg = <-p.updates
+	}
+}
+
+var (
+	enter     = []tea.KeyMsg{{Type: tea.KeyEnter}}
+	esc       = []tea.KeyMsg{{Type: tea.KeyEsc}}
+	backspace = []tea.KeyMsg{{Type: tea.KeyBackspace}}
+)
+
+// TestAddTodo tests adding a todo from the todo panel
+func TestAddTodo(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "existing"), script(keys("la"), keys("buy milk"), enter)...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if got := todo.NewTodoList(m.TodoList.Path()).Todos[0].Title; got != "buy milk" {
+		t.Errorf("Expected new todo on disk at top, got %q", got)
+	}
+}
+
+// TestAddTodoCancel tests that Esc discards the new todo
+func TestAddTodoCancel(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "existing"), script(keys("la"), keys("nope"), esc)...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if len(m.TodoList.Todos) != 1 {
+		t.Errorf("Expected 1 todo after cancel, got %d", len(m.TodoList.Todos))
+	}
+}
+
+// TestEditTodo tests editing a todo title in place
+func TestEditTodo(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "second"),
+		script(keys("lji"), backspace, backspace, backspace, backspace, backspace, backspace, keys("2nd"), enter)...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if got := m.TodoList.Todos[1].Title; got != "2nd" {
+		t.Errorf("Expected edited title 2nd, got %q", got)
+	}
+}
+
+// TestDeleteTodo tests deleting the selected todo
+func TestDeleteTodo(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "second", "third"), keys("ljd")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if len(todo.NewTodoList(m.TodoList.Path()).Todos) != 2 {
+		t.Error("Expected deletion to be saved")
+	}
+}
+
+// TestToggleTodo tests completing a todo moves it to the bottom
+func TestToggleTodo(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "second"), keys("lx")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if !m.TodoList.Todos[1].Completed {
+		t.Error("Expected completed todo at the bottom")
+	}
+}
+
+// TestArchivePrompt tests the archive dialog shown when every todo is complete
+func TestArchivePrompt(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "only"), keys("lx")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+}
+
+// TestDeleteConfirmation tests the dialog asking before a file is deleted, drawn like
+// the archive dialog
+func TestDeleteConfirmation(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "keep me"), keys("d")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+}
+
+// TestMoveInBackground tests that deleting a file runs off the update, with keys that
+// could touch the files waiting until it's done
+func TestMoveInBackground(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "keep me"), keys("d")...)
+	model, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if !exists(m.TodoDir, "work.json") {
+		t.Fatal("Expected work.json deleted in the background, not right away")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if m = model.(Model); m.Dialog != NoDialog || m.StatusMessage != "Still moving files, please wait" {
+		t.Fatalf("Expected adding a file to wait for the move, got %q", m.StatusMessage)
+	}
+
+	model, _ = m.Update(result(cmd))
+	if m = model.(Model); m.moving || exists(m.TodoDir, "work.json") || m.CurrentFile != "default.json" {
+		t.Errorf("Expected work.json deleted and default.json open, got %q", m.CurrentFile)
+	}
+}
+
+// TestModalButtons tests that Enter answers a modal with its focused choice, the safe
+// one until the arrow keys or tab move the focus
+func TestModalButtons(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "keep me"), keys("d")...)
+	m = runKeys(t, m, enter...)
+	if m.Dialog != NoDialog || m.StatusMessage != "Cancelled" {
+		t.Fatalf("Expected Enter to cancel by default, got %q", m.StatusMessage)
+	}
+	if _, err := os.Stat(filepath.Join(m.TodoDir, "work.json")); err != nil {
+		t.Fatalf("Expected work.json kept: %v", err)
+	}
+
+	m = runKeys(t, m, keys("d")...)
+	m = runKeys(t, m, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyTab})
+	if d, _ := m.openModal(); m.focusedChoice(d) != 1 {
+		t.Errorf("Expected the focus to wrap around to No, got %d", m.focusedChoice(d))
+	}
+	m = runMove(t, m, tea.KeyMsg{Type: tea.KeyLeft}, enter[0])
+	if _, err := os.Stat(filepath.Join(m.TodoDir, "work.json")); !os.IsNotExist(err) {
+		t.Error("Expected Enter on Yes to delete work.json")
+	}
+
+	// Choices named by Esc are answered as Esc, and the focus starts over in each modal
+	m = newTestModel(t, "only")
+	m.openDialog(ConfirmQuit)
+	if d, _ := m.openModal(); m.focusedChoice(d) != 2 {
+		t.Errorf("Expected Stay focused, got %d", m.focusedChoice(d))
+	}
+	m = runKeys(t, m, enter...)
+	if m.Dialog != NoDialog {
+		t.Error("Expected Enter on Stay to close the modal")
+	}
+}
+
+// TestArchiveFile tests archiving a completed list
+func TestArchiveFile(t *testing.T) {
+	m := runMove(t, newTestModel(t, "only"), keys("lxy")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if _, err := os.Stat(filepath.Join(m.ArchiveDir, "work.json")); err != nil {
+		t.Errorf("Expected work.json in archive: %v", err)
+	}
+}
+
+// TestDeleteFile tests deleting a file after confirmation
+func TestDeleteFile(t *testing.T) {
+	m := newTestModel(t, "keep me")
+	other := todo.NewTodoList(filepath.Join(m.TodoDir, "other.json"))
+	other.Save()
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.FileCursor = 1
+
+	m = runMove(t, m, keys("dy")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if _, err := os.Stat(filepath.Join(m.TodoDir, "work.json")); !os.IsNotExist(err) {
+		t.Error("Expected work.json to be deleted")
+	}
+}
+
+// TestScrollFollowsCursor tests that long lists render only the window around the cursor
+func TestScrollFollowsCursor(t *testing.T) {
+	titles := make([]string, 40)
+	for i := range titles {
+		titles[i] = fmt.Sprintf("todo %d", i+1)
+	}
+	m := runKeys(t, newTestModel(t, titles...), keys("l"+strings.Repeat("j", 30))...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if view := m.View(); strings.Contains(view, "todo 1 ") || !strings.Contains(view, "todo 31") {
+		t.Error("Expected the window to scroll to the cursor")
+	}
+}
+
+// TestSortSetting tests that turning on sorting moves completed todos down, saved in
+// the background like other changes
+func TestSortSetting(t *testing.T) {
+	m := newTestModel(t, "done first", "still open")
+	m.Behavior.SortCompleted = false
+	m.TodoList.SetAutoSort(false)
+	m.TodoList.SetDeferredSave(true)
+	m.TodoList.Toggle(0)
+
+	m.openSettings()
+	m.SettingsCursor = 2
+	model, _ := m.update(enter[0])
+	m = model.(Model)
+	if !m.Behavior.SortCompleted || m.TodoList.Todos[0].Title != "still open" || !m.TodoList.Dirty() {
+		t.Fatalf("Expected the list sorted and left for saveList, got %v", m.TodoList.Todos)
+	}
+	result(m.saveList())
+	if got := todo.NewTodoList(m.TodoList.Path()).Todos[0].Title; got != "still open" {
+		t.Errorf("Expected the sorted order saved, got %q first", got)
+	}
+}
+
+// TestOpenLargeListInBackground tests that large files load asynchronously behind a spinner
+func TestOpenLargeListInBackground(t *testing.T) {
+	m := newTestModel(t, "small")
+	big := todo.NewTodoList(filepath.Join(m.TodoDir, "big.json"))
+	for i := 0; i < 5000; i++ {
+		big.Todos = append(big.Todos, todo.Todo{ID: i + 1, Title: fmt.Sprintf("todo %d with a long enough title", i+1)})
+	}
+	big.NextID = 5001
+	big.Save()
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.Width, m.Height = 100, 24
+	m.Behavior.MaxTitleLength = 10
+
+	model, cmd := m.update(tea.KeyMsg{Type: tea.KeyEnter}) // just the load, without toast timers
+	m = model.(Model)
+	if m.Loading == "" || cmd == nil {
+		t.Fatal("Expected big.json to load in the background")
+	}
+	if !strings.Contains(m.View(), "Loading big.json") {
+		t.Error("Expected a loading spinner in the todo panel")
+	}
+
+	// Todo actions wait for the load
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if model.(Model).TodoList.Todos[0].Completed {
+		t.Error("Expected toggle to be blocked while loading")
+	}
+
+	model, _ = m.Update(result(cmd))
+	m = model.(Model)
+	if m.Loading != "" || len(m.TodoList.Todos) != 5000 {
+		t.Errorf("Expected big.json to be shown after loading, got %d todos", len(m.TodoList.Todos))
+	}
+	m.TodoList.Add("a title over the limit")
+	if slices.ContainsFunc(m.TodoList.Todos, func(td todo.Todo) bool { return td.Title == "a title over the limit" }) {
+		t.Error("Expected the behavior settings applied to the loaded list")
+	}
+}
+
+// TestTodayView tests jumping from the Today view to a todo in another file
+func TestTodayView(t *testing.T) {
+	m := newTestModel(t, "not due")
+	home := todo.NewTodoList(filepath.Join(m.TodoDir, "home.json"))
+	home.Add("pay rent")
+	home.Add("water plants")
+	yesterday := time.Now().AddDate(0, 0, -1)
+	home.SetDue(1, &yesterday)
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.Width, m.Height = 100, 30
+
+	model, _ := m.Update(result(m.scanToday(true)))
+	m = model.(Model)
+	if m.Dialog != TodayScreen || len(m.Today) != 1 {
+		t.Fatalf("Expected Today view with one todo, got %d", len(m.Today))
+	}
+	if view := m.View(); !strings.Contains(view, "Overdue") || !strings.Contains(view, "pay rent") {
+		t.Error("Expected the overdue todo in the Today view")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.CurrentFile != "home.json" || m.TodoList.Todos[m.TodoCursor].Title != "pay rent" {
+		t.Errorf("Expected to land on pay rent in home.json, got %s", m.CurrentFile)
+	}
+}
+
+// TestFocusMode tests that focus mode hides todos that are neither flagged nor due today
+func TestFocusMode(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "urgent", "third"), keys("lj!F")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if visible := m.visibleIndices(); len(visible) != 1 || m.TodoList.Todos[visible[0]].Title != "urgent" {
+		t.Errorf("Expected only the flagged todo in focus mode, got %v", visible)
+	}
+	if !todo.NewTodoList(m.TodoList.Path()).Todos[1].Flagged {
+		t.Error("Expected the flag to be saved")
+	}
+}
+
+// TestCelebrateConfetti tests that completing a todo plays the confetti animation to the end
+func TestCelebrateConfetti(t *testing.T) {
+	m := newTestModel(t, "first", "second")
+	m.Behavior.Celebrate = config.CelebrateConfetti
+	m.ActivePanel = TodoPanel
+	m.Width, m.Height = 100, 24
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = model.(Model)
+	if cmd == nil || m.renderConfetti() == "" {
+		t.Fatal("Expected confetti after completing a todo")
+	}
+
+	for i := 0; i < celebrateFrames; i++ {
+		model, _ = model.Update(celebrateTickMsg{gen: m.celebrateGen})
+	}
+	if model.(Model).renderConfetti() != "" {
+		t.Error("Expected the animation to finish")
+	}
+
+	// Unchecking is not celebrated
+	for _, k := range keys("jx") {
+		model, _ = model.Update(k)
+	}
+	if model.(Model).TodoList.Todos[1].Completed || model.(Model).renderConfetti() != "" {
+		t.Error("Expected no confetti when unchecking")
+	}
+}
+
+// TestCelebrateListEvents tests that completions anywhere in the data directory are
+// celebrated, and those of lists elsewhere are not
+func TestCelebrateListEvents(t *testing.T) {
+	m := newTestModel(t, "call landlord")
+	m.Behavior.Celebrate = config.CelebrateConfetti
+	inbox := todo.NewTodoList(filepath.Join(m.TodoDir, "inbox.json"))
+	inbox.Add("read contract")
+	inbox.Save()
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.ActivePanel = TodoPanel
+
+	m = runKeys(t, m, script(keys("|"), enter, []tea.KeyMsg{{Type: tea.KeyTab}}, keys("x"))...)
+	if m.celebrateFrame != 2*celebrateFrames {
+		t.Errorf("Expected the completed inbox to be celebrated, got %d frames", m.celebrateFrame)
+	}
+
+	m.celebrateFrame = 0
+	other := todo.NewTodoList(filepath.Join(t.TempDir(), "other.json"))
+	other.Add("not ours")
+	other.Toggle(0)
+	if m = runKeys(t, m, keys("j")...); m.celebrateFrame != 0 {
+		t.Error("Expected no confetti for a list outside the data directory")
+	}
+}
+
+// TestCompletionSound tests that the sound command is run for the enabled events only
+func TestCompletionSound(t *testing.T) {
+	m := newTestModel(t, "first")
+	log := filepath.Join(t.TempDir(), "events.log")
+	m.Behavior.SoundCommand = fmt.Sprintf(`echo "$JUSTDOIT_EVENT" >> %q`, log)
+	if m.playSound(true) != nil {
+		t.Fatal("Expected silence by default")
+	}
+
+	m.Behavior.SoundTodo, m.Behavior.SoundList = true, true
+	for _, listDone := range []bool{false, true} {
+		if msg := m.playSound(listDone)(); msg != nil {
+			t.Fatalf("Expected the sound to play, got %v", msg)
+		}
+	}
+	m.Behavior.SoundList = false
+	m.playSound(true)()
+	if data, _ := os.ReadFile(log); string(data) != "todo\nlist\ntodo\n" {
+		t.Errorf("Expected a todo, list and todo sound, got %q", data)
+	}
+
+	m.Behavior.SoundCommand = "exit 3"
+	model, _ := m.Update(m.playSound(false)())
+	if status := model.(Model).StatusMessage; !strings.Contains(status, "exit status 3") {
+		t.Errorf("Expected a warning for the failed command, got %q", status)
+	}
+}
+
+// TestSuggestions tests completing a new todo from earlier titles and contexts
+func TestSuggestions(t *testing.T) {
+	m := newTestModel(t, "water the plants @home", "call mom @family")
+	other := todo.NewTodoList(filepath.Join(m.TodoDir, "chores.json"))
+	other.Add("pay water bill @errands")
+	other.Save()
+	m.ActivePanel = TodoPanel
+	m.Width, m.Height = 100, 24
+
+	var model tea.Model = m
+	press := func(msgs ...tea.KeyMsg) {
+		for _, k := range msgs {
+			model, _ = model.Update(k)
+		}
+	}
+	press(keys("a")...)
+	model, _ = model.Update(model.(Model).loadSuggestions()())
+	press(keys("wat")...)
+	m = model.(Model)
+	if want := []string{"water the plants @home", "pay water bill @errands"}; !slices.Equal(m.suggestions, want) {
+		t.Fatalf("Expected earlier titles suggested, got %v", m.suggestions)
+	}
+	if view := m.View(); !strings.Contains(view, "pay water bill @errands") {
+		t.Errorf("Expected the suggestions below the input, got:\n%s", view)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	press(enter...)
+	press(keys(" @fa")...)
+	if got := model.(Model).suggestions; !slices.Equal(got, []string{"@family"}) {
+		t.Fatalf("Expected the context suggested, got %v", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	press(enter...)
+	m = model.(Model)
+	if m.Dialog != NoDialog {
+		t.Fatalf("Expected the todo saved, got dialog %v with %q", m.Dialog, m.InputText)
+	}
+	if got := m.TodoList.Todos[m.TodoCursor].Title; got != "pay water bill @errands @family" {
+		t.Errorf("Expected the completed title, got %q", got)
+	}
+}
+
+// TestAuthors tests that the initials of other people sharing a list are shown
+func TestAuthors(t *testing.T) {
+	m := newTestModel(t, "pack kitchen", "book van")
+	m.Author = "Sam"
+	m.TodoList.SetAuthor(m.Author)
+	m.TodoList.Todos[0].AddedBy = "alex"
+	m.TodoList.Todos[1].AddedBy = "sam"
+	m.Width, m.Height = 100, 24
+	if view := m.View(); !strings.Contains(view, " A") || strings.Contains(view, " S") {
+		t.Errorf("Expected only the other author's initial, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, keys("lx")...)
+	done := m.TodoList.Todos[m.TodoList.IndexOf(2)]
+	if done.CompletedBy != "Sam" {
+		t.Fatalf("Expected the completion attributed, got %+v", done)
+	}
+	if view := m.View(); strings.Contains(view, " A") {
+		t.Errorf("Expected no initial once you completed it, got:\n%s", view)
+	}
+}
+
+// TestJiraImport tests importing a Jira issue by key as a todo keeping its key and link
+func TestJiraImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "dev@example.com" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/rest/api/2/issue/PROJ-7" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"key":"PROJ-7","fields":{"summary":"Fix login redirect"}}`)
+	}))
+	defer server.Close()
+
+	m := newTestModel(t, "stand-up")
+	m.ActivePanel = TodoPanel
+	var model tea.Model = m
+	var cmd tea.Cmd
+	importKey := func(key string) {
+		for _, k := range script(keys("gi"), keys(key), enter) {
+			model, cmd = model.(Model).update(k) // without toast timers
+		}
+		if cmd != nil {
+			model, _ = model.Update(result(cmd))
+		}
+	}
+
+	for _, k := range keys("gi") {
+		model, _ = model.Update(k)
+	}
+	if m = model.(Model); m.Dialog != NoDialog || !strings.Contains(m.StatusMessage, "jira.url") {
+		t.Errorf("Expected a hint to set up Jira, got %q", m.StatusMessage)
+	}
+
+	m.Jira = &config.Jira{URL: server.URL + "/", Email: "dev@example.com", Token: "secret"}
+	model = m
+	importKey("proj-7")
+	m = model.(Model)
+	got := m.TodoList.Todos[m.TodoCursor]
+	if got.Title != "Fix login redirect" || got.Fields[todo.JiraField] != "PROJ-7" || got.Notes != server.URL+"/browse/PROJ-7" {
+		t.Fatalf("Expected the issue imported at the cursor, got %+v", got)
+	}
+
+	importKey("PROJ-7")
+	if m = model.(Model); len(m.TodoList.Todos) != 2 || !strings.Contains(m.StatusMessage, "already") {
+		t.Errorf("Expected an issue imported once, got %d todos and %q", len(m.TodoList.Todos), m.StatusMessage)
+	}
+	importKey("PROJ-8")
+	if m = model.(Model); len(m.TodoList.Todos) != 2 || !strings.Contains(m.StatusMessage, "PROJ-8") {
+		t.Errorf("Expected an error for a missing issue, got %q", m.StatusMessage)
+	}
+}
+
+// TestTagIcons tests that configured tags show their icon and the others a generic one
+func TestTagIcons(t *testing.T) {
+	m := newTestModel(t, "fix crash @bug @work", "call plumber @Call")
+	m.Width, m.Height = 100, 24
+	if view := m.View(); strings.Contains(view, "") {
+		t.Errorf("Expected tags left alone without icons, got:\n%s", view)
+	}
+
+	m.TagIcons = CompileTagIcons(map[string]config.TagIcon{
+		"bug":   {Icon: "", Color: "red"},
+		"#call": {Icon: "", Color: "#00ff00"},
+	})
+	if m.TagIcons["bug"].color != ColorRed || m.TagIcons["call"].color != "#00ff00" {
+		t.Errorf("Expected list color names and hex colors, got %+v", m.TagIcons)
+	}
+	view := m.View()
+	for _, want := range []string{" @bug", " @work", " @Call"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the todo panel, got:\n%s", want, view)
+		}
+	}
+}
+
+// TestTableLayout tests the todo panel as a table with configurable columns
+func TestTableLayout(t *testing.T) {
+	m := newTestModel(t, "ship release @work", "renew passport")
+	m.Width, m.Height = 120, 24
+	due := time.Date(2031, time.March, 4, 9, 0, 0, 0, time.Local)
+	m.TodoList.SetDue(0, &due)
+	m.TodoList.SetField(0, "priority", "high")
+	m.Behavior.TodoLayout = config.LayoutTable
+
+	view := m.View()
+	for _, want := range []string{"Title", "Tags", "Due", "Priority", "Age", "ship release ", "@work", "Mar 4 09:00", "high", "0h"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the table, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "release @work") {
+		t.Error("Expected the tags moved out of the title")
+	}
+
+	m.Table = []config.TableColumn{{Name: "title", Width: 20}, {Name: "age", Width: 6}, {Name: "bogus"}}
+	columns := m.tableColumns()
+	if len(columns) != 2 || columns[0].width != 20 || columns[1].width != 6 {
+		t.Errorf("Expected the configured columns and widths, got %+v", columns)
+	}
+	if view := m.View(); strings.Contains(view, "Tags") || !strings.Contains(view, "release @work") {
+		t.Errorf("Expected only the configured columns, got:\n%s", view)
+	}
+
+	m.TodoList.SetKind(todo.KindHabit)
+	if m.tableColumns() != nil {
+		t.Error("Expected habit lists to keep the list layout")
+	}
+}
+
+// TestCompareLists tests showing a second list beside the open one and moving todos
+// between them
+func TestCompareLists(t *testing.T) {
+	m := newTestModel(t, "call landlord", "file taxes")
+	inbox := todo.NewTodoList(filepath.Join(m.TodoDir, "inbox.json"))
+	inbox.Add("read contract")
+	inbox.Save()
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.ActivePanel = TodoPanel
+
+	m = runKeys(t, m, script(keys("|"), enter)...)
+	if m.Dialog != CompareScreen || m.compare.file != "inbox.json" {
+		t.Fatalf("Expected inbox beside work, got dialog %v", m.Dialog)
+	}
+	m.Width, m.Height = 120, 24
+	if view := m.View(); !strings.Contains(view, "call landlord") || !strings.Contains(view, "read contract") {
+		t.Errorf("Expected both lists on screen, got:\n%s", view)
+	}
+
+	// Move "call landlord" across, then "read contract" back from the inbox
+	m = runKeys(t, m, script(keys("m"), []tea.KeyMsg{{Type: tea.KeyTab}}, keys("jm"), esc)...)
+	titles := func(tl *todo.TodoList) []string {
+		var titles []string
+		for _, t := range tl.Todos {
+			titles = append(titles, t.Title)
+		}
+		return titles
+	}
+	if got := titles(todo.NewTodoList(filepath.Join(m.TodoDir, "inbox.json"))); !slices.Equal(got, []string{"call landlord"}) {
+		t.Errorf("Expected the inbox to hold the moved todo, got %v", got)
+	}
+	if got := titles(todo.NewTodoList(filepath.Join(m.TodoDir, "work.json"))); !slices.Equal(got, []string{"read contract", "file taxes"}) {
+		t.Errorf("Expected work to get the inbox's todo, got %v", got)
+	}
+	if m.Dialog != NoDialog || m.compare != nil {
+		t.Error("Expected Esc to close the compare screen")
+	}
+}
+
+// TestHabitList tests that habit lists show streaks and never offer to archive
+func TestHabitList(t *testing.T) {
+	m := newTestModel(t, "stretch", "read")
+	m.Behavior.ArchivePrompt = true
+	m = runKeys(t, m, keys("lbxx")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if m.Mode != NormalMode {
+		t.Error("Expected no archive prompt for a completed habit list")
+	}
+	saved := todo.NewTodoList(m.TodoList.Path())
+	if !saved.IsHabit() || len(saved.Todos[0].History) != 1 {
+		t.Errorf("Expected a saved habit list with history, got kind %q and %v", saved.Kind, saved.Todos[0].History)
+	}
+}
+
+// TestNewFromTemplate tests filling in a template's placeholders and creating a list from it
+func TestNewFromTemplate(t *testing.T) {
+	m := newTestModel(t, "first")
+	m.TemplateDir = filepath.Join(m.TodoDir, "templates")
+	os.MkdirAll(m.TemplateDir, 0755)
+	tmpl := todo.NewTodoList(filepath.Join(m.TemplateDir, "trip-{{city}}.json"))
+	tmpl.Add("Book hotel in {{city}}")
+	tmpl.Add("Pack for {{nights}} nights")
+	tmpl.Save()
+
+	m = runKeys(t, m, script(
+		keys("N"),
+		[]tea.KeyMsg{{Type: tea.KeyEnter}},
+		keys("3"),
+		[]tea.KeyMsg{{Type: tea.KeyEnter}},
+		keys("Oslo"),
+		[]tea.KeyMsg{{Type: tea.KeyEnter}, {Type: tea.KeyEnter}},
+	)...)
+
+	if m.CurrentFile != "trip-Oslo.json" || m.Mode != NormalMode {
+		t.Fatalf("Expected trip-Oslo.json to be opened, got %q", m.CurrentFile)
+	}
+	saved := todo.NewTodoList(filepath.Join(m.TodoDir, "trip-Oslo.json"))
+	if len(saved.Todos) != 2 || saved.Todos[0].Title != "Pack for 3 nights" || saved.Todos[1].Title != "Book hotel in Oslo" {
+		t.Errorf("Expected filled-in todos, got %+v", saved.Todos)
+	}
+}
+
+// TestFollowLink tests jumping to a todo in another list and back by reference
+func TestFollowLink(t *testing.T) {
+	m := newTestModel(t, "call plumber [[home#2]]", "unlinked")
+	home := todo.NewTodoList(filepath.Join(m.TodoDir, "home.json"))
+	home.Add("fix sink")
+	home.Add("buy pipes ref:1")
+	home.Save()
+	m.Files = LoadTodoFiles(m.TodoDir)
+
+	m = runKeys(t, m, keys("lo")...)
+	if m.CurrentFile != "home.json" || m.TodoList.Todos[m.TodoCursor].Title != "buy pipes ref:1" {
+		t.Fatalf("Expected to land on todo #2 in home.json, got %q in %s", m.TodoList.Todos[m.TodoCursor].Title, m.CurrentFile)
+	}
+
+	m = runKeys(t, m, keys("o")...)
+	if m.TodoList.Todos[m.TodoCursor].Title != "fix sink" {
+		t.Errorf("Expected ref:1 to jump to fix sink, got %q", m.TodoList.Todos[m.TodoCursor].Title)
+	}
+
+	m = runKeys(t, m, keys("o")...)
+	if m.StatusMessage != "No link in this todo" {
+		t.Errorf("Expected a no-link message, got %q", m.StatusMessage)
+	}
+}
+
+// TestFoldSection tests folding the cursor's section with the "z a" sequence
+func TestFoldSection(t *testing.T) {
+	m := newTestModel(t, "Trip", "book hotel", "pack")
+	m.TodoList.ToggleHeading(0)
+
+	m = runKeys(t, m, keys("ljjza")...)
+	if !m.TodoList.Todos[0].Collapsed || m.TodoCursor != 0 {
+		t.Fatalf("Expected the section folded with the cursor on its heading, got cursor %d", m.TodoCursor)
+	}
+
+	// An interrupted sequence does nothing, then "z a" unfolds
+	m = runKeys(t, m, keys("zjza")...)
+	if m.TodoList.Todos[0].Collapsed || m.TodoCursor != 0 {
+		t.Errorf("Expected the section unfolded, got cursor %d", m.TodoCursor)
+	}
+}
+
+// TestCustomFields tests setting a field on a todo and filtering by it
+func TestCustomFields(t *testing.T) {
+	m := newTestModel(t, "fix login", "write docs")
+	m = runKeys(t, m, script(
+		keys("lm"),
+		keys("client=acme"),
+		[]tea.KeyMsg{{Type: tea.KeyEnter}},
+		keys("=client"),
+		[]tea.KeyMsg{{Type: tea.KeyEnter}},
+	)...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if visible := m.visibleIndices(); len(visible) != 1 || m.TodoList.Todos[visible[0]].Title != "fix login" {
+		t.Errorf("Expected only the todo with a client field, got %v", visible)
+	}
+	if saved := todo.NewTodoList(m.TodoList.Path()); saved.Todos[0].Fields["client"] != "acme" {
+		t.Errorf("Expected the field to be saved, got %v", saved.Todos[0].Fields)
+	}
+}
+
+// TestCopyToClipboard tests copying the selected todo and the whole list
+func TestCopyToClipboard(t *testing.T) {
+	var copied []string
+	clipboardWrite = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	t.Cleanup(func() { clipboardWrite = writeClipboard })
+
+	m := runKeys(t, newTestModel(t, "first", "second"), keys("ljyY")...)
+
+	want := []string{"second", "# work\n\n- [ ] first\n- [ ] second\n"}
+	if len(copied) != 2 || copied[0] != want[0] || copied[1] != want[1] {
+		t.Errorf("Expected %q copied, got %q", want, copied)
+	}
+	if m.StatusMessage != "Copied work.json as Markdown" {
+		t.Errorf("Unexpected status %q", m.StatusMessage)
+	}
+}
+
+// TestPasteIntoInput tests pasting from the clipboard and bracketed paste while adding
+func TestPasteIntoInput(t *testing.T) {
+	clipboardRead = func() (string, error) { return "call\r\nplumber\x1b", nil }
+	t.Cleanup(func() { clipboardRead = readClipboard })
+
+	m := runKeys(t, newTestModel(t), script(
+		keys("la"),
+		[]tea.KeyMsg{{Type: tea.KeyCtrlV}},
+		[]tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("\tsoon"), Paste: true}},
+	)...)
+	if m.InputText != "call plumber soon" {
+		t.Errorf("Expected sanitized paste, got %q", m.InputText)
+	}
+
+	long := []rune(strings.Repeat("x", maxInputLength+10))
+	model, _ := m.handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: long, Paste: true})
+	m = model.(Model)
+	if len([]rune(m.InputText)) != maxInputLength || !strings.Contains(m.StatusMessage, "truncated") {
+		t.Errorf("Expected truncation to %d with a warning, got %d runes and %q", maxInputLength, len([]rune(m.InputText)), m.StatusMessage)
+	}
+}
+
+// TestLongTitlesKeepLayout tests that titles wider than the panel are cut instead of wrapping
+func TestLongTitlesKeepLayout(t *testing.T) {
+	long := strings.Repeat("very long title ", 10)
+	wide := strings.Repeat("日本語", 20)
+	m := runKeys(t, newTestModel(t, long, wide, "short"), keys("l")...)
+
+	view := m.View()
+	teatest.RequireEqualOutput(t, []byte(view))
+	if lines := strings.Count(view, "\n"); lines != 23 {
+		t.Errorf("Expected the view to fill 24 rows, got %d", lines+1)
+	}
+}
+
+// TestAccessibleMode tests the line-oriented view with text markers instead of icons
+func TestAccessibleMode(t *testing.T) {
+	m := newTestModel(t, "write report", "call bob", "water plants")
+	m.Behavior.Accessible = true
+	m = runKeys(t, m, keys("l!jx")...)
+
+	view := m.View()
+	teatest.RequireEqualOutput(t, []byte(view))
+	if !strings.Contains(view, "> [ ] water plants") || !strings.Contains(view, "[DONE] call bob") {
+		t.Errorf("Expected text markers and a cursor marker, got:\n%s", view)
+	}
+	for _, r := range view {
+		if unicode.In(r, unicode.Co) {
+			t.Fatalf("Expected no icon glyphs, found %U", r)
+		}
+	}
+}
+
+// TestResizePanels tests moving the panel split, saving it, and maximizing the todo panel
+func TestResizePanels(t *testing.T) {
+	m := newTestModel(t, "write report")
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	m = runKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlL}, tea.KeyMsg{Type: tea.KeyCtrlL}, tea.KeyMsg{Type: tea.KeyCtrlH})
+
+	if left, right := m.panelWidths(); left != 30 || right != 66 {
+		t.Errorf("Expected 30/66 columns at 30%%, got %d/%d", left, right)
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil || cfg.Behavior.FilePanelWidth != 30 {
+		t.Errorf("Expected width 30 saved to config, got %d (%v)", cfg.Behavior.FilePanelWidth, err)
+	}
+
+	m = runKeys(t, m, keys("M")...)
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if !m.Maximized || m.ActivePanel != TodoPanel {
+		t.Fatal("Expected the todo panel maximized and active")
+	}
+
+	m = runKeys(t, m, keys("h")...)
+	if m.Maximized {
+		t.Error("Expected going to the file panel to restore the split")
+	}
+}
+
+// TestHints tests that the hints show the bound keys, put the filters in use first,
+// and fit the window with a pointer to the rest
+func TestHints(t *testing.T) {
+	m := newTestModel(t, "write report @work")
+	m.ActivePanel = TodoPanel
+	var err error
+	if m.Keys, err = NewKeymap(map[string][]string{"toggle": {"ctrl+t"}}); err != nil {
+		t.Fatal(err)
+	}
+	if hints := ansi.Strip(m.renderHints()); !strings.Contains(hints, "ctrl+t/Space   toggle") || !strings.Contains(hints, "q   quit") {
+		t.Errorf("Expected the rebound key and every hint without a width, got %q", hints)
+	}
+
+	m.Width = 60
+	hints := ansi.Strip(m.renderHints())
+	if ansi.StringWidth(hints) > m.Width || !strings.HasSuffix(strings.TrimSpace(hints), "?   for more") {
+		t.Errorf("Expected the hints cut to fit, ending in ? for more, got %q", hints)
+	}
+
+	m.ActiveContext = "work"
+	if hints := ansi.Strip(m.renderHints()); !strings.Contains(hints, "C   clear filters") {
+		t.Errorf("Expected a hint to clear the filters in use, got %q", hints)
+	}
+}
+
+// TestZenMode tests hiding the file panel, borders and hints around the open list
+func TestZenMode(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "write report", "call bob"), keys("Zjx")...)
+
+	view := m.View()
+	teatest.RequireEqualOutput(t, []byte(view))
+	if strings.Contains(view, "Files") || strings.Contains(view, "navigate") {
+		t.Errorf("Expected no file panel or hints in zen mode, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, esc...)
+	if m.Zen || m.ActivePanel != FilePanel {
+		t.Error("Expected Esc to leave zen mode for the file panel")
+	}
+}
+
+// TestTitleBar tests the breadcrumb of data directory and file, and the save state
+func TestTitleBar(t *testing.T) {
+	m := newTestModel(t, "write report")
+	m.Behavior.TitleBar = true
+	m.Behavior.AutosaveSeconds = 60
+	m.TodoList.SetDeferredSave(true)
+	m = runKeys(t, m, keys("l")...)
+
+	bar := m.renderTitleBar()
+	if !strings.Contains(bar, m.TodoDir+" › work.json") || !strings.Contains(bar, "saved") {
+		t.Errorf("Expected the path and save state, got %q", bar)
+	}
+	if lines := strings.Count(m.View(), "\n"); lines != 23 {
+		t.Errorf("Expected the view to fill 24 rows with the bar, got %d", lines+1)
+	}
+
+	m = runKeys(t, m, keys("x")...)
+	if bar := m.renderTitleBar(); !strings.Contains(bar, "unsaved changes") {
+		t.Errorf("Expected pending changes to show, got %q", bar)
+	}
+}
+
+// TestShortenHome tests abbreviating paths under the home directory
+func TestShortenHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got := shortenHome(filepath.Join(home, ".tui_todos")); got != filepath.Join("~", ".tui_todos") {
+		t.Errorf("Expected ~/.tui_todos, got %q", got)
+	}
+	if got := shortenHome("/srv/todos"); got != "/srv/todos" {
+		t.Errorf("Expected paths outside home unchanged, got %q", got)
+	}
+}
+
+// TestClearFilters tests that one key drops every filter and the chip row goes away
+func TestClearFilters(t *testing.T) {
+	m := newTestModel(t, "call plumber @home", "write report @office")
+	m.ActiveContext = "home"
+	m.FieldFilter = "client"
+	m = runKeys(t, m, keys("lF")...)
+	if m.renderFilterRow() == "" {
+		t.Fatal("Expected filter chips while filters are active")
+	}
+
+	m = runKeys(t, m, keys("C")...)
+	if m.ActiveContext != "" || m.FieldFilter != "" || m.Focus {
+		t.Errorf("Expected all filters cleared, got context %q, field %q, focus %v", m.ActiveContext, m.FieldFilter, m.Focus)
+	}
+	if m.renderFilterRow() != "" || len(m.visibleIndices()) != 2 {
+		t.Error("Expected the whole list without filter chips")
+	}
+}
+
+// TestLineNumbers tests relative line numbers and jumping with counts and the : prompt
+func TestLineNumbers(t *testing.T) {
+	m := newTestModel(t, "one", "two", "three", "four", "five")
+	m.Behavior.LineNumbers = config.LineNumbersRelative
+	m = runKeys(t, m, keys("l3j")...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if m.TodoCursor != 3 {
+		t.Fatalf("Expected 3j to move to the fourth todo, got %d", m.TodoCursor)
+	}
+
+	m = runKeys(t, m, keys("2G")...)
+	if m.TodoCursor != 1 {
+		t.Errorf("Expected 2G to jump to line 2, got %d", m.TodoCursor)
+	}
+	m = runKeys(t, m, keys("G")...)
+	if m.TodoCursor != 4 {
+		t.Errorf("Expected G to jump to the last line, got %d", m.TodoCursor)
+	}
+	m = runKeys(t, m, script(keys(":1"), enter)...)
+	if m.TodoCursor != 0 || m.Mode != NormalMode {
+		t.Errorf("Expected :1 to jump to the first line, got %d", m.TodoCursor)
+	}
+	m = runKeys(t, m, script(keys(":9"), enter)...)
+	if m.TodoCursor != 0 || m.StatusMessage != "No line 9" {
+		t.Errorf("Expected a missing line to be reported, got %q", m.StatusMessage)
+	}
+}
+
+// TestQuickToggle tests checking off todos of the previewed file with number keys
+func TestQuickToggle(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "buy milk", "call bob", "water plants"), keys("2")...)
+
+	if m.ActivePanel != FilePanel {
+		t.Error("Expected to stay in the file panel")
+	}
+	saved := todo.NewTodoList(m.TodoList.Path())
+	for _, td := range saved.Todos {
+		if td.Completed != (td.Title == "call bob") {
+			t.Errorf("Expected only the second todo done, got %q completed=%v", td.Title, td.Completed)
+		}
+	}
+
+	m = runKeys(t, m, keys("9")...)
+	if m.StatusMessage != "No todo 9 in work.json" {
+		t.Errorf("Expected a missing todo to be reported, got %q", m.StatusMessage)
+	}
+}
+
+// TestScratchpad tests jotting todos in memory and promoting one to the open file
+func TestScratchpad(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "existing"), script(
+		keys("s"),
+		keys("a"), keys("idea"), enter,
+		keys("a"), keys("keep"), enter,
+	)...)
+
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if !m.TodoList.IsScratch() || len(m.TodoList.Todos) != 2 {
+		t.Fatalf("Expected two todos in the scratchpad, got %+v", m.TodoList.Todos)
+	}
+	if got := todo.NewTodoList(filepath.Join(m.TodoDir, "work.json")).Todos; len(got) != 1 {
+		t.Fatalf("Expected the file untouched before promoting, got %+v", got)
+	}
+
+	m = runKeys(t, m, keys("ps")...)
+	if m.TodoList.IsScratch() || m.TodoList.Todos[0].Title != "keep" {
+		t.Errorf("Expected the promoted todo on top of work.json, got %+v", m.TodoList.Todos)
+	}
+
+	m = runKeys(t, m, keys("s")...)
+	if len(m.TodoList.Todos) != 1 || m.TodoList.Todos[0].Title != "idea" {
+		t.Errorf("Expected the scratchpad to keep the rest for the session, got %+v", m.TodoList.Todos)
+	}
+}
+
+// TestEscalateLists tests compiling escalation rules and applying them to the data directory
+func TestEscalateLists(t *testing.T) {
+	if _, err := CompileEscalations([]config.EscalationRule{{OpenFor: "14d"}}); err == nil {
+		t.Error("Expected a rule without flag or field to be rejected")
+	}
+	if _, err := CompileEscalations([]config.EscalationRule{{OverdueFor: "soon", Flag: true}}); err == nil {
+		t.Error("Expected an invalid duration to be rejected")
+	}
+	rules, err := CompileEscalations([]config.EscalationRule{{OpenFor: "14d", Field: "priority=high"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(t, "old", "new")
+	m.TodoList.Todos[0].CreatedAt = time.Now().AddDate(0, 0, -15)
+	m.TodoList.Save()
+
+	if got := EscalateLists(m.TodoDir, rules); got != "Escalated 1 neglected todos in work" {
+		t.Errorf("Expected a summary of the escalation, got %q", got)
+	}
+	if got := EscalateLists(m.TodoDir, rules); got != "" {
+		t.Errorf("Expected nothing left to escalate, got %q", got)
+	}
+	if saved := todo.NewTodoList(m.TodoList.Path()); saved.Todos[0].Fields["priority"] != "high" {
+		t.Errorf("Expected priority=high on the old todo, got %+v", saved.Todos[0])
+	}
+}
+
+// TestCheckTodos tests that a todo's shell command decides whether it is done
+func TestCheckTodos(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "backup ran today", "write report"), script(
+		keys("lc"), keys("test -f backup.log"), enter,
+	)...)
+	if m.TodoList.Todos[0].Check != "test -f backup.log" {
+		t.Fatalf("Expected the check command saved, got %+v", m.TodoList.Todos[0])
+	}
+	id := m.TodoList.Todos[0].ID
+
+	run := func() Model {
+		cmd := m.startChecks(-1)
+		model, _ := m.Update(result(cmd))
+		return model.(Model)
+	}
+	m = run()
+	if m.TodoList.Todos[0].Completed || !strings.HasPrefix(m.StatusMessage, "Checks: 0 passed, 1 failed") {
+		t.Errorf("Expected the failing check to keep the todo open, got %q", m.StatusMessage)
+	}
+
+	os.WriteFile(filepath.Join(m.TodoDir, "backup.log"), nil, 0644)
+	m = run()
+	done := m.TodoList.Todos[m.TodoList.IndexOf(id)]
+	if !done.Completed || m.StatusMessage != "Checks: 1 passed, 0 failed" {
+		t.Errorf("Expected the passing check to complete the todo, got %q", m.StatusMessage)
+	}
+
+	if err := runCheck(m.TodoDir, "sleep 5", 50*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a slow check to time out, got %v", err)
+	}
+}
+
+// TestUntrustedChecks tests that check commands coming with a list are asked about
+// before they run
+func TestUntrustedChecks(t *testing.T) {
+	m := newTestModel(t, "cleaned up")
+	m.TodoList.SetCheck(0, "touch ran")
+	ran := filepath.Join(m.TodoDir, "ran")
+
+	m = runKeys(t, m, keys("lu")...)
+	if m.Dialog != ConfirmChecks || !strings.Contains(m.View(), "touch ran") {
+		t.Fatalf("Expected to be asked about the command, got dialog %d", m.Dialog)
+	}
+	m = runKeys(t, m, keys("n")...)
+	if exists(m.TodoDir, "ran") || len(m.TrustedChecks) != 0 {
+		t.Fatalf("Expected the cancelled command not run")
+	}
+
+	m = runKeys(t, m, keys("u")...)
+	model, cmd := m.update(keys("y")[0])
+	model, _ = model.Update(result(cmd))
+	m = model.(Model)
+	if _, err := os.Stat(ran); err != nil || !m.TodoList.Todos[0].Completed {
+		t.Errorf("Expected the trusted command run, got %q", m.StatusMessage)
+	}
+	if !slices.Equal(m.TrustedChecks, []string{"touch ran"}) {
+		t.Errorf("Expected the command trusted from now on, got %v", m.TrustedChecks)
+	}
+}
+
+// TestResolveConflict tests merging a list changed both here and on the sync server
+func TestResolveConflict(t *testing.T) {
+	m := newTestModel(t, "only here", "renamed here", "shared")
+	os.MkdirAll(filepath.Join(m.TodoDir, remote.ConflictDir), 0755)
+	server := todo.NewTodoList(filepath.Join(m.TodoDir, remote.ConflictDir, "work.json"))
+	server.Todos = slices.Clone(m.TodoList.Todos[1:])
+	server.NextID = m.TodoList.NextID
+	server.Update(0, "renamed there")
+	server.Add("only there")
+	m.Conflicts = remote.Conflicts(m.TodoDir)
+
+	m = runKeys(t, m, keys("X")...)
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+	if m.Dialog != ConflictResolver || len(m.resolving.items) != 3 {
+		t.Fatalf("Expected the resolver with 3 diverging todos, got dialog %d", m.Dialog)
+	}
+
+	m = runKeys(t, m, script(keys("jb"), enter)...)
+	var titles []string
+	for _, td := range m.TodoList.Todos {
+		titles = append(titles, td.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "only here, renamed here, renamed there, shared, only there" {
+		t.Errorf("Expected the merge in the open list, got %s", got)
+	}
+	if len(m.Conflicts) != 0 || m.renderConflictBanner() != "" {
+		t.Errorf("Expected the conflict settled, got %v", m.Conflicts)
+	}
+}
+
+// translatable returns the UI strings passed to i18n.T, i18n.Tf and renderDesc,
+// plus the settings and keybinding labels
+func translatable(t *testing.T) []string {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strs := append([]string{}, settingNames...)
+	for _, a := range actions {
+		strs = append(strs, a.description)
+	}
+	ast.Inspect(pkgs["ui"], func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		switch fn := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := fn.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+				return true
+			}
+		case *ast.Ident:
+			if fn.Name != "renderDesc" {
+				return true
+			}
+		default:
+			return true
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			s, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			strs = append(strs, s)
+		}
+		return true
+	})
+	return strs
+}
+
+// TestStatsView tests the forecast and burndown of the open list and the completion heatmap
+func TestStatsView(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "second", "third"), keys("lxI")...)
+	if m.Dialog != StatsScreen {
+		t.Fatalf("Expected the stats view, got dialog %d", m.Dialog)
+	}
+	view := m.View()
+	for _, want := range []string{"2 open, 1 done and 3 added in the last 14 days", "Not shrinking at the current pace", "██"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the stats view:\n%s", want, view)
+		}
+	}
+
+	model, _ := m.Update(m.openStats()())
+	m = runKeys(t, model.(Model), keys("hh")...)
+	weekStart := m.stats.heatmap.Start.AddDate(0, 0, 7*(heatmapWeeks-3))
+	if want := "Week of " + weekStart.Format("Jan 2") + ": 0 completed"; !strings.Contains(m.View(), want) {
+		t.Errorf("Expected %q after moving back two weeks:\n%s", want, m.View())
+	}
+
+	m = runKeys(t, m, esc...)
+	if m.Mode != NormalMode || m.stats != nil {
+		t.Error("Expected Esc to close the stats view")
+	}
+}
+
+// TestStatsFollowsChanges tests that the open stats view follows changes to its list
+// made outside it
+func TestStatsFollowsChanges(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "first", "second", "third"), keys("I")...)
+	m.TodoList.Toggle(0)
+	m.TodoList.Add("fourth")
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 24})
+	if want := "3 open, 1 done and 4 added in the last 14 days"; !strings.Contains(model.(Model).View(), want) {
+		t.Errorf("Expected %q in the stats view:\n%s", want, model.(Model).View())
+	}
+}
+
+// TestStatsDrillDown tests that selecting a breakdown row filters the open list
+func TestStatsDrillDown(t *testing.T) {
+	m := runKeys(t, newTestModel(t, "call mum @phone", "fix sink @home", "buy milk @home"), keys("I")...)
+	if view := m.View(); !strings.Contains(view, "@home                2     0") {
+		t.Fatalf("Expected two open todos for @home:\n%s", view)
+	}
+
+	m = runKeys(t, m, script(keys("j"), enter)...)
+	if m.Mode != NormalMode || m.ActiveContext != "phone" || m.ActivePanel != TodoPanel {
+		t.Fatalf("Expected the @phone todos in the todo panel, got context %q", m.ActiveContext)
+	}
+	if visible := m.visibleIndices(); len(visible) != 1 || m.TodoList.Todos[visible[0]].Title != "call mum @phone" {
+		t.Errorf("Expected only the @phone todo, got %v", visible)
+	}
+}
+
+// TestSplitShownTodos tests that splitting moves the todos the search shows, not just a context
+func TestSplitShownTodos(t *testing.T) {
+	m := newTestModel(t, "call mum @phone", "buy milk", "buy bread")
+	m = runKeys(t, m, keys("lS")...)
+	if m.Dialog == SplitPrompt {
+		t.Fatal("Expected splitting an unfiltered list to be refused")
+	}
+
+	m = runKeys(t, m, script(keys("/"), keys("buy"), enter, keys("S"))...)
+	if m.Dialog != SplitPrompt || !strings.Contains(m.StatusMessage, "Move the 2 shown todos") {
+		t.Fatalf("Expected to be asked for a file for the 2 shown todos, got %q", m.StatusMessage)
+	}
+	m = runKeys(t, m, script(keys("errands"), enter)...)
+	errands := todo.NewTodoList(filepath.Join(m.TodoDir, "errands.json"))
+	if len(errands.Todos) != 2 || len(m.TodoList.Todos) != 1 || m.TodoList.Todos[0].Title != "call mum @phone" {
+		t.Errorf("Expected the searched todos split off, got %d moved and %v left", len(errands.Todos), m.TodoList.Todos)
+	}
+}
+
+// TestSearchAndSavedFilters tests searching the open list, saving the search and opening it as a virtual list
+func TestSearchAndSavedFilters(t *testing.T) {
+	m := newTestModel(t, "call mum @phone", "fix sink @home", "buy milk @home")
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	garden := todo.NewTodoList(filepath.Join(m.TodoDir, "garden.json"))
+	garden.Add("mow lawn @home")
+	m.Files = LoadTodoFiles(m.TodoDir)
+
+	m = runKeys(t, m, script(keys("l/"), keys("@home OR"), enter)...)
+	if m.Mode != EditMode || !strings.HasPrefix(m.StatusMessage, "Invalid query") {
+		t.Fatalf("Expected the prompt to stay open on a bad query, got %q", m.StatusMessage)
+	}
+	m = runKeys(t, m, script(backspace, backspace, backspace, enter, keys("W"), keys("home"), enter)...)
+	if visible := m.visibleIndices(); len(visible) != 2 || m.Search != "@home" {
+		t.Fatalf("Expected the two @home todos, got %v for %q", visible, m.Search)
+	}
+	cfg, _ := config.Load(m.ConfigPath)
+	if want := []config.Filter{{Name: "home", Query: "@home"}}; !reflect.DeepEqual(cfg.Filters, want) || !reflect.DeepEqual(m.Filters, want) {
+		t.Fatalf("Expected the filter saved, got %v", cfg.Filters)
+	}
+
+	// garden.json, work.json, then the filter
+	m = runKeys(t, m, script(keys("Chjj"), enter)...)
+	if m.listName() != "home" || m.Search != "" {
+		t.Fatalf("Expected the home filter open without a search, got %s", m.listName())
+	}
+	teatest.RequireEqualOutput(t, []byte(m.View()))
+
+	m = runKeys(t, m, keys("i")...)
+	if m.Mode != NormalMode || !strings.HasPrefix(m.StatusMessage, "Saved filters are read-only") {
+		t.Errorf("Expected edits to be refused, got %q", m.StatusMessage)
+	}
+
+	m = runKeys(t, m, script(keys("jjj"), enter)...)
+	if m.virtual != nil || m.CurrentFile != "work.json" || m.TodoList.Todos[m.TodoCursor].Title != "fix sink @home" {
+		t.Errorf("Expected to land on fix sink in work.json, got %s", m.CurrentFile)
+	}
+}
+
+// TestRegexSearch tests the regex toggle, its inline errors and regex saved filters
+func TestRegexSearch(t *testing.T) {
+	m := newTestModel(t, "Review JIRA-42 fix", "ask about JIRA board", "NOJIRA-7 cleanup")
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	ctrlR := tea.KeyMsg{Type: tea.KeyCtrlR}
+
+	m = runKeys(t, m, script(keys("l/"), []tea.KeyMsg{ctrlR}, keys(`\bJIRA-(\d+\b`))...)
+	if !m.SearchRegex || !strings.Contains(m.View(), "✗ missing closing )") {
+		t.Fatalf("Expected the compile error shown while typing, got %q", m.renderSearchChip())
+	}
+	m = runKeys(t, m, enter...)
+	if m.Mode != EditMode || !strings.HasPrefix(m.StatusMessage, "Invalid pattern") {
+		t.Fatalf("Expected the prompt to stay open on a bad pattern, got %q", m.StatusMessage)
+	}
+
+	m = runKeys(t, m, script(backspace, backspace, backspace, backspace, backspace, backspace, keys(`\d+\b`), enter)...)
+	if visible := m.visibleIndices(); len(visible) != 1 || m.TodoList.Todos[visible[0]].Title != "Review JIRA-42 fix" {
+		t.Fatalf("Expected only JIRA-42 for %q, got %v", m.Search, visible)
+	}
+
+	m = runKeys(t, m, script(keys("W"), keys("tickets"), enter)...)
+	if want := []config.Filter{{Name: "tickets", Query: `\bJIRA-\d+\b`, Regex: true}}; !reflect.DeepEqual(m.Filters, want) {
+		t.Fatalf("Expected a regex filter, got %v", m.Filters)
+	}
+	m = runKeys(t, m, script(keys("Chj"), enter)...)
+	if m.listName() != "tickets" || len(m.TodoList.Todos) != 2 {
+		t.Errorf("Expected the filter to find JIRA-42 under its list, got %v", m.TodoList.Todos)
+	}
+}
+
+// TestViewSettings tests that smart views keep their own sort order and columns in the config
+func TestViewSettings(t *testing.T) {
+	m := newTestModel(t, "pay rent @home", "fix sink @home", "call mum @phone")
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	soon := time.Now().Add(time.Hour)
+	m.TodoList.SetDue(1, &soon)
+	garden := todo.NewTodoList(filepath.Join(m.TodoDir, "garden.json"))
+	garden.Add("mow lawn @home")
+	m.Files = LoadTodoFiles(m.TodoDir)
+	m.Filters = []config.Filter{{Name: "home", Query: "@home"}}
+
+	// garden.json, work.json, then the filter
+	m = runKeys(t, m, script(keys("jj"), enter, keys("v"))...)
+	var titles []string
+	for _, todo := range m.TodoList.Todos {
+		titles = append(titles, todo.Title)
+	}
+	if want := []string{"fix sink @home", "mow lawn @home", "pay rent @home"}; !slices.Equal(titles, want) {
+		t.Fatalf("Expected the filter sorted by due date without headings, got %v", titles)
+	}
+	if !strings.Contains(m.View(), "fix sink @home  󰃰") || !strings.Contains(m.View(), "mow lawn @home  garden") {
+		t.Errorf("Expected the due date and list shown")
+	}
+
+	m = runKeys(t, m, keys("V")...)
+	if m.StatusMessage != "Columns: fields" || strings.Contains(m.View(), "fix sink @home  󰃰") {
+		t.Errorf("Expected the due date hidden, got %q", m.StatusMessage)
+	}
+	cfg, _ := config.Load(m.ConfigPath)
+	if want := (config.View{Sort: config.SortDue, Hide: []string{config.ColumnDue}}); !reflect.DeepEqual(cfg.Filters[0].View, want) {
+		t.Errorf("Expected the view saved with the filter, got %+v", cfg.Filters[0].View)
+	}
+
+	hits := []search.Hit{{File: "b.json", Title: "water plants", Due: &soon}, {File: "a.json", Title: "book dentist", Due: &soon}}
+	next, _ := m.Update(todayMsg{hits: hits, open: true})
+	next, _ = next.(Model).handleTodayView(keys("v")[0])
+	m = next.(Model)
+	if m.Today[0].Title != "book dentist" || m.StatusMessage != "Sorted by title" {
+		t.Errorf("Expected the Today view sorted by title, got %v", m.Today)
+	}
+	if cfg, _ := config.Load(m.ConfigPath); cfg.Today.Sort != config.SortTitle || cfg.Filters[0].Sort != config.SortDue {
+		t.Errorf("Expected each view to keep its own sort, got %+v", cfg)
+	}
+}
+
+// TestBulkEdit tests applying text edited in $EDITOR, with removed lines deleted once confirmed
+func TestBulkEdit(t *testing.T) {
+	m := newTestModel(t, "buy milk @home", "call mum @phone", "fix sink @home", "pay rent @home")
+	m.ActiveContext = "home"
+
+	// What openBulkEditor writes for the shown todos, edited
+	listed := []int{4, 2, 1} // buy milk, fix sink, pay rent
+	text := strings.NewReplacer(
+		"- [ ] buy milk", "- [ ] buy oat milk",
+		"- [ ] fix sink", "- [x] fix sink",
+		"- [ ] pay rent @home {#1}", "water plants @home",
+	).Replace(todo.BulkText("work", []todo.Todo{m.TodoList.Todos[0], m.TodoList.Todos[2], m.TodoList.Todos[3]}))
+	path := filepath.Join(t.TempDir(), "bulk.md")
+	os.WriteFile(path, []byte(text), 0644)
+
+	next, _ := m.handleBulkEdited(bulkEditedMsg{path: path, listed: listed})
+	m = next.(Model)
+	if m.Dialog != ConfirmBulkDelete || m.StatusMessage != "Delete 1 todos removed in the editor? (y/n)" {
+		t.Fatalf("Expected the deletion to be confirmed first, got %q", m.StatusMessage)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("Expected the temporary file removed")
+	}
+
+	m = runKeys(t, m, keys("y")...)
+	if m.StatusMessage != "Bulk edit: 1 added, 2 updated, 1 deleted" {
+		t.Fatalf("Unexpected status %q", m.StatusMessage)
+	}
+	var titles []string
+	for _, todo := range todo.NewTodoList(m.TodoList.Path()).Todos {
+		titles = append(titles, todo.Title)
+	}
+	if want := []string{"buy oat milk @home", "call mum @phone", "water plants @home", "fix sink @home"}; !slices.Equal(titles, want) {
+		t.Errorf("Expected %v, got %v", want, titles)
+	}
+}
+
+// TestSafeModeSkipsIndexCache tests that the startup scan writes no index cache in safe mode
+func TestSafeModeSkipsIndexCache(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	m.IndexPath = filepath.Join(t.TempDir(), "index.json")
+	m.Safe = true
+
+	if msg := result(m.scanToday(false)).(todayMsg); msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	if _, err := os.Stat(m.IndexPath); err == nil {
+		t.Error("Expected no index cache written")
+	}
+}
+
+// TestDebugLog tests that keys are logged with slog and shown in the log viewer
+func TestDebugLog(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	m = runKeys(t, m, keys("L")...)
+	if !strings.HasPrefix(m.StatusMessage, "No debug log") {
+		t.Errorf("Expected a hint to start with --debug, got %q", m.StatusMessage)
+	}
+
+	m.DebugLog = filepath.Join(t.TempDir(), "debug.log")
+	f, _ := os.Create(m.DebugLog)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	slog.Error("save", "path", "work.json", "err", "disk full")
+	f.Close()
+
+	m = runKeys(t, next.(Model), keys("L")...)
+	view := m.View()
+	if m.Dialog != DebugLogScreen || !strings.Contains(view, "msg=key key=l") || !strings.Contains(view, "err=\"disk full\"") {
+		t.Errorf("Expected the key and error in the viewer, got:\n%s", view)
+	}
+}
+
+// TestInlineInputKeepsContext tests that adding far down a long list keeps the rows
+// around the cursor in view and that long input wraps instead of being cut
+func TestInlineInputKeepsContext(t *testing.T) {
+	var titles []string
+	for i := 1; i <= 40; i++ {
+		titles = append(titles, fmt.Sprintf("todo %02d", i))
+	}
+	m := newTestModel(t, titles...)
+	long := strings.Repeat("word ", 30) + "end"
+	m = runKeys(t, m, script(keys("l30Ga"), keys("new one"))...)
+	view := m.View()
+	if !strings.Contains(view, "todo 30") || !strings.Contains(view, "new one█") || strings.Contains(view, "todo 01") {
+		t.Errorf("Expected the input above the rows around the cursor, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, script(esc, keys("i"), keys(long))...)
+	view = m.View()
+	if !strings.Contains(view, "end█") || strings.Count(view, "word") != 30 || !strings.Contains(view, "todo 29") {
+		t.Errorf("Expected the edited line to wrap with its neighbours in view, got:\n%s", view)
+	}
+}
+
+// TestNewFileName tests that unusable file names are refused with the reason shown
+// and that typed names are tidied before the file is created
+func TestNewFileName(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	m = runKeys(t, m, script(keys("a../evil"))...)
+	if !strings.Contains(m.View(), "✗ names can't contain") {
+		t.Errorf("Expected the problem below the prompt, got:\n%s", m.View())
+	}
+	m = runKeys(t, m, enter...)
+	if m.Mode != EditMode || !strings.HasPrefix(m.StatusMessage, "Invalid name") {
+		t.Errorf("Expected the prompt to stay open, got %q", m.StatusMessage)
+	}
+
+	m.InputText = "work"
+	if _, err := m.newFileName(); err == nil {
+		t.Error("Expected an existing list to be refused")
+	}
+
+	m.InputText = ""
+	m.Behavior.LowercaseNames = true
+	m = runKeys(t, m, script(keys(" Side Project "), enter)...)
+	if m.CurrentFile != "side-project.json" || !slices.Contains(m.Files, "side-project.json") {
+		t.Errorf("Expected side-project.json to be created, got %q in %v", m.CurrentFile, m.Files)
+	}
+}
+
+// TestForeignFile tests that a JSON file that isn't a todo list is marked and left untouched
+func TestForeignFile(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	config := filepath.Join(m.TodoDir, "config.json")
+	os.WriteFile(config, []byte(`{"theme": "dark"}`), 0644)
+	m.loadFiles()
+	m.Width, m.Height = 100, 24
+	if view := m.View(); !strings.Contains(view, "⊘ config.json") {
+		t.Errorf("Expected config.json marked as foreign, got:\n%s", view)
+	}
+
+	m.CurrentFile = "config.json"
+	m.TodoList = m.loadList(config)
+	m = runKeys(t, m, keys("lanew")...)
+	if m.Mode != NormalMode || !strings.HasPrefix(m.StatusMessage, "Not a todo list") {
+		t.Errorf("Expected adding to be refused, got %q", m.StatusMessage)
+	}
+	if data, _ := os.ReadFile(config); string(data) != `{"theme": "dark"}` {
+		t.Errorf("Expected config.json untouched, got %s", data)
+	}
+}
+
+// TestHiddenFiles tests that internal files stay out of the file panel until revealed,
+// and are read-only then
+func TestHiddenFiles(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	sync := filepath.Join(m.TodoDir, ".sync.json")
+	os.WriteFile(sync, []byte(`{"todos": [{"id": 1, "title": "state"}]}`), 0644)
+	os.WriteFile(filepath.Join(m.TodoDir, "work.json.corrupted"), []byte(`{"todos": [`), 0644)
+	m.loadFiles()
+	if slices.Contains(m.Files, ".sync.json") || slices.Contains(m.Files, "work.json.corrupted") {
+		t.Fatalf("Expected hidden files left out, got %v", m.Files)
+	}
+
+	m = runKeys(t, m, keys(".")...)
+	if !slices.Contains(m.Files, ".sync.json") || !slices.Contains(m.Files, "work.json.corrupted") {
+		t.Fatalf("Expected hidden files revealed, got %v", m.Files)
+	}
+
+	m.CurrentFile = ".sync.json"
+	m.TodoList = m.loadList(sync)
+	m = runKeys(t, m, keys("lx")...)
+	if !strings.HasPrefix(m.StatusMessage, "Hidden file") {
+		t.Errorf("Expected toggling to be refused, got %q", m.StatusMessage)
+	}
+	if m.TodoList.Todos[0].Completed {
+		t.Error("Expected the hidden file's todo unchanged")
+	}
+
+	m = runKeys(t, m, keys(".")...)
+	if slices.Contains(m.Files, ".sync.json") || m.CurrentFile == ".sync.json" {
+		t.Errorf("Expected hidden files hidden again, got %v open at %q", m.Files, m.CurrentFile)
+	}
+}
+
+// TestFileNames tests that files are sorted naturally and shown without .json or
+// under their title
+func TestFileNames(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	for _, name := range []string{"week10.json", "week2.json", "Week1.json"} {
+		todo.NewTodoList(filepath.Join(m.TodoDir, name)).Save()
+	}
+	m.loadFiles()
+	if want := []string{"Week1.json", "week2.json", "week10.json", "work.json"}; !slices.Equal(m.Files, want) {
+		t.Errorf("Expected %v, got %v", want, m.Files)
+	}
+
+	m = runKeys(t, m, script(keys("tAlpha"), enter)...)
+	if got := todo.ReadTitle(filepath.Join(m.TodoDir, "work.json")); got != "Alpha" {
+		t.Errorf("Expected the title saved in work.json, got %q", got)
+	}
+	if m.Files[0] != "work.json" || m.FileCursor != 0 {
+		t.Errorf("Expected work.json sorted by its title, got %v at %d", m.Files, m.FileCursor)
+	}
+	m.Width, m.Height = 100, 24
+	view := m.View()
+	if !strings.Contains(view, "Alpha") || !strings.Contains(view, "week10 ") || strings.Contains(view, "week10.json") {
+		t.Errorf("Expected display names without .json, got:\n%s", view)
+	}
+}
+
+// TestRecentFiles tests that opened files are remembered, shown above the files and
+// that ctrl+o jumps back
+func TestRecentFiles(t *testing.T) {
+	m := newTestModel(t, "pay rent", "buy stamps")
+	todo.NewTodoList(filepath.Join(m.TodoDir, "week.json")).Save()
+	m.loadFiles()
+
+	m = runKeys(t, m, enter...)
+	if m.CurrentFile != "week.json" || !slices.Equal(m.Recent, []string{"week.json", "work.json"}) {
+		t.Fatalf("Expected week.json opened after work.json, got %q with %v", m.CurrentFile, m.Recent)
+	}
+	m.Width, m.Height = 100, 24
+	if view := m.View(); !strings.Contains(view, "recent") || !strings.Contains(view, "󰋚 work") {
+		t.Errorf("Expected work in the recent group, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.CurrentFile != "work.json" {
+		t.Errorf("Expected ctrl+o to go back to work.json, got %q", m.CurrentFile)
+	}
+	if got := LoadRecent(m.TodoDir); !slices.Equal(got, []string{"work.json", "week.json"}) {
+		t.Errorf("Expected the recent files saved, got %v", got)
+	}
+	if slices.Contains(LoadTodoFiles(m.TodoDir), recentFile) {
+		t.Error("Expected the recent file hidden from the file panel")
+	}
+
+	// Backtick and ctrl+^ flip between the two, each keeping its selected todo
+	m = runKeys(t, m, keys("j`")...)
+	if m.CurrentFile != "week.json" {
+		t.Fatalf("Expected backtick to flip to week.json, got %q", m.CurrentFile)
+	}
+	m = runKeys(t, m, tea.KeyMsg{Type: tea.KeyCtrlCaret})
+	if m.CurrentFile != "work.json" || m.TodoList.Todos[m.TodoCursor].Title != "buy stamps" {
+		t.Errorf("Expected ctrl+^ back at the todo left in work.json, got %q at %d", m.CurrentFile, m.TodoCursor)
+	}
+
+	// Safe mode keeps them in memory only
+	m.Safe = true
+	m = runKeys(t, m, keys("`")...)
+	if got := LoadRecent(m.TodoDir); m.Recent[0] != "week.json" || got[0] != "work.json" {
+		t.Errorf("Expected the recent files left unsaved in safe mode, got %v on disk", got)
+	}
+}
+
+// TestFilePicker tests that the file picker lists recent files first, narrows them to
+// the typed name or title, best match first, and opens the chosen one
+func TestFilePicker(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	for _, name := range []string{"big-road-trip.json", "groceries.json", "weekend.json"} {
+		todo.NewTodoList(filepath.Join(m.TodoDir, name)).Save()
+	}
+	m.loadFiles()
+	m.Recent = []string{"weekend.json"}
+	m.Titles = map[string]string{"groceries.json": "Shopping"}
+
+	m.OpenFilePicker()
+	if m.Dialog != FilePicker || m.picker.matches[0] != "weekend.json" || len(m.picker.matches) != 4 {
+		t.Fatalf("Expected every list with the recent one first, got %v", m.picker.matches)
+	}
+	m = runKeys(t, m, keys("gro")...)
+	if !slices.Equal(m.picker.matches, []string{"groceries.json", "big-road-trip.json"}) {
+		t.Errorf("Expected the closest match first, got %v", m.picker.matches)
+	}
+	m = runKeys(t, m, keys("zz")...)
+	m = runKeys(t, m, enter...)
+	if m.Dialog != FilePicker || !strings.Contains(m.StatusMessage, "No list matches") {
+		t.Errorf("Expected the picker kept open without a match, got %q", m.StatusMessage)
+	}
+
+	m.InputText = ""
+	m = runKeys(t, m, keys("shop")...)
+	m = runKeys(t, m, enter...)
+	if m.Dialog != NoDialog || m.CurrentFile != "groceries.json" || m.ActivePanel != TodoPanel {
+		t.Errorf("Expected groceries.json opened by its title, got %q", m.CurrentFile)
+	}
+}
+
+// TestArchiveByMonth tests that archived files are grouped under foldable months and
+// that archiving records the date
+func TestArchiveByMonth(t *testing.T) {
+	m := newTestModel(t, "only")
+	for name, day := range map[string]string{"a.json": "2024-06-01", "b.json": "2024-06-20", "c.json": "2024-05-03"} {
+		at, _ := time.Parse(time.DateOnly, day)
+		todo.NewTodoList(filepath.Join(m.ArchiveDir, name)).SetArchived(at, "")
+	}
+	old := filepath.Join(m.ArchiveDir, "old.json")
+	todo.NewTodoList(old).Save()
+	before := time.Date(2023, 1, 10, 0, 0, 0, 0, time.Local)
+	os.Chtimes(old, before, before)
+	scanned, _ := m.handleArchiveScanned(result(m.scanArchive()).(archiveScannedMsg))
+	m = scanned.(Model)
+
+	m = runKeys(t, m, keys("z")...)
+	var got []string
+	for _, row := range m.archiveRows() {
+		got = append(got, fmt.Sprintf("%s:%s:%d:%t", row.month, row.file, row.count, row.folded))
+	}
+	want := []string{"2024-06::2:false", "2024-06:b.json:0:false", "2024-06:a.json:0:false", "2024-05::1:true", "2023-01::1:true"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected rows %v, got %v", want, got)
+	}
+
+	m = runKeys(t, m, script(keys("jjj"), enter)...)
+	if rows := m.archiveRows(); len(rows) != 6 || rows[4].file != "c.json" {
+		t.Errorf("Expected May unfolded, got %v", rows)
+	}
+	m = runMove(t, m, script(keys("j"), enter)...)
+	if m.ShowingArchive || m.CurrentFile != "c.json" {
+		t.Fatalf("Expected c.json unarchived, got %q", m.CurrentFile)
+	}
+	if at := todo.ReadHeader(filepath.Join(m.TodoDir, "c.json")).Archived; !at.IsZero() {
+		t.Errorf("Expected the archive date cleared, got %v", at)
+	}
+
+	m = runMove(t, m, keys("hAy")...)
+	if at := m.Archived["c.json"].Archived; time.Since(at) > time.Minute {
+		t.Errorf("Expected the archive date recorded, got %v", at)
+	}
+}
+
+// TestDatedArchive tests that dated archives get the date in their name, can be
+// renamed past an earlier archive, and get their old name back when unarchived
+func TestDatedArchive(t *testing.T) {
+	m := newTestModel(t, "only")
+	m.Behavior.DatedArchives = true
+	dated := "work_" + time.Now().Format(time.DateOnly)
+	todo.NewTodoList(filepath.Join(m.ArchiveDir, dated+".json")).Save()
+
+	m = runMove(t, m, keys("Ayr")...)
+	archived := filepath.Join(m.ArchiveDir, dated+"-2.json")
+	if h := todo.ReadHeader(archived); h.Original != "work.json" {
+		t.Fatalf("Expected %s archived from work.json, got %+v", archived, h)
+	}
+
+	m = runKeys(t, m, keys("z")...)
+	m.FileCursor = slices.IndexFunc(m.archiveRows(), func(r archiveRow) bool { return r.file == dated+"-2.json" })
+	m = runMove(t, m, enter...)
+	if m.CurrentFile != "work.json" {
+		t.Errorf("Expected work.json restored, got %q", m.CurrentFile)
+	}
+	if h := todo.ReadHeader(filepath.Join(m.TodoDir, "work.json")); h.Original != "" || !h.Archived.IsZero() {
+		t.Errorf("Expected the archive metadata cleared, got %+v", h)
+	}
+}
+
+// TestArchiveCollision tests that archiving and unarchiving onto a taken name asks
+// first, and that cancelling, overwriting and renaming do what they say
+func TestArchiveCollision(t *testing.T) {
+	m := newTestModel(t, "new work")
+	old := todo.NewTodoList(filepath.Join(m.ArchiveDir, "work.json"))
+	old.Add("old work")
+
+	m = runKeys(t, m, keys("Ay")...)
+	if m.Dialog != NameCollision || m.collision == nil {
+		t.Fatalf("Expected the collision prompt, got dialog %d", m.Dialog)
+	}
+	m = runKeys(t, m, keys("c")...)
+	if m.Mode != NormalMode || !exists(m.TodoDir, "work.json") {
+		t.Fatal("Expected cancelling to leave work.json in place")
+	}
+	if got := todo.NewTodoList(filepath.Join(m.ArchiveDir, "work.json")).Todos[0].Title; got != "old work" {
+		t.Errorf("Expected the old archive untouched, got %q", got)
+	}
+
+	m = runMove(t, m, keys("Ayo")...)
+	if got := todo.NewTodoList(filepath.Join(m.ArchiveDir, "work.json")).Todos[0].Title; got != "new work" {
+		t.Errorf("Expected the archive overwritten, got %q", got)
+	}
+
+	active := todo.NewTodoList(filepath.Join(m.TodoDir, "work.json"))
+	active.Add("active work")
+	m.loadFiles()
+	m = runKeys(t, m, keys("z")...)
+	m.FileCursor = slices.IndexFunc(m.archiveRows(), func(r archiveRow) bool { return r.file == "work.json" })
+	m = runKeys(t, m, enter...)
+	if m.Dialog != NameCollision || !m.collision.unarchive {
+		t.Fatalf("Expected the collision prompt when unarchiving, got dialog %d", m.Dialog)
+	}
+	m = runMove(t, m, keys("r")...)
+	if m.CurrentFile != "work-2.json" || exists(m.ArchiveDir, "work.json") {
+		t.Errorf("Expected work.json restored as work-2.json, got %q", m.CurrentFile)
+	}
+	if got := todo.NewTodoList(filepath.Join(m.TodoDir, "work.json")).Todos[0].Title; got != "active work" {
+		t.Errorf("Expected the active work.json untouched, got %q", got)
+	}
+}
+
+// TestToasts tests that status messages stack as toasts instead of replacing each
+// other, and that each one is dismissed once its time is up
+func TestToasts(t *testing.T) {
+	m := newTestModel(t, "write report")
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	grow := tea.KeyMsg{Type: tea.KeyCtrlL}
+	m = runKeys(t, m, grow, grow, grow, grow)
+
+	if len(m.Toasts) != maxToasts || m.Toasts[0].Text != "File panel width: 35%" {
+		t.Fatalf("Expected the %d newest toasts, got %v", maxToasts, m.Toasts)
+	}
+	model, _ := m.Update(toastExpiredMsg{id: m.Toasts[0].ID})
+	m = model.(Model)
+	if len(m.Toasts) != maxToasts-1 || m.Toasts[0].Text != "File panel width: 40%" {
+		t.Errorf("Expected the oldest toast dismissed, got %v", m.Toasts)
+	}
+
+	model, cmd := m.Update(listSavedMsg{path: m.TodoList.Path(), err: errors.New("disk full")})
+	m = model.(Model)
+	last := m.Toasts[len(m.Toasts)-1]
+	if last.Severity != SeverityError || !strings.Contains(last.Text, "disk full") || cmd == nil {
+		t.Errorf("Expected a failed save as an error toast with a timer, got %+v", last)
+	}
+
+	// Doing the same thing again shows its message again
+	m = newTestModel(t, "write report", "file taxes")
+	m.ActivePanel = TodoPanel
+	m = runKeys(t, m, keys("xjx")...)
+	if len(m.Toasts) != 2 || m.Toasts[0].Text != m.Toasts[1].Text {
+		t.Errorf("Expected a toast for each toggle, got %v", m.Toasts)
+	}
+}
+
+// TestProgress tests that a slow background operation shows how far it got, and that
+// its result is handled once it's done
+func TestProgress(t *testing.T) {
+	type copiedMsg struct{}
+	m := newTestModel(t, "first")
+	m.Width, m.Height = 100, 30
+
+	release := make(chan struct{})
+	msg := runProgress("Copying", func(report func(done, total int)) tea.Msg {
+		report(1, 4)
+		<-release
+		return copiedMsg{}
+	})().(progressMsg)
+	model, _ := m.Update(msg)
+	m = model.(Model)
+	if view := m.View(); msg.finished || !strings.Contains(view, "Copying 25%") {
+		t.Fatalf("Expected the operation shown at 25%%, got:\n%s", view)
+	}
+
+	close(release)
+	model, cmd := m.Update(waitProgress(msg.updates)())
+	m = model.(Model)
+	if len(m.running) != 0 || cmd == nil {
+		t.Fatal("Expected the finished operation gone")
+	}
+	if _, ok := cmd().(copiedMsg); !ok {
+		t.Error("Expected the result handed on")
+	}
+
+	quick := runProgress("Quick", func(func(done, total int)) tea.Msg { return copiedMsg{} })().(progressMsg)
+	if !quick.finished {
+		t.Error("Expected a quick operation to finish without being shown")
+	}
+}
+
+// TestQuitGuard tests that quitting writes pending changes first, and asks when they
+// can't be written
+func TestQuitGuard(t *testing.T) {
+	m := newTestModel(t, "first")
+	m.Width, m.Height = 100, 30
+	m.Behavior.AutosaveSeconds = 30
+	m.TodoList.SetDeferredSave(true)
+	path := m.TodoList.Path()
+
+	m.TodoList.Add("second")
+	if view := m.View(); !strings.Contains(view, "● unsaved") {
+		t.Fatalf("Expected the pending changes shown, got:\n%s", view)
+	}
+	model, cmd := m.Update(keys("q")[0])
+	m = model.(Model)
+	if _, ok := result(cmd).(tea.QuitMsg); !ok || len(todo.NewTodoList(path).Todos) != 2 {
+		t.Fatal("Expected q to save the pending changes and quit")
+	}
+
+	// Writes fail while the directory is gone
+	m.TodoList.Add("third")
+	gone := m.TodoDir + ".gone"
+	if err := os.Rename(m.TodoDir, gone); err != nil {
+		t.Fatal(err)
+	}
+	model, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = model.(Model)
+	if m.Dialog != ConfirmQuit || cmd != nil {
+		t.Fatalf("Expected ctrl+c to ask before quitting, got dialog %d", m.Dialog)
+	}
+	if view := m.View(); !strings.Contains(view, "Unsaved changes") || !strings.Contains(view, "work.json") {
+		t.Errorf("Expected the unsaved file named, got:\n%s", view)
+	}
+	model, _ = m.Update(keys("r")[0])
+	m = model.(Model)
+	if m.Dialog != ConfirmQuit {
+		t.Fatal("Expected a failed retry to keep asking")
+	}
+
+	if err := os.Rename(gone, m.TodoDir); err != nil {
+		t.Fatal(err)
+	}
+	model, cmd = m.Update(keys("r")[0])
+	m = model.(Model)
+	if _, ok := result(cmd).(tea.QuitMsg); !ok || len(todo.NewTodoList(path).Todos) != 3 {
+		t.Error("Expected the retry to save the changes and quit")
+	}
+	if len(todo.Unsaved()) != 0 {
+		t.Errorf("Expected nothing left unsaved, got %v", todo.Unsaved())
+	}
+}
+
+// TestNestedDialogs tests that a prompt opened over another one goes back to it on Esc
+// and that finishing it closes both
+func TestNestedDialogs(t *testing.T) {
+	m := newTestModel(t, "new work")
+	todo.NewTodoList(filepath.Join(m.ArchiveDir, "work.json")).Save()
+
+	m = runKeys(t, m, keys("Aye")...)
+	if m.Dialog != ArchiveNamePrompt || m.InputText != "work-2" {
+		t.Fatalf("Expected the name prompt over the collision prompt, got dialog %d with %q", m.Dialog, m.InputText)
+	}
+	m = runKeys(t, m, esc...)
+	if m.Dialog != NameCollision || m.InputText != "" {
+		t.Fatalf("Expected Esc to go back to the collision prompt, got dialog %d", m.Dialog)
+	}
+	m = runKeys(t, m, esc...)
+	if m.Dialog != ConfirmArchive || m.StatusMessage != "Archive this file? (y/n)" {
+		t.Fatalf("Expected Esc to go back to the archive question, got dialog %d: %q", m.Dialog, m.StatusMessage)
+	}
+
+	m = runMove(t, m, script(keys("ye"), backspace, backspace, keys("-old"), enter)...)
+	if m.Mode != NormalMode || len(m.dialogs) != 0 || !exists(m.ArchiveDir, "work-old.json") {
+		t.Errorf("Expected work.json archived as work-old.json, got dialog %d", m.Dialog)
+	}
+}
+
+// TestPreview tests that a batch operation lists the todos it would delete before
+// it runs, and that y in the preview runs it
+func TestPreview(t *testing.T) {
+	m := newTestModel(t, "work todo")
+	for _, name := range []string{"a", "b"} {
+		tl := todo.NewTodoList(filepath.Join(m.TodoDir, name+".json"))
+		tl.Add(name + " first")
+		tl.Add(name + " second")
+	}
+	m.loadFiles()
+
+	m = runKeys(t, m, keys("  dp")...)
+	if m.Dialog != PreviewScreen || len(m.preview.rows) != 6 || m.preview.rows[1].text != "a second" {
+		t.Fatalf("Expected both files and their todos previewed, got %+v", m.preview)
+	}
+	m.Width, m.Height = 80, 14
+	if view := m.View(); !strings.Contains(view, "a, deleted with 2 todos") || !strings.Contains(view, "↓ 4 more") {
+		t.Errorf("Expected the preview cut to fit, got:\n%s", view)
+	}
+	for _, k := range keys("jjjjj") {
+		model, _ := m.Update(k)
+		m = model.(Model)
+	}
+	if m.preview.scroll != 4 {
+		t.Errorf("Expected the preview scrolled to its last rows, got %d", m.preview.scroll)
+	}
+
+	m = runKeys(t, m, esc...)
+	if m.Dialog != ConfirmBatch || m.preview != nil || !exists(m.TodoDir, "a.json") {
+		t.Fatalf("Expected Esc back at the question with nothing deleted, got dialog %d", m.Dialog)
+	}
+	m = runMove(t, m, keys("py")...)
+	if m.Mode != NormalMode || !slices.Equal(m.Files, []string{"work.json"}) {
+		t.Errorf("Expected y in the preview to delete both files, got %v", m.Files)
+	}
+}
+
+// TestTodoProgress tests that + and - step a todo's percent done, shown as a bar,
+// and that reaching 100% completes it
+func TestTodoProgress(t *testing.T) {
+	m := newTestModel(t, "write thesis", "buy milk")
+	m = runKeys(t, m, keys("l+++-")...)
+	if got := m.TodoList.Todos[0].Progress; got != 20 {
+		t.Fatalf("Expected 20%%, got %d", got)
+	}
+	if view := m.View(); !strings.Contains(view, "▰▱▱▱▱ 20%") {
+		t.Errorf("Expected a progress bar, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, keys("++++++++")...)
+	if i := m.TodoList.IndexOf(2); !m.TodoList.Todos[i].Completed || m.StatusMessage != "Toggled todo status" {
+		t.Fatalf("Expected 100%% to complete the todo, got %+v", m.TodoList.Todos[i])
+	}
+	m.TodoCursor = m.TodoList.IndexOf(2)
+	m = runKeys(t, m, keys("-")...)
+	if todo := m.TodoList.Todos[m.TodoList.IndexOf(2)]; todo.Completed || todo.Progress != 90 {
+		t.Errorf("Expected - to reopen the todo at 90%%, got %+v", todo)
+	}
+
+	// Merging files has its own binding
+	keys, err := NewKeymap(map[string][]string{"merge_files": {"ctrl+g"}})
+	if err != nil || keys.Action("+") != ActionProgressUp || keys.Action("ctrl+g") != ActionMerge {
+		t.Errorf("Expected rebinding merge to leave + raising progress, got %q, %v", keys.Action("+"), err)
+	}
+}
+
+// TestDeferredTodos tests setting a start date with w, and deferred todos leaving
+// focus mode and, with the setting on, the list
+func TestDeferredTodos(t *testing.T) {
+	m := newTestModel(t, "file taxes", "buy milk")
+	m.TodoList.ToggleFlag(1) // "buy milk" is in focus
+	m = runKeys(t, m, script(keys("ljw"), keys("2099-01-02"), enter)...)
+	milk := m.TodoList.Todos[1]
+	if milk.Start == nil || milk.Start.Format("2006-01-02 15:04") != "2099-01-02 00:00" {
+		t.Fatalf("Expected a start date, got %v (%s)", milk.Start, m.StatusMessage)
+	}
+	if view := m.View(); !strings.Contains(view, "󰔟 Jan 2") {
+		t.Errorf("Expected a start badge, got:\n%s", view)
+	}
+
+	m.Focus = true
+	if visible := m.visibleIndices(); len(visible) != 0 {
+		t.Errorf("Expected deferred todos out of focus, got %v", visible)
+	}
+	m.Focus = false
+	m.Behavior.HideDeferred = true
+	if visible := m.visibleIndices(); len(visible) != 1 || visible[0] != 0 {
+		t.Errorf("Expected only the actionable todo, got %v", visible)
+	}
+	if view := m.View(); !strings.Contains(view, "1 deferred hidden") {
+		t.Errorf("Expected a chip for the hidden todos, got:\n%s", view)
+	}
+
+	m.Behavior.HideDeferred = false
+	clear := slices.Repeat(backspace, len("2099-01-02 00:00"))
+	m = runKeys(t, m, script(keys("jw"), clear, enter)...)
+	if milk := m.TodoList.Todos[1]; milk.Start != nil {
+		t.Errorf("Expected empty input to clear the start date, got %v", milk.Start)
+	}
+}
+
+// TestCopyLink tests that g y copies a Markdown link opening justdoit at the todo
+func TestCopyLink(t *testing.T) {
+	var copied string
+	clipboardWrite = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { clipboardWrite = writeClipboard })
+
+	m := newTestModel(t, "see [RFC] draft", "buy milk")
+	m = runKeys(t, m, keys("lgy")...)
+	if want := `[see \[RFC\] draft](justdoit://work/2)`; copied != want {
+		t.Errorf("Expected %q copied, got %q (%s)", want, copied, m.StatusMessage)
+	}
+}
+
+// TestListSettings tests changing the open list's own settings with P
+func TestListSettings(t *testing.T) {
+	m := newTestModel(t, "water plants", "buy milk")
+	m = runKeys(t, m, script(keys("lPll"), keys("jj"), enter, keys("@home"), enter, keys("jl"))...)
+	s := m.TodoList.ListSettings()
+	if s.Sort != todo.SortTitle || !slices.Equal(s.Tags, []string{"home"}) || s.Color != "red" {
+		t.Fatalf("Expected the sort, tags and color set, got %+v (%s)", s, m.StatusMessage)
+	}
+	if m.Colors["work.json"] != "red" {
+		t.Errorf("Expected the file panel to know the color, got %v", m.Colors)
+	}
+	if view := m.View(); !strings.Contains(view, "@home") || !strings.Contains(view, "by title") {
+		t.Errorf("Expected the settings shown, got:\n%s", view)
+	}
+
+	m = runKeys(t, m, script(keys("qa"), keys("call mom"), enter)...)
+	if m.Dialog != NoDialog {
+		t.Fatalf("Expected the dialogs closed, got %v", m.Dialog)
+	}
+	if got := m.TodoList.Todos[m.TodoCursor].Title; m.TodoCursor != 1 || got != "call mom @home" {
+		t.Errorf("Expected the cursor on the new todo sorted by title, got %d %q", m.TodoCursor, got)
+	}
+	if view := m.View(); !strings.Contains(view, "⇅ by title") {
+		t.Errorf("Expected a chip for the sort, got:\n%s", view)
+	}
+	if todo.NewTodoList(filepath.Join(m.TodoDir, "work.json")).ListSettings().Color != "red" {
+		t.Error("Expected the settings saved in the list file")
+	}
+}
+
+// TestMarkedFiles tests marking files with space and running one confirmed
+// operation on all of them
+func TestMarkedFiles(t *testing.T) {
+	var copied []string
+	clipboardWrite = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	t.Cleanup(func() { clipboardWrite = writeClipboard })
+
+	m := newTestModel(t, "work todo")
+	for _, name := range []string{"a", "b", "c"} {
+		todo.NewTodoList(filepath.Join(m.TodoDir, name+".json")).Add(name + " todo")
+	}
+	m.loadFiles()
+
+	m = runKeys(t, m, keys("  A")...)
+	if m.Dialog != ConfirmBatch || !strings.Contains(m.StatusMessage, "Archive 2 files?") {
+		t.Fatalf("Expected the count confirmed first, got %q", m.StatusMessage)
+	}
+	m = runKeys(t, m, keys("nY")...)
+	if m.Dialog != ConfirmBatch || !strings.Contains(m.StatusMessage, "Copy 2 lists") {
+		t.Fatalf("Expected the marks kept after cancelling, got %q", m.StatusMessage)
+	}
+	m = runKeys(t, m, keys("y")...)
+	if len(copied) != 1 || !strings.Contains(copied[0], "# a\n") || !strings.Contains(copied[0], "- [ ] b todo") {
+		t.Errorf("Expected both lists copied, got %q", copied)
+	}
+
+	m.FileCursor = 0
+	m = runMove(t, m, keys("  &y")...)
+	merged := todo.NewTodoList(filepath.Join(m.TodoDir, "a.json"))
+	if len(merged.Todos) != 2 || merged.Todos[1].Title != "b todo" || exists(m.TodoDir, "b.json") {
+		t.Errorf("Expected b merged into a, got %+v", merged.Todos)
+	}
+	if len(m.Marked) != 0 {
+		t.Errorf("Expected the marks cleared, got %v", m.Marked)
+	}
+
+	m.FileCursor = 0
+	m = runMove(t, m, keys("  dy")...)
+	if !slices.Equal(m.Files, []string{"work.json"}) {
+		t.Errorf("Expected a and c deleted, got %v", m.Files)
+	}
+
+	m = runKeys(t, m, script(keys(" "), esc)...)
+	if len(m.Marked) != 0 {
+		t.Errorf("Expected Esc to clear the marks, got %v", m.Marked)
+	}
+}
+
+// TestRenderList tests printing a list outside the TUI, without a cursor
+func TestRenderList(t *testing.T) {
+	m := newTestModel(t, "first", "second")
+	m.TodoList.Toggle(0)
+	out := RenderList(m.TodoList, "work.json", m.Behavior, nil)
+	for _, want := range []string{"work", "1/2", "first", "second"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the printed list:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "▊") {
+		t.Errorf("Expected no cursor in the printed list:\n%s", out)
+	}
+}
+
+// TestFrameCache tests that background messages reuse the last frame and others render a new one
+func TestFrameCache(t *testing.T) {
+	m := newTestModel(t, "pay rent", "call mum")
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 24})
+	m = next.(Model)
+	first := m.View()
+
+	next, _ = m.Update(tea.MouseMsg{X: 50, Y: 5, Action: tea.MouseActionMotion})
+	if idle := next.(Model); !idle.reuseFrame || idle.View() != first {
+		t.Error("Expected mouse motion to reuse the last frame")
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	moved := next.(Model)
+	if moved.reuseFrame || moved.View() == first {
+		t.Error("Expected a key to render a new frame")
+	}
+	if m.View() != first {
+		t.Error("Expected an earlier model to keep its own frame")
+	}
+}
+
+// TestSmallWindow tests that a tiny window shows a placeholder and keeps working
+func TestSmallWindow(t *testing.T) {
+	m := newTestModel(t, "pay rent")
+	for _, size := range [][2]int{{30, 20}, {100, 5}, {1, 1}} {
+		next, _ := m.Update(tea.WindowSizeMsg{Width: size[0], Height: size[1]})
+		m = next.(Model)
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		m = next.(Model)
+		if view := m.View(); size[0] > 1 && !strings.Contains(view, "Window too small") {
+			t.Errorf("Expected the placeholder at %dx%d, got:\n%s", size[0], size[1], view)
+		}
+	}
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 24})
+	if view := next.(Model).View(); !strings.Contains(view, "pay rent") {
+		t.Errorf("Expected the panels back, got:\n%s", view)
+	}
+}
+
+// TestCrashSummary tests that crash reports count todos without naming them
+func TestCrashSummary(t *testing.T) {
+	m := newTestModel(t, "pay rent", "call mum")
+	summary := m.CrashSummary()
+	if !strings.Contains(summary, "todos=2") || strings.Contains(summary, "rent") || strings.Contains(summary, "work") {
+		t.Errorf("Unexpected crash summary %q", summary)
+	}
+}
+
+// TestGermanCatalogComplete tests that every UI string has a German translation
+func TestGermanCatalogComplete(t *testing.T) {
+	for _, s := range translatable(t) {
+		if _, ok := i18n.Lookup("de", s); !ok {
+			t.Errorf("Missing German translation for %q", s)
+		}
+	}
+}
@@ -2,88 +2,181 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/todo"
 )
 
-// View renders the UI (Bubble Tea interface)
+// View renders the UI (Bubble Tea interface). Background messages that changed
+// nothing on screen show the previous frame again instead of rendering a new one.
 func (m Model) View() string {
+	if view, ok := m.cachedView(); ok {
+		return view
+	}
+	return m.storeView(m.render())
+}
+
+// render renders a frame
+func (m Model) render() string {
+	defer logSlowRender(time.Now(), m.Width, m.Height)
 	if m.Width == 0 {
-		return "Loading..."
+		return i18n.T("Loading...")
+	}
+	if m.accessibleView() {
+		return m.renderAccessible()
 	}
+	if m.tooSmall() {
+		return m.renderTooSmall()
+	}
+	if m.Behavior.Accessible {
+		return stripGlyphs(m.renderScreen())
+	}
+	return m.renderScreen()
+}
 
-	leftWidth := m.Width / 4
-	rightWidth := m.Width - leftWidth - 4
+// renderScreen renders the panels, or the overlay that replaces them
+func (m Model) renderScreen() string {
+	leftWidth, rightWidth := m.panelWidths()
 
 	// Calculate panel height based on whether status bar is showing
-	panelHeight := m.Height - 4
-	if m.StatusMessage != "" && m.EditingIndex != -3 && m.EditingIndex != -4 {
-		panelHeight = m.Height - 7 // Account for status bar extra lines
-	}
+	panelHeight := m.panelHeight()
+	banner := m.renderBanners()
 
 	// Render panels
 	leftPanel := m.renderFilePanelWithHeight(leftWidth, panelHeight)
 	rightPanel := m.renderTodoPanelWithHeight(rightWidth, panelHeight)
+	if m.Dialog == CompareScreen {
+		rightPanel = m.renderCompare(rightWidth, panelHeight)
+	}
 	mainView := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+	if m.Maximized {
+		mainView = rightPanel
+	}
+
+	if m.Dialog == PreviewScreen {
+		return m.renderPreview() + "\n\n" + m.renderHints()
+	}
 
 	// Handle special confirmation dialogs
-	if m.Mode == EditMode && m.EditingIndex == -4 {
-		return m.renderDeleteConfirmation()
+	if d, ok := m.openModal(); ok {
+		return m.renderModal(d)
 	}
 
-	if m.Mode == EditMode && m.EditingIndex == -3 {
-		return m.renderArchiveConfirmation()
+	if m.Dialog == ContextSwitcher {
+		return m.renderContextSwitcher()
+	}
+
+	if m.Dialog == TemplatePicker {
+		return m.renderTemplatePicker()
+	}
+
+	if m.Dialog == ComparePicker {
+		return m.renderComparePicker()
+	}
+
+	if m.Dialog == FilePicker {
+		return m.renderFilePicker()
+	}
+
+	if m.Dialog == TodayScreen {
+		return m.renderToday() + "\n\n" + m.renderHints()
+	}
+
+	if m.Dialog == SettingsScreen {
+		return m.renderSettings() + "\n\n" + m.renderHints() + m.renderStatusBar()
+	}
+
+	if m.Dialog == ListSettingScreen || m.Dialog == ListTagsPrompt {
+		return m.renderListSettings() + "\n\n" + m.renderHints() + m.renderStatusBar()
+	}
+
+	if m.Dialog == KeybindingEditor {
+		return m.renderKeybindings() + "\n\n" + m.renderHints() + m.renderStatusBar()
+	}
+
+	if m.Dialog == ConflictResolver {
+		return m.renderResolver() + "\n\n" + m.renderHints() + m.renderStatusBar()
+	}
+
+	if m.Dialog == StatsScreen {
+		return m.renderStats() + "\n\n" + m.renderHints()
+	}
+
+	if m.Dialog == DebugLogScreen {
+		return m.renderDebugLog() + "\n\n" + m.renderHints()
+	}
+
+	if m.Zen {
+		return banner + m.renderZen() + m.renderStatusBar()
 	}
 
 	// Render hints and status
 	hints := m.renderHints()
 	statusBar := m.renderStatusBar()
 
-	return mainView + "\n\n" + hints + statusBar
+	return banner + mainView + "\n\n" + hints + statusBar
 }
 
 // renderFilePanelWithHeight renders the left file panel with specified height
 func (m Model) renderFilePanelWithHeight(width int, height int) string {
 	content := ""
 
-	if m.Mode == EditMode && m.EditingIndex == -2 {
-		// Creating new file
+	if m.Dialog == NewFile || m.Dialog == SplitPrompt {
+		// Creating new file (or splitting into one)
 		content = m.Styles.Edit.Render("  "+m.InputText+"█.json") + "\n"
+		content += m.renderFileNameCheck(width - 2)
 		for _, file := range m.Files {
-			content += m.Styles.Normal.Render("  󰈔 "+file) + "\n"
+			content += m.Styles.Normal.Render("  󰈔 "+m.displayName(file)) + "\n"
 		}
+	} else if m.Dialog == TemplatePrompt {
+		content = m.renderTemplatePrompt()
 	} else if m.ShowingArchive {
-		// Show archived files
-		content += m.Styles.Separator.Render("  ─── archived ───") + "\n\n"
-		for i, file := range m.ArchivedFiles {
-			if m.ActivePanel == FilePanel && i == m.FileCursor {
-				cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
-				content += m.Styles.Selected.Render(" " + cursor + " " + file + " ") + "\n"
-			} else {
-				content += m.Styles.Dimmed.Render("  󰃨 "+file) + "\n"
-			}
-		}
+		// Show archived files, grouped by month
+		content += m.renderArchive()
 	} else {
-		// Show active files
+		// Show active files, below the recent ones
+		content += m.renderRecent()
 		for i, file := range m.Files {
+			name := m.displayName(file)
+			if m.Foreign[file] {
+				name = "⊘ " + name // not a todo list, shown read-only
+			}
+			if m.Marked[file] {
+				name = "● " + name
+			}
 			if m.ActivePanel == FilePanel && i == m.FileCursor && !m.ShowingArchive {
 				cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
-				content += m.Styles.Selected.Render(" " + cursor + " " + file + " ") + "\n"
-			} else if file == m.CurrentFile {
-				content += m.Styles.CurrentFile.Render("󰄲 "+file) + "\n"
+				content += m.Styles.Selected.Render(" "+cursor+" "+name+" ") + "\n"
+			} else if file == m.CurrentFile && m.virtual == nil {
+				content += m.Styles.CurrentFile.Render("󰄲 "+name) + "\n"
+			} else if m.Foreign[file] {
+				content += m.Styles.Dimmed.Render("  "+name) + "\n"
+			} else if c, ok := listColors[m.Colors[file]]; ok {
+				content += "  " + lipgloss.NewStyle().Foreground(c).Render("󰈔") + m.Styles.Normal.Render(" "+name) + "\n"
 			} else {
-				content += m.Styles.Normal.Render("  󰈔 "+file) + "\n"
+				content += m.Styles.Normal.Render("  󰈔 "+name) + "\n"
 			}
 		}
 
+		content += m.renderFilterEntries()
+
+		if n := len(m.markedFiles()); n > 0 {
+			content += "\n" + m.Styles.Badge.Render(i18n.Tf(" %d marked ", n)) + "\n"
+		}
+
 		// Show archive section
 		if len(m.ArchivedFiles) > 0 {
 			content += "\n"
 			content += m.Styles.Separator.Render("  ─────────────") + "\n"
-			content += m.Styles.Badge.Render(fmt.Sprintf(" %d archived ", len(m.ArchivedFiles))) + "\n"
+			content += m.Styles.Badge.Render(i18n.Tf(" %d archived ", len(m.ArchivedFiles))) + "\n"
 		}
 	}
 
+	content = fitLines(content, width-2)
+
 	// Apply border
 	borderStyle := m.Styles.Border
 	if m.ActivePanel == FilePanel {
@@ -95,7 +188,7 @@ func (m Model) renderFilePanelWithHeight(width int, height int) string {
 	if m.ShowingArchive {
 		titleIcon = "󰃨"
 	}
-	title := m.Styles.Title.Render(fmt.Sprintf(" %s Files ", titleIcon))
+	title := m.Styles.Title.Render(i18n.Tf(" %s Files ", titleIcon))
 
 	return borderStyle.
 		Width(width).
@@ -106,19 +199,7 @@ func (m Model) renderFilePanelWithHeight(width int, height int) string {
 
 // renderTodoPanelWithHeight renders the right todo panel with specified height
 func (m Model) renderTodoPanelWithHeight(width int, height int) string {
-	content := ""
-
-	// Always show renderTodoList when adding new todo to show input preview
-	if m.Mode == EditMode && m.EditingIndex == -1 {
-		content = m.renderTodoList()
-	} else if len(m.TodoList.Todos) == 0 {
-		emptyIcon := "󰄱"
-		emptyMsg := m.Styles.Dimmed.Italic(true).Render(fmt.Sprintf("  %s  No todos yet", emptyIcon))
-		emptyHint := m.Styles.Muted.Render("  Press 'a' to add one")
-		content = emptyMsg + "\n" + emptyHint
-	} else {
-		content = m.renderTodoList()
-	}
+	content := fitLines(m.renderTodoContent(), width-2)
 
 	// Apply border
 	borderStyle := m.Styles.Border
@@ -126,14 +207,8 @@ func (m Model) renderTodoPanelWithHeight(width int, height int) string {
 		borderStyle = m.Styles.ActiveBorder
 	}
 
-	// Title with stats
-	completed := 0
-	for _, todo := range m.TodoList.Todos {
-		if todo.Completed {
-			completed++
-		}
-	}
-	total := len(m.TodoList.Todos)
+	// Title with stats (none until a background load finishes)
+	completed, total := m.todoStats()
 
 	titleIcon := " "
 	stats := ""
@@ -141,12 +216,21 @@ func (m Model) renderTodoPanelWithHeight(width int, height int) string {
 		stats = m.Styles.Badge.Render(fmt.Sprintf(" %d/%d ", completed, total))
 	}
 
+	titleStyle := m.Styles.Title
+	if c, ok := m.listColor(); ok {
+		titleStyle = titleStyle.Foreground(c)
+	}
 	title := lipgloss.JoinHorizontal(
 		lipgloss.Left,
-		m.Styles.Title.Render(fmt.Sprintf(" %s %s ", titleIcon, m.CurrentFile)),
+		titleStyle.Render(fmt.Sprintf(" %s %s ", titleIcon, m.listName())),
 		" ",
 		stats,
+		m.renderHabitChip(),
+		m.renderSaveChip(),
 	)
+	if row := m.renderFilterRow(); row != "" {
+		title += "\n" + row
+	}
 
 	return borderStyle.
 		Width(width).
@@ -155,43 +239,107 @@ func (m Model) renderTodoPanelWithHeight(width int, height int) string {
 		Render(title + "\n\n" + content)
 }
 
+// todoStats counts the completed and total todos shown, or zeros while a list loads
+func (m Model) todoStats() (int, int) {
+	if m.Loading != "" {
+		return 0, 0
+	}
+	if m.ActiveContext == "" && m.FieldFilter == "" && m.Search == "" && !m.Focus && !m.Behavior.HideDeferred {
+		return m.TodoList.Counts()
+	}
+	return m.TodoList.CountFunc(m.matchesFilter)
+}
+
+// renderTodoContent renders the todo rows, or a spinner or empty-state hint in their place
+func (m Model) renderTodoContent() string {
+	content := ""
+
+	// Show a spinner while a large list loads; always show renderTodoList when adding new todo to show input preview
+	if m.Loading != "" {
+		content = m.renderLoading()
+	} else if m.Dialog == AddTodo {
+		content = m.renderTodoList()
+	} else if len(m.TodoList.Todos) > 0 && len(m.visibleIndices()) == 0 && m.Focus {
+		emptyMsg := m.Styles.Dimmed.Italic(true).Render(i18n.T("  󰄱  Nothing flagged or due today"))
+		emptyHint := m.Styles.Muted.Render(i18n.T("  Press 'F' to leave focus mode"))
+		content = emptyMsg + "\n" + emptyHint
+	} else if len(m.TodoList.Todos) > 0 && len(m.visibleIndices()) == 0 && m.ActiveContext == "" {
+		emptyMsg := m.Styles.Dimmed.Italic(true).Render(i18n.T("  󰄱  Nothing matches the filters"))
+		emptyHint := m.Styles.Muted.Render(i18n.T("  Press 'C' to clear them"))
+		content = emptyMsg + "\n" + emptyHint
+	} else if len(m.TodoList.Todos) > 0 && len(m.visibleIndices()) == 0 {
+		emptyMsg := m.Styles.Dimmed.Italic(true).Render(i18n.Tf("  󰄱  No todos in @%s", m.ActiveContext))
+		emptyHint := m.Styles.Muted.Render(i18n.T("  Press '@' to switch context"))
+		content = emptyMsg + "\n" + emptyHint
+	} else if m.TodoList.Foreign() {
+		emptyMsg := m.Styles.Dimmed.Italic(true).Render(i18n.T("  ⊘  Not a todo list"))
+		emptyHint := m.Styles.Muted.Render(i18n.T("  Shown read-only, justdoit won't write to this file"))
+		content = emptyMsg + "\n" + emptyHint
+	} else if len(m.TodoList.Todos) == 0 && m.virtual != nil {
+		content = m.Styles.Dimmed.Italic(true).Render(i18n.T("  󰄱  Nothing matches this filter"))
+	} else if len(m.TodoList.Todos) == 0 {
+		emptyIcon := "󰄱"
+		emptyMsg := m.Styles.Dimmed.Italic(true).Render(i18n.Tf("  %s  No todos yet", emptyIcon))
+		emptyHint := m.Styles.Muted.Render(i18n.T("  Press 'a' to add one"))
+		content = emptyMsg + "\n" + emptyHint
+	} else {
+		content = m.renderTodoList()
+	}
+	return content
+}
+
 // renderTodoList renders the list of todos
 func (m Model) renderTodoList() string {
 	content := ""
 
-	// Show new todo input inline at the top
-	if m.Mode == EditMode && m.EditingIndex == -1 {
-		newCheckbox := m.Styles.Checkbox.Render("")
-		content += m.Styles.Edit.Render(fmt.Sprintf("  %s  %s█", newCheckbox, m.InputText)) + "\n"
+	// Only materialize the rows inside the scroll window
+	visible := m.visibleIndices()
+	start, end := m.todoWindow(len(visible))
+	cursorLine := m.cursorLine(visible)
+	blankGutter := m.renderLineNumber(-1, cursorLine, len(visible))
+
+	// The table layout labels its columns above the rows
+	columns := m.tableColumns()
+	if columns != nil {
+		content += blankGutter + "  " + m.renderTableHeader(columns) + "\n"
+	}
+
+	// Show new todo input inline at the top of the section it will be added to,
+	// or pinned above the window when that is scrolled out of view
+	inputAt := -1
+	if m.Dialog == AddTodo {
+		inputAt = m.TodoList.SectionStart(m.TodoCursor)
 	}
 
-	for i, todo := range m.TodoList.Todos {
-		var checkbox string
-		var checkStyle lipgloss.Style
+	for n, i := range visible[start:end] {
+		todo := m.TodoList.Todos[i]
+		gutter := m.renderLineNumber(start+n, cursorLine, len(visible))
 
-		if todo.Completed {
-			checkbox = ""
-			checkStyle = m.Styles.CheckboxDone
-		} else {
-			checkbox = ""
-			checkStyle = m.Styles.Checkbox
+		if inputAt >= 0 && i >= inputAt {
+			content += m.renderInlineInput(blankGutter, blankGutter) + "\n"
+			inputAt = -1
 		}
 
-		checkboxStr := checkStyle.Render(checkbox)
+		if m.Dialog == EditTodo && m.EditingIndex == i {
+			content += m.renderInlineInput(gutter, blankGutter) + "\n"
+			continue
+		}
+		if todo.Heading {
+			content += gutter + m.renderHeading(i) + "\n"
+			continue
+		}
 
-		// Apply style based on completion
 		var line string
-		if todo.Completed {
-			textStyle := m.Styles.Completed
-			line = fmt.Sprintf("%s  %s", checkboxStr, textStyle.Render(todo.Title))
+		if columns != nil {
+			line = m.renderTableRow(todo, columns)
 		} else {
-			line = fmt.Sprintf("%s  %s", checkboxStr, m.Styles.Normal.Render(todo.Title))
+			line = m.renderListRow(todo)
 		}
 
 		// Handle editing mode
-		if m.Mode == EditMode && m.EditingIndex == i {
-			editIcon := m.Styles.Edit.Render("")
-			line = m.Styles.Edit.Render(fmt.Sprintf(" %s  %s█", editIcon, m.InputText))
+		if (m.Dialog == DuePrompt || m.Dialog == StartPrompt || m.Dialog == ReminderPrompt || m.Dialog == FieldPrompt || m.Dialog == CheckPrompt) && i == m.TodoCursor {
+			content += m.renderInlineInput(gutter, blankGutter) + "\n"
+			continue
 		} else if m.ActivePanel == TodoPanel && i == m.TodoCursor {
 			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
 			line = m.Styles.Selected.Render(" " + cursor + " " + line + " ")
@@ -199,134 +347,266 @@ func (m Model) renderTodoList() string {
 			line = "  " + line
 		}
 
-		content += line + "\n"
+		content += gutter + line + "\n"
+	}
+
+	// Input for an empty trailing section goes after its heading
+	if inputAt >= 0 {
+		content += m.renderInlineInput(blankGutter, blankGutter) + "\n"
 	}
 
 	return content
 }
 
-// renderDeleteConfirmation renders the delete confirmation dialog
-func (m Model) renderDeleteConfirmation() string {
-	confirmStyle := lipgloss.NewStyle().
-		Border(ThickBorder).
-		BorderForeground(ColorRed).
-		Padding(2, 4).
-		Align(lipgloss.Center)
+// renderHeading renders a section heading with its fold state and completion count
+func (m Model) renderHeading(i int) string {
+	heading := m.TodoList.Todos[i]
+	// Count the same todos as the panel title so filtered views add up
+	completed, total := m.TodoList.SectionStatsFunc(i, m.matchesFilter)
 
-	titleIcon := lipgloss.NewStyle().
-		Foreground(ColorRed).
-		Bold(true).
-		Render(" ")
+	arrow := "▾"
+	if heading.Collapsed {
+		arrow = "▸"
+	}
+	line := lipgloss.NewStyle().Foreground(ColorMauve).Bold(true).Render(arrow+" "+heading.Title) +
+		" " + m.Styles.Muted.Render(fmt.Sprintf("%d/%d", completed, total))
 
-	title := lipgloss.NewStyle().
-		Foreground(ColorRed).
-		Bold(true).
-		Render("Delete Confirmation")
+	if m.ActivePanel == TodoPanel && i == m.TodoCursor {
+		cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+		return m.Styles.Selected.Render(" " + cursor + " " + line + " ")
+	}
+	return "  " + line
+}
 
-	titleBar := lipgloss.JoinHorizontal(lipgloss.Left, titleIcon, " ", title)
+// renderCheckbox renders the checkbox of a todo
+func (m Model) renderCheckbox(t todo.Todo) string {
+	var checkbox string
+	var checkStyle lipgloss.Style
 
-	filename := lipgloss.NewStyle().
-		Foreground(ColorLavender).
-		Background(ColorCrust).
-		Bold(true).
-		Padding(0, 1).
-		Render(m.CurrentFile)
+	if t.Completed {
+		checkbox = ""
+		checkStyle = m.Styles.CheckboxDone
+	} else {
+		checkbox = ""
+		checkStyle = m.Styles.Checkbox
+	}
 
-	question := m.Styles.Normal.Render("Permanently delete this file?")
+	return checkStyle.Render(checkbox)
+}
 
-	yesKey := m.Styles.HintKey.Render(" y ")
-	yesText := m.Styles.Hint.Render(" Yes, delete")
-	noKey := m.Styles.HintKey.Render(" n ")
-	noText := m.Styles.Hint.Render(" No, cancel")
+// renderListRow renders a todo as a line of the list layout: its checkbox and title
+// followed by its badges
+func (m Model) renderListRow(t todo.Todo) string {
+	checkboxStr := m.renderCheckbox(t)
 
-	options := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		yesKey, yesText, "    ", noKey, noText,
-	)
+	// Apply style based on completion
+	var line string
+	if t.Completed {
+		textStyle := m.Styles.Completed
+		line = fmt.Sprintf("%s  %s", checkboxStr, textStyle.Render(t.Title))
+	} else {
+		base := m.Styles.Normal
+		if t.Deferred(time.Now()) {
+			base = m.Styles.Muted // not actionable before its start date
+		}
+		line = fmt.Sprintf("%s  %s", checkboxStr, m.renderTitle(t.Title, m.titleStyle(t, base)))
+	}
 
-	confirmContent := lipgloss.JoinVertical(
-		lipgloss.Center,
-		titleBar,
-		"",
-		filename,
-		question,
-		"",
-		options,
-	)
+	if t.Flagged {
+		line += "  " + lipgloss.NewStyle().Foreground(ColorRed).Render("󰈻")
+	}
+	if t.Notes != "" {
+		line += "  " + m.Styles.Muted.Render("󰎞")
+	}
+	if t.Check != "" {
+		line += "  " + m.Styles.Muted.Render("")
+	}
+	line += m.renderAuthor(t)
+	line += m.renderPercent(t)
+	if m.TodoList.IsHabit() {
+		line += m.renderHabit(t)
+	}
+	if !m.hidesColumn(config.ColumnFields) {
+		line += m.renderFields(t)
+	}
+	if !m.hidesColumn(config.ColumnDue) {
+		line += m.renderSchedule(t)
+	}
+	line += m.renderVirtualSource(t)
+	return line
+}
 
-	confirmBox := confirmStyle.Render(confirmContent)
+// renderSchedule renders the start date, due date and reminder badges for a todo
+func (m Model) renderSchedule(t todo.Todo) string {
+	badge := ""
+	if t.Deferred(time.Now()) {
+		badge = "  " + m.Styles.Muted.Render("󰔟 "+t.Start.Format("Jan 2"))
+	}
+	if t.Due == nil {
+		return badge
+	}
 
-	panels := lipgloss.Place(
-		m.Width,
-		m.Height-4,
-		lipgloss.Center,
-		lipgloss.Center,
-		confirmBox,
-	)
+	style := m.Styles.Muted
+	if !t.Completed && time.Now().After(*t.Due) {
+		style = lipgloss.NewStyle().Foreground(ColorRed)
+	}
+	badge += "  " + style.Render("󰃰 "+t.Due.Format("Jan 2 15:04"))
+	if t.RemindBefore != nil && !t.ReminderAcked {
+		badge += " " + lipgloss.NewStyle().Foreground(ColorYellow).Render("󰂚")
+	}
+	return badge
+}
 
-	return panels
+// renderBanners renders every active banner above the panels
+func (m Model) renderBanners() string {
+	return m.renderTitleBar() + m.renderConflictBanner() + m.renderReminderBanner() + m.renderTodayBanner()
 }
 
-// renderArchiveConfirmation renders the archive confirmation dialog
-func (m Model) renderArchiveConfirmation() string {
-	confirmStyle := lipgloss.NewStyle().
-		Border(ThickBorder).
-		BorderForeground(ColorSapphire).
-		Padding(2, 4).
-		Align(lipgloss.Center)
+// renderReminderBanner renders fired reminders above the panels
+func (m Model) renderReminderBanner() string {
+	if len(m.Reminders) == 0 {
+		return ""
+	}
+
+	first := m.Reminders[0]
+	text := i18n.Tf("󰂚 %s (%s, due %s)", first.Title, first.File, first.Due.Format("Jan 2 15:04"))
+	if len(m.Reminders) > 1 {
+		text += i18n.Tf(" +%d more", len(m.Reminders)-1)
+	}
+	text += i18n.T("  ·  R to acknowledge")
 
-	titleIcon := lipgloss.NewStyle().
-		Foreground(ColorSapphire).
+	bannerStyle := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorYellow).
 		Bold(true).
-		Render("󰃨 ")
+		Padding(0, 1)
+	return bannerStyle.Render(text) + "\n\n"
+}
 
-	title := lipgloss.NewStyle().
-		Foreground(ColorSapphire).
+// renderContextChip renders the active @context next to the todo panel title
+func (m Model) renderContextChip() string {
+	if m.ActiveContext == "" {
+		return ""
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorTeal).
 		Bold(true).
-		Render("Archive Confirmation")
+		Padding(0, 1).
+		Render("@" + m.ActiveContext)
+	return " " + chip
+}
 
-	titleBar := lipgloss.JoinHorizontal(lipgloss.Left, titleIcon, title)
+// renderFocusChip marks the todo panel title while focus mode is on
+func (m Model) renderFocusChip() string {
+	if !m.Focus {
+		return ""
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorPeach).
+		Bold(true).
+		Padding(0, 1).
+		Render(i18n.T("◎ focus"))
+	return " " + chip
+}
 
-	filename := lipgloss.NewStyle().
-		Foreground(ColorLavender).
-		Background(ColorCrust).
+// renderHabitChip marks habit lists in the todo panel title
+func (m Model) renderHabitChip() string {
+	if !m.TodoList.IsHabit() || m.Loading != "" {
+		return ""
+	}
+	chip := lipgloss.NewStyle().
+		Foreground(ColorBase).
+		Background(ColorGreen).
 		Bold(true).
 		Padding(0, 1).
-		Render(m.CurrentFile)
+		Render(i18n.T("↻ habits"))
+	return " " + chip
+}
 
-	question := m.Styles.Normal.Render("Archive this file?")
+// renderHabit renders a habit's streak and a heatmap of the last two weeks
+func (m Model) renderHabit(t todo.Todo) string {
+	now := time.Now()
+	done := lipgloss.NewStyle().Foreground(ColorGreen)
+	var heatmap string
+	for _, d := range t.HabitDays(now, 14) {
+		if d {
+			heatmap += done.Render("■")
+		} else {
+			heatmap += m.Styles.Muted.Render("·")
+		}
+	}
 
-	yesKey := m.Styles.HintKey.Render(" y ")
-	yesText := m.Styles.Hint.Render(" Yes, archive")
-	noKey := m.Styles.HintKey.Render(" n ")
-	noText := m.Styles.Hint.Render(" No, cancel")
+	streak := ""
+	if n := t.Streak(now); n > 0 {
+		streak = lipgloss.NewStyle().Foreground(ColorPeach).Render(fmt.Sprintf(" 󰈸%d", n))
+	}
+	return "  " + heatmap + streak
+}
 
-	options := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		yesKey, yesText, "    ", noKey, noText,
-	)
+// renderContextSwitcher renders the context switcher overlay
+func (m Model) renderContextSwitcher() string {
+	switcherStyle := lipgloss.NewStyle().
+		Border(ThickBorder).
+		BorderForeground(ColorTeal).
+		Padding(1, 4)
 
-	confirmContent := lipgloss.JoinVertical(
-		lipgloss.Center,
-		titleBar,
-		"",
-		filename,
-		question,
-		"",
-		options,
-	)
+	title := lipgloss.NewStyle().
+		Foreground(ColorTeal).
+		Bold(true).
+		Render(i18n.T("@ Switch Context"))
 
-	confirmBox := confirmStyle.Render(confirmContent)
+	content := title + "\n\n"
+	for i, c := range m.Contexts {
+		label := "@" + c
+		if c == "" {
+			label = i18n.T("All contexts")
+		}
+		if c == m.ActiveContext {
+			label += i18n.T(" (active)")
+		}
+		if i == m.ContextCursor {
+			cursor := lipgloss.NewStyle().Foreground(ColorTeal).Render("▊")
+			content += m.Styles.Selected.Render(" "+cursor+" "+label+" ") + "\n"
+		} else {
+			content += m.Styles.Normal.Render("   "+label) + "\n"
+		}
+	}
+	if len(m.Contexts) == 1 {
+		content += "\n" + m.Styles.Muted.Render(i18n.T("No @contexts found in any list"))
+	}
 
-	panels := lipgloss.Place(
+	return lipgloss.Place(
 		m.Width,
 		m.Height-4,
 		lipgloss.Center,
 		lipgloss.Center,
-		confirmBox,
+		switcherStyle.Render(content),
 	)
+}
+
+// deleteModal asks before deleting the current file
+func (m Model) deleteModal() modal {
+	return modal{
+		variant:  modalDanger,
+		title:    i18n.T("Delete Confirmation"),
+		subject:  m.displayName(m.CurrentFile),
+		question: i18n.T("Permanently delete this file?"),
+		choices:  []choice{{"y", i18n.T(" Yes, delete")}, {"n", i18n.T(" No, cancel")}},
+		safe:     1,
+	}
+}
 
-	return panels
+// archiveModal asks before archiving the current file
+func (m Model) archiveModal() modal {
+	return modal{
+		icon:     "󰃨",
+		title:    i18n.T("Archive Confirmation"),
+		subject:  m.displayName(m.CurrentFile),
+		question: i18n.T("Archive this file?"),
+		choices:  []choice{{"y", i18n.T(" Yes, archive")}, {"n", i18n.T(" No, cancel")}},
+	}
 }
 
 // renderHints renders the hints bar at the bottom
@@ -335,23 +615,144 @@ func (m Model) renderHints() string {
 		return m.Styles.HintKey.Render(fmt.Sprintf(" %s ", key))
 	}
 	renderDesc := func(desc string) string {
-		return m.Styles.Hint.Render(fmt.Sprintf(" %s ", desc))
+		return m.Styles.Hint.Render(" " + i18n.T(desc) + " ")
 	}
 	sep := m.Styles.Muted.Render(" │ ")
+	if m.Behavior.Accessible {
+		renderKey = func(key string) string { return key }
+		renderDesc = func(desc string) string { return " " + i18n.T(desc) }
+		sep = ", "
+	}
+	// Normal mode shows the keys bound in the keymap, leaving out unbound actions
+	renderAction := func(desc string, actions ...Action) string {
+		if keys := m.hintKeys(actions...); keys != "" {
+			return renderKey(keys) + renderDesc(desc)
+		}
+		return ""
+	}
 
 	var hints []string
 
 	if m.Mode == EditMode {
-		switch m.EditingIndex {
-		case -2:
+		switch m.Dialog {
+		case NewFile, SplitPrompt:
 			hints = []string{
 				renderKey("Enter") + renderDesc("create"),
 				renderKey("Esc") + renderDesc("cancel"),
 			}
-		case -3, -4:
+		case ConfirmArchive, ConfirmDelete, ConfirmChecks:
+			hints = []string{
+				renderKey("y") + renderDesc("yes"),
+				renderKey("n") + renderDesc("no"),
+			}
+		case ConfirmBatch:
 			hints = []string{
 				renderKey("y") + renderDesc("yes"),
 				renderKey("n") + renderDesc("no"),
+				renderKey("p") + renderDesc("preview"),
+			}
+		case NameCollision:
+			hints = []string{
+				renderKey("r") + renderDesc("rename"),
+				renderKey("e") + renderDesc("edit name"),
+				renderKey("o") + renderDesc("overwrite"),
+				renderKey("c") + renderDesc("cancel"),
+			}
+		case ConfirmQuit:
+			hints = []string{
+				renderKey("r") + renderDesc("retry"),
+				renderKey("q") + renderDesc("quit anyway"),
+				renderKey("Esc") + renderDesc("stay"),
+			}
+		case ArchiveNamePrompt:
+			hints = []string{
+				renderKey("Enter") + renderDesc("save"),
+				renderKey("Esc") + renderDesc("back"),
+			}
+		case ConfirmBulkDelete:
+			hints = []string{
+				renderKey("y") + renderDesc("delete"),
+				renderKey("n") + renderDesc("keep"),
+				renderKey("p") + renderDesc("preview"),
+				renderKey("Esc") + renderDesc("discard edit"),
+			}
+		case PreviewScreen:
+			hints = []string{
+				renderKey("j/k") + renderDesc("scroll"),
+				renderKey("y") + renderDesc("apply"),
+				renderKey("Esc") + renderDesc("back"),
+			}
+		case SettingsScreen, ListSettingScreen:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("change"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case KeybindingEditor:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("rebind"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case ContextSwitcher, TemplatePicker, ComparePicker:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("select"),
+				renderKey("Esc") + renderDesc("cancel"),
+			}
+		case FilePicker:
+			hints = []string{
+				renderKey("↑/↓") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("open"),
+				renderKey("Esc") + renderDesc("cancel"),
+			}
+		case CompareScreen:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Tab") + renderDesc("other list"),
+				renderKey("m") + renderDesc("move across"),
+				renderKey("x") + renderDesc("toggle"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case TodayScreen:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("jump to todo"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case ConflictResolver:
+			hints = []string{
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("h") + renderDesc("keep local"),
+				renderKey("l") + renderDesc("keep remote"),
+				renderKey("b") + renderDesc("keep both"),
+				renderKey("Enter") + renderDesc("write merge"),
+				renderKey("Esc") + renderDesc("cancel"),
+			}
+		case DebugLogScreen:
+			hints = []string{
+				renderKey("j/k") + renderDesc("scroll"),
+				renderKey("g/G") + renderDesc("oldest / latest"),
+				renderKey("r") + renderDesc("reload"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case StatsScreen:
+			hints = []string{
+				renderKey("h/l") + renderDesc("select week"),
+				renderKey("j/k") + renderDesc("navigate"),
+				renderKey("Enter") + renderDesc("show todos"),
+				renderKey("Esc") + renderDesc("close"),
+			}
+		case AddTodo:
+			hints = []string{
+				renderKey("Enter") + renderDesc("save"),
+				renderKey("Esc") + renderDesc("cancel"),
+			}
+			if len(m.suggestions) > 0 {
+				hints = append(hints,
+					renderKey("↑/↓")+renderDesc("suggestions"),
+					renderKey("Tab")+renderDesc("complete"),
+				)
 			}
 		default:
 			hints = []string{
@@ -359,60 +760,127 @@ func (m Model) renderHints() string {
 				renderKey("Esc") + renderDesc("cancel"),
 			}
 		}
+		if d, ok := m.openModal(); ok && d.input == "" {
+			hints = append(hints, renderKey("←/→")+renderDesc("choose"), renderKey("Enter")+renderDesc("confirm"))
+		}
 	} else if m.ActivePanel == FilePanel {
 		if m.ShowingArchive {
 			hints = []string{
-				renderKey("j/k") + renderDesc("navigate"),
-				renderKey("Enter") + renderDesc("unarchive"),
-				renderKey("z") + renderDesc("show active"),
-				renderKey("h/l") + renderDesc("switch"),
-				renderKey("q") + renderDesc("quit"),
+				renderAction("navigate", ActionDown, ActionUp),
+				renderAction("unarchive", ActionOpen),
+				renderAction("show active", ActionShowArchive),
+				renderAction("switch", ActionLeft, ActionRight),
+				renderAction("quit", ActionQuit),
+			}
+		} else if len(m.markedFiles()) > 0 {
+			hints = []string{
+				renderAction("mark", ActionSelect),
+				renderAction("archive marked", ActionArchive),
+				renderAction("delete marked", ActionDelete),
+				renderAction("merge marked", ActionMerge),
+				renderAction("copy marked", ActionCopyList),
+				renderAction("unmark", ActionBack),
+				renderAction("quit", ActionQuit),
 			}
 		} else {
 			hints = []string{
-				renderKey("j/k") + renderDesc("navigate"),
-				renderKey("a") + renderDesc("new"),
-				renderKey("d") + renderDesc("delete"),
-				renderKey("Enter") + renderDesc("open"),
-				renderKey("A") + renderDesc("archive"),
-				renderKey("z") + renderDesc("archived"),
-				renderKey("h/l") + renderDesc("switch"),
-				renderKey("q") + renderDesc("quit"),
+				renderAction("navigate", ActionDown, ActionUp),
+				renderAction("new", ActionAdd),
+				renderAction("template", ActionTemplate),
+				renderAction("delete", ActionDelete),
+				renderAction("open", ActionOpen),
+				renderAction("archive", ActionArchive),
+				renderAction("archived", ActionShowArchive),
+				renderAction("context", ActionContext),
+				renderAction("today", ActionToday),
+				renderAction("habits", ActionHabit),
+				renderAction("$EDITOR", ActionEditor),
+				renderAction("switch", ActionLeft, ActionRight),
+				renderAction("quit", ActionQuit),
 			}
 		}
 	} else {
-		hints = []string{
-			renderKey("j/k") + renderDesc("navigate"),
-			renderKey("a") + renderDesc("add"),
-			renderKey("i") + renderDesc("edit"),
-			renderKey("d") + renderDesc("delete"),
-			renderKey("x/Space") + renderDesc("toggle"),
-			renderKey("h/l") + renderDesc("switch"),
-			renderKey("q") + renderDesc("quit"),
+		hints = []string{renderAction("navigate", ActionDown, ActionUp)}
+		// What's in use comes first, before the hints a narrow window leaves out
+		if m.clearableFilters() {
+			hints = append(hints, renderAction("clear filters", ActionClearFilters))
 		}
+		if m.virtual != nil {
+			hints = append(hints, renderAction("sort view", ActionViewSort), renderAction("columns", ActionViewColumns))
+		}
+		if m.TodoList.IsScratch() {
+			hints = append(hints, renderAction("promote", ActionPromote))
+		}
+		hints = append(hints,
+			renderAction("add", ActionAdd),
+			renderAction("edit", ActionEdit),
+			renderAction("delete", ActionDelete),
+			renderAction("toggle", ActionToggle, ActionSelect),
+			renderAction("due", ActionDue),
+			renderAction("remind", ActionRemind),
+			renderAction("flag", ActionFlag),
+			renderAction("follow link", ActionFollowLink),
+			renderAction("copy todo/list", ActionCopy, ActionCopyList),
+			renderAction("field", ActionField),
+			renderAction("filter field", ActionFieldFilter),
+			renderAction("focus", ActionFocus),
+			renderAction("context", ActionContext),
+			renderAction("heading", ActionHeading),
+			renderAction("move section", ActionSectionDown, ActionSectionUp),
+			renderAction("split", ActionSplit),
+			renderAction("switch", ActionLeft, ActionRight),
+			renderAction("quit", ActionQuit),
+		)
 	}
 
-	// Join hints with separator
-	result := " "
-	for i, hint := range hints {
-		result += hint
-		if i < len(hints)-1 {
-			result += sep
+	// The keybindings screen lists the hints left out of a narrow window
+	more := ""
+	if m.Mode == NormalMode {
+		if keys := m.hintKeys(ActionKeybindings); keys != "" {
+			more = renderKey(keys) + renderDesc("for more")
 		}
 	}
-	return result
+	return m.fitHints(hints, sep, more)
 }
 
 // renderStatusBar renders the status message
 func (m Model) renderStatusBar() string {
-	if m.StatusMessage != "" && m.EditingIndex != -3 && m.EditingIndex != -4 {
+	if m.Dialog == GotoPrompt {
+		return "\n\n" + m.Styles.Edit.Render(" :"+m.InputText+"█")
+	}
+	if m.Dialog == FilterNamePrompt {
+		return "\n\n" + m.Styles.Edit.Render(" "+i18n.T("Filter name")+": "+m.InputText+"█")
+	}
+	if m.Dialog == TitlePrompt {
+		return "\n\n" + m.Styles.Edit.Render(" "+i18n.T("List title")+": "+m.InputText+"█")
+	}
+	if m.Dialog == JiraPrompt {
+		return "\n\n" + m.Styles.Edit.Render(" "+i18n.T("Jira issue")+": "+m.InputText+"█")
+	}
+	if m.Mode == NormalMode {
+		lines := m.renderProgress()
+		if toasts := m.renderToasts(); lines == "" {
+			lines = toasts
+		} else if toasts != "" {
+			lines += "\n" + toasts
+		}
+		if lines == "" {
+			return ""
+		}
+		return "\n\n" + lines
+	}
+	if m.statusLines() > 0 {
 		statusIcon := "󰙎 "
 		statusStyle := lipgloss.NewStyle().
 			Foreground(ColorGreen).
 			Background(ColorMantle).
 			Padding(0, 1).
 			Bold(true)
-		return "\n\n" + statusStyle.Render(statusIcon+m.StatusMessage)
+		status := statusStyle.Render(statusIcon + m.StatusMessage)
+		if confetti := m.renderConfetti(); confetti != "" {
+			status += " " + confetti
+		}
+		return "\n\n" + status
 	}
 	return ""
 }
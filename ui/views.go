@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"justdoit/config"
+	"justdoit/i18n"
+	"justdoit/search"
+	"justdoit/todo"
+)
+
+// Orders and columns each smart view cycles through; "" is the view's own order
+var (
+	todaySorts    = []string{"", config.SortTitle, config.SortList}
+	todayColumns  = []string{config.ColumnList, config.ColumnDue}
+	filterSorts   = []string{"", config.SortDue, config.SortTitle, config.SortCreated}
+	filterColumns = []string{config.ColumnDue, config.ColumnFields}
+)
+
+// openFilterIndex returns the index of the saved filter open in the todo panel, or -1
+func (m Model) openFilterIndex() int {
+	if m.virtual == nil {
+		return -1
+	}
+	return slices.IndexFunc(m.Filters, func(f config.Filter) bool { return f.Name == m.virtual.name })
+}
+
+// inTodayView reports whether the Today view is on screen
+func (m Model) inTodayView() bool {
+	return m.Dialog == TodayScreen
+}
+
+// hidesColumn reports whether the saved filter on screen leaves out a column
+func (m Model) hidesColumn(column string) bool {
+	if i := m.openFilterIndex(); i >= 0 {
+		return m.Filters[i].Hides(column)
+	}
+	return false
+}
+
+// cycleViewSort switches the smart view on screen to its next sort order and remembers it
+func (m *Model) cycleViewSort() {
+	sorts := filterSorts
+	if m.inTodayView() {
+		sorts = todaySorts
+	}
+	m.updateView(func(v *config.View) {
+		v.Sort = sorts[(slices.Index(sorts, v.Sort)+1)%len(sorts)]
+		m.toast(SeverityInfo, i18n.Tf("Sorted by %s", m.sortLabel(v.Sort)))
+	})
+}
+
+// cycleViewColumns steps the smart view on screen through every combination of its
+// optional columns and remembers the choice
+func (m *Model) cycleViewColumns() {
+	columns := filterColumns
+	if m.inTodayView() {
+		columns = todayColumns
+	}
+	m.updateView(func(v *config.View) {
+		// Count through the hidden columns as bits
+		mask := 0
+		for i, c := range columns {
+			if v.Hides(c) {
+				mask |= 1 << i
+			}
+		}
+		mask = (mask + 1) % (1 << len(columns))
+
+		v.Hide = nil
+		var shown []string
+		for i, c := range columns {
+			if mask&(1<<i) != 0 {
+				v.Hide = append(v.Hide, c)
+			} else {
+				shown = append(shown, columnLabel(c))
+			}
+		}
+		if len(shown) == 0 {
+			m.toast(SeverityInfo, i18n.T("Columns: none"))
+		} else {
+			m.toast(SeverityInfo, i18n.Tf("Columns: %s", strings.Join(shown, ", ")))
+		}
+	})
+}
+
+// updateView changes the settings of the smart view on screen, saves them to the config
+// and shows the view again with them applied
+func (m *Model) updateView(change func(v *config.View)) {
+	filter := m.openFilterIndex()
+	if !m.inTodayView() && filter < 0 {
+		m.toast(SeverityInfo, i18n.T("Sort and columns are kept for the Today view and saved filters"))
+		return
+	}
+
+	today := m.TodayView
+	filters := slices.Clone(m.Filters)
+	if m.inTodayView() {
+		change(&today)
+	} else {
+		change(&filters[filter].View)
+	}
+	status := m.StatusMessage
+	if err := m.saveViews(today, filters); err != nil {
+		m.toast(SeverityError, err.Error())
+		return
+	}
+	m.TodayView, m.Filters = today, filters
+
+	if m.inTodayView() {
+		sortHits(m.Today, today.Sort)
+		m.TodayCursor = 0
+	} else {
+		m.openFilter(filter)
+	}
+	m.toast(SeverityInfo, status)
+}
+
+// saveViews writes the Today view and saved filters to the config file, keeping its other sections
+func (m Model) saveViews(today config.View, filters []config.Filter) error {
+	if m.ConfigPath == "" {
+		return nil
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		return err
+	}
+	cfg.Today = today
+	cfg.Filters = filters
+	return config.Save(m.ConfigPath, cfg)
+}
+
+// sortHits orders Today view hits, by due time for the view's own order
+func sortHits(hits []search.Hit, by string) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		switch by {
+		case config.SortTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case config.SortList:
+			return filepath.Base(a.File) < filepath.Base(b.File)
+		}
+		return a.Due != nil && (b.Due == nil || a.Due.Before(*b.Due))
+	})
+}
+
+// sortTodos orders the rows of a saved filter shown without list headings
+func sortTodos(todos []todo.Todo, by string) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		switch by {
+		case config.SortDue:
+			return a.Due != nil && (b.Due == nil || a.Due.Before(*b.Due))
+		case config.SortTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case config.SortCreated:
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return false
+	})
+}
+
+// sortLabel names a sort order of the smart view on screen
+func (m Model) sortLabel(by string) string {
+	switch by {
+	case config.SortDue:
+		return i18n.T("due date")
+	case config.SortTitle:
+		return i18n.T("title")
+	case config.SortCreated:
+		return i18n.T("newest first")
+	case config.SortList:
+		return i18n.T("list")
+	}
+	if m.inTodayView() {
+		return i18n.T("due time")
+	}
+	return i18n.T("list order")
+}
+
+// columnLabel names an optional column
+func columnLabel(column string) string {
+	switch column {
+	case config.ColumnDue:
+		return i18n.T("due date")
+	case config.ColumnFields:
+		return i18n.T("fields")
+	}
+	return i18n.T("list")
+}
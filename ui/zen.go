@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderZen renders the open list full-width, with only its name, progress and filters above it
+func (m Model) renderZen() string {
+	header := m.Styles.Muted.Render(m.listName())
+	if completed, total := m.todoStats(); total > 0 {
+		header += m.Styles.Muted.Render(fmt.Sprintf("  %d/%d", completed, total))
+	}
+	if row := m.renderFilterRow(); row != "" {
+		header += "\n" + row
+	}
+
+	content := fitLines(m.renderTodoContent(), m.Width-2)
+	return lipgloss.NewStyle().
+		Height(m.panelHeight()+2).
+		Padding(1, 1).
+		Render(header + "\n\n" + content)
+}